@@ -3,6 +3,8 @@ package main
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"wosbTrade/internal/bot"
 
@@ -38,6 +40,39 @@ func main() {
 
 	adminRoleID := os.Getenv("ADMIN_ROLE_ID")
 
+	// Development only: register commands to one guild so changes apply instantly
+	devGuildID := os.Getenv("DEV_GUILD_ID")
+
+	// Optional read-only HTTP API, off unless API_ADDR is set
+	apiAddr := os.Getenv("API_ADDR")
+	apiKey := os.Getenv("API_KEY")
+
+	// Optional Prometheus endpoint, off unless METRICS_ADDR is set
+	metricsAddr := os.Getenv("METRICS_ADDR")
+
+	// Optional default for how long /submit confirmations stay open
+	var submissionTimeout time.Duration
+	if v := os.Getenv("SUBMISSION_TIMEOUT_MINUTES"); v != "" {
+		minutes, err := strconv.Atoi(v)
+		if err != nil || minutes <= 0 {
+			log.Fatalf("SUBMISSION_TIMEOUT_MINUTES must be a positive number of minutes, got %q", v)
+		}
+		submissionTimeout = time.Duration(minutes) * time.Minute
+	}
+
+	// Optional cap on /submit screenshot size
+	var maxImageBytes int
+	if v := os.Getenv("SUBMISSION_MAX_IMAGE_MB"); v != "" {
+		mb, err := strconv.Atoi(v)
+		if err != nil || mb <= 0 {
+			log.Fatalf("SUBMISSION_MAX_IMAGE_MB must be a positive number of megabytes, got %q", v)
+		}
+		maxImageBytes = mb << 20
+	}
+
+	// Optional list of words barred from notes, names and relayed DMs
+	wordFilterPath := os.Getenv("WORD_FILTER_FILE")
+
 	// Create bot instance
 	config := bot.Config{
 		Token:          token,
@@ -45,6 +80,14 @@ func main() {
 		ImagePath:      imagePath,
 		ClaudeCodePath: claudeCodePath,
 		AdminRoleID:    adminRoleID,
+		DevGuildID:     devGuildID,
+		APIAddr:        apiAddr,
+		APIKey:         apiKey,
+		MetricsAddr:    metricsAddr,
+
+		SubmissionTimeout: submissionTimeout,
+		MaxImageBytes:     maxImageBytes,
+		WordFilterPath:    wordFilterPath,
 	}
 
 	b, err := bot.New(config)
@@ -0,0 +1,208 @@
+// Package api serves read-only market data as JSON for guild websites.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"wosbTrade/internal/database"
+)
+
+// APIKeyHeader carries the shared key every request must present
+const APIKeyHeader = "X-API-Key"
+
+// shutdownTimeout bounds how long Close waits for in-flight requests
+const shutdownTimeout = 5 * time.Second
+
+// Server is the optional embedded HTTP API
+type Server struct {
+	db     *database.DB
+	apiKey string
+	http   *http.Server
+}
+
+// New creates an API server listening on addr (e.g. ":8080"). A key is
+// required so the board isn't readable by anyone who finds the port.
+func New(db *database.DB, addr, apiKey string) (*Server, error) {
+	if strings.TrimSpace(apiKey) == "" {
+		return nil, errors.New("an API key is required to enable the HTTP API")
+	}
+
+	s := &Server{db: db, apiKey: apiKey}
+	s.http = &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s, nil
+}
+
+// Handler returns the API routes wrapped in the API key check
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/prices", s.handlePrices)
+	mux.HandleFunc("/api/port/", s.handlePort)
+	mux.HandleFunc("/api/stats", s.handleStats)
+	return s.requireAPIKey(mux)
+}
+
+// Start listens in the background. Listen errors are returned immediately;
+// errors after that are logged.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.http.Addr, err)
+	}
+
+	log.Printf("HTTP API listening on %s", listener.Addr())
+	go func() {
+		if err := s.http.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("HTTP API stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Close stops accepting requests and waits briefly for in-flight ones
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.http.Shutdown(ctx)
+}
+
+func (s *Server) requireAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(APIKeyHeader)
+		if subtle.ConstantTimeCompare([]byte(key), []byte(s.apiKey)) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+			return
+		}
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "only GET is supported")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// order is the public shape of a market order; submitter IDs and
+// screenshot hashes stay internal
+type order struct {
+	Port        string    `json:"port"`
+	Region      string    `json:"region,omitempty"`
+	Item        string    `json:"item"`
+	OrderType   string    `json:"order_type"`
+	Price       int       `json:"price"`
+	Quantity    int       `json:"quantity"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func newOrders(markets []database.Market) []order {
+	orders := make([]order, 0, len(markets))
+	for _, m := range markets {
+		o := order{
+			OrderType:   m.OrderType,
+			Price:       m.Price,
+			Quantity:    m.Quantity,
+			SubmittedAt: m.SubmittedAt.UTC(),
+			ExpiresAt:   m.ExpiresAt.UTC(),
+		}
+		if m.Port != nil {
+			o.Port, o.Region = m.Port.DisplayName, m.Port.Region
+		}
+		if m.Item != nil {
+			o.Item = m.Item.DisplayName
+		}
+		orders = append(orders, o)
+	}
+	return orders
+}
+
+// handlePrices serves GET /api/prices?item=<name>
+func (s *Server) handlePrices(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSpace(r.URL.Query().Get("item"))
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "item query parameter is required")
+		return
+	}
+
+	matches, err := s.db.FindItemMatches(r.Context(), name, 1)
+	if err != nil || len(matches) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("item not found: %s", name))
+		return
+	}
+	item := matches[0].Item
+
+	markets, err := s.db.GetPricesByItem(r.Context(), item.ID, nil, 0, 0, 0, 0)
+	if err != nil {
+		log.Printf("API error querying prices: %v", err)
+		writeError(w, http.StatusInternalServerError, "database error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"item":   item.DisplayName,
+		"orders": newOrders(markets),
+	})
+}
+
+// handlePort serves GET /api/port/<name>
+func (s *Server) handlePort(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/api/port/"))
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "port name is required")
+		return
+	}
+
+	matches, err := s.db.FindPortMatches(r.Context(), name, 1)
+	if err != nil || len(matches) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("port not found: %s", name))
+		return
+	}
+	port := matches[0].Port
+
+	markets, err := s.db.GetOrdersByPort(r.Context(), port.ID)
+	if err != nil {
+		log.Printf("API error querying port: %v", err)
+		writeError(w, http.StatusInternalServerError, "database error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"port":   port.DisplayName,
+		"region": port.Region,
+		"orders": newOrders(markets),
+	})
+}
+
+// handleStats serves GET /api/stats
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.db.GetStats(r.Context())
+	if err != nil {
+		log.Printf("API error querying stats: %v", err)
+		writeError(w, http.StatusInternalServerError, "database error")
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("API error writing response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"wosbTrade/internal/database"
+)
+
+const testKey = "secret"
+
+func setupTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	tmpfile, err := os.CreateTemp("", "test-*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp db: %v", err)
+	}
+	tmpfile.Close()
+
+	db, err := database.New(tmpfile.Name())
+	if err != nil {
+		os.Remove(tmpfile.Name())
+		t.Fatalf("failed to initialize database: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		os.Remove(tmpfile.Name())
+	})
+
+	ctx := context.Background()
+	if _, err := db.CreateRegion(ctx, "Caribbean", "test"); err != nil {
+		t.Fatalf("failed to create region: %v", err)
+	}
+	port, err := db.CreatePort(ctx, "Port Royal", "Port Royal", "Caribbean", "test")
+	if err != nil {
+		t.Fatalf("failed to create port: %v", err)
+	}
+	item, err := db.CreateItem(ctx, "Cannon", "Cannon", "test")
+	if err != nil {
+		t.Fatalf("failed to create item: %v", err)
+	}
+	markets := []database.Market{{ItemID: item.ID, Price: 100, Quantity: 4}}
+	if err := db.ReplacePortOrders(ctx, port.ID, "sell", markets, "user123", "hash", ""); err != nil {
+		t.Fatalf("failed to insert orders: %v", err)
+	}
+
+	s, err := New(db, "127.0.0.1:0", testKey)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return s
+}
+
+func get(t *testing.T, s *Server, path, key string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if key != "" {
+		req.Header.Set(APIKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON: %q", rec.Body.String())
+	}
+	return rec, body
+}
+
+func TestNewRequiresAPIKey(t *testing.T) {
+	if _, err := New(nil, ":0", " "); err == nil {
+		t.Error("expected an empty API key to be rejected")
+	}
+}
+
+func TestAPIKeyRequired(t *testing.T) {
+	s := setupTestServer(t)
+
+	for _, key := range []string{"", "wrong"} {
+		rec, _ := get(t, s, "/api/stats", key)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("key %q: expected 401, got %d", key, rec.Code)
+		}
+	}
+}
+
+func TestPricesEndpoint(t *testing.T) {
+	s := setupTestServer(t)
+
+	rec, body := get(t, s, "/api/prices?item=cannon", testKey)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %v", rec.Code, body)
+	}
+	orders := body["orders"].([]interface{})
+	if body["item"] != "Cannon" || len(orders) != 1 {
+		t.Fatalf("expected one Cannon order, got %v", body)
+	}
+	o := orders[0].(map[string]interface{})
+	if o["port"] != "Port Royal" || o["price"].(float64) != 100 {
+		t.Errorf("unexpected order %v", o)
+	}
+	if _, leaked := o["SubmittedBy"]; leaked {
+		t.Error("expected submitter IDs to stay private")
+	}
+
+	if rec, _ := get(t, s, "/api/prices", testKey); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without item, got %d", rec.Code)
+	}
+	if rec, _ := get(t, s, "/api/prices?item=zzzzzz", testKey); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown item, got %d", rec.Code)
+	}
+}
+
+func TestPortEndpoint(t *testing.T) {
+	s := setupTestServer(t)
+
+	rec, body := get(t, s, "/api/port/Port%20Royal", testKey)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %v", rec.Code, body)
+	}
+	if body["port"] != "Port Royal" || body["region"] != "Caribbean" || len(body["orders"].([]interface{})) != 1 {
+		t.Errorf("unexpected port response %v", body)
+	}
+}
+
+func TestStatsEndpoint(t *testing.T) {
+	s := setupTestServer(t)
+
+	rec, body := get(t, s, "/api/stats", testKey)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %v", rec.Code, body)
+	}
+	if body["total_orders"].(float64) != 1 {
+		t.Errorf("expected 1 order in stats, got %v", body)
+	}
+}
+
+func TestStartAndClose(t *testing.T) {
+	s := setupTestServer(t)
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+}
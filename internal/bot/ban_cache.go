@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"wosbTrade/internal/database"
+)
+
+// banCacheClearTTL is how long a "not banned" lookup is trusted. Bans made
+// through the bot update the cache directly; this only bounds how stale it
+// can get if the database is changed some other way.
+const banCacheClearTTL = 10 * time.Minute
+
+// BanCache answers "is this user trade-banned?" from memory so trade commands
+// and every relayed DM don't each run a query. Users it hasn't seen are
+// looked up once and remembered.
+type BanCache struct {
+	mu       sync.Mutex
+	bans     map[string]database.TradeBan // userID -> active ban
+	clear    map[string]time.Time         // userID -> when found not banned
+	gen      map[string]uint64            // userID -> bumped by every Set or Remove
+	lookup   func(ctx context.Context, userID string) (*database.TradeBan, error)
+	clearTTL time.Duration
+	now      func() time.Time
+}
+
+// NewBanCache creates a cache that falls back to lookup on a miss
+func NewBanCache(lookup func(ctx context.Context, userID string) (*database.TradeBan, error)) *BanCache {
+	return &BanCache{
+		bans:     make(map[string]database.TradeBan),
+		clear:    make(map[string]time.Time),
+		gen:      make(map[string]uint64),
+		lookup:   lookup,
+		clearTTL: banCacheClearTTL,
+		now:      time.Now,
+	}
+}
+
+// Load seeds the cache with the bans active at startup
+func (bc *BanCache) Load(bans []database.TradeBan) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	for _, ban := range bans {
+		bc.bans[ban.UserID] = ban
+		delete(bc.clear, ban.UserID)
+	}
+}
+
+// IsUserBanned returns the user's active ban, or nil if they aren't banned.
+// Expired bans are dropped on lookup.
+func (bc *BanCache) IsUserBanned(ctx context.Context, userID string) (*database.TradeBan, error) {
+	bc.mu.Lock()
+	now := bc.now()
+	if ban, ok := bc.bans[userID]; ok {
+		if ban.ExpiresAt == nil || now.Before(*ban.ExpiresAt) {
+			bc.mu.Unlock()
+			return &ban, nil
+		}
+		delete(bc.bans, userID)
+		bc.clear[userID] = now
+		bc.mu.Unlock()
+		return nil, nil
+	}
+	if checked, ok := bc.clear[userID]; ok && now.Sub(checked) < bc.clearTTL {
+		bc.mu.Unlock()
+		return nil, nil
+	}
+	gen := bc.gen[userID]
+	bc.mu.Unlock()
+
+	// Query without holding the lock. If a Set or Remove lands meanwhile,
+	// the lookup may predate it, so keep what they recorded instead.
+	ban, err := bc.lookup(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if bc.gen[userID] != gen {
+		if current, ok := bc.bans[userID]; ok {
+			return &current, nil
+		}
+		return nil, nil
+	}
+	if ban != nil {
+		bc.bans[userID] = *ban
+	} else {
+		bc.clear[userID] = bc.now()
+	}
+	return ban, nil
+}
+
+// Set records a ban just issued
+func (bc *BanCache) Set(ban database.TradeBan) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	bc.bans[ban.UserID] = ban
+	delete(bc.clear, ban.UserID)
+	bc.gen[ban.UserID]++
+}
+
+// Remove records that a user's ban was lifted
+func (bc *BanCache) Remove(userID string) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	delete(bc.bans, userID)
+	bc.clear[userID] = bc.now()
+	bc.gen[userID]++
+}
@@ -0,0 +1,113 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"wosbTrade/internal/database"
+)
+
+// countingLookup is a ban lookup backed by a map that counts its calls
+type countingLookup struct {
+	bans  map[string]*database.TradeBan
+	calls int
+}
+
+func (cl *countingLookup) lookup(ctx context.Context, userID string) (*database.TradeBan, error) {
+	cl.calls++
+	return cl.bans[userID], nil
+}
+
+func TestBanCacheReflectsBansAndUnbans(t *testing.T) {
+	ctx := context.Background()
+	db := &countingLookup{bans: map[string]*database.TradeBan{}}
+	bc := NewBanCache(db.lookup)
+	bc.Load([]database.TradeBan{{UserID: "loaded", Reason: "scam"}})
+
+	if ban, _ := bc.IsUserBanned(ctx, "loaded"); ban == nil {
+		t.Errorf("Expected a ban loaded at startup to be found")
+	}
+	if db.calls != 0 {
+		t.Errorf("Expected a loaded ban to be served from memory, got %d lookups", db.calls)
+	}
+
+	// A miss queries once, then is remembered
+	for n := 0; n < 3; n++ {
+		if ban, _ := bc.IsUserBanned(ctx, "user1"); ban != nil {
+			t.Fatalf("Expected user1 not to be banned")
+		}
+	}
+	if db.calls != 1 {
+		t.Errorf("Expected one lookup for repeated misses, got %d", db.calls)
+	}
+
+	bc.Set(database.TradeBan{UserID: "user1", Reason: "spam"})
+	if ban, _ := bc.IsUserBanned(ctx, "user1"); ban == nil || ban.Reason != "spam" {
+		t.Errorf("Expected a new ban to be visible immediately, got %+v", ban)
+	}
+
+	bc.Remove("user1")
+	if ban, _ := bc.IsUserBanned(ctx, "user1"); ban != nil {
+		t.Errorf("Expected an unban to be visible immediately, got %+v", ban)
+	}
+	if db.calls != 1 {
+		t.Errorf("Expected bans and unbans not to need lookups, got %d", db.calls)
+	}
+}
+
+func TestBanCacheHonorsExpiry(t *testing.T) {
+	ctx := context.Background()
+	db := &countingLookup{bans: map[string]*database.TradeBan{}}
+	bc := NewBanCache(db.lookup)
+
+	now := time.Now()
+	bc.now = func() time.Time { return now }
+	expires := now.Add(time.Hour)
+	bc.Set(database.TradeBan{UserID: "user1", ExpiresAt: &expires})
+
+	if ban, _ := bc.IsUserBanned(ctx, "user1"); ban == nil {
+		t.Fatalf("Expected the ban to apply before it expires")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if ban, _ := bc.IsUserBanned(ctx, "user1"); ban != nil {
+		t.Errorf("Expected an expired ban to be ignored, got %+v", ban)
+	}
+
+	// "Not banned" answers go stale so outside changes are eventually seen
+	db.bans["user1"] = &database.TradeBan{UserID: "user1"}
+	now = now.Add(banCacheClearTTL)
+	if ban, _ := bc.IsUserBanned(ctx, "user1"); ban == nil {
+		t.Errorf("Expected a stale miss to be looked up again")
+	}
+}
+
+func TestBanCacheRemoveDuringLookupWins(t *testing.T) {
+	ctx := context.Background()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	bc := NewBanCache(func(ctx context.Context, userID string) (*database.TradeBan, error) {
+		close(started)
+		<-release
+		// The lookup read the ban before the unban committed
+		return &database.TradeBan{UserID: userID, Reason: "stale"}, nil
+	})
+
+	done := make(chan *database.TradeBan)
+	go func() {
+		ban, _ := bc.IsUserBanned(ctx, "user1")
+		done <- ban
+	}()
+
+	<-started
+	bc.Remove("user1")
+	close(release)
+
+	if ban := <-done; ban != nil {
+		t.Errorf("Expected the racing unban to win, got %+v", ban)
+	}
+	if ban, _ := bc.IsUserBanned(ctx, "user1"); ban != nil {
+		t.Errorf("Expected the stale lookup not to be cached, got %+v", ban)
+	}
+}
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// bestRoute pairs the cheapest place to buy an item with the best place to
+// sell it. Either side is nil when no port has orders of that type.
+type bestRoute struct {
+	BuyFrom *database.Market // lowest sell order: the port sells to you
+	SellTo  *database.Market // highest buy order: the port buys from you
+}
+
+// Profit is the per-unit gain from buying at BuyFrom and selling at SellTo.
+// ok is false unless both sides exist.
+func (r bestRoute) Profit() (profit int, ok bool) {
+	if r.BuyFrom == nil || r.SellTo == nil {
+		return 0, false
+	}
+	return r.SellTo.Price - r.BuyFrom.Price, true
+}
+
+// findBestRoute picks the lowest sell and highest buy order. Ties go to the
+// freshest submission.
+func findBestRoute(markets []database.Market) bestRoute {
+	var route bestRoute
+	for idx := range markets {
+		m := &markets[idx]
+		switch m.OrderType {
+		case "sell":
+			if route.BuyFrom == nil || m.Price < route.BuyFrom.Price ||
+				(m.Price == route.BuyFrom.Price && m.SubmittedAt.After(route.BuyFrom.SubmittedAt)) {
+				route.BuyFrom = m
+			}
+		case "buy":
+			if route.SellTo == nil || m.Price > route.SellTo.Price ||
+				(m.Price == route.SellTo.Price && m.SubmittedAt.After(route.SellTo.SubmittedAt)) {
+				route.SellTo = m
+			}
+		}
+	}
+	return route
+}
+
+// buildBestRouteEmbed renders a route for /best-route
+func buildBestRouteEmbed(item *database.Item, route bestRoute, now time.Time) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:     fmt.Sprintf("🧭 Best Route: %s", item.DisplayName),
+		Color:     0x3498db,
+		Timestamp: now.Format(time.RFC3339),
+	}
+
+	routeLine := func(m *database.Market) string {
+		return fmt.Sprintf("**%s**: %s (qty: %d) - %s",
+			m.Port.DisplayName, formatGold(int64(m.Price)), m.Quantity, formatAge(now.Sub(m.SubmittedAt)))
+	}
+
+	if route.BuyFrom != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: "Buy From", Value: routeLine(route.BuyFrom),
+		})
+	}
+	if route.SellTo != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: "Sell To", Value: routeLine(route.SellTo),
+		})
+	}
+
+	profit, ok := route.Profit()
+	switch {
+	case !ok && route.BuyFrom != nil:
+		embed.Description = "No port is buying this item right now, so there's no route to sell it."
+	case !ok:
+		embed.Description = "No port is selling this item right now, so there's no route to buy it."
+	case profit > 0:
+		units := route.BuyFrom.Quantity
+		if route.SellTo.Quantity < units {
+			units = route.SellTo.Quantity
+		}
+		embed.Description = fmt.Sprintf("Profit: **%s per unit**, up to %s for %d units.", formatGold(int64(profit)), formatGold(orderTotal(profit, units)), units)
+		embed.Color = 0x2ecc71
+	case profit == 0:
+		embed.Description = "No profitable route: the best buy price only matches the cheapest sell price."
+	default:
+		embed.Description = fmt.Sprintf("No profitable route: the best buy price is %s below the cheapest sell price.", formatGold(int64(-profit)))
+		embed.Color = 0xe74c3c
+	}
+
+	return embed
+}
@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"wosbTrade/internal/database"
+)
+
+func routeMarket(port, orderType string, price, quantity int, submitted time.Time) database.Market {
+	return database.Market{
+		OrderType:   orderType,
+		Price:       price,
+		Quantity:    quantity,
+		SubmittedAt: submitted,
+		Port:        &database.Port{DisplayName: port},
+	}
+}
+
+func TestFindBestRoute(t *testing.T) {
+	now := time.Now()
+	markets := []database.Market{
+		routeMarket("Tortuga", "buy", 130, 5, now),
+		routeMarket("Nassau", "buy", 140, 3, now.Add(-time.Hour)),
+		routeMarket("Port Royal", "sell", 100, 10, now.Add(-2*time.Hour)),
+		routeMarket("Havana", "sell", 100, 8, now), // same price, fresher
+		routeMarket("Tortuga", "sell", 120, 10, now),
+	}
+
+	route := findBestRoute(markets)
+	if route.BuyFrom == nil || route.BuyFrom.Port.DisplayName != "Havana" {
+		t.Fatalf("Expected to buy from Havana, got %+v", route.BuyFrom)
+	}
+	if route.SellTo == nil || route.SellTo.Port.DisplayName != "Nassau" {
+		t.Fatalf("Expected to sell to Nassau, got %+v", route.SellTo)
+	}
+	if profit, ok := route.Profit(); !ok || profit != 40 {
+		t.Errorf("Expected 40 profit, got %d (ok=%v)", profit, ok)
+	}
+
+	embed := buildBestRouteEmbed(&database.Item{DisplayName: "Cannon"}, route, now)
+	if !strings.Contains(embed.Description, "40 gold per unit") || !strings.Contains(embed.Description, "120 gold for 3 units") {
+		t.Errorf("Unexpected profit line %q", embed.Description)
+	}
+	if len(embed.Fields) != 2 || !strings.Contains(embed.Fields[1].Value, "1h ago") {
+		t.Errorf("Expected buy and sell fields with data age, got %+v", embed.Fields)
+	}
+}
+
+func TestBestRouteOneSided(t *testing.T) {
+	now := time.Now()
+	route := findBestRoute([]database.Market{routeMarket("Tortuga", "sell", 120, 10, now)})
+
+	if _, ok := route.Profit(); ok {
+		t.Error("Expected no profit without a buy order")
+	}
+	embed := buildBestRouteEmbed(&database.Item{DisplayName: "Cannon"}, route, now)
+	if len(embed.Fields) != 1 || embed.Fields[0].Name != "Buy From" || !strings.Contains(embed.Description, "No port is buying") {
+		t.Errorf("Expected only the buy side, got %q %+v", embed.Description, embed.Fields)
+	}
+}
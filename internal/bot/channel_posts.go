@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// channelPostRetryInterval is how often queued channel posts are retried
+	channelPostRetryInterval = 30 * time.Second
+	// channelPostMaxAttempts is how many sends (including the first) are tried before giving up
+	channelPostMaxAttempts = 5
+)
+
+// pendingChannelPost is an embed that failed to send and is waiting for a retry
+type pendingChannelPost struct {
+	channelID   string
+	embed       *discordgo.MessageEmbed
+	attempts    int
+	nextAttempt time.Time
+	lastErr     error
+}
+
+// ChannelPostQueue sends best-effort channel announcements, keeping failed
+// sends (missing permissions, rate limits, outages) for retry with backoff
+// instead of dropping them.
+type ChannelPostQueue struct {
+	mu          sync.Mutex
+	send        func(channelID string, embed *discordgo.MessageEmbed) error
+	pending     []*pendingChannelPost
+	maxAttempts int
+	backoff     time.Duration
+	now         func() time.Time
+
+	// onGiveUp is called for posts dropped after maxAttempts failures
+	onGiveUp func(channelID string, embed *discordgo.MessageEmbed, err error)
+}
+
+// NewChannelPostQueue creates a queue that delivers posts with send
+func NewChannelPostQueue(send func(channelID string, embed *discordgo.MessageEmbed) error) *ChannelPostQueue {
+	return &ChannelPostQueue{
+		send:        send,
+		maxAttempts: channelPostMaxAttempts,
+		backoff:     channelPostRetryInterval,
+		now:         time.Now,
+		onGiveUp: func(channelID string, embed *discordgo.MessageEmbed, err error) {
+			log.Printf("Giving up on post %q to channel %s after %d attempts: %v",
+				embed.Title, channelID, channelPostMaxAttempts, err)
+		},
+	}
+}
+
+// Post sends an embed now, queueing it for retry if the send fails
+func (q *ChannelPostQueue) Post(channelID string, embed *discordgo.MessageEmbed) {
+	err := q.send(channelID, embed)
+	if err == nil {
+		return
+	}
+
+	log.Printf("Error posting to channel %s, will retry: %v", channelID, err)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, &pendingChannelPost{
+		channelID:   channelID,
+		embed:       embed,
+		attempts:    1,
+		nextAttempt: q.now().Add(q.backoff),
+		lastErr:     err,
+	})
+}
+
+// Retry resends every queued post whose backoff has elapsed. Each failure
+// doubles the wait before the next attempt.
+func (q *ChannelPostQueue) Retry() {
+	q.mu.Lock()
+	now := q.now()
+	var due, waiting []*pendingChannelPost
+	for _, post := range q.pending {
+		if now.Before(post.nextAttempt) {
+			waiting = append(waiting, post)
+		} else {
+			due = append(due, post)
+		}
+	}
+	q.pending = waiting
+	q.mu.Unlock()
+
+	// Send without holding the lock so new posts aren't blocked on Discord
+	var retry []*pendingChannelPost
+	for _, post := range due {
+		err := q.send(post.channelID, post.embed)
+		if err == nil {
+			continue
+		}
+
+		post.attempts++
+		post.lastErr = err
+		if post.attempts >= q.maxAttempts {
+			q.onGiveUp(post.channelID, post.embed, err)
+			continue
+		}
+
+		post.nextAttempt = now.Add(q.backoff << (post.attempts - 1))
+		retry = append(retry, post)
+	}
+
+	q.mu.Lock()
+	q.pending = append(q.pending, retry...)
+	q.mu.Unlock()
+}
+
+// Pending returns the number of posts waiting for a retry
+func (q *ChannelPostQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// retryLoop periodically retries queued posts until ctx is done
+func (q *ChannelPostQueue) retryLoop(ctx context.Context) {
+	ticker := time.NewTicker(channelPostRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.Retry()
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// flakySender fails the first failures sends and records every attempt
+type flakySender struct {
+	failures  int
+	attempts  int
+	delivered []string
+}
+
+func (f *flakySender) send(channelID string, embed *discordgo.MessageEmbed) error {
+	f.attempts++
+	if f.attempts <= f.failures {
+		return errors.New("HTTP 403 Forbidden, Missing Permissions")
+	}
+	f.delivered = append(f.delivered, channelID+":"+embed.Title)
+	return nil
+}
+
+func newTestPostQueue(sender *flakySender) (*ChannelPostQueue, *time.Time) {
+	now := time.Now()
+	q := NewChannelPostQueue(sender.send)
+	q.now = func() time.Time { return now }
+	return q, &now
+}
+
+func TestChannelPostQueueRetriesFailedSend(t *testing.T) {
+	sender := &flakySender{failures: 2}
+	q, now := newTestPostQueue(sender)
+
+	q.Post("chan1", &discordgo.MessageEmbed{Title: "Trade Ban Issued"})
+	if q.Pending() != 1 {
+		t.Fatalf("Expected failed post to be queued, got %d pending", q.Pending())
+	}
+
+	// Not due yet
+	q.Retry()
+	if sender.attempts != 1 {
+		t.Errorf("Expected no retry before backoff, got %d attempts", sender.attempts)
+	}
+
+	// Second attempt fails and doubles the backoff
+	*now = now.Add(channelPostRetryInterval)
+	q.Retry()
+	if sender.attempts != 2 || q.Pending() != 1 {
+		t.Fatalf("Expected second failed attempt to stay queued, got %d attempts, %d pending", sender.attempts, q.Pending())
+	}
+
+	*now = now.Add(channelPostRetryInterval)
+	q.Retry()
+	if sender.attempts != 2 {
+		t.Errorf("Expected backoff to double after second failure, got %d attempts", sender.attempts)
+	}
+
+	*now = now.Add(channelPostRetryInterval)
+	q.Retry()
+	if len(sender.delivered) != 1 || sender.delivered[0] != "chan1:Trade Ban Issued" {
+		t.Errorf("Expected post delivered once, got %v", sender.delivered)
+	}
+	if q.Pending() != 0 {
+		t.Errorf("Expected empty queue after delivery, got %d", q.Pending())
+	}
+}
+
+func TestChannelPostQueueGivesUp(t *testing.T) {
+	sender := &flakySender{failures: 100}
+	q, now := newTestPostQueue(sender)
+
+	var gaveUp []string
+	q.onGiveUp = func(channelID string, embed *discordgo.MessageEmbed, err error) {
+		gaveUp = append(gaveUp, channelID)
+	}
+
+	q.Post("chan1", &discordgo.MessageEmbed{Title: "New Trade Report #1"})
+	for n := 0; n < 20; n++ {
+		*now = now.Add(time.Hour)
+		q.Retry()
+	}
+
+	if sender.attempts != channelPostMaxAttempts {
+		t.Errorf("Expected %d attempts, got %d", channelPostMaxAttempts, sender.attempts)
+	}
+	if len(gaveUp) != 1 || gaveUp[0] != "chan1" {
+		t.Errorf("Expected one give-up alert for chan1, got %v", gaveUp)
+	}
+	if q.Pending() != 0 {
+		t.Errorf("Expected dropped post to leave the queue, got %d", q.Pending())
+	}
+}
+
+func TestChannelPostQueueSuccessIsNotQueued(t *testing.T) {
+	sender := &flakySender{}
+	q, _ := newTestPostQueue(sender)
+
+	q.Post("chan1", &discordgo.MessageEmbed{Title: "Report #1 — Dismissed"})
+	if q.Pending() != 0 || len(sender.delivered) != 1 {
+		t.Errorf("Expected immediate delivery, got %d pending and %v delivered", q.Pending(), sender.delivered)
+	}
+}
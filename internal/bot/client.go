@@ -5,13 +5,17 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"wosbTrade/internal/api"
 	"wosbTrade/internal/database"
+	"wosbTrade/internal/metrics"
 	"wosbTrade/internal/ocr"
 
 	"github.com/bwmarrin/discordgo"
@@ -22,9 +26,31 @@ type Bot struct {
 	db                 *database.DB
 	claudeClient       *ocr.ClaudeClient
 	imagePath          string
+	maxImageBytes      int // largest /submit attachment downloaded
 	adminRoleID        string
+	devGuildID         string
 	submissionManager  *SubmissionManager
 	tradeConversations *TradeConversationManager
+	tradeDrafts        *TradeDraftManager
+	overview           *overviewCache
+	channelPosts       *ChannelPostQueue
+	relayQueue         *RelayQueue
+	bans               *BanCache
+	words              *WordFilter
+	api                *api.Server     // nil unless APIAddr is configured
+	metrics            *metrics.Server // nil unless MetricsAddr is configured
+
+	// failedCommands holds IDs of slash command interactions answered with an
+	// error while their handler runs, for command outcome metrics
+	failedCommands sync.Map
+
+	// locales holds each user's locale from their latest interaction, so DMs
+	// sent outside an interaction can be translated
+	locales sync.Map
+
+	// Background loops run until stopBackground cancels them
+	cancelBackground context.CancelFunc
+	background       sync.WaitGroup
 }
 
 type Config struct {
@@ -33,6 +59,19 @@ type Config struct {
 	ImagePath      string
 	ClaudeCodePath string
 	AdminRoleID    string
+	DevGuildID     string // registers commands to this guild only, for development
+	APIAddr        string // enables the read-only HTTP API when set, e.g. ":8080"
+	APIKey         string
+	MetricsAddr    string // enables the Prometheus /metrics listener when set, e.g. ":9090"
+
+	// SubmissionTimeout is how long /submit confirmations stay open unless a
+	// guild sets its own; 0 uses defaultSubmissionTimeout
+	SubmissionTimeout time.Duration
+	// MaxImageBytes caps /submit attachments; 0 uses defaultMaxImageBytes
+	MaxImageBytes int
+	// WordFilterPath names a list of words barred alongside those admins
+	// add with /admin-filter-add; empty uses only the admins' words
+	WordFilterPath string
 }
 
 // New creates a new Discord bot instance
@@ -57,16 +96,65 @@ func New(cfg Config) (*Bot, error) {
 	// Create Claude client
 	claudeClient := ocr.NewClaudeClient(cfg.ClaudeCodePath)
 
+	submissionTimeout := defaultSubmissionTimeout
+	if cfg.SubmissionTimeout > 0 {
+		submissionTimeout = clampSubmissionTimeout(cfg.SubmissionTimeout)
+	}
+	maxImageBytes := defaultMaxImageBytes
+	if cfg.MaxImageBytes > 0 {
+		maxImageBytes = min(cfg.MaxImageBytes, ocr.MaxImageBytes)
+	}
+	submissions := NewSubmissionManager(submissionTimeout, func(sub *PendingSubmission) {
+		notifySubmissionExpired(session, sub)
+	})
+
 	bot := &Bot{
 		session:            session,
 		db:                 db,
 		claudeClient:       claudeClient,
 		imagePath:          cfg.ImagePath,
+		maxImageBytes:      maxImageBytes,
 		adminRoleID:        strings.TrimSpace(cfg.AdminRoleID),
-		submissionManager:  NewSubmissionManager(5 * time.Minute),
+		devGuildID:         strings.TrimSpace(cfg.DevGuildID),
+		submissionManager:  submissions,
 		tradeConversations: NewTradeConversationManager(30 * time.Minute),
+		tradeDrafts:        NewTradeDraftManager(10 * time.Minute),
+		bans:               NewBanCache(db.IsUserBanned),
+		words:              NewWordFilter(),
+		overview: newOverviewCache(overviewCacheTTL, func(ctx context.Context) (*database.MarketOverview, error) {
+			return db.GetMarketOverview(ctx, overviewListLimit)
+		}),
+		channelPosts: NewChannelPostQueue(func(channelID string, embed *discordgo.MessageEmbed) error {
+			_, err := session.ChannelMessageSendEmbed(channelID, embed)
+			return err
+		}),
+	}
+
+	bot.relayQueue = NewRelayQueue(bot.deliverRelay)
+
+	if cfg.WordFilterPath != "" {
+		words, err := readWordList(cfg.WordFilterPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load word filter: %w", err)
+		}
+		bot.words.LoadList(words)
 	}
 
+	// Optional read-only HTTP API
+	if cfg.APIAddr != "" {
+		bot.api, err = api.New(db, cfg.APIAddr, cfg.APIKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure HTTP API: %w", err)
+		}
+	}
+
+	// Optional Prometheus metrics
+	if cfg.MetricsAddr != "" {
+		bot.metrics = metrics.NewServer(cfg.MetricsAddr)
+	}
+	metrics.RegisterGauge("wosb_active_conversations", "Trade conversations currently relayed by the bot.",
+		func() float64 { return float64(bot.tradeConversations.Count()) })
+
 	// Set intents
 	session.Identify.Intents = discordgo.IntentsGuilds |
 		discordgo.IntentsGuildMessages |
@@ -77,6 +165,8 @@ func New(cfg Config) (*Bot, error) {
 	session.AddHandler(bot.ready)
 	session.AddHandler(bot.interactionCreate)
 	session.AddHandler(bot.messageCreate)
+	session.AddHandler(bot.messageUpdate)
+	session.AddHandler(bot.messageDelete)
 
 	return bot, nil
 }
@@ -89,15 +179,40 @@ func (b *Bot) Start() error {
 
 	log.Println("Bot is now running. Press CTRL-C to exit.")
 
+	// Seed the ban cache; on failure lookups just fall back to the database
+	if bans, err := b.db.GetActiveTradeBans(context.Background()); err != nil {
+		log.Printf("Error loading trade bans: %v", err)
+	} else {
+		b.bans.Load(bans)
+	}
+
+	// Admins' filtered words; the list file was loaded in New
+	if words, err := b.db.GetFilteredWords(context.Background()); err != nil {
+		log.Printf("Error loading filtered words: %v", err)
+	} else {
+		for _, word := range words {
+			b.words.Add(word)
+		}
+	}
+
 	// Register slash commands
 	if err := b.registerCommands(); err != nil {
 		return fmt.Errorf("failed to register commands: %w", err)
 	}
 
 	// Start background goroutines
-	go b.expiryChecker()
-	go b.playerOrderExpiryChecker()
-	go b.conversationTimeoutChecker()
+	b.startBackground()
+
+	if b.api != nil {
+		if err := b.api.Start(); err != nil {
+			return fmt.Errorf("failed to start HTTP API: %w", err)
+		}
+	}
+	if b.metrics != nil {
+		if err := b.metrics.Start(); err != nil {
+			return fmt.Errorf("failed to start metrics listener: %w", err)
+		}
+	}
 
 	// Recover active conversations from DB into memory
 	b.recoverActiveConversations()
@@ -114,6 +229,23 @@ func (b *Bot) Start() error {
 func (b *Bot) Close() error {
 	log.Println("Shutting down bot...")
 
+	// Stop background loops before the session and database they use
+	b.stopBackground()
+
+	// Deliver any DMs still waiting in the relay queue
+	b.relayQueue.Flush()
+
+	if b.api != nil {
+		if err := b.api.Close(); err != nil {
+			log.Printf("Error shutting down HTTP API: %v", err)
+		}
+	}
+	if b.metrics != nil {
+		if err := b.metrics.Close(); err != nil {
+			log.Printf("Error shutting down metrics listener: %v", err)
+		}
+	}
+
 	if err := b.session.Close(); err != nil {
 		log.Printf("Error closing Discord session: %v", err)
 	}
@@ -133,14 +265,54 @@ func (b *Bot) ready(s *discordgo.Session, event *discordgo.Ready) {
 	s.UpdateGameStatus(0, "World of Sea Battle Markets")
 }
 
+// startBackground launches the periodic jobs and manager cleanup loops
+func (b *Bot) startBackground() {
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancelBackground = cancel
+
+	loops := []func(context.Context){
+		b.expiryChecker,
+		b.playerOrderExpiryChecker,
+		b.conversationTimeoutChecker,
+		b.channelPosts.retryLoop,
+		b.submissionManager.cleanupLoop,
+		b.tradeConversations.cleanupLoop,
+		b.tradeDrafts.cleanupLoop,
+	}
+	for _, loop := range loops {
+		b.background.Add(1)
+		go func(loop func(context.Context)) {
+			defer b.background.Done()
+			loop(ctx)
+		}(loop)
+	}
+}
+
+// stopBackground cancels the background loops and waits for them to return
+func (b *Bot) stopBackground() {
+	if b.cancelBackground == nil {
+		return
+	}
+	b.cancelBackground()
+	b.background.Wait()
+}
+
 // expiryChecker runs periodically to remove expired orders
-func (b *Bot) expiryChecker() {
+func (b *Bot) expiryChecker(ctx context.Context) {
 	ticker := time.NewTicker(1 * time.Hour)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		ctx := context.Background()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		start := time.Now()
 		count, err := b.db.DeleteExpiredOrders(ctx)
+		metrics.ObserveDBQuery("delete_expired_orders", start)
+		metrics.JobRunsTotal.WithLabelValues("market_expiry", metrics.Outcome(err)).Inc()
 		if err != nil {
 			log.Printf("Error deleting expired orders: %v", err)
 			continue
@@ -153,6 +325,11 @@ func (b *Bot) expiryChecker() {
 
 // isAdmin checks if a user has the admin role (checks both global and guild-specific)
 func (b *Bot) isAdmin(guildID string, member *discordgo.Member) bool {
+	// Interactions outside a guild (DMs) carry no member or roles
+	if member == nil {
+		return false
+	}
+
 	ctx := context.Background()
 
 	// First check guild-specific admin role
@@ -185,32 +362,265 @@ func (b *Bot) isAdmin(guildID string, member *discordgo.Member) bool {
 	return false
 }
 
-// playerOrderExpiryChecker periodically expires player orders
-func (b *Bot) playerOrderExpiryChecker() {
-	ticker := time.NewTicker(1 * time.Hour)
+// playerOrderExpiryInterval is how often expired player orders are reconciled.
+// Reads already hide expired orders; this keeps the stored status in step.
+const playerOrderExpiryInterval = 15 * time.Minute
+
+// orderReminderWindow is how long before an order expires its owner is
+// reminded they can relist it
+const orderReminderWindow = 6 * time.Hour
+
+// playerOrderExpiryChecker expires player orders and lapsed trade bans,
+// announces scheduled orders that have started, and reminds owners of orders
+// about to expire, at startup and then periodically
+func (b *Bot) playerOrderExpiryChecker(ctx context.Context) {
+	b.expirePlayerOrders(ctx)
+	b.activateScheduledOrders(ctx)
+	b.remindExpiringOrders(ctx)
+	b.expireTradeBans(ctx)
+
+	ticker := time.NewTicker(playerOrderExpiryInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		ctx := context.Background()
-		count, err := b.db.DeleteExpiredPlayerOrders(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.expirePlayerOrders(ctx)
+			b.activateScheduledOrders(ctx)
+			b.remindExpiringOrders(ctx)
+			b.expireTradeBans(ctx)
+		}
+	}
+}
+
+// expirePlayerOrders runs a single player order expiry pass
+func (b *Bot) expirePlayerOrders(ctx context.Context) {
+	start := time.Now()
+	orders, err := b.db.DeleteExpiredPlayerOrders(ctx)
+	metrics.ObserveDBQuery("delete_expired_player_orders", start)
+	metrics.JobRunsTotal.WithLabelValues("player_order_expiry", metrics.Outcome(err)).Inc()
+	if err != nil {
+		log.Printf("Error expiring player orders: %v", err)
+		return
+	}
+	if len(orders) > 0 {
+		log.Printf("Expired %d player orders", len(orders))
+		b.notifyExpiredOrders(b.session, orders)
+	}
+}
+
+// activateScheduledOrders marks scheduled orders whose start has passed as
+// live and tells each owner. Searches show them from their start time either
+// way; this pass only keeps storage in step and sends the notice.
+func (b *Bot) activateScheduledOrders(ctx context.Context) {
+	start := time.Now()
+	orders, err := b.db.ActivateScheduledPlayerOrders(ctx)
+	metrics.ObserveDBQuery("activate_scheduled_player_orders", start)
+	metrics.JobRunsTotal.WithLabelValues("player_order_activation", metrics.Outcome(err)).Inc()
+	if err != nil {
+		log.Printf("Error activating scheduled orders: %v", err)
+		return
+	}
+	if len(orders) == 0 {
+		return
+	}
+
+	userIDs, byUser := groupOrdersByOwner(orders)
+	for _, userID := range userIDs {
+		if err := relayToUser(b.session, userID, []string{activatedOrdersMessage(byUser[userID])}); err != nil {
+			log.Printf("Error telling %s their scheduled orders started: %v", userID, err)
+		}
+	}
+	log.Printf("Activated %d scheduled player orders", len(orders))
+}
+
+// remindExpiringOrders DMs owners whose orders expire within
+// orderReminderWindow. Each order is only reminded about once, even if the
+// DM can't be delivered, so a closed inbox isn't retried every pass.
+func (b *Bot) remindExpiringOrders(ctx context.Context) {
+	start := time.Now()
+	orders, err := b.db.GetOrdersExpiringSoon(ctx, orderReminderWindow)
+	metrics.ObserveDBQuery("get_orders_expiring_soon", start)
+	metrics.JobRunsTotal.WithLabelValues("player_order_reminder", metrics.Outcome(err)).Inc()
+	if err != nil {
+		log.Printf("Error finding orders expiring soon: %v", err)
+		return
+	}
+	if len(orders) == 0 {
+		return
+	}
+
+	ids := make([]int, len(orders))
+	for idx, o := range orders {
+		ids[idx] = o.ID
+	}
+	if err := b.db.MarkOrdersReminded(ctx, ids); err != nil {
+		log.Printf("Error marking orders reminded: %v", err)
+		return
+	}
+
+	userIDs, byUser := groupOrdersByOwner(orders)
+	for _, userID := range userIDs {
+		msg := expiringOrdersMessage(byUser[userID], time.Now())
+		if err := relayToUser(b.session, userID, []string{msg}); err != nil {
+			log.Printf("Error reminding %s of expiring orders: %v", userID, err)
+		}
+	}
+	log.Printf("Reminded owners of %d orders expiring soon", len(orders))
+}
+
+// expireTradeBans deactivates bans past their expiry and tells each user
+// they can trade again
+func (b *Bot) expireTradeBans(ctx context.Context) {
+	start := time.Now()
+	bans, err := b.db.ExpireBans(ctx)
+	metrics.ObserveDBQuery("expire_trade_bans", start)
+	metrics.JobRunsTotal.WithLabelValues("trade_ban_expiry", metrics.Outcome(err)).Inc()
+	if err != nil {
+		log.Printf("Error expiring trade bans: %v", err)
+		return
+	}
+
+	for _, ban := range bans {
+		b.bans.Remove(ban.UserID)
+		err := relayToUser(b.session, ban.UserID, []string{t(b.userLocale(ban.UserID), "trade.ban_expired")})
 		if err != nil {
-			log.Printf("Error expiring player orders: %v", err)
+			log.Printf("Error notifying %s of expired ban: %v", ban.UserID, err)
+		}
+	}
+	if len(bans) > 0 {
+		log.Printf("Expired %d trade bans", len(bans))
+	}
+}
+
+// groupOrdersByOwner splits orders by owner so each gets one DM, keeping
+// owners in the order they first appear
+func groupOrdersByOwner(orders []database.PlayerOrder) ([]string, map[string][]database.PlayerOrder) {
+	byUser := make(map[string][]database.PlayerOrder)
+	var userIDs []string
+	for _, o := range orders {
+		if _, ok := byUser[o.UserID]; !ok {
+			userIDs = append(userIDs, o.UserID)
+		}
+		byUser[o.UserID] = append(byUser[o.UserID], o)
+	}
+	return userIDs, byUser
+}
+
+// expiryNoticeMaxOrders caps the orders listed in one expiry DM
+const expiryNoticeMaxOrders = 20
+
+// notifyExpiredOrders DMs each owner once about all of their orders that expired
+func (b *Bot) notifyExpiredOrders(s *discordgo.Session, orders []database.PlayerOrder) {
+	userIDs, byUser := groupOrdersByOwner(orders)
+
+	for _, userID := range userIDs {
+		ch, err := s.UserChannelCreate(userID)
+		if err != nil {
+			log.Printf("Error opening DM for expiry notice: %v", err)
 			continue
 		}
-		if count > 0 {
-			log.Printf("Expired %d player orders", count)
+		if _, err := s.ChannelMessageSend(ch.ID, expiredOrdersMessage(byUser[userID])); err != nil {
+			log.Printf("Error sending expiry notice: %v", err)
+		}
+	}
+}
+
+// describeNoticeOrder names an order in an expiry DM, e.g. "#7 (SELL Cannon)"
+func describeNoticeOrder(o database.PlayerOrder) string {
+	item := fmt.Sprintf("item %d", o.ItemID)
+	if o.Item != nil {
+		item = o.Item.DisplayName
+	}
+	return fmt.Sprintf("#%d (%s %s)", o.ID, strings.ToUpper(o.OrderType), item)
+}
+
+// expiredOrdersMessage tells an owner which of their orders expired
+func expiredOrdersMessage(orders []database.PlayerOrder) string {
+	if len(orders) == 1 {
+		return fmt.Sprintf("⌛ Your order %s has expired. Use `/trade-create` to post it again.", describeNoticeOrder(orders[0]))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⌛ %d of your orders have expired:\n", len(orders)))
+	for idx, o := range orders {
+		if idx == expiryNoticeMaxOrders {
+			sb.WriteString(fmt.Sprintf("…and %d more\n", len(orders)-idx))
+			break
 		}
+		sb.WriteString("- " + describeNoticeOrder(o) + "\n")
 	}
+	sb.WriteString("Use `/trade-create` to post them again, or `/trade-my-history` to review them.")
+	return sb.String()
+}
+
+// activatedOrdersMessage tells an owner their scheduled orders are now live
+func activatedOrdersMessage(orders []database.PlayerOrder) string {
+	if len(orders) == 1 {
+		return fmt.Sprintf("✅ Your scheduled order %s is now live and shows up in `/trade-search`.", describeNoticeOrder(orders[0]))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("✅ %d of your scheduled orders are now live:\n", len(orders)))
+	for idx, o := range orders {
+		if idx == expiryNoticeMaxOrders {
+			sb.WriteString(fmt.Sprintf("…and %d more\n", len(orders)-idx))
+			break
+		}
+		sb.WriteString("- " + describeNoticeOrder(o) + "\n")
+	}
+	sb.WriteString("They show up in `/trade-search` now.")
+	return sb.String()
+}
+
+// expiringOrdersMessage warns an owner that their orders are about to expire.
+// Time left is rounded up to whole hours.
+func expiringOrdersMessage(orders []database.PlayerOrder, now time.Time) string {
+	hoursLeft := func(o database.PlayerOrder) int {
+		hours := int(math.Ceil(o.ExpiresAt.Sub(now).Hours()))
+		if hours < 1 {
+			hours = 1
+		}
+		return hours
+	}
+
+	if len(orders) == 1 {
+		o := orders[0]
+		return fmt.Sprintf("⏰ Your order %s expires in %dh. Use `/trade-relist order-id:%d` to extend it.",
+			describeNoticeOrder(o), hoursLeft(o), o.ID)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⏰ %d of your orders expire soon:\n", len(orders)))
+	for idx, o := range orders {
+		if idx == expiryNoticeMaxOrders {
+			sb.WriteString(fmt.Sprintf("…and %d more\n", len(orders)-idx))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("- %s in %dh\n", describeNoticeOrder(o), hoursLeft(o)))
+	}
+	sb.WriteString("Use `/trade-relist` to extend any you want to keep.")
+	return sb.String()
 }
 
 // conversationTimeoutChecker closes stale trade conversations and notifies both parties
-func (b *Bot) conversationTimeoutChecker() {
+func (b *Bot) conversationTimeoutChecker(ctx context.Context) {
 	ticker := time.NewTicker(5 * time.Minute)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		ctx := context.Background()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		start := time.Now()
 		stale, err := b.db.GetStaleConversations(ctx, 30*time.Minute)
+		metrics.ObserveDBQuery("get_stale_conversations", start)
+		metrics.JobRunsTotal.WithLabelValues("conversation_timeout", metrics.Outcome(err)).Inc()
 		if err != nil {
 			log.Printf("Error getting stale conversations: %v", err)
 			continue
@@ -232,12 +642,10 @@ func (b *Bot) conversationTimeoutChecker() {
 			b.tradeConversations.Remove(ac)
 
 			// Notify both parties
-			msg := "Your trade conversation has been closed due to inactivity. Use `/trade-search` to find more trades."
-			if ch, err := b.session.UserChannelCreate(conv.InitiatorUserID); err == nil {
-				b.session.ChannelMessageSend(ch.ID, msg)
-			}
-			if ch, err := b.session.UserChannelCreate(conv.CreatorUserID); err == nil {
-				b.session.ChannelMessageSend(ch.ID, msg)
+			for _, userID := range []string{conv.InitiatorUserID, conv.CreatorUserID} {
+				if ch, err := b.session.UserChannelCreate(userID); err == nil {
+					b.session.ChannelMessageSend(ch.ID, t(b.userLocale(userID), "relay.closed_inactive"))
+				}
 			}
 
 			log.Printf("Closed stale conversation %d between %s and %s",
@@ -260,9 +668,9 @@ func (b *Bot) recoverActiveConversations() {
 			ConversationID:      conv.ID,
 			OrderID:             conv.OrderID,
 			InitiatorUserID:     conv.InitiatorUserID,
-			InitiatorIngameName: conv.InitiatorIngameName,
+			InitiatorIngameName: safeName(conv.InitiatorIngameName),
 			CreatorUserID:       conv.CreatorUserID,
-			CreatorIngameName:   conv.CreatorIngameName,
+			CreatorIngameName:   safeName(conv.CreatorIngameName),
 		}
 		b.tradeConversations.Register(ac)
 	}
@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestBackgroundLoopsStopCleanly(t *testing.T) {
+	b, _ := setupTradeDraftBot(t)
+	b.submissionManager = NewSubmissionManager(time.Minute, nil)
+	b.tradeConversations = NewTradeConversationManager(time.Minute)
+	b.channelPosts = NewChannelPostQueue(func(string, *discordgo.MessageEmbed) error { return nil })
+
+	b.startBackground()
+
+	stopped := make(chan struct{})
+	go func() {
+		b.stopBackground()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background loops did not stop")
+	}
+}
+
+func TestStopBackgroundWithoutStart(t *testing.T) {
+	// Close runs this even when Start failed before launching anything
+	b := &Bot{}
+	b.stopBackground()
+}
+
+func TestCommandGuildID(t *testing.T) {
+	if got := (&Bot{}).commandGuildID(); got != "" {
+		t.Errorf("expected global registration without a dev guild, got %q", got)
+	}
+	if got := (&Bot{devGuildID: "123"}).commandGuildID(); got != "123" {
+		t.Errorf("expected dev guild registration, got %q", got)
+	}
+	if err := (&Bot{}).cleanupCommands(true); err == nil {
+		t.Error("expected dev guild cleanup to fail without a dev guild")
+	}
+}
+
+func TestTradeConversationCount(t *testing.T) {
+	tcm := NewTradeConversationManager(time.Minute)
+	tcm.Register(&ActiveConversation{ConversationID: 1, InitiatorUserID: "a", CreatorUserID: "b"})
+	tcm.Register(&ActiveConversation{ConversationID: 2, InitiatorUserID: "c", CreatorUserID: "d"})
+
+	// Both parties have an entry, but each conversation counts once
+	if got := tcm.Count(); got != 2 {
+		t.Errorf("Expected 2 conversations, got %d", got)
+	}
+}
+
+func TestTradeConversationDeliveryFailures(t *testing.T) {
+	tcm := NewTradeConversationManager(time.Minute)
+	tcm.Register(&ActiveConversation{ConversationID: 1, InitiatorUserID: "a", CreatorUserID: "b"})
+
+	for want := 1; want <= 2; want++ {
+		if got := tcm.RecordDeliveryResult("a", false); got != want {
+			t.Errorf("Expected %d failures, got %d", want, got)
+		}
+	}
+	// Failures are per conversation, so either party's delivery resets them
+	if got := tcm.RecordDeliveryResult("b", true); got != 0 {
+		t.Errorf("Expected a delivery to reset failures, got %d", got)
+	}
+	if got := tcm.RecordDeliveryResult("z", false); got != 0 {
+		t.Errorf("Expected 0 for a user without a conversation, got %d", got)
+	}
+}
+
+func TestExpiredOrdersMessage(t *testing.T) {
+	order := func(id int) database.PlayerOrder {
+		return database.PlayerOrder{ID: id, OrderType: "sell", Item: &database.Item{DisplayName: "Cannon"}}
+	}
+
+	single := expiredOrdersMessage([]database.PlayerOrder{order(7)})
+	if !strings.Contains(single, "#7 (SELL Cannon) has expired") {
+		t.Errorf("unexpected single-order notice: %q", single)
+	}
+
+	var many []database.PlayerOrder
+	for id := 1; id <= expiryNoticeMaxOrders+3; id++ {
+		many = append(many, order(id))
+	}
+	batch := expiredOrdersMessage(many)
+	if !strings.Contains(batch, fmt.Sprintf("%d of your orders", len(many))) || !strings.Contains(batch, "…and 3 more") {
+		t.Errorf("unexpected batched notice: %q", batch)
+	}
+	if strings.Contains(batch, fmt.Sprintf("#%d ", expiryNoticeMaxOrders+1)) {
+		t.Errorf("expected orders past the cap to be summarized, got %q", batch)
+	}
+}
+
+func TestExpiringOrdersMessage(t *testing.T) {
+	now := time.Now()
+	order := func(id int, left time.Duration) database.PlayerOrder {
+		return database.PlayerOrder{ID: id, OrderType: "buy", Item: &database.Item{DisplayName: "Cannon"}, ExpiresAt: now.Add(left)}
+	}
+
+	single := expiringOrdersMessage([]database.PlayerOrder{order(7, 4*time.Hour+time.Minute)}, now)
+	want := "⏰ Your order #7 (BUY Cannon) expires in 5h. Use `/trade-relist order-id:7` to extend it."
+	if single != want {
+		t.Errorf("unexpected single-order reminder: %q", single)
+	}
+
+	// Orders in their last hour still show 1h rather than 0h
+	batch := expiringOrdersMessage([]database.PlayerOrder{order(1, 2*time.Hour), order(2, 10*time.Second)}, now)
+	if !strings.Contains(batch, "2 of your orders expire soon") ||
+		!strings.Contains(batch, "- #1 (BUY Cannon) in 2h") || !strings.Contains(batch, "- #2 (BUY Cannon) in 1h") {
+		t.Errorf("unexpected batched reminder: %q", batch)
+	}
+}
+
+func TestActivatedOrdersMessage(t *testing.T) {
+	order := func(id int) database.PlayerOrder {
+		return database.PlayerOrder{ID: id, OrderType: "sell", Item: &database.Item{DisplayName: "Cannon"}}
+	}
+
+	single := activatedOrdersMessage([]database.PlayerOrder{order(7)})
+	if !strings.Contains(single, "scheduled order #7 (SELL Cannon) is now live") {
+		t.Errorf("unexpected single-order notice: %q", single)
+	}
+
+	batch := activatedOrdersMessage([]database.PlayerOrder{order(1), order(2)})
+	if !strings.Contains(batch, "2 of your scheduled orders are now live") || !strings.Contains(batch, "- #2 (SELL Cannon)") {
+		t.Errorf("unexpected batched notice: %q", batch)
+	}
+}
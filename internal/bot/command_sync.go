@@ -0,0 +1,130 @@
+package bot
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// commandDiff lists command names that differ between Discord and commands
+type commandDiff struct {
+	Created []string
+	Updated []string
+	Deleted []string
+}
+
+// Empty reports whether Discord already has the desired commands
+func (d commandDiff) Empty() bool {
+	return len(d.Created) == 0 && len(d.Updated) == 0 && len(d.Deleted) == 0
+}
+
+// diffCommands compares the registered commands with the desired set by name
+func diffCommands(registered, desired []*discordgo.ApplicationCommand) commandDiff {
+	byName := make(map[string]*discordgo.ApplicationCommand, len(registered))
+	for _, cmd := range registered {
+		byName[cmd.Name] = cmd
+	}
+
+	var diff commandDiff
+	for _, cmd := range desired {
+		existing, ok := byName[cmd.Name]
+		switch {
+		case !ok:
+			diff.Created = append(diff.Created, cmd.Name)
+		case !commandsEqual(existing, cmd):
+			diff.Updated = append(diff.Updated, cmd.Name)
+		}
+		delete(byName, cmd.Name)
+	}
+	for name := range byName {
+		diff.Deleted = append(diff.Deleted, name)
+	}
+	sort.Strings(diff.Deleted)
+	return diff
+}
+
+// commandsEqual reports whether two command definitions would behave the same.
+// Discord fills in defaults our definitions leave unset (type, DM permission),
+// so those are normalized before comparing; IDs and versions are ignored.
+func commandsEqual(a, b *discordgo.ApplicationCommand) bool {
+	return a.Name == b.Name &&
+		a.Description == b.Description &&
+		commandType(a) == commandType(b) &&
+		int64PtrEqual(a.DefaultMemberPermissions, b.DefaultMemberPermissions) &&
+		boolPtrOr(a.DMPermission, true) == boolPtrOr(b.DMPermission, true) &&
+		boolPtrOr(a.NSFW, false) == boolPtrOr(b.NSFW, false) &&
+		optionsEqual(a.Options, b.Options)
+}
+
+func optionsEqual(a, b []*discordgo.ApplicationCommandOption) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		x, y := a[idx], b[idx]
+		if x.Type != y.Type || x.Name != y.Name || x.Description != y.Description ||
+			x.Required != y.Required || x.Autocomplete != y.Autocomplete ||
+			x.MaxValue != y.MaxValue || x.MaxLength != y.MaxLength ||
+			!float64PtrEqual(x.MinValue, y.MinValue) || !intPtrEqual(x.MinLength, y.MinLength) ||
+			!channelTypesEqual(x.ChannelTypes, y.ChannelTypes) ||
+			!choicesEqual(x.Choices, y.Choices) ||
+			!optionsEqual(x.Options, y.Options) {
+			return false
+		}
+	}
+	return true
+}
+
+// choicesEqual compares values by their printed form, since Discord returns
+// numbers as float64 where our definitions may use ints
+func choicesEqual(a, b []*discordgo.ApplicationCommandOptionChoice) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx].Name != b[idx].Name || fmt.Sprint(a[idx].Value) != fmt.Sprint(b[idx].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func channelTypesEqual(a, b []discordgo.ChannelType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+// commandType treats an unset type as a slash command, as Discord does
+func commandType(cmd *discordgo.ApplicationCommand) discordgo.ApplicationCommandType {
+	if cmd.Type == 0 {
+		return discordgo.ChatApplicationCommand
+	}
+	return cmd.Type
+}
+
+func boolPtrOr(v *bool, fallback bool) bool {
+	if v == nil {
+		return fallback
+	}
+	return *v
+}
+
+func int64PtrEqual(a, b *int64) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func intPtrEqual(a, b *int) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func float64PtrEqual(a, b *float64) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
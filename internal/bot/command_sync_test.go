@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// registeredCopy returns commands as Discord would hand them back: decoded
+// from JSON with IDs, versions and defaults filled in
+func registeredCopy(t *testing.T, cmds []*discordgo.ApplicationCommand) []*discordgo.ApplicationCommand {
+	t.Helper()
+	data, err := json.Marshal(cmds)
+	if err != nil {
+		t.Fatalf("failed to marshal commands: %v", err)
+	}
+	var registered []*discordgo.ApplicationCommand
+	if err := json.Unmarshal(data, &registered); err != nil {
+		t.Fatalf("failed to unmarshal commands: %v", err)
+	}
+	dmAllowed := true
+	for idx, cmd := range registered {
+		cmd.ID = string(rune('a' + idx))
+		cmd.Version = "1"
+		cmd.Type = discordgo.ChatApplicationCommand
+		cmd.DMPermission = &dmAllowed
+	}
+	return registered
+}
+
+func TestDiffCommandsUnchanged(t *testing.T) {
+	diff := diffCommands(registeredCopy(t, commands), commands)
+	if !diff.Empty() {
+		t.Errorf("expected no changes for identical commands, got %+v", diff)
+	}
+}
+
+func TestDiffCommandsChanges(t *testing.T) {
+	desired := []*discordgo.ApplicationCommand{
+		{Name: "price", Description: "Check prices", Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "item", Description: "Item", Required: true},
+		}},
+		{Name: "ports", Description: "List ports"},
+		{Name: "items", Description: "Browse items"},
+	}
+	registered := registeredCopy(t, desired[:2])
+	registered[0].Options[0].Required = false
+	registered = append(registered, &discordgo.ApplicationCommand{Name: "old-command", Description: "Gone"})
+
+	diff := diffCommands(registered, desired)
+	if len(diff.Created) != 1 || diff.Created[0] != "items" {
+		t.Errorf("expected items created, got %v", diff.Created)
+	}
+	if len(diff.Updated) != 1 || diff.Updated[0] != "price" {
+		t.Errorf("expected price updated, got %v", diff.Updated)
+	}
+	if len(diff.Deleted) != 1 || diff.Deleted[0] != "old-command" {
+		t.Errorf("expected old-command deleted, got %v", diff.Deleted)
+	}
+}
+
+func TestCommandsEqualComparesChoicesAndPermissions(t *testing.T) {
+	perm := int64(discordgo.PermissionManageServer)
+	base := func() *discordgo.ApplicationCommand {
+		return &discordgo.ApplicationCommand{
+			Name:        "items",
+			Description: "Browse items",
+			Options: []*discordgo.ApplicationCommandOption{{
+				Type: discordgo.ApplicationCommandOptionInteger, Name: "page", Description: "Page",
+				Choices: []*discordgo.ApplicationCommandOptionChoice{{Name: "First", Value: 1}},
+			}},
+		}
+	}
+
+	// Discord returns numeric choice values as float64
+	returned := base()
+	returned.Options[0].Choices[0].Value = float64(1)
+	if !commandsEqual(base(), returned) {
+		t.Error("expected numeric choices to compare equal across types")
+	}
+
+	changed := base()
+	changed.Options[0].Choices[0].Value = 2
+	if commandsEqual(base(), changed) {
+		t.Error("expected different choice values to differ")
+	}
+
+	admin := base()
+	admin.DefaultMemberPermissions = &perm
+	if commandsEqual(base(), admin) {
+		t.Error("expected permission changes to differ")
+	}
+}
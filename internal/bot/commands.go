@@ -1,6 +1,7 @@
 package bot
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/bwmarrin/discordgo"
@@ -13,6 +14,10 @@ var (
 
 var commands = []*discordgo.ApplicationCommand{
 	// User Commands
+	{
+		Name:        "submit-status",
+		Description: "Show or cancel your pending screenshot submission",
+	},
 	{
 		Name:        "submit",
 		Description: "Submit a market screenshot (attach image)",
@@ -39,8 +44,40 @@ var commands = []*discordgo.ApplicationCommand{
 				Description: "Market screenshot image",
 				Required:    true,
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionAttachment,
+				Name:        "screenshot-2",
+				Description: "Additional screenshot of the same board (if it scrolls)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionAttachment,
+				Name:        "screenshot-3",
+				Description: "Additional screenshot of the same board (if it scrolls)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionAttachment,
+				Name:        "screenshot-4",
+				Description: "Additional screenshot of the same board (if it scrolls)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "preview",
+				Description: "Review the parsed prices before they are saved (default: off)",
+				Required:    false,
+			},
 		},
 	},
+	{
+		Name:        "overview",
+		Description: "Market-wide summary: busiest ports, price spreads and most requested items",
+	},
+	{
+		Name:        "market-overview",
+		Description: "One-page dashboard: busiest ports, most traded items and biggest buy/sell spreads",
+	},
 	{
 		Name:        "price",
 		Description: "Query prices for an item across all ports",
@@ -69,6 +106,24 @@ var commands = []*discordgo.ApplicationCommand{
 				Description: "Maximum price filter (optional)",
 				Required:    false,
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "max-age",
+				Description: "Only show prices submitted in the last N hours (default 48, 0 for all)",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "best-route",
+		Description: "Find the cheapest port to buy an item and the best port to sell it",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "item",
+				Description: "Item name to search for",
+				Required:    true,
+			},
 		},
 	},
 	{
@@ -81,6 +136,68 @@ var commands = []*discordgo.ApplicationCommand{
 				Description: "Port name",
 				Required:    true,
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "order-type",
+				Description: "Show only buy or sell orders, in full",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Buy", Value: "buy"},
+					{Name: "Sell", Value: "sell"},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "item",
+				Description: "Only items whose name contains this text",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "port-compare",
+		Description: "Compare the orders at two ports side by side",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "port-a",
+				Description: "First port",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "port-b",
+				Description: "Second port",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "port-export",
+		Description: "Export a port's current board as a text or CSV file",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "port",
+				Description: "Port name",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "format",
+				Description: "File format (default: text)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Text", Value: "text"},
+					{Name: "CSV", Value: "csv"},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "public",
+				Description: "Post the export in the channel instead of only to you",
+				Required:    false,
+			},
 		},
 	},
 	{
@@ -105,11 +222,57 @@ var commands = []*discordgo.ApplicationCommand{
 				Description: "Comma-separated tag names (e.g., weapon,heavy)",
 				Required:    false,
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "mode",
+				Description: "Match items with any of the tags (default) or all of them",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Any tag", Value: "any"},
+					{Name: "All tags", Value: "all"},
+				},
+			},
+		},
+	},
+	{
+		Name:        "item-info",
+		Description: "Show an item's tags, aliases and best current prices",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "item",
+				Description: "Item name",
+				Required:    true,
+			},
 		},
 	},
 	{
 		Name:        "stats",
 		Description: "Show bot statistics",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "region",
+				Description: "Only count ports in this region (optional)",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "leaderboard",
+		Description: "Top market screenshot contributors",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "period",
+				Description: "Time window (default: this week)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "This week", Value: "week"},
+					{Name: "This month", Value: "month"},
+				},
+			},
+		},
 	},
 
 	// Admin Commands - Port Management
@@ -137,6 +300,18 @@ var commands = []*discordgo.ApplicationCommand{
 			},
 		},
 	},
+	{
+		Name:        "admin-port-import",
+		Description: "Add ports in bulk from a CSV file with name,region,notes columns (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionAttachment,
+				Name:        "file",
+				Description: "CSV file with a name,region,notes header",
+				Required:    true,
+			},
+		},
+	},
 	{
 		Name:        "admin-port-edit",
 		Description: "Edit a port (admin only)",
@@ -161,6 +336,18 @@ var commands = []*discordgo.ApplicationCommand{
 			},
 		},
 	},
+	{
+		Name:        "admin-region-add",
+		Description: "Add a known port region (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "name",
+				Description: "Region name, e.g. Caribbean",
+				Required:    true,
+			},
+		},
+	},
 	{
 		Name:        "admin-port-remove",
 		Description: "Remove a port (admin only)",
@@ -223,6 +410,43 @@ var commands = []*discordgo.ApplicationCommand{
 			},
 		},
 	},
+	{
+		Name:        "admin-item-list-all",
+		Description: "Browse every item with its tags (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "tag",
+				Description: "Only items with this tag (optional)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "category",
+				Description: "Only items with a tag in this category (optional)",
+				Required:    false,
+				MaxLength:   50,
+			},
+		},
+	},
+	{
+		Name:        "admin-item-tag-all",
+		Description: "Tag every untagged item whose name contains some text (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "pattern",
+				Description: "Text the item name must contain (case-insensitive)",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "tags",
+				Description: "Comma-separated tag names (e.g., ammunition,heavy)",
+				Required:    true,
+			},
+		},
+	},
 	{
 		Name:        "admin-item-untag",
 		Description: "Remove tags from an item (admin only)",
@@ -295,6 +519,66 @@ var commands = []*discordgo.ApplicationCommand{
 			},
 		},
 	},
+	{
+		Name:        "admin-item-list-duplicates",
+		Description: "List items that are probably duplicates of each other (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "min-score",
+				Description: "Minimum name similarity in percent, 50-100 (default: 85)",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "admin-item-archive",
+		Description: "Retire an item, keeping its market and order history (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "item",
+				Description: "Item name",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "admin-item-restore",
+		Description: "Bring an archived item back into use (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "item",
+				Description: "Item name",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "admin-item-price-bounds",
+		Description: "Flag trade orders priced outside a range (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "item",
+				Description: "Item name",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "min-price",
+				Description: "Lowest expected price (omit both limits to clear)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "max-price",
+				Description: "Highest expected price (omit both limits to clear)",
+				Required:    false,
+			},
+		},
+	},
 
 	// Admin Commands - Tag Management
 	{
@@ -339,6 +623,54 @@ var commands = []*discordgo.ApplicationCommand{
 			},
 		},
 	},
+	{
+		Name:        "admin-tag-edit",
+		Description: "Change a tag's category, color or icon (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "name",
+				Description: "Tag to edit",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "category",
+				Description: "New category (optional)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "color",
+				Description: "New hex color code, e.g. #FF5733 (optional)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "icon",
+				Description: "New emoji or icon (optional)",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "admin-tag-rename",
+		Description: "Rename a tag, keeping the items tagged with it (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "old-name",
+				Description: "Current tag name",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "new-name",
+				Description: "New tag name",
+				Required:    true,
+			},
+		},
+	},
 	{
 		Name:        "admin-tag-delete",
 		Description: "Delete a tag (admin only)",
@@ -367,6 +699,33 @@ var commands = []*discordgo.ApplicationCommand{
 				Description: "Port name to purge",
 				Required:    true,
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "scope",
+				Description: "What to remove (default: market data)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Market data (screenshots)", Value: "market"},
+					{Name: "Player trade orders", Value: "player"},
+					{Name: "Both", Value: "both"},
+				},
+			},
+		},
+	},
+	{
+		Name:        "admin-export",
+		Description: "Download all active market orders as CSV or JSON (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "format",
+				Description: "File format (default: CSV)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "CSV", Value: "csv"},
+					{Name: "JSON", Value: "json"},
+				},
+			},
 		},
 	},
 
@@ -384,6 +743,72 @@ var commands = []*discordgo.ApplicationCommand{
 		},
 		DefaultMemberPermissions: &adminPermission,
 	},
+	{
+		Name:        "config-set-log-channel",
+		Description: "Set the channel for moderation events like bans and reports",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "channel",
+				Description:  "Channel to post moderation events in (omit to disable)",
+				Required:     false,
+				ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+			},
+		},
+		DefaultMemberPermissions: &adminPermission,
+	},
+	{
+		Name:        "config-set-trade-preview",
+		Description: "Require a preview and confirmation before /trade-create posts orders",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enabled",
+				Description: "Show order previews (default: on)",
+				Required:    true,
+			},
+		},
+		DefaultMemberPermissions: &adminPermission,
+	},
+	{
+		Name:        "config-set-submission-timeout",
+		Description: "Set how long /submit confirmations stay open in this server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "minutes",
+				Description: "Minutes to wait for confirmation, 2-30 (omit for the default)",
+				Required:    false,
+			},
+		},
+		DefaultMemberPermissions: &adminPermission,
+	},
+	{
+		Name:        "config-set-report-autoban",
+		Description: "Automatically ban traders reported by this many different users",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "threshold",
+				Description: "Number of reporters, 2-20 (0 turns automatic bans off)",
+				Required:    true,
+			},
+		},
+		DefaultMemberPermissions: &adminPermission,
+	},
+	{
+		Name:        "config-set-max-orders",
+		Description: "Set how many active trade orders each trader may have",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "limit",
+				Description: "Active orders per trader, 1-100 (omit for the default of 25)",
+				Required:    false,
+			},
+		},
+		DefaultMemberPermissions: &adminPermission,
+	},
 	{
 		Name:        "config-show",
 		Description: "Show current server configuration",
@@ -446,6 +871,12 @@ var commands = []*discordgo.ApplicationCommand{
 					{Name: "14 Days", Value: "14d"},
 				},
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "start",
+				Description: "Start later: a delay like 2h or 1d, or a UTC time like 2026-10-20 18:00 (optional)",
+				Required:    false,
+			},
 			{
 				Type:        discordgo.ApplicationCommandOptionString,
 				Name:        "port",
@@ -457,6 +888,7 @@ var commands = []*discordgo.ApplicationCommand{
 				Name:        "notes",
 				Description: "Additional notes (optional)",
 				Required:    false,
+				MaxLength:   maxNotesLength,
 			},
 		},
 	},
@@ -498,12 +930,78 @@ var commands = []*discordgo.ApplicationCommand{
 				Description: "Maximum price filter",
 				Required:    false,
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "sort",
+				Description: "How to order results (default: newest)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Newest", Value: "newest"},
+					{Name: "Cheapest first", Value: "price-asc"},
+					{Name: "Most expensive first", Value: "price-desc"},
+					{Name: "Expiring soonest", Value: "expiring"},
+					{Name: "Reputation", Value: "reputation"},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "trader",
+				Description: "Only orders from traders whose in-game name contains this",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "available-only",
+				Description: "Only show traders who aren't in another trade conversation",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "trade-profile",
+		Description: "Show a trader's in-game name and activity",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user",
+				Description: "Trader to look up (defaults to you)",
+				Required:    false,
+			},
 		},
 	},
 	{
 		Name:        "trade-my-orders",
 		Description: "View your active trade orders",
 	},
+	{
+		Name:        "trade-my-history",
+		Description: "View your completed, cancelled and expired trade orders",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "status",
+				Description: "Only show orders that ended this way",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Completed", Value: "completed"},
+					{Name: "Cancelled", Value: "cancelled"},
+					{Name: "Expired", Value: "expired"},
+				},
+			},
+		},
+	},
+	{
+		Name:        "trade-view",
+		Description: "See the full details of one trade order",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "order-id",
+				Description: "The order ID to view",
+				Required:    true,
+			},
+		},
+	},
 	{
 		Name:        "trade-cancel",
 		Description: "Cancel one of your trade orders",
@@ -516,6 +1014,46 @@ var commands = []*discordgo.ApplicationCommand{
 			},
 		},
 	},
+	{
+		Name:        "trade-cancel-all",
+		Description: "Cancel all of your active trade orders (asks for confirmation)",
+	},
+	{
+		Name:        "trade-relist",
+		Description: "Extend one of your active trade orders",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "order-id",
+				Description: "The order ID to extend",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "duration",
+				Description: "How long from now the order stays active (default 7 days)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "1 Day", Value: "1d"},
+					{Name: "3 Days", Value: "3d"},
+					{Name: "7 Days", Value: "7d"},
+					{Name: "14 Days", Value: "14d"},
+				},
+			},
+		},
+	},
+	{
+		Name:        "trade-complete",
+		Description: "Mark one of your trade orders as completed",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "order-id",
+				Description: "The order ID that was traded",
+				Required:    true,
+			},
+		},
+	},
 	{
 		Name:        "trade-contact",
 		Description: "Contact the creator of a trade order via DM",
@@ -600,6 +1138,70 @@ var commands = []*discordgo.ApplicationCommand{
 		Name:        "admin-trade-bans",
 		Description: "List all active trade bans (admin only)",
 	},
+	{
+		Name:        "admin-set-name",
+		Description: "Change or clear a user's in-game name (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user",
+				Description: "The user whose name to change",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "name",
+				Description: "New in-game name (omit to clear it)",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "admin-order-remove",
+		Description: "Remove a single player order without banning its owner (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "order-id",
+				Description: "The order ID to remove",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "reason",
+				Description: "Why it was removed (shown to the owner)",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "admin-filter-add",
+		Description: "Bar a word from order notes, in-game names and trade DMs (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "word",
+				Description: "The word to filter",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "admin-filter-remove",
+		Description: "Stop filtering a word (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "word",
+				Description: "The word to stop filtering",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "admin-filter-list",
+		Description: "List filtered words (admin only)",
+	},
 	{
 		Name:        "admin-trade-reports",
 		Description: "View trade reports (admin only)",
@@ -645,34 +1247,96 @@ var commands = []*discordgo.ApplicationCommand{
 			},
 		},
 	},
+	{
+		Name:        "admin-audit-log",
+		Description: "Review recent audit log entries (admin only)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "action",
+				Description: "Only show this action (optional)",
+				Required:    false,
+				Choices:     auditActionChoices(),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "limit",
+				Description: "Number of entries (default 10, max 25)",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "admin-stats",
+		Description: "View moderation workload: reports, bans and response times (admin only)",
+	},
+}
+
+// auditActionChoices offers every audit action as a filter choice
+func auditActionChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(auditActions))
+	for idx, action := range auditActions {
+		choices[idx] = &discordgo.ApplicationCommandOptionChoice{Name: action, Value: action}
+	}
+	return choices
+}
+
+// commandGuildID is where slash commands are registered: the dev guild when
+// configured (changes apply instantly), otherwise globally ("") which can
+// take up to an hour to propagate
+func (b *Bot) commandGuildID() string {
+	return b.devGuildID
 }
 
-// registerCommands registers all slash commands with Discord
+// registerCommands syncs the slash commands with Discord, overwriting the
+// registered set only when it differs from commands
 func (b *Bot) registerCommands() error {
-	log.Println("Registering slash commands...")
+	appID := b.session.State.User.ID
+	guildID := b.commandGuildID()
+	scope := "globally"
+	if guildID != "" {
+		scope = "to dev guild " + guildID
+	}
 
-	for _, cmd := range commands {
-		_, err := b.session.ApplicationCommandCreate(b.session.State.User.ID, "", cmd)
-		if err != nil {
-			return err
-		}
-		log.Printf("Registered command: %s", cmd.Name)
+	registered, err := b.session.ApplicationCommands(appID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch registered commands: %w", err)
+	}
+
+	diff := diffCommands(registered, commands)
+	if diff.Empty() {
+		log.Printf("Slash commands %s are up to date (%d commands)", scope, len(commands))
+		return nil
 	}
 
+	if _, err := b.session.ApplicationCommandBulkOverwrite(appID, guildID, commands); err != nil {
+		return fmt.Errorf("failed to overwrite commands: %w", err)
+	}
+	log.Printf("Synced slash commands %s: created %v, updated %v, deleted %v",
+		scope, diff.Created, diff.Updated, diff.Deleted)
+
 	return nil
 }
 
-// cleanupCommands removes all registered commands (useful for development)
-func (b *Bot) cleanupCommands() error {
-	log.Println("Cleaning up slash commands...")
+// cleanupCommands removes all registered commands (useful for development).
+// With devGuild it clears the dev guild's commands instead of the global ones.
+func (b *Bot) cleanupCommands(devGuild bool) error {
+	guildID := ""
+	if devGuild {
+		if b.devGuildID == "" {
+			return fmt.Errorf("no dev guild configured")
+		}
+		guildID = b.devGuildID
+	}
+	log.Printf("Cleaning up slash commands (guild %q)...", guildID)
 
-	registeredCommands, err := b.session.ApplicationCommands(b.session.State.User.ID, "")
+	registeredCommands, err := b.session.ApplicationCommands(b.session.State.User.ID, guildID)
 	if err != nil {
 		return err
 	}
 
 	for _, cmd := range registeredCommands {
-		err := b.session.ApplicationCommandDelete(b.session.State.User.ID, "", cmd.ID)
+		err := b.session.ApplicationCommandDelete(b.session.State.User.ID, guildID, cmd.ID)
 		if err != nil {
 			log.Printf("Failed to delete command %s: %v", cmd.Name, err)
 		}
@@ -0,0 +1,20 @@
+package bot
+
+// Emoji used in trade embeds and DM relay reactions. They are written as
+// escapes so an editor or tool re-encoding the file can't turn them into
+// mojibake.
+const (
+	emojiBuyOrder  = "\U0001F4D7" // 📗 green book
+	emojiSellOrder = "\U0001F4D5" // 📕 red book
+	emojiSearch    = "\U0001F50D" // 🔍 magnifying glass
+	emojiHandshake = "\U0001F91D" // 🤝 handshake
+	emojiDelivered = "\u2705"     // ✅ check mark, reacted on relayed DMs
+)
+
+// orderTypeEmoji returns the book emoji for a buy or sell trade order
+func orderTypeEmoji(orderType string) string {
+	if orderType == "sell" {
+		return emojiSellOrder
+	}
+	return emojiBuyOrder
+}
@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEmojiConstants(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  rune
+	}{
+		{"buy order", emojiBuyOrder, 0x1F4D7},
+		{"sell order", emojiSellOrder, 0x1F4D5},
+		{"search", emojiSearch, 0x1F50D},
+		{"handshake", emojiHandshake, 0x1F91D},
+		{"delivered", emojiDelivered, 0x2705},
+	}
+
+	for _, tt := range tests {
+		if !utf8.ValidString(tt.value) {
+			t.Errorf("%s: %q is not valid UTF-8", tt.name, tt.value)
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(tt.value)
+		if r != tt.want || size != len(tt.value) {
+			t.Errorf("%s: expected single rune %U, got %q", tt.name, tt.want, tt.value)
+		}
+	}
+}
+
+func TestOrderTypeEmoji(t *testing.T) {
+	if orderTypeEmoji("buy") != emojiBuyOrder || orderTypeEmoji("sell") != emojiSellOrder {
+		t.Error("Expected green book for buy and red book for sell")
+	}
+}
@@ -6,14 +6,19 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	"wosbTrade/internal/metrics"
+
 	"github.com/bwmarrin/discordgo"
 )
 
 // interactionCreate handles all slash command and component interactions
 func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	b.rememberLocale(i)
+
 	switch i.Type {
 	case discordgo.InteractionApplicationCommand:
 		b.handleCommand(s, i)
@@ -32,14 +37,59 @@ func (b *Bot) handleComponentInteraction(s *discordgo.Session, i *discordgo.Inte
 	// Route based on custom ID prefix
 	parts := strings.Split(customID, "_")
 	switch {
-	case strings.HasPrefix(customID, "port_select_"):
+	case strings.HasPrefix(customID, "port_select:"):
 		b.handlePortSelect(s, i, parts)
 	case strings.HasPrefix(customID, "port_create"):
 		b.handlePortCreate(s, i)
-	case strings.HasPrefix(customID, "item_select_"):
-		b.handleItemConfirm(s, i, parts)
+	case strings.HasPrefix(customID, "item_confirm:"):
+		// item_confirm:<userID>:<OCR name>; the name may contain colons
+		b.handleItemConfirm(s, i, strings.SplitN(customID, ":", 3))
+	case strings.HasPrefix(customID, "submission_override:"):
+		b.handleSubmissionOverride(s, i)
+	case strings.HasPrefix(customID, "submission_replace:"):
+		b.handleSubmissionReplace(s, i, orderModeReplace)
+	case strings.HasPrefix(customID, "submission_merge:"):
+		b.handleSubmissionReplace(s, i, orderModeMerge)
+	case strings.HasPrefix(customID, "submission_edit:"):
+		b.handleSubmissionEdit(s, i)
+	case strings.HasPrefix(customID, "submission_commit:"):
+		b.handleSubmissionCommit(s, i)
+	case strings.HasPrefix(customID, "submission_cancel:"):
+		b.handleSubmissionCancel(s, i)
+	case strings.HasPrefix(customID, "port_hint:"):
+		b.handlePortHint(s, i)
+	case strings.HasPrefix(customID, "order_type_confirm:"):
+		b.handleOrderTypeConfirm(s, i)
+	case strings.HasPrefix(customID, "trade_draft_confirm:"):
+		b.handleTradeDraftConfirm(s, i)
+	case strings.HasPrefix(customID, "trade_draft_edit:"):
+		b.handleTradeDraftEdit(s, i)
+	case strings.HasPrefix(customID, "trade_item_select:"):
+		b.handleTradeItemSelect(s, i)
+	case strings.HasPrefix(customID, "trade_draft_cancel:"):
+		b.handleTradeDraftCancel(s, i)
+	case strings.HasPrefix(customID, "items_tag_select:"):
+		b.handleItemsTagSelect(s, i)
+	case strings.HasPrefix(customID, "overview_page:"):
+		b.handleOverviewPage(s, i, customID)
+	case strings.HasPrefix(customID, "ports_page:"):
+		b.handlePortsPage(s, i, customID)
+	case strings.HasPrefix(customID, "item_list_page:"):
+		b.handleItemListPage(s, i, customID)
+	case strings.HasPrefix(customID, "trade_cancel_all_confirm:"):
+		b.handleTradeCancelAllConfirm(s, i, customID)
+	case customID == "trade_cancel_all_keep":
+		b.handleTradeCancelAllKeep(s, i)
+	case strings.HasPrefix(customID, "admin_purge_confirm:"):
+		b.handleAdminPurgeConfirm(s, i, customID)
+	case customID == "admin_purge_cancel":
+		b.handleAdminPurgeCancel(s, i)
+	case strings.HasPrefix(customID, "rate:"):
+		b.handleRateButton(s, i, customID)
 	case strings.HasPrefix(customID, "trade_contact_"):
 		b.handleTradeContactButton(s, i, parts)
+	case strings.HasPrefix(customID, "trade_interest:"):
+		b.handleTradeInterestButton(s, i, customID)
 	default:
 		log.Printf("Unknown component interaction: %s", customID)
 	}
@@ -54,6 +104,12 @@ func (b *Bot) handleModalSubmit(s *discordgo.Session, i *discordgo.InteractionCr
 	switch {
 	case strings.HasPrefix(customID, "new_port_"):
 		b.handleCreatePortModal(s, i)
+	case strings.HasPrefix(customID, "port_hint_modal:"):
+		b.handlePortHintModal(s, i)
+	case strings.HasPrefix(customID, "submission_edit_modal:"):
+		b.handleSubmissionEditModal(s, i)
+	case strings.HasPrefix(customID, "trade_draft_modal:"):
+		b.handleTradeDraftModal(s, i)
 	default:
 		log.Printf("Unknown modal submit: %s", customID)
 	}
@@ -63,42 +119,84 @@ func (b *Bot) handleModalSubmit(s *discordgo.Session, i *discordgo.InteractionCr
 func (b *Bot) handleCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	data := i.ApplicationCommandData()
 
+	start := time.Now()
+	outcome := metrics.OutcomeOK
+	defer func() {
+		if _, failed := b.failedCommands.LoadAndDelete(i.ID); failed {
+			outcome = metrics.OutcomeError
+		}
+		metrics.CommandsTotal.WithLabelValues(data.Name, outcome).Inc()
+		metrics.CommandDuration.WithLabelValues(data.Name).Observe(time.Since(start).Seconds())
+	}()
+
 	switch data.Name {
 	// User commands
+	case "submit-status":
+		b.handleSubmitStatus(s, i)
 	case "submit":
 		b.handleSubmit(s, i)
 	case "price":
 		b.handlePrice(s, i)
+	case "best-route":
+		b.handleBestRoute(s, i)
 	case "port":
 		b.handlePortView(s, i)
+	case "port-compare":
+		b.handlePortCompare(s, i)
+	case "port-export":
+		b.handlePortExport(s, i)
 	case "ports":
 		b.handlePortsList(s, i)
 	case "items":
 		b.handleItemsList(s, i)
+	case "item-info":
+		b.handleItemInfo(s, i)
 	case "stats":
 		b.handleStats(s, i)
+	case "leaderboard":
+		b.handleLeaderboard(s, i)
+	case "overview":
+		b.handleOverview(s, i)
+	case "market-overview":
+		b.handleMarketOverview(s, i)
 
 	// Admin port commands
 	case "admin-port-add":
 		b.handleAdminPortAdd(s, i)
+	case "admin-port-import":
+		b.handleAdminPortImport(s, i)
 	case "admin-port-edit":
 		b.handleAdminPortEdit(s, i)
 	case "admin-port-remove":
 		b.handleAdminPortRemove(s, i)
 	case "admin-port-alias":
 		b.handleAdminPortAlias(s, i)
+	case "admin-region-add":
+		b.handleAdminRegionAdd(s, i)
 
 	// Admin item commands
 	case "admin-item-list-untagged":
 		b.handleAdminItemListUntagged(s, i)
 	case "admin-item-tag":
 		b.handleAdminItemTag(s, i)
+	case "admin-item-list-all":
+		b.handleAdminItemListAll(s, i)
+	case "admin-item-tag-all":
+		b.handleAdminItemTagAll(s, i)
 	case "admin-item-untag":
 		b.handleAdminItemUntag(s, i)
 	case "admin-item-alias":
 		b.handleAdminItemAlias(s, i)
 	case "admin-item-rename":
 		b.handleAdminItemRename(s, i)
+	case "admin-item-price-bounds":
+		b.handleAdminItemPriceBounds(s, i)
+	case "admin-item-list-duplicates":
+		b.handleAdminItemListDuplicates(s, i)
+	case "admin-item-archive":
+		b.handleAdminItemArchive(s, i)
+	case "admin-item-restore":
+		b.handleAdminItemRestore(s, i)
 	case "admin-item-merge":
 		b.handleAdminItemMerge(s, i)
 
@@ -107,6 +205,10 @@ func (b *Bot) handleCommand(s *discordgo.Session, i *discordgo.InteractionCreate
 		b.handleAdminTagCreate(s, i)
 	case "admin-tag-list":
 		b.handleAdminTagList(s, i)
+	case "admin-tag-edit":
+		b.handleAdminTagEdit(s, i)
+	case "admin-tag-rename":
+		b.handleAdminTagRename(s, i)
 	case "admin-tag-delete":
 		b.handleAdminTagDelete(s, i)
 
@@ -115,10 +217,22 @@ func (b *Bot) handleCommand(s *discordgo.Session, i *discordgo.InteractionCreate
 		b.handleAdminExpire(s, i)
 	case "admin-purge":
 		b.handleAdminPurge(s, i)
+	case "admin-export":
+		b.handleAdminExport(s, i)
 
 	// Configuration commands
 	case "config-set-admin-role":
 		b.handleConfigSetAdminRole(s, i)
+	case "config-set-log-channel":
+		b.handleConfigSetLogChannel(s, i)
+	case "config-set-trade-preview":
+		b.handleConfigSetTradePreview(s, i)
+	case "config-set-submission-timeout":
+		b.handleConfigSetSubmissionTimeout(s, i)
+	case "config-set-report-autoban":
+		b.handleConfigSetReportAutoban(s, i)
+	case "config-set-max-orders":
+		b.handleConfigSetMaxOrders(s, i)
 	case "config-show":
 		b.handleConfigShow(s, i)
 
@@ -129,10 +243,22 @@ func (b *Bot) handleCommand(s *discordgo.Session, i *discordgo.InteractionCreate
 		b.handleTradeCreate(s, i)
 	case "trade-search":
 		b.handleTradeSearch(s, i)
+	case "trade-view":
+		b.handleTradeView(s, i)
+	case "trade-profile":
+		b.handleTradeProfile(s, i)
 	case "trade-my-orders":
 		b.handleTradeMyOrders(s, i)
+	case "trade-my-history":
+		b.handleTradeMyHistory(s, i)
 	case "trade-cancel":
 		b.handleTradeCancel(s, i)
+	case "trade-cancel-all":
+		b.handleTradeCancelAll(s, i)
+	case "trade-relist":
+		b.handleTradeRelist(s, i)
+	case "trade-complete":
+		b.handleTradeComplete(s, i)
 	case "trade-contact":
 		b.handleTradeContact(s, i)
 	case "trade-end":
@@ -147,19 +273,43 @@ func (b *Bot) handleCommand(s *discordgo.Session, i *discordgo.InteractionCreate
 		b.handleAdminTradeUnban(s, i)
 	case "admin-trade-bans":
 		b.handleAdminTradeBans(s, i)
+	case "admin-set-name":
+		b.handleAdminSetName(s, i)
+	case "admin-order-remove":
+		b.handleAdminOrderRemove(s, i)
+	case "admin-filter-add":
+		b.handleAdminFilterAdd(s, i)
+	case "admin-filter-remove":
+		b.handleAdminFilterRemove(s, i)
+	case "admin-filter-list":
+		b.handleAdminFilterList(s, i)
 	case "admin-trade-reports":
 		b.handleAdminTradeReports(s, i)
 	case "admin-trade-report-action":
 		b.handleAdminTradeReportAction(s, i)
+	case "admin-audit-log":
+		b.handleAdminAuditLog(s, i)
+	case "admin-stats":
+		b.handleAdminStats(s, i)
 
 	default:
-		b.respondError(s, i, "Unknown command")
+		outcome = metrics.OutcomeUnknown
+		b.respondError(s, i, t(i.Locale, "error.unknown_command"))
+	}
+}
+
+// markCommandFailed records that a slash command answered with an error, so
+// handleCommand can label its metrics
+func (b *Bot) markCommandFailed(i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionApplicationCommand {
+		b.failedCommands.Store(i.ID, struct{}{})
 	}
 }
 
 // Helper functions
 
 func (b *Bot) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) {
+	b.markCommandFailed(i)
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
@@ -180,6 +330,7 @@ func (b *Bot) respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCre
 }
 
 func (b *Bot) followUpError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) {
+	b.markCommandFailed(i)
 	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 		Content: stringPtr(fmt.Sprintf("❌ %s", message)),
 	})
@@ -212,6 +363,35 @@ func downloadFile(url, filepath string) error {
 	return err
 }
 
+// orderTotal is what a whole order is worth. It's computed in int64 so bulk
+// orders can't overflow on 32-bit builds.
+func orderTotal(price, quantity int) int64 {
+	return int64(price) * int64(quantity)
+}
+
+// formatGold renders an amount of gold with thousands separators, e.g.
+// "1,250,000 gold"
+func formatGold(n int64) string {
+	return groupThousands(n) + " gold"
+}
+
+// groupThousands writes n with a comma between each group of three digits
+func groupThousands(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	var b strings.Builder
+	for idx, r := range digits {
+		if idx > 0 && (len(digits)-idx)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return sign + b.String()
+}
+
 func formatAge(d time.Duration) string {
 	if d < time.Minute {
 		return "just now"
@@ -240,11 +420,11 @@ func parseOptions(options []*discordgo.ApplicationCommandInteractionDataOption)
 // checkAdmin validates if the user is an admin and responds if not
 func (b *Bot) checkAdmin(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
 	if i.Member == nil {
-		b.respondError(s, i, "This command must be used in a server")
+		b.respondError(s, i, t(i.Locale, "error.guild_only"))
 		return false
 	}
 	if !b.isAdmin(i.GuildID, i.Member) {
-		b.respondError(s, i, "This command requires the admin role")
+		b.respondError(s, i, t(i.Locale, "error.admin_required"))
 		return false
 	}
 	return true
@@ -1,10 +1,18 @@
 package bot
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
+
+	"wosbTrade/internal/database"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -25,7 +33,11 @@ func (b *Bot) handleAdminPortAdd(s *discordgo.Session, i *discordgo.InteractionC
 	}
 
 	ctx := context.Background()
-	port, err := b.db.CreatePort(ctx, name, name, region, i.Member.User.ID)
+	port, err := b.db.CreatePort(ctx, name, name, region, getUserID(i))
+	if errors.Is(err, database.ErrUnknownRegion) {
+		b.respondError(s, i, b.unknownRegionMessage(ctx, region))
+		return
+	}
 	if err != nil {
 		log.Printf("Error creating port: %v", err)
 		b.respondError(s, i, "Failed to create port (may already exist)")
@@ -33,23 +45,123 @@ func (b *Bot) handleAdminPortAdd(s *discordgo.Session, i *discordgo.InteractionC
 	}
 
 	_ = notes // TODO: Add notes support
-	_ = port
 
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("✅ Created port: **%s** (Region: %s)", name, region),
+			Content: fmt.Sprintf("✅ Created port: **%s** (Region: %s)", port.DisplayName, port.Region),
 		},
 	})
 }
 
+// handleAdminPortImport creates ports from an attached CSV file
+func (b *Bot) handleAdminPortImport(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	// Defer response while the file downloads
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	attachment := i.ApplicationCommandData().Resolved.Attachments[options["file"].Value.(string)]
+	if attachment == nil {
+		b.followUpError(s, i, "Could not find the attached file")
+		return
+	}
+	if attachment.Size > portImportMaxBytes {
+		b.followUpError(s, i, fmt.Sprintf("The file is too large (max %d KB)", portImportMaxBytes/1024))
+		return
+	}
+
+	resp, err := http.Get(attachment.URL)
+	if err != nil {
+		log.Printf("Error downloading port import: %v", err)
+		b.followUpError(s, i, "Failed to download the file")
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Error downloading port import: HTTP %d", resp.StatusCode)
+		b.followUpError(s, i, "Failed to download the file")
+		return
+	}
+
+	ports, err := parsePortImportCSV(io.LimitReader(resp.Body, portImportMaxBytes), getUserID(i))
+	if err != nil {
+		b.followUpError(s, i, fmt.Sprintf("Invalid CSV: %v", err))
+		return
+	}
+
+	ctx := context.Background()
+	created, skipped, err := b.db.BulkCreatePorts(ctx, ports)
+	if errors.Is(err, database.ErrUnknownRegion) {
+		b.followUpError(s, i, fmt.Sprintf("Nothing imported: %v. Add it with /admin-region-add first.", err))
+		return
+	}
+	if err != nil {
+		log.Printf("Error importing ports: %v", err)
+		b.followUpError(s, i, "Failed to import ports")
+		return
+	}
+
+	message := fmt.Sprintf("✅ Imported ports: %d created, %d skipped (already exist)", created, skipped)
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &message,
+	})
+}
+
 func (b *Bot) handleAdminPortEdit(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	if !b.checkAdmin(s, i) {
 		return
 	}
 
-	b.respondError(s, i, "Port editing not yet implemented")
-	// TODO: Implement port editing
+	options := parseOptions(i.ApplicationCommandData().Options)
+	name := options["name"].StringValue()
+	newName, region := "", ""
+	if opt := options["new-name"]; opt != nil {
+		newName = opt.StringValue()
+	}
+	if opt := options["region"]; opt != nil {
+		region = opt.StringValue()
+	}
+	if newName == "" && region == "" {
+		b.respondError(s, i, "Nothing to change: give a new name or region")
+		return
+	}
+
+	ctx := context.Background()
+	port, err := b.db.GetPortByName(ctx, name)
+	if err != nil {
+		b.respondError(s, i, fmt.Sprintf("Port not found: %s", name))
+		return
+	}
+
+	err = b.db.UpdatePort(ctx, port.ID, newName, region)
+	if errors.Is(err, database.ErrUnknownRegion) {
+		b.respondError(s, i, b.unknownRegionMessage(ctx, region))
+		return
+	}
+	if err != nil {
+		log.Printf("Error updating port: %v", err)
+		b.respondError(s, i, "Failed to update port (name may already be taken)")
+		return
+	}
+
+	lookup := port.Name
+	if newName != "" {
+		lookup = newName
+	}
+	updated, err := b.db.GetPortByName(ctx, lookup)
+	if err != nil {
+		log.Printf("Error reloading port: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	b.respondEphemeral(s, i, fmt.Sprintf("✅ Updated port: **%s** (Region: %s)", updated.DisplayName, updated.Region))
 }
 
 func (b *Bot) handleAdminPortRemove(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -70,6 +182,61 @@ func (b *Bot) handleAdminPortAlias(s *discordgo.Session, i *discordgo.Interactio
 	// TODO: Implement port alias creation
 }
 
+// Admin Region Management Handlers
+
+func (b *Bot) handleAdminRegionAdd(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	name := options["name"].StringValue()
+
+	ctx := context.Background()
+	region, err := b.db.CreateRegion(ctx, name, getUserID(i))
+	if errors.Is(err, database.ErrRegionExists) {
+		b.respondError(s, i, fmt.Sprintf("Can't add '%s': %v", name, err))
+		return
+	}
+	if err != nil {
+		log.Printf("Error creating region: %v", err)
+		b.respondError(s, i, "Failed to create region")
+		return
+	}
+
+	b.respondEphemeral(s, i, fmt.Sprintf("✅ Added region: **%s**", region.Name))
+}
+
+// resolveRegionOption resolves a region filter option, responding with the
+// known regions when it doesn't match one
+func (b *Bot) resolveRegionOption(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, input string) (*database.Region, bool) {
+	region, err := b.db.ResolveRegion(ctx, input)
+	if errors.Is(err, database.ErrUnknownRegion) {
+		b.respondError(s, i, b.unknownRegionMessage(ctx, input))
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Error resolving region: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return nil, false
+	}
+	return region, true
+}
+
+// unknownRegionMessage explains an unknown region and lists the known ones
+func (b *Bot) unknownRegionMessage(ctx context.Context, input string) string {
+	regions, err := b.db.GetAllRegions(ctx)
+	if err != nil || len(regions) == 0 {
+		return fmt.Sprintf("Unknown region '%s'. No regions have been added yet; admins can add one with `/admin-region-add`", input)
+	}
+
+	names := make([]string, len(regions))
+	for idx, r := range regions {
+		names[idx] = r.Name
+	}
+	return fmt.Sprintf("Unknown region '%s'. Known regions: %s", input, strings.Join(names, ", "))
+}
+
 // Admin Item Management Handlers
 
 func (b *Bot) handleAdminItemListUntagged(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -87,7 +254,7 @@ func (b *Bot) handleAdminItemListUntagged(s *discordgo.Session, i *discordgo.Int
 	items, err := b.db.GetUntaggedItems(ctx, limit)
 	if err != nil {
 		log.Printf("Error getting untagged items: %v", err)
-		b.respondError(s, i, "Database error")
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
@@ -132,6 +299,88 @@ func (b *Bot) handleAdminItemListUntagged(s *discordgo.Session, i *discordgo.Int
 	})
 }
 
+// handleAdminItemListAll pages through the whole catalog with each item's tags
+func (b *Bot) handleAdminItemListAll(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	ctx := context.Background()
+
+	var filter itemListFilter
+	if opt := options["tag"]; opt != nil {
+		tag, err := b.db.GetTagByName(ctx, opt.StringValue())
+		if err != nil {
+			b.respondError(s, i, fmt.Sprintf("Tag not found: %s", opt.StringValue()))
+			return
+		}
+		filter.TagID, filter.TagName = tag.ID, tag.Name
+	}
+	if opt := options["category"]; opt != nil {
+		filter.Category = strings.TrimSpace(opt.StringValue())
+	}
+
+	b.showItemListPage(s, i, discordgo.InteractionResponseChannelMessageWithSource, 0, filter)
+}
+
+// handleItemListPage switches an /admin-item-list-all message to another page
+func (b *Bot) handleItemListPage(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	page, filter, err := parseItemListPage(customID)
+	if err != nil {
+		b.respondError(s, i, t(i.Locale, "error.invalid_page"))
+		return
+	}
+
+	if filter.TagID != 0 {
+		tags, err := b.db.GetAllTags(context.Background(), "")
+		if err != nil {
+			log.Printf("Error getting tags: %v", err)
+			b.respondError(s, i, t(i.Locale, "error.database"))
+			return
+		}
+		for _, tag := range tags {
+			if tag.ID == filter.TagID {
+				filter.TagName = tag.Name
+			}
+		}
+	}
+
+	b.showItemListPage(s, i, discordgo.InteractionResponseUpdateMessage, page, filter)
+}
+
+// showItemListPage loads the filtered catalog and responds with one page of it
+func (b *Bot) showItemListPage(s *discordgo.Session, i *discordgo.InteractionCreate, responseType discordgo.InteractionResponseType, page int, filter itemListFilter) {
+	items, err := b.db.GetItemsWithTags(context.Background(), filter.TagID, filter.Category)
+	if err != nil {
+		log.Printf("Error listing items: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	// The catalog may have shrunk since the buttons were drawn; stay in range
+	total := itemListPageCount(len(items))
+	if page >= total {
+		page = total - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{buildItemListPage(items, page, filter)},
+			Components: itemListPageComponents(page, total, filter),
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
 func (b *Bot) handleAdminItemTag(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	if !b.checkAdmin(s, i) {
 		return
@@ -150,22 +399,44 @@ func (b *Bot) handleAdminItemTag(s *discordgo.Session, i *discordgo.InteractionC
 		return
 	}
 
-	// Parse tag names
-	tagNameList := strings.Split(tagNames, ",")
-	var tagIDs []int
+	tagIDs, ok := b.resolveTagNames(ctx, s, i, tagNames)
+	if !ok {
+		return
+	}
+
+	// Add tags to item
+	err = b.db.AddTagsToItem(ctx, item.ID, tagIDs)
+	if err != nil {
+		log.Printf("Error adding tags: %v", err)
+		b.respondError(s, i, t(i.Locale, "tags.add_failed"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("✅ Tagged **%s** with: %s", item.DisplayName, tagNames),
+		},
+	})
+}
+
+// resolveTagNames turns a comma-separated tag list into tag IDs, responding
+// with an error and returning false if any tag doesn't exist
+func (b *Bot) resolveTagNames(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, tagNames string) ([]int, bool) {
+	allTags, err := b.db.GetAllTags(ctx, "")
+	if err != nil {
+		log.Printf("Error getting tags: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return nil, false
+	}
 
-	for _, tagName := range tagNameList {
+	var tagIDs []int
+	for _, tagName := range strings.Split(tagNames, ",") {
 		tagName = strings.TrimSpace(tagName)
 		if tagName == "" {
 			continue
 		}
 
-		// Find or suggest tag
-		allTags, err := b.db.GetAllTags(ctx, "")
-		if err != nil {
-			continue
-		}
-
 		found := false
 		for _, tag := range allTags {
 			if strings.EqualFold(tag.Name, tagName) {
@@ -177,29 +448,68 @@ func (b *Bot) handleAdminItemTag(s *discordgo.Session, i *discordgo.InteractionC
 
 		if !found {
 			b.respondError(s, i, fmt.Sprintf("Tag not found: %s. Create it first with `/admin-tag-create`", tagName))
-			return
+			return nil, false
 		}
 	}
 
 	if len(tagIDs) == 0 {
 		b.respondError(s, i, "No valid tags provided")
+		return nil, false
+	}
+	return tagIDs, true
+}
+
+// bulkTagMinPattern is the shortest pattern /admin-item-tag-all accepts, so a
+// stray letter doesn't tag half the catalog
+const bulkTagMinPattern = 2
+
+// handleAdminItemTagAll tags every untagged item whose name contains a pattern
+func (b *Bot) handleAdminItemTagAll(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
 		return
 	}
 
-	// Add tags to item
-	err = b.db.AddTagsToItem(ctx, item.ID, tagIDs)
+	options := parseOptions(i.ApplicationCommandData().Options)
+	pattern := strings.TrimSpace(options["pattern"].StringValue())
+	tagNames := options["tags"].StringValue()
+
+	if len([]rune(pattern)) < bulkTagMinPattern {
+		b.respondError(s, i, fmt.Sprintf("The pattern must be at least %d characters", bulkTagMinPattern))
+		return
+	}
+
+	ctx := context.Background()
+	tagIDs, ok := b.resolveTagNames(ctx, s, i, tagNames)
+	if !ok {
+		return
+	}
+
+	items, err := b.db.FindUntaggedItemsByPattern(ctx, pattern)
 	if err != nil {
+		log.Printf("Error finding untagged items: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+	if len(items) == 0 {
+		b.respondEphemeral(s, i, fmt.Sprintf("No untagged items contain '%s'", pattern))
+		return
+	}
+
+	itemIDs := make([]int, len(items))
+	names := make([]string, len(items))
+	for idx, item := range items {
+		itemIDs[idx] = item.ID
+		names[idx] = item.DisplayName
+	}
+
+	if err := b.db.AddTagsToItems(ctx, itemIDs, tagIDs); err != nil {
 		log.Printf("Error adding tags: %v", err)
-		b.respondError(s, i, "Failed to add tags")
+		b.respondError(s, i, t(i.Locale, "tags.add_failed"))
 		return
 	}
 
-	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("✅ Tagged **%s** with: %s", item.DisplayName, tagNames),
-		},
-	})
+	response := fmt.Sprintf("✅ Tagged %d item(s) matching '%s' with: %s\n", len(items), pattern, tagNames)
+	b.respondEphemeral(s, i, response+joinLimited(names, ", ", maxMessageContent-len(response)))
 }
 
 func (b *Bot) handleAdminItemUntag(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -238,6 +548,167 @@ func (b *Bot) handleAdminItemMerge(s *discordgo.Session, i *discordgo.Interactio
 	// TODO: Implement item merging with market order transfer
 }
 
+// handleAdminItemArchive retires an item without deleting its history
+func (b *Bot) handleAdminItemArchive(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	itemName := options["item"].StringValue()
+	ctx := context.Background()
+
+	item, err := b.db.GetItemByName(ctx, itemName)
+	if err != nil || item == nil {
+		b.respondError(s, i, fmt.Sprintf("Item not found: %s", itemName))
+		return
+	}
+
+	if err := b.db.ArchiveItem(ctx, item.ID, getUserID(i)); err != nil {
+		log.Printf("Error archiving item: %v", err)
+		b.respondError(s, i, fmt.Sprintf("Failed to archive item: %v", err))
+		return
+	}
+
+	b.respondEphemeral(s, i, fmt.Sprintf("✅ Archived **%s**. It no longer matches submissions and players can't add it again; existing orders are kept. Use `/admin-item-restore` to bring it back.", item.DisplayName))
+}
+
+// handleAdminItemRestore undoes /admin-item-archive
+func (b *Bot) handleAdminItemRestore(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	itemName := options["item"].StringValue()
+	ctx := context.Background()
+
+	item, err := b.db.GetItemByName(ctx, itemName)
+	if err != nil || item == nil {
+		b.respondError(s, i, fmt.Sprintf("Item not found: %s", itemName))
+		return
+	}
+
+	if err := b.db.RestoreItem(ctx, item.ID, getUserID(i)); err != nil {
+		log.Printf("Error restoring item: %v", err)
+		b.respondError(s, i, fmt.Sprintf("Failed to restore item: %v", err))
+		return
+	}
+
+	b.respondEphemeral(s, i, fmt.Sprintf("✅ Restored **%s**. It matches submissions and can be traded again.", item.DisplayName))
+}
+
+// duplicateListLimit caps the pairs /admin-item-list-duplicates shows
+const duplicateListLimit = 15
+
+// handleAdminItemListDuplicates lists likely duplicate items with the archive
+// command that would retire each duplicate in favour of the item to keep
+func (b *Bot) handleAdminItemListDuplicates(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	minScore := int(database.HighConfidenceThreshold * 100)
+	if opt := options["min-score"]; opt != nil {
+		minScore = int(opt.IntValue())
+	}
+	if minScore < 50 || minScore > 100 {
+		b.respondError(s, i, "min-score must be between 50 and 100")
+		return
+	}
+
+	pairs, err := b.db.FindLikelyDuplicates(context.Background(), float64(minScore)/100)
+	if err != nil {
+		log.Printf("Error finding duplicate items: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	if len(pairs) == 0 {
+		b.respondEphemeral(s, i, fmt.Sprintf("✅ No items are at least %d%% similar.", minScore))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{buildDuplicatesEmbed(pairs, minScore)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// buildDuplicatesEmbed renders the best duplicate pairs, one field each
+func buildDuplicatesEmbed(pairs []database.DuplicatePair, minScore int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "🔍 Likely Duplicate Items",
+		Description: fmt.Sprintf("%d pair(s) at least %d%% similar, most similar first.", len(pairs), minScore),
+		Color:       0xe67e22,
+	}
+
+	shown := pairs
+	if len(shown) > duplicateListLimit {
+		shown = shown[:duplicateListLimit]
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Showing the top %d; raise min-score to narrow the list", duplicateListLimit),
+		}
+	}
+
+	for _, p := range shown {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s ↔ %s (%.0f%%)", p.Duplicate.DisplayName, p.Keep.DisplayName, p.Score*100),
+			Value: fmt.Sprintf("Keep **%s**, retire the duplicate with `/admin-item-archive item:%s`", p.Keep.DisplayName, p.Duplicate.Name),
+		})
+	}
+	return embed
+}
+
+// handleAdminItemPriceBounds sets the price range /trade-create accepts
+// without a warning; omitting both limits clears them
+func (b *Bot) handleAdminItemPriceBounds(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	itemName := options["item"].StringValue()
+	minPrice, maxPrice := 0, 0
+	if opt := options["min-price"]; opt != nil {
+		minPrice = int(opt.IntValue())
+	}
+	if opt := options["max-price"]; opt != nil {
+		maxPrice = int(opt.IntValue())
+	}
+
+	ctx := context.Background()
+
+	item, err := b.db.GetItemByName(ctx, itemName)
+	if err != nil || item == nil {
+		b.respondError(s, i, fmt.Sprintf("Item not found: %s", itemName))
+		return
+	}
+
+	if err := b.db.SetItemPriceBounds(ctx, item.ID, minPrice, maxPrice, getUserID(i)); err != nil {
+		log.Printf("Error setting price bounds: %v", err)
+		b.respondError(s, i, fmt.Sprintf("Failed to set price bounds: %v", err))
+		return
+	}
+
+	var response string
+	switch {
+	case minPrice == 0 && maxPrice == 0:
+		response = fmt.Sprintf("✅ Cleared price bounds for **%s**", item.DisplayName)
+	case maxPrice == 0:
+		response = fmt.Sprintf("✅ Trade orders for **%s** below %s will now be flagged", item.DisplayName, formatGold(int64(minPrice)))
+	case minPrice == 0:
+		response = fmt.Sprintf("✅ Trade orders for **%s** above %s will now be flagged", item.DisplayName, formatGold(int64(maxPrice)))
+	default:
+		response = fmt.Sprintf("✅ Trade orders for **%s** outside %s–%s will now be flagged", item.DisplayName, groupThousands(int64(minPrice)), formatGold(int64(maxPrice)))
+	}
+	b.respondEphemeral(s, i, response)
+}
+
 // Admin Tag Management Handlers
 
 func (b *Bot) handleAdminTagCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -260,6 +731,10 @@ func (b *Bot) handleAdminTagCreate(s *discordgo.Session, i *discordgo.Interactio
 
 	ctx := context.Background()
 	tag, err := b.db.CreateTag(ctx, name, category, color, icon)
+	if errors.Is(err, database.ErrInvalidTagColor) {
+		b.respondError(s, i, fmt.Sprintf("Invalid color '%s': use a hex color like #FF5733", color))
+		return
+	}
 	if err != nil {
 		log.Printf("Error creating tag: %v", err)
 		b.respondError(s, i, "Failed to create tag (may already exist)")
@@ -294,7 +769,7 @@ func (b *Bot) handleAdminTagList(s *discordgo.Session, i *discordgo.InteractionC
 	tags, err := b.db.GetAllTags(ctx, category)
 	if err != nil {
 		log.Printf("Error getting tags: %v", err)
-		b.respondError(s, i, "Database error")
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
@@ -344,6 +819,112 @@ func (b *Bot) handleAdminTagList(s *discordgo.Session, i *discordgo.InteractionC
 	})
 }
 
+// handleAdminTagEdit changes a tag's category, color or icon in place
+func (b *Bot) handleAdminTagEdit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	name := options["name"].StringValue()
+
+	var category, color, icon *string
+	if opt := options["category"]; opt != nil {
+		v := opt.StringValue()
+		category = &v
+	}
+	if opt := options["color"]; opt != nil {
+		v := opt.StringValue()
+		color = &v
+	}
+	if opt := options["icon"]; opt != nil {
+		v := opt.StringValue()
+		icon = &v
+	}
+	if category == nil && color == nil && icon == nil {
+		b.respondError(s, i, "Give at least one of category, color or icon to change")
+		return
+	}
+
+	ctx := context.Background()
+	before, err := b.db.GetTagByName(ctx, name)
+	if err != nil {
+		b.respondError(s, i, fmt.Sprintf("Tag not found: %s", name))
+		return
+	}
+
+	after, err := b.db.UpdateTag(ctx, before.ID, nil, category, color, icon)
+	if errors.Is(err, database.ErrInvalidTagColor) {
+		b.respondError(s, i, fmt.Sprintf("Invalid color '%s': use a hex color like #FF5733", *color))
+		return
+	}
+	if err != nil {
+		log.Printf("Error updating tag: %v", err)
+		b.respondError(s, i, "Failed to update tag")
+		return
+	}
+
+	b.respondEphemeral(s, i, fmt.Sprintf("✅ Updated tag **%s**\n%s", after.Name, formatTagChanges(before, after)))
+}
+
+// handleAdminTagRename renames a tag without touching its item links
+func (b *Bot) handleAdminTagRename(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	oldName := options["old-name"].StringValue()
+	newName := options["new-name"].StringValue()
+
+	ctx := context.Background()
+	before, err := b.db.GetTagByName(ctx, oldName)
+	if err != nil {
+		b.respondError(s, i, fmt.Sprintf("Tag not found: %s", oldName))
+		return
+	}
+
+	after, err := b.db.RenameTag(ctx, before.ID, newName)
+	if errors.Is(err, database.ErrTagNameTaken) {
+		b.respondError(s, i, fmt.Sprintf("A tag named '%s' already exists", strings.TrimSpace(newName)))
+		return
+	}
+	if err != nil {
+		log.Printf("Error renaming tag: %v", err)
+		b.respondError(s, i, fmt.Sprintf("Failed to rename tag: %v", err))
+		return
+	}
+
+	b.respondEphemeral(s, i, fmt.Sprintf("✅ Renamed tag\n%s\nItems tagged with it keep the tag.", formatTagChanges(before, after)))
+}
+
+// formatTagChanges lists the fields that differ between two versions of a
+// tag as "before → after" lines
+func formatTagChanges(before, after *database.Tag) string {
+	show := func(v string) string {
+		if v == "" {
+			return "(none)"
+		}
+		return v
+	}
+
+	var lines []string
+	for _, f := range []struct{ label, old, new string }{
+		{"Name", before.Name, after.Name},
+		{"Category", before.Category, after.Category},
+		{"Color", before.Color, after.Color},
+		{"Icon", before.Icon, after.Icon},
+	} {
+		if f.old != f.new {
+			lines = append(lines, fmt.Sprintf("**%s**: %s → %s", f.label, show(f.old), show(f.new)))
+		}
+	}
+	if len(lines) == 0 {
+		return "Nothing changed."
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (b *Bot) handleAdminTagDelete(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	if !b.checkAdmin(s, i) {
 		return
@@ -364,16 +945,28 @@ func (b *Bot) handleAdminExpire(s *discordgo.Session, i *discordgo.InteractionCr
 	count, err := b.db.DeleteExpiredOrders(ctx)
 	if err != nil {
 		log.Printf("Error deleting expired orders: %v", err)
-		b.respondError(s, i, "Database error")
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	// Player orders are normally expired by the hourly loop; do them here too
+	// so the manual trigger is a full pass
+	expired, err := b.db.DeleteExpiredPlayerOrders(ctx)
+	if err != nil {
+		log.Printf("Error expiring player orders: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("✅ Deleted %d expired orders", count),
+			Content: fmt.Sprintf("✅ Deleted %d market orders, expired %d player orders", count, len(expired)),
 		},
 	})
+
+	// Owners hear about it whichever pass expires their orders
+	b.notifyExpiredOrders(s, expired)
 }
 
 func (b *Bot) handleAdminPurge(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -383,6 +976,10 @@ func (b *Bot) handleAdminPurge(s *discordgo.Session, i *discordgo.InteractionCre
 
 	options := parseOptions(i.ApplicationCommandData().Options)
 	portName := options["port"].StringValue()
+	scope := purgeScopeMarket
+	if opt := options["scope"]; opt != nil {
+		scope = opt.StringValue()
+	}
 
 	ctx := context.Background()
 
@@ -393,17 +990,283 @@ func (b *Bot) handleAdminPurge(s *discordgo.Session, i *discordgo.InteractionCre
 		return
 	}
 
-	count, err := b.db.PurgePort(ctx, port.ID, i.Member.User.ID)
+	var markets, players int
+	if scope != purgeScopePlayer {
+		if markets, err = b.db.CountOrdersByPort(ctx, port.ID); err != nil {
+			log.Printf("Error counting port orders: %v", err)
+			b.respondError(s, i, t(i.Locale, "error.database"))
+			return
+		}
+	}
+	if scope != purgeScopeMarket {
+		if players, err = b.db.CountOpenPlayerOrdersByPort(ctx, port.ID); err != nil {
+			log.Printf("Error counting port player orders: %v", err)
+			b.respondError(s, i, t(i.Locale, "error.database"))
+			return
+		}
+	}
+
+	// Big purges can't be undone, so ask first; small ones stay one step
+	if markets+players > adminPurgeConfirmThreshold {
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content:    adminPurgePrompt(port.DisplayName, scope, markets, players),
+				Components: adminPurgeComponents(port.ID, scope),
+				Flags:      discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+
+	result, err := b.runAdminPurge(ctx, s, port, scope, getUserID(i))
 	if err != nil {
 		log.Printf("Error purging port: %v", err)
-		b.respondError(s, i, "Database error")
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("✅ Purged %d orders from port '%s'", count, port.DisplayName),
+			Content: result.summary(port.DisplayName, scope),
+		},
+	})
+}
+
+// /admin-purge scopes: OCR market data (the default), player trade orders,
+// or both
+const (
+	purgeScopeMarket = "market"
+	purgeScopePlayer = "player"
+	purgeScopeBoth   = "both"
+)
+
+// adminPurgeConfirmThreshold is the largest purge /admin-purge runs without confirmation
+const adminPurgeConfirmThreshold = 50
+
+// adminPurgeResult is what one /admin-purge removed
+type adminPurgeResult struct {
+	Markets      int64 // market orders deleted
+	PlayerOrders int   // player orders cancelled
+}
+
+// summary reports a purge's counts, each kind on its own
+func (r adminPurgeResult) summary(portName, scope string) string {
+	switch scope {
+	case purgeScopePlayer:
+		return fmt.Sprintf("✅ Cancelled %d player orders at port '%s'", r.PlayerOrders, portName)
+	case purgeScopeBoth:
+		return fmt.Sprintf("✅ Purged port '%s': deleted %d market orders, cancelled %d player orders",
+			portName, r.Markets, r.PlayerOrders)
+	default:
+		return fmt.Sprintf("✅ Purged %d market orders from port '%s'", r.Markets, portName)
+	}
+}
+
+// adminPurgePrompt asks an admin to confirm a large purge
+func adminPurgePrompt(portName, scope string, markets, players int) string {
+	var parts []string
+	if scope != purgeScopePlayer {
+		parts = append(parts, fmt.Sprintf("permanently delete **%d market orders**", markets))
+	}
+	if scope != purgeScopeMarket {
+		parts = append(parts, fmt.Sprintf("cancel **%d player orders**", players))
+	}
+	return fmt.Sprintf("⚠️ This will %s at port '%s'. Are you sure?", strings.Join(parts, " and "), portName)
+}
+
+// runAdminPurge purges a port's market data, player orders or both, and
+// tells the owners of any player orders cancelled
+func (b *Bot) runAdminPurge(ctx context.Context, s *discordgo.Session, port *database.Port, scope, adminID string) (adminPurgeResult, error) {
+	var result adminPurgeResult
+	if scope != purgeScopePlayer {
+		count, err := b.db.PurgePort(ctx, port.ID, adminID)
+		if err != nil {
+			return result, err
+		}
+		result.Markets = count
+	}
+	if scope != purgeScopeMarket {
+		orders, err := b.db.PurgePlayerOrders(ctx, port.ID, adminID)
+		if err != nil {
+			return result, err
+		}
+		result.PlayerOrders = len(orders)
+
+		userIDs, byUser := groupOrdersByOwner(orders)
+		for _, userID := range userIDs {
+			if err := relayToUser(s, userID, []string{purgedOrdersMessage(port.DisplayName, byUser[userID])}); err != nil {
+				log.Printf("Error telling %s their orders were purged: %v", userID, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// purgedOrdersMessage tells an owner an admin cleared their orders at a port
+func purgedOrdersMessage(portName string, orders []database.PlayerOrder) string {
+	if len(orders) == 1 {
+		return fmt.Sprintf("🛑 An admin cleared all orders at **%s**, so your order %s was cancelled. Use `/trade-create` to post it at another port.",
+			portName, describeNoticeOrder(orders[0]))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🛑 An admin cleared all orders at **%s**, so %d of your orders were cancelled:\n", portName, len(orders)))
+	for idx, o := range orders {
+		if idx == expiryNoticeMaxOrders {
+			sb.WriteString(fmt.Sprintf("…and %d more\n", len(orders)-idx))
+			break
+		}
+		sb.WriteString("- " + describeNoticeOrder(o) + "\n")
+	}
+	sb.WriteString("Use `/trade-create` to post them at another port.")
+	return sb.String()
+}
+
+// adminPurgeComponents returns the Purge/Cancel buttons for a large purge
+func adminPurgeComponents(portID int, scope string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Purge",
+					Style:    discordgo.DangerButton,
+					CustomID: fmt.Sprintf("admin_purge_confirm:%d:%s", portID, scope),
+				},
+				discordgo.Button{
+					Label:    "Cancel",
+					Style:    discordgo.SecondaryButton,
+					CustomID: "admin_purge_cancel",
+				},
+			},
+		},
+	}
+}
+
+// parseAdminPurgeConfirm reads a Purge button's port and scope. Buttons from
+// before scopes existed carry only the port and purge market data.
+func parseAdminPurgeConfirm(customID string) (int, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(customID, "admin_purge_confirm:"), ":", 2)
+	portID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", fmt.Errorf("bad port in %q: %w", customID, err)
+	}
+	scope := purgeScopeMarket
+	if len(parts) == 2 {
+		scope = parts[1]
+	}
+	return portID, scope, nil
+}
+
+// handleAdminPurgeConfirm performs a purge confirmed from the /admin-purge prompt
+func (b *Bot) handleAdminPurgeConfirm(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	portID, scope, err := parseAdminPurgeConfirm(customID)
+	if err != nil {
+		b.respondError(s, i, "Invalid port")
+		return
+	}
+
+	ctx := context.Background()
+	port, err := b.db.GetPortByID(ctx, portID)
+	if err != nil || port == nil {
+		b.respondError(s, i, "Port not found, it may have been removed")
+		return
+	}
+
+	result, err := b.runAdminPurge(ctx, s, port, scope, getUserID(i))
+	if err != nil {
+		log.Printf("Error purging port: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    result.summary(port.DisplayName, scope),
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+// handleAdminPurgeCancel dismisses the /admin-purge prompt
+func (b *Bot) handleAdminPurgeCancel(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    "Purge cancelled.",
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+// handleAdminExport sends every active market order as CSV or JSON
+// attachments, one file per message so large boards stay under the limit
+func (b *Bot) handleAdminExport(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	format := "csv"
+	if opt := options["format"]; opt != nil {
+		format = opt.StringValue()
+	}
+	contentType := "text/csv"
+	if format == "json" {
+		contentType = "application/json"
+	}
+
+	// Defer response, large exports can take a moment to build
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
 		},
 	})
+
+	markets, err := b.db.ExportMarkets(context.Background())
+	if err != nil {
+		log.Printf("Error exporting markets: %v", err)
+		b.followUpError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+	if len(markets) == 0 {
+		b.followUpError(s, i, "No active orders to export")
+		return
+	}
+
+	files, err := buildMarketExport(markets, format, portExportMaxBytes)
+	if err != nil {
+		log.Printf("Error building market export: %v", err)
+		b.followUpError(s, i, "Failed to build export")
+		return
+	}
+
+	date := time.Now().UTC().Format("20060102")
+	for idx, data := range files {
+		name := fmt.Sprintf("markets_%s.%s", date, format)
+		content := fmt.Sprintf("📄 Market export (%d orders)", len(markets))
+		if len(files) > 1 {
+			name = fmt.Sprintf("markets_%s_part%d.%s", date, idx+1, format)
+			content = fmt.Sprintf("📄 Market export part %d/%d (%d orders)", idx+1, len(files), len(markets))
+		}
+
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: content,
+			Files: []*discordgo.File{
+				{Name: name, ContentType: contentType, Reader: bytes.NewReader(data)},
+			},
+			Flags: discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			log.Printf("Error sending market export part %d: %v", idx+1, err)
+			return
+		}
+	}
 }
@@ -13,7 +13,7 @@ import (
 func (b *Bot) handleConfigSetAdminRole(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	// This command requires Manage Server permission (enforced by Discord via DefaultMemberPermissions)
 	if i.GuildID == "" {
-		b.respondError(s, i, "This command must be used in a server")
+		b.respondError(s, i, t(i.Locale, "error.guild_only"))
 		return
 	}
 
@@ -28,10 +28,10 @@ func (b *Bot) handleConfigSetAdminRole(s *discordgo.Session, i *discordgo.Intera
 
 	// Save to database
 	ctx := context.Background()
-	err := b.db.SetGuildAdminRole(ctx, i.GuildID, roleID, i.Member.User.ID)
+	err := b.db.SetGuildAdminRole(ctx, i.GuildID, roleID, getUserID(i))
 	if err != nil {
 		log.Printf("Error setting guild admin role: %v", err)
-		b.respondError(s, i, "Failed to save configuration")
+		b.respondError(s, i, t(i.Locale, "error.save_config"))
 		return
 	}
 
@@ -54,7 +54,7 @@ func (b *Bot) handleConfigSetAdminRole(s *discordgo.Session, i *discordgo.Intera
 			},
 			{
 				Name:   "Configured By",
-				Value:  i.Member.User.Mention(),
+				Value:  fmt.Sprintf("<@%s>", getUserID(i)),
 				Inline: true,
 			},
 		},
@@ -72,10 +72,152 @@ func (b *Bot) handleConfigSetAdminRole(s *discordgo.Session, i *discordgo.Intera
 	})
 }
 
+// handleConfigSetLogChannel sets or clears the moderation log channel for the current guild
+func (b *Bot) handleConfigSetLogChannel(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		b.respondError(s, i, t(i.Locale, "error.guild_only"))
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	channelID := ""
+	if opt := options["channel"]; opt != nil {
+		channelID = opt.ChannelValue(s).ID
+	}
+
+	ctx := context.Background()
+	if err := b.db.SetGuildLogChannel(ctx, i.GuildID, channelID, getUserID(i)); err != nil {
+		log.Printf("Error setting guild log channel: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.save_config"))
+		return
+	}
+
+	if channelID == "" {
+		b.respondEphemeral(s, i, "Moderation log channel cleared. Ban and report events will no longer be posted.")
+		return
+	}
+	b.respondEphemeral(s, i, fmt.Sprintf("Ban and report events will now be posted in <#%s>.", channelID))
+}
+
+// handleConfigSetTradePreview toggles the /trade-create preview step for the current guild
+func (b *Bot) handleConfigSetTradePreview(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		b.respondError(s, i, t(i.Locale, "error.guild_only"))
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	enabled := options["enabled"].BoolValue()
+
+	ctx := context.Background()
+	if err := b.db.SetGuildTradePreview(ctx, i.GuildID, enabled, getUserID(i)); err != nil {
+		log.Printf("Error setting guild trade preview: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.save_config"))
+		return
+	}
+
+	msg := "Trade order previews are now **enabled**. `/trade-create` will ask for confirmation before posting."
+	if !enabled {
+		msg = "Trade order previews are now **disabled**. `/trade-create` will post orders immediately."
+	}
+	b.respondEphemeral(s, i, msg)
+}
+
+// handleConfigSetSubmissionTimeout sets or resets how long /submit
+// confirmations stay open in the current guild
+func (b *Bot) handleConfigSetSubmissionTimeout(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		b.respondError(s, i, t(i.Locale, "error.guild_only"))
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	minutes := 0
+	if opt := options["minutes"]; opt != nil {
+		minutes = int(clampSubmissionTimeout(time.Duration(opt.IntValue())*time.Minute) / time.Minute)
+	}
+
+	ctx := context.Background()
+	if err := b.db.SetGuildSubmissionTimeout(ctx, i.GuildID, minutes, getUserID(i)); err != nil {
+		log.Printf("Error setting guild submission timeout: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.save_config"))
+		return
+	}
+
+	if minutes == 0 {
+		b.respondEphemeral(s, i, fmt.Sprintf("Submission timeout reset to the default of **%d minutes**.", b.submissionManager.timeout/time.Minute))
+		return
+	}
+	b.respondEphemeral(s, i, fmt.Sprintf("`/submit` confirmations will now stay open for **%d minutes**.", minutes))
+}
+
+// handleConfigSetReportAutoban sets or turns off the number of reports that
+// get a user banned automatically in the current guild
+func (b *Bot) handleConfigSetReportAutoban(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		b.respondError(s, i, t(i.Locale, "error.guild_only"))
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	threshold := int(options["threshold"].IntValue())
+	if threshold != 0 && (threshold < minReportAutobanThreshold || threshold > maxReportAutobanThreshold) {
+		b.respondError(s, i, fmt.Sprintf("Threshold must be 0 (off) or between %d and %d",
+			minReportAutobanThreshold, maxReportAutobanThreshold))
+		return
+	}
+
+	ctx := context.Background()
+	if err := b.db.SetGuildReportAutobanThreshold(ctx, i.GuildID, threshold, getUserID(i)); err != nil {
+		log.Printf("Error setting guild report autoban threshold: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.save_config"))
+		return
+	}
+
+	if threshold == 0 {
+		b.respondEphemeral(s, i, "Automatic bans from reports are now **off**.")
+		return
+	}
+	b.respondEphemeral(s, i, fmt.Sprintf("Users reported by **%d** different traders will now be banned for %s automatically.",
+		threshold, formatWaitTime(reportAutobanDuration)))
+}
+
+// handleConfigSetMaxOrders sets or resets how many live orders each trader
+// may have in the current guild
+func (b *Bot) handleConfigSetMaxOrders(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		b.respondError(s, i, t(i.Locale, "error.guild_only"))
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	limit := 0
+	if opt := options["limit"]; opt != nil {
+		limit = int(opt.IntValue())
+		if limit < 1 || limit > maxActiveOrdersLimit {
+			b.respondError(s, i, fmt.Sprintf("Limit must be between 1 and %d", maxActiveOrdersLimit))
+			return
+		}
+	}
+
+	ctx := context.Background()
+	if err := b.db.SetGuildMaxActiveOrders(ctx, i.GuildID, limit, getUserID(i)); err != nil {
+		log.Printf("Error setting guild max active orders: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.save_config"))
+		return
+	}
+
+	if limit == 0 {
+		b.respondEphemeral(s, i, fmt.Sprintf("Active order limit reset to the default of **%d**.", defaultMaxActiveOrders))
+		return
+	}
+	b.respondEphemeral(s, i, fmt.Sprintf("Traders can now have up to **%d** active orders at once.", limit))
+}
+
 // handleConfigShow displays current server configuration
 func (b *Bot) handleConfigShow(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	if i.GuildID == "" {
-		b.respondError(s, i, "This command must be used in a server")
+		b.respondError(s, i, t(i.Locale, "error.guild_only"))
 		return
 	}
 
@@ -144,6 +286,56 @@ func (b *Bot) handleConfigShow(s *discordgo.Session, i *discordgo.InteractionCre
 		}
 	}
 
+	logChannel := "❌ Not configured"
+	if settings != nil && settings.LogChannelID != "" {
+		logChannel = fmt.Sprintf("<#%s>", settings.LogChannelID)
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Moderation Log Channel",
+		Value:  logChannel,
+		Inline: true,
+	})
+
+	tradePreview := "✅ Enabled"
+	if settings != nil && !settings.TradePreview {
+		tradePreview = "❌ Disabled"
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Trade Order Preview",
+		Value:  tradePreview,
+		Inline: true,
+	})
+
+	submissionTimeout := fmt.Sprintf("%d minutes (default)", b.submissionManager.timeout/time.Minute)
+	if settings != nil && settings.SubmissionTimeoutMinutes > 0 {
+		submissionTimeout = fmt.Sprintf("%d minutes", settings.SubmissionTimeoutMinutes)
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Submission Timeout",
+		Value:  submissionTimeout,
+		Inline: true,
+	})
+
+	autoban := "Off"
+	if settings != nil && settings.ReportAutobanThreshold > 0 {
+		autoban = fmt.Sprintf("After %d reports", settings.ReportAutobanThreshold)
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Report Autoban",
+		Value:  autoban,
+		Inline: true,
+	})
+
+	maxOrders := fmt.Sprintf("%d (default)", defaultMaxActiveOrders)
+	if settings != nil && settings.MaxActiveOrders > 0 {
+		maxOrders = fmt.Sprintf("%d", settings.MaxActiveOrders)
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Active Orders per Trader",
+		Value:  maxOrders,
+		Inline: true,
+	})
+
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
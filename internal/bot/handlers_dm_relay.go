@@ -5,10 +5,23 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+const (
+	// relayMaxDeliveryFailures is how many relays in a row may fail, usually
+	// because the recipient has DMs closed, before the conversation is closed
+	relayMaxDeliveryFailures = 3
+	// relayMaxContentLength is how much of one DM's text is relayed, counted
+	// after escaping and masking; longer text is cut short and both sides
+	// are told
+	relayMaxContentLength = 1500
+	// relayMaxAttachments is how many attachment links one DM may relay
+	relayMaxAttachments = 3
+)
+
 // messageCreate handles incoming messages, specifically DMs for trade relay
 func (b *Bot) messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
 	// Ignore the bot's own messages
@@ -25,55 +38,285 @@ func (b *Bot) messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
 	conv, ok := b.tradeConversations.GetByUser(m.Author.ID)
 	if !ok {
 		// No active conversation - send help message
-		s.ChannelMessageSend(m.ChannelID,
-			"You don't have an active trade conversation.\n\n"+
-				"Use `/trade-search` in a server to find orders, then `/trade-contact` to start chatting with a trader.")
+		s.ChannelMessageSend(m.ChannelID, t(b.userLocale(m.Author.ID), "relay.no_conversation"))
+		return
+	}
+
+	if b.closeIfSenderBanned(s, conv, m.Author.ID) {
 		return
 	}
 
-	// Get the other party's info
+	var urls []string
+	for _, att := range m.Attachments {
+		urls = append(urls, att.URL)
+	}
 	otherUserID, _ := conv.GetOtherParty(m.Author.ID)
-	senderIngameName := conv.GetIngameName(m.Author.ID)
+	text, shortened, leftOut := b.relayText(b.userLocale(otherUserID), conv.GetIngameName(m.Author.ID), m.Content, urls)
+	if text == "" {
+		return
+	}
 
-	// Open a DM channel to the other party
-	otherCh, err := s.UserChannelCreate(otherUserID)
-	if err != nil {
-		log.Printf("Error creating DM channel to %s: %v", otherUserID, err)
-		s.ChannelMessageSend(m.ChannelID, "Failed to deliver your message. The other trader may have DMs disabled.")
+	// Text is cut to fit, so only oversized attachment links can get here.
+	// Anything Discord would refuse is turned back rather than counted as a
+	// delivery failure; it gets no delivery reaction.
+	locale := b.userLocale(m.Author.ID)
+	if utf8.RuneCountInString(text) > relayMaxBatchLength {
+		s.ChannelMessageSend(m.ChannelID, t(locale, "relay.too_long"))
 		return
 	}
+	var notices []string
+	if shortened {
+		notices = append(notices, t(locale, "relay.shortened"))
+	}
+	if leftOut > 0 {
+		notices = append(notices, t(locale, "relay.attachments_left_out", relayMaxAttachments, leftOut))
+	}
+	if len(notices) > 0 {
+		s.ChannelMessageSend(m.ChannelID, strings.Join(notices, "\n"))
+	}
 
-	// Relay the text message
-	if m.Content != "" {
-		relayMsg := fmt.Sprintf("**[%s]**: %s", senderIngameName, m.Content)
-		_, err := s.ChannelMessageSend(otherCh.ID, relayMsg)
-		if err != nil {
-			log.Printf("Error relaying message to %s: %v", otherUserID, err)
-			s.ChannelMessageSend(m.ChannelID, "Failed to deliver your message. The other trader may have DMs disabled.")
-			return
+	// The delivery reaction is added when the batch is relayed
+	b.relayQueue.Add(conv, m.Author.ID, m.ChannelID, relayEntry{messageID: m.ID, text: text})
+}
+
+// relayText builds the relayed form of a DM in the recipient's locale: the
+// text, then up to relayMaxAttachments attachment links. Text is cut short to
+// fit (see relayLine). shortened and leftOut say what the sender should be
+// told was cut; the recipient sees a marker in the message. text is empty if
+// there's nothing to relay.
+func (b *Bot) relayText(locale discordgo.Locale, senderName, content string, attachmentURLs []string) (text string, shortened bool, leftOut int) {
+	var attachments string
+	if len(attachmentURLs) > 0 {
+		shown := attachmentURLs
+		if len(shown) > relayMaxAttachments {
+			shown = shown[:relayMaxAttachments]
+		}
+		attachments = t(locale, "relay.shared", senderName) + "\n" + strings.Join(shown, "\n")
+		if leftOut = len(attachmentURLs) - len(shown); leftOut > 0 {
+			attachments += "\n" + t(locale, "relay.more_attachments", leftOut)
 		}
 	}
 
-	// Forward attachment URLs
-	if len(m.Attachments) > 0 {
-		var attachmentLines []string
-		for _, att := range m.Attachments {
-			attachmentLines = append(attachmentLines, att.URL)
-		}
-		attachMsg := fmt.Sprintf("**[%s]** shared:\n%s", senderIngameName, strings.Join(attachmentLines, "\n"))
-		_, err := s.ChannelMessageSend(otherCh.ID, attachMsg)
-		if err != nil {
-			log.Printf("Error relaying attachments to %s: %v", otherUserID, err)
+	var lines []string
+	if strings.TrimSpace(content) != "" {
+		// The attachments share the message, so the text gets what's left
+		room := relayMaxBatchLength
+		if attachments != "" {
+			room -= utf8.RuneCountInString(attachments) + 1
 		}
+		var line string
+		line, shortened = b.relayLine(locale, fmt.Sprintf("**[%s]**: ", senderName), content, room)
+		lines = append(lines, line)
+	}
+	if attachments != "" {
+		lines = append(lines, attachments)
+	}
+
+	return strings.Join(lines, "\n"), shortened, leftOut
+}
+
+// relayLine formats one relayed line: prefix, then the content sanitized and
+// masked. The formatted text is what's measured, since escaping can double
+// it, and it's cut to relayMaxContentLength runes or whatever keeps the line
+// within room, whichever is less. A cut line ends with the locale's
+// "shortened" marker.
+func (b *Bot) relayLine(locale discordgo.Locale, prefix, content string, room int) (line string, shortened bool) {
+	body := b.words.Mask(sanitizeUserText(content, 0))
+	marker := " " + t(locale, "relay.shortened_marker")
+
+	limit := room - utf8.RuneCountInString(prefix) - utf8.RuneCountInString(marker)
+	limit = min(limit, relayMaxContentLength)
+	if limit > 0 && utf8.RuneCountInString(body) > limit {
+		return prefix + truncateEscaped(body, limit) + marker, true
 	}
+	return prefix + body, false
+}
+
+// truncateEscaped is truncateLabel for text that has been through
+// sanitizeUserText: a cut that would leave a lone backslash escaping the
+// ellipsis drops it too
+func truncateEscaped(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	cut := runes[:limit-1]
+	slashes := 0
+	for idx := len(cut) - 1; idx >= 0 && cut[idx] == '\\'; idx-- {
+		slashes++
+	}
+	if slashes%2 == 1 {
+		cut = cut[:len(cut)-1]
+	}
+	return string(cut) + "…"
+}
+
+// deliverRelay sends a batch of queued DMs to the other party, reacting to
+// each of the sender's messages once it has been delivered
+func (b *Bot) deliverRelay(batch *relayBatch) {
+	conv := batch.conv
+
+	// The conversation may have ended while the batch was queued
+	if current, ok := b.tradeConversations.GetByUser(batch.senderID); !ok || current != conv {
+		return
+	}
+
+	otherUserID, _ := conv.GetOtherParty(batch.senderID)
+	if err := relayToUser(b.session, otherUserID, []string{batch.Text()}); err != nil {
+		log.Printf("Error relaying message to %s: %v", otherUserID, err)
+		b.handleRelayFailure(b.session, batch.channelID, batch.senderID, conv)
+		return
+	}
+	b.tradeConversations.RecordDeliveryResult(batch.senderID, true)
 
 	// Add checkmark reaction to confirm delivery
-	s.MessageReactionAdd(m.ChannelID, m.ID, "✅")
+	for _, messageID := range batch.MessageIDs() {
+		b.session.MessageReactionAdd(batch.channelID, messageID, emojiDelivered)
+	}
 
 	// Update activity timestamp (memory + DB)
-	b.tradeConversations.Touch(m.Author.ID)
+	b.tradeConversations.Touch(batch.senderID)
 	ctx := context.Background()
 	if err := b.db.UpdateConversationActivity(ctx, conv.ConversationID); err != nil {
 		log.Printf("Error updating conversation activity: %v", err)
 	}
 }
+
+// messageUpdate relays DM edits so a trader can't quietly change terms
+// after the other side has read the original
+func (b *Bot) messageUpdate(s *discordgo.Session, m *discordgo.MessageUpdate) {
+	// Link previews also fire updates; those have no author or edit timestamp
+	if m.GuildID != "" || m.Author == nil || m.EditedTimestamp == nil {
+		return
+	}
+	if m.Author.ID == s.State.User.ID {
+		return
+	}
+
+	conv, ok := b.tradeConversations.GetByUser(m.Author.ID)
+	if !ok || b.closeIfSenderBanned(s, conv, m.Author.ID) {
+		return
+	}
+
+	// Queued behind any unsent lines so the edit never arrives before the
+	// original. Edits are cut short like new messages.
+	otherUserID, _ := conv.GetOtherParty(m.Author.ID)
+	to := b.userLocale(otherUserID)
+	relay, shortened := b.relayLine(to, t(to, "relay.edited", conv.GetIngameName(m.Author.ID))+" ", m.Content, relayMaxBatchLength)
+	if shortened {
+		s.ChannelMessageSend(m.ChannelID, t(b.userLocale(m.Author.ID), "relay.shortened"))
+	}
+	b.relayQueue.Add(conv, m.Author.ID, m.ChannelID, relayEntry{text: relay})
+}
+
+// messageDelete tells the other party a DM was deleted. Delete events carry
+// no author, so the sender is taken from the DM channel's recipient.
+func (b *Bot) messageDelete(s *discordgo.Session, m *discordgo.MessageDelete) {
+	if m.GuildID != "" {
+		return
+	}
+
+	userID := dmRecipient(s, m.ChannelID)
+	if userID == "" {
+		return
+	}
+	conv, ok := b.tradeConversations.GetByUser(userID)
+	if !ok || b.closeIfSenderBanned(s, conv, userID) {
+		return
+	}
+
+	otherUserID, _ := conv.GetOtherParty(userID)
+	relay := t(b.userLocale(otherUserID), "relay.deleted", conv.GetIngameName(userID))
+	b.relayQueue.Add(conv, userID, m.ChannelID, relayEntry{text: relay})
+}
+
+// dmRecipient returns the user the bot shares a DM channel with, or "" if
+// the channel isn't a DM or can't be fetched
+func dmRecipient(s *discordgo.Session, channelID string) string {
+	ch, err := s.State.Channel(channelID)
+	if err != nil {
+		if ch, err = s.Channel(channelID); err != nil {
+			log.Printf("Error fetching channel %s: %v", channelID, err)
+			return ""
+		}
+	}
+	if ch.Type != discordgo.ChannelTypeDM || len(ch.Recipients) == 0 {
+		return ""
+	}
+	return ch.Recipients[0].ID
+}
+
+// relayToUser sends messages to a user's DMs in order, stopping at the first failure
+func relayToUser(s *discordgo.Session, userID string, messages []string) error {
+	ch, err := s.UserChannelCreate(userID)
+	if err != nil {
+		return fmt.Errorf("failed to open DM channel: %w", err)
+	}
+	for _, msg := range messages {
+		if _, err := s.ChannelMessageSend(ch.ID, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closeIfSenderBanned closes conv if senderID has been banned since it
+// started, so a ban issued mid-conversation stops every kind of relay:
+// messages, edits and deletions. It reports whether the conversation was
+// closed; a failed ban lookup lets the relay through.
+func (b *Bot) closeIfSenderBanned(s *discordgo.Session, conv *ActiveConversation, senderID string) bool {
+	ban, err := b.bans.IsUserBanned(context.Background(), senderID)
+	if err != nil {
+		log.Printf("Error checking trade ban: %v", err)
+		return false
+	}
+	if ban == nil {
+		return false
+	}
+	b.closeConversationForBan(s, conv, senderID)
+	return true
+}
+
+// closeConversationForBan ends a conversation because bannedUserID can no
+// longer trade, and tells both parties why it stopped
+func (b *Bot) closeConversationForBan(s *discordgo.Session, conv *ActiveConversation, bannedUserID string) {
+	if err := b.db.CloseTradeConversation(context.Background(), conv.ConversationID); err != nil {
+		log.Printf("Error closing conversation %d for banned user: %v", conv.ConversationID, err)
+	}
+	b.tradeConversations.Remove(conv)
+
+	otherUserID, otherIngameName := conv.GetOtherParty(bannedUserID)
+	bannedIngameName := conv.GetIngameName(bannedUserID)
+
+	if err := relayToUser(s, bannedUserID, []string{
+		t(b.userLocale(bannedUserID), "relay.closed_banned", otherIngameName),
+	}); err != nil {
+		log.Printf("Error notifying banned user %s: %v", bannedUserID, err)
+	}
+	if err := relayToUser(s, otherUserID, []string{
+		t(b.userLocale(otherUserID), "relay.closed_partner_banned", bannedIngameName),
+	}); err != nil {
+		log.Printf("Error notifying %s of closed conversation: %v", otherUserID, err)
+	}
+}
+
+// handleRelayFailure tells the sender their message didn't arrive and, once
+// relayMaxDeliveryFailures relays in a row have failed, closes the
+// conversation so it doesn't silently rot
+func (b *Bot) handleRelayFailure(s *discordgo.Session, channelID, senderID string, conv *ActiveConversation) {
+	failures := b.tradeConversations.RecordDeliveryResult(senderID, false)
+	_, otherIngameName := conv.GetOtherParty(senderID)
+
+	if failures < relayMaxDeliveryFailures {
+		s.ChannelMessageSend(channelID, t(b.userLocale(senderID), "relay.delivery_failed", failures, relayMaxDeliveryFailures))
+		return
+	}
+
+	if err := b.db.CloseTradeConversation(context.Background(), conv.ConversationID); err != nil {
+		log.Printf("Error closing undeliverable conversation %d: %v", conv.ConversationID, err)
+	}
+	b.tradeConversations.Remove(conv)
+
+	log.Printf("Closed conversation %d after %d failed deliveries", conv.ConversationID, failures)
+	s.ChannelMessageSend(channelID, t(b.userLocale(senderID), "relay.closed_undeliverable", failures, otherIngameName))
+}
@@ -1,7 +1,9 @@
 package bot
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -30,6 +32,36 @@ func parseBanDuration(d string) time.Duration {
 	}
 }
 
+const (
+	// minReportAutobanThreshold and maxReportAutobanThreshold bound
+	// /config-set-report-autoban; one report alone never bans anyone
+	minReportAutobanThreshold = 2
+	maxReportAutobanThreshold = 20
+	// reportAutobanDuration is how long an automatic ban lasts, long enough
+	// for an admin to review the reports and lift or extend it
+	reportAutobanDuration = 7 * 24 * time.Hour
+)
+
+// postModerationLog posts an embed to the guild's moderation log channel, if
+// one is configured. Failed sends are retried in the background and never
+// fail the calling command.
+func (b *Bot) postModerationLog(guildID string, embed *discordgo.MessageEmbed) {
+	if guildID == "" {
+		return
+	}
+
+	settings, err := b.db.GetGuildSettings(context.Background(), guildID)
+	if err != nil {
+		log.Printf("Error fetching guild settings for moderation log: %v", err)
+		return
+	}
+	if settings == nil || settings.LogChannelID == "" {
+		return
+	}
+
+	b.channelPosts.Post(settings.LogChannelID, embed)
+}
+
 // --- /trade-report ---
 
 func (b *Bot) handleTradeReport(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -46,14 +78,9 @@ func (b *Bot) handleTradeReport(s *discordgo.Session, i *discordgo.InteractionCr
 	ctx := context.Background()
 
 	// Look up the order to get the reported user
-	order, err := b.db.GetPlayerOrder(ctx, orderID)
-	if err != nil {
-		log.Printf("Error getting order for report: %v", err)
-		b.respondError(s, i, "Failed to look up order")
-		return
-	}
+	order, problem := b.lookupLiveOrder(ctx, i.Locale, orderID)
 	if order == nil {
-		b.respondError(s, i, "Order not found or has expired")
+		b.respondError(s, i, problem)
 		return
 	}
 
@@ -63,6 +90,19 @@ func (b *Bot) handleTradeReport(s *discordgo.Session, i *discordgo.InteractionCr
 		return
 	}
 
+	// One open report per trader is enough; reporting each of their orders
+	// would only pad the count toward an autoban
+	duplicate, err := b.db.HasExistingPendingReport(ctx, userID, order.UserID, nil)
+	if err != nil {
+		log.Printf("Error checking for an existing report: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+	if duplicate {
+		b.respondError(s, i, t(i.Locale, "report.duplicate"))
+		return
+	}
+
 	report := database.TradeReport{
 		ReporterUserID: userID,
 		ReportedUserID: order.UserID,
@@ -70,16 +110,78 @@ func (b *Bot) handleTradeReport(s *discordgo.Session, i *discordgo.InteractionCr
 		Reason:         reason,
 	}
 
-	_, err = b.db.CreateTradeReport(ctx, report)
+	autoban := database.ReportAutoban{BanFor: reportAutobanDuration}
+	if i.GuildID != "" {
+		if settings, err := b.db.GetGuildSettings(ctx, i.GuildID); err != nil {
+			log.Printf("Error fetching guild settings for report autoban: %v", err)
+		} else if settings != nil {
+			autoban.Threshold = settings.ReportAutobanThreshold
+		}
+	}
+
+	created, escalation, err := b.db.CreateTradeReport(ctx, report, autoban)
 	if err != nil {
 		log.Printf("Error creating trade report: %v", err)
 		b.respondError(s, i, "Failed to submit report")
 		return
 	}
 
+	b.postModerationLog(i.GuildID, &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("New Trade Report #%d", created.ID),
+		Color: 0xf39c12,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Reporter", Value: fmt.Sprintf("<@%s>", userID), Inline: true},
+			{Name: "Reported", Value: fmt.Sprintf("<@%s>", order.UserID), Inline: true},
+			{Name: "Order", Value: fmt.Sprintf("#%d", orderID), Inline: true},
+			{Name: "Reason", Value: sanitizeUserText(reason, 0)},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Review with /admin-trade-report-action",
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+
+	if escalation != nil {
+		b.applyReportAutoban(s, i.GuildID, escalation)
+	}
+
 	b.respondEphemeral(s, i, "Your report has been submitted and will be reviewed by an admin. Thank you.")
 }
 
+// applyReportAutoban enforces a ban CreateTradeReport issued automatically
+// the same way an admin's ban is: orders cancelled, any conversation closed,
+// and the log channel told which reports triggered it
+func (b *Bot) applyReportAutoban(s *discordgo.Session, guildID string, escalation *database.ReportEscalation) {
+	ban := escalation.Ban
+	b.bans.Set(ban)
+
+	cancelled, err := b.db.CancelAllUserOrders(context.Background(), ban.UserID)
+	if err != nil {
+		log.Printf("Error cancelling orders for auto-banned user %s: %v", ban.UserID, err)
+	}
+	if conv, ok := b.tradeConversations.GetByUser(ban.UserID); ok {
+		b.closeConversationForBan(s, conv, ban.UserID)
+	}
+
+	reports := make([]string, len(escalation.ReportIDs))
+	for idx, id := range escalation.ReportIDs {
+		reports[idx] = fmt.Sprintf("#%d", id)
+	}
+	b.postModerationLog(guildID, &discordgo.MessageEmbed{
+		Title:       "Automatic Trade Ban",
+		Description: fmt.Sprintf("<@%s> was reported by %d different users and has been banned until <t:%d:f>.", ban.UserID, escalation.Reporters, ban.ExpiresAt.Unix()),
+		Color:       0xe74c3c,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Reports Marked Reviewed", Value: joinLimited(reports, ", ", maxEmbedFieldValue), Inline: true},
+			{Name: "Orders Cancelled", Value: fmt.Sprintf("%d", cancelled), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Lift it early with /admin-trade-unban",
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
 // --- /admin-trade-ban ---
 
 func (b *Bot) handleAdminTradeBan(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -106,7 +208,7 @@ func (b *Bot) handleAdminTradeBan(s *discordgo.Session, i *discordgo.Interaction
 	ctx := context.Background()
 
 	// Check if already banned
-	existing, _ := b.db.IsUserBanned(ctx, targetUser.ID)
+	existing, _ := b.bans.IsUserBanned(ctx, targetUser.ID)
 	if existing != nil {
 		b.respondError(s, i, "This user is already banned from trading")
 		return
@@ -115,20 +217,26 @@ func (b *Bot) handleAdminTradeBan(s *discordgo.Session, i *discordgo.Interaction
 	ban := database.TradeBan{
 		UserID:    targetUser.ID,
 		Reason:    reason,
-		BannedBy:  i.Member.User.ID,
+		BannedBy:  getUserID(i),
 		ExpiresAt: expiresAt,
 	}
 
-	_, err := b.db.CreateTradeBan(ctx, ban)
+	created, err := b.db.CreateTradeBan(ctx, ban)
 	if err != nil {
 		log.Printf("Error creating trade ban: %v", err)
 		b.respondError(s, i, "Failed to create trade ban")
 		return
 	}
+	b.bans.Set(*created)
 
 	// Cancel all their active orders
 	cancelled, _ := b.db.CancelAllUserOrders(ctx, targetUser.ID)
 
+	// Stop relaying their DMs right away rather than on their next message
+	if conv, ok := b.tradeConversations.GetByUser(targetUser.ID); ok {
+		b.closeConversationForBan(s, conv, targetUser.ID)
+	}
+
 	expStr := "Permanent"
 	if expiresAt != nil {
 		expStr = fmt.Sprintf("<t:%d:F>", expiresAt.Unix())
@@ -141,7 +249,7 @@ func (b *Bot) handleAdminTradeBan(s *discordgo.Session, i *discordgo.Interaction
 			{Name: "User", Value: fmt.Sprintf("<@%s>", targetUser.ID), Inline: true},
 			{Name: "Reason", Value: reason, Inline: true},
 			{Name: "Duration", Value: expStr, Inline: true},
-			{Name: "Banned By", Value: fmt.Sprintf("<@%s>", i.Member.User.ID), Inline: true},
+			{Name: "Banned By", Value: fmt.Sprintf("<@%s>", getUserID(i)), Inline: true},
 			{Name: "Orders Cancelled", Value: fmt.Sprintf("%d", cancelled), Inline: true},
 		},
 		Timestamp: time.Now().Format(time.RFC3339),
@@ -154,6 +262,8 @@ func (b *Bot) handleAdminTradeBan(s *discordgo.Session, i *discordgo.Interaction
 			Flags:  discordgo.MessageFlagsEphemeral,
 		},
 	})
+
+	b.postModerationLog(i.GuildID, embed)
 }
 
 // --- /admin-trade-unban ---
@@ -167,15 +277,239 @@ func (b *Bot) handleAdminTradeUnban(s *discordgo.Session, i *discordgo.Interacti
 	targetUser := options["user"].UserValue(s)
 
 	ctx := context.Background()
-	err := b.db.RemoveTradeBan(ctx, targetUser.ID, i.Member.User.ID)
+	err := b.db.RemoveTradeBan(ctx, targetUser.ID, getUserID(i))
 	if err != nil {
 		b.respondError(s, i, err.Error())
 		return
 	}
+	b.bans.Remove(targetUser.ID)
 
 	b.respondEphemeral(s, i, fmt.Sprintf("Trade ban removed for <@%s>.", targetUser.ID))
 }
 
+// --- /admin-set-name ---
+
+// handleAdminSetName overwrites a user's in-game name, or clears it when no
+// name is given so they must run /trade-set-name again
+func (b *Bot) handleAdminSetName(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	targetUser := options["user"].UserValue(s)
+	adminID := getUserID(i)
+	ctx := context.Background()
+
+	embed := &discordgo.MessageEmbed{
+		Color:     0xe67e22,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	if opt := options["name"]; opt == nil {
+		if err := b.db.ClearPlayerProfile(ctx, targetUser.ID, adminID); err != nil {
+			b.respondError(s, i, err.Error())
+			return
+		}
+		embed.Title = "In-Game Name Cleared"
+		embed.Description = fmt.Sprintf("<@%s> must set a new name with `/trade-set-name` before trading again.", targetUser.ID)
+	} else {
+		name := normalizeIngameName(opt.StringValue())
+		if !validIngameName(name) {
+			b.respondError(s, i, t(i.Locale, "trade.name_length"))
+			return
+		}
+
+		taken, err := b.db.IsIngameNameTaken(ctx, name, targetUser.ID)
+		if err != nil {
+			log.Printf("Error checking in-game name: %v", err)
+			b.respondError(s, i, t(i.Locale, "trade.name_update_failed"))
+			return
+		}
+		if taken {
+			b.respondError(s, i, fmt.Sprintf("**%s** is already registered by another player", name))
+			return
+		}
+
+		if err := b.db.AdminSetPlayerProfile(ctx, targetUser.ID, name, adminID); err != nil {
+			log.Printf("Error setting player profile: %v", err)
+			b.respondError(s, i, t(i.Locale, "trade.name_update_failed"))
+			return
+		}
+		embed.Title = "In-Game Name Changed"
+		embed.Description = fmt.Sprintf("<@%s> is now **%s**. Their active orders were renamed too.", targetUser.ID, name)
+	}
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{Name: "Changed By", Value: fmt.Sprintf("<@%s>", adminID), Inline: true},
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+
+	b.postModerationLog(i.GuildID, embed)
+}
+
+// --- /admin-order-remove ---
+
+// handleAdminOrderRemove takes down a single order without banning its
+// owner, and tells the owner why
+func (b *Bot) handleAdminOrderRemove(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	orderID := int(options["order-id"].IntValue())
+	reason := ""
+	if opt := options["reason"]; opt != nil {
+		reason = opt.StringValue()
+	}
+	adminID := getUserID(i)
+
+	order, err := b.db.AdminCancelPlayerOrder(context.Background(), orderID, adminID, reason)
+	if msg := orderErrorMessage(i.Locale, orderID, err); msg != "" {
+		b.respondError(s, i, msg)
+		return
+	}
+	if err != nil {
+		log.Printf("Error removing order %d: %v", orderID, err)
+		b.respondError(s, i, "Failed to remove order")
+		return
+	}
+
+	shownReason := reason
+	if shownReason == "" {
+		shownReason = "No reason given"
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "Order Removed",
+		Description: fmt.Sprintf("Order #%d by <@%s> (%s) was cancelled.", order.ID, order.UserID, safeName(order.IngameName)),
+		Color:       0xe67e22,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Order", Value: fmt.Sprintf("%s %s x%d @ %s", strings.ToUpper(order.OrderType), order.Item.DisplayName, order.Quantity, formatGold(int64(order.Price))), Inline: true},
+			{Name: "Removed By", Value: fmt.Sprintf("<@%s>", adminID), Inline: true},
+			{Name: "Reason", Value: shownReason},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+
+	b.postModerationLog(i.GuildID, embed)
+
+	if err := relayToUser(s, order.UserID, []string{removedOrderMessage(*order, reason)}); err != nil {
+		log.Printf("Error telling %s their order was removed: %v", order.UserID, err)
+	}
+}
+
+// removedOrderMessage tells an owner an admin took down one of their orders
+func removedOrderMessage(order database.PlayerOrder, reason string) string {
+	msg := fmt.Sprintf("🛑 An admin removed your order %s.", describeNoticeOrder(order))
+	if reason != "" {
+		msg += fmt.Sprintf("\nReason: %s", reason)
+	}
+	return msg + "\nIf you think this was a mistake, contact the server's admins."
+}
+
+// --- /admin-filter-add, /admin-filter-remove, /admin-filter-list ---
+
+// handleAdminFilterAdd bars a word from notes, names and relayed DMs
+func (b *Bot) handleAdminFilterAdd(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	word := normalizeFilterWord(options["word"].StringValue())
+	if !validFilterWord(word) {
+		b.respondError(s, i, fmt.Sprintf("Filter words must be a single word of %d-%d letters or digits",
+			filterWordMinLength, filterWordMaxLength))
+		return
+	}
+
+	added, err := b.db.AddFilteredWord(context.Background(), word, getUserID(i))
+	if err != nil {
+		log.Printf("Error adding filtered word: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+	b.words.Add(word)
+
+	if !added {
+		b.respondEphemeral(s, i, fmt.Sprintf("||%s|| is already filtered.", word))
+		return
+	}
+	b.respondEphemeral(s, i, fmt.Sprintf("✅ ||%s|| is now filtered. Existing orders aren't changed; use `/admin-order-remove` for those.", word))
+}
+
+// handleAdminFilterRemove lifts a word added with /admin-filter-add
+func (b *Bot) handleAdminFilterRemove(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	word := normalizeFilterWord(options["word"].StringValue())
+
+	removed, err := b.db.RemoveFilteredWord(context.Background(), word, getUserID(i))
+	if err != nil {
+		log.Printf("Error removing filtered word: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+	listed := b.words.Remove(word)
+
+	switch {
+	case listed:
+		b.respondError(s, i, fmt.Sprintf("||%s|| comes from the bot's word list file, so it stays filtered until it's removed there.", word))
+	case !removed:
+		b.respondError(s, i, fmt.Sprintf("||%s|| isn't filtered.", word))
+	default:
+		b.respondEphemeral(s, i, fmt.Sprintf("✅ ||%s|| is no longer filtered.", word))
+	}
+}
+
+// handleAdminFilterList shows every filtered word, spoilered
+func (b *Bot) handleAdminFilterList(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	words := b.words.Words()
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("🚫 Filtered Words (%d)", len(words)),
+		Color: 0xe74c3c,
+	}
+	if len(words) == 0 {
+		embed.Description = "No words are filtered. Add one with `/admin-filter-add`."
+	} else {
+		spoilered := make([]string, len(words))
+		for idx, word := range words {
+			spoilered[idx] = fmt.Sprintf("||%s||", word)
+		}
+		embed.Description = joinLimited(spoilered, ", ", maxEmbedDescription)
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
 // --- /admin-trade-bans ---
 
 func (b *Bot) handleAdminTradeBans(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -209,8 +543,12 @@ func (b *Bot) handleAdminTradeBans(s *discordgo.Session, i *discordgo.Interactio
 			expStr = fmt.Sprintf("<t:%d:R>", ban.ExpiresAt.Unix())
 		}
 
-		value := fmt.Sprintf("Reason: %s\nBanned by: <@%s>\nExpires: %s",
-			ban.Reason, ban.BannedBy, expStr)
+		bannedBy := fmt.Sprintf("<@%s>", ban.BannedBy)
+		if ban.BannedBy == database.SystemUserID {
+			bannedBy = "Automatic (reports)"
+		}
+		value := fmt.Sprintf("Reason: %s\nBanned by: %s\nExpires: %s",
+			ban.Reason, bannedBy, expStr)
 
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:  fmt.Sprintf("Ban #%d — <@%s>", ban.ID, ban.UserID),
@@ -268,7 +606,7 @@ func (b *Bot) handleAdminTradeReports(s *discordgo.Session, i *discordgo.Interac
 
 		value := fmt.Sprintf("Reporter: <@%s>\nReported: <@%s>\nOrder: %s\nReason: %s\nSubmitted: <t:%d:R>",
 			report.ReporterUserID, report.ReportedUserID, orderInfo,
-			report.Reason, report.CreatedAt.Unix())
+			sanitizeUserLine(report.Reason, 0), report.CreatedAt.Unix())
 
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:  fmt.Sprintf("Report #%d", report.ID),
@@ -313,7 +651,7 @@ func (b *Bot) handleAdminTradeReportAction(s *discordgo.Session, i *discordgo.In
 		return
 	}
 
-	adminID := i.Member.User.ID
+	adminID := getUserID(i)
 
 	switch action {
 	case "dismiss":
@@ -325,6 +663,16 @@ func (b *Bot) handleAdminTradeReportAction(s *discordgo.Session, i *discordgo.In
 		}
 		b.respondEphemeral(s, i, fmt.Sprintf("Report #%d dismissed.", reportID))
 
+		b.postModerationLog(i.GuildID, &discordgo.MessageEmbed{
+			Title: fmt.Sprintf("Report #%d — Dismissed", reportID),
+			Color: 0x95a5a6,
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "Reported User", Value: fmt.Sprintf("<@%s>", report.ReportedUserID), Inline: true},
+				{Name: "Dismissed By", Value: fmt.Sprintf("<@%s>", adminID), Inline: true},
+			},
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+
 	case "ban":
 		// Mark report as reviewed
 		err := b.db.UpdateTradeReportStatus(ctx, reportID, "reviewed", adminID)
@@ -341,7 +689,7 @@ func (b *Bot) handleAdminTradeReportAction(s *discordgo.Session, i *discordgo.In
 		}
 
 		// Check if already banned
-		existing, _ := b.db.IsUserBanned(ctx, report.ReportedUserID)
+		existing, _ := b.bans.IsUserBanned(ctx, report.ReportedUserID)
 		if existing != nil {
 			b.respondEphemeral(s, i, fmt.Sprintf("Report #%d reviewed. User <@%s> is already banned.", reportID, report.ReportedUserID))
 			return
@@ -353,15 +701,19 @@ func (b *Bot) handleAdminTradeReportAction(s *discordgo.Session, i *discordgo.In
 			Reason:   reason,
 			BannedBy: adminID,
 		}
-		_, err = b.db.CreateTradeBan(ctx, ban)
+		created, err := b.db.CreateTradeBan(ctx, ban)
 		if err != nil {
 			log.Printf("Error creating ban from report: %v", err)
 			b.respondError(s, i, "Failed to ban user")
 			return
 		}
+		b.bans.Set(*created)
 
 		// Cancel their active orders
 		cancelled, _ := b.db.CancelAllUserOrders(ctx, report.ReportedUserID)
+		if conv, ok := b.tradeConversations.GetByUser(report.ReportedUserID); ok {
+			b.closeConversationForBan(s, conv, report.ReportedUserID)
+		}
 
 		embed := &discordgo.MessageEmbed{
 			Title: fmt.Sprintf("Report #%d — User Banned", reportID),
@@ -382,5 +734,188 @@ func (b *Bot) handleAdminTradeReportAction(s *discordgo.Session, i *discordgo.In
 				Flags:  discordgo.MessageFlagsEphemeral,
 			},
 		})
+
+		b.postModerationLog(i.GuildID, embed)
+	}
+}
+
+// --- /admin-audit-log ---
+
+const (
+	// auditLogDefaultLimit and auditLogMaxLimit bound /admin-audit-log;
+	// an embed holds at most 25 fields
+	auditLogDefaultLimit = 10
+	auditLogMaxLimit     = 25
+	// auditDetailsMaxLen keeps each entry short enough that a full page
+	// stays under Discord's 6000 character embed limit
+	auditDetailsMaxLen = 140
+)
+
+// auditActions are the actions written to audit_log, offered as filter choices
+var auditActions = []string{
+	"submission", "replace_orders", "merge_orders", "expire_orders", "purge_port", "purge_player_orders", "archive_item", "restore_item",
+	"trade_ban", "trade_unban", "trade_ban_expired", "admin_set_name", "admin_clear_name", "admin_remove_order", "word_filter_add", "word_filter_remove", "trade_report", "trade_report_action", "trades_completed",
+}
+
+// formatAuditDetails pretty-prints a JSON details blob as a code block,
+// falling back to the raw text when it isn't JSON
+func formatAuditDetails(details string) string {
+	if strings.TrimSpace(details) == "" {
+		return ""
+	}
+
+	var pretty bytes.Buffer
+	text := details
+	if err := json.Indent(&pretty, []byte(details), "", "  "); err == nil {
+		text = pretty.String()
+	}
+	if runes := []rune(text); len(runes) > auditDetailsMaxLen {
+		text = string(runes[:auditDetailsMaxLen-3]) + "..."
+	}
+	return "```json\n" + text + "\n```"
+}
+
+// buildAuditLogEmbed renders audit entries, one field each
+func buildAuditLogEmbed(entries []database.AuditLog, action string) *discordgo.MessageEmbed {
+	description := fmt.Sprintf("%d most recent entries", len(entries))
+	if action != "" {
+		description += fmt.Sprintf(" for `%s`", action)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Audit Log",
+		Description: description,
+		Color:       0x95a5a6,
+		Timestamp:   time.Now().Format(time.RFC3339),
 	}
+
+	for _, entry := range entries {
+		actor := "system"
+		if entry.UserID != "system" {
+			actor = fmt.Sprintf("<@%s>", entry.UserID)
+		}
+
+		value := fmt.Sprintf("By: %s • <t:%d:f>", actor, entry.Timestamp.Unix())
+		if details := formatAuditDetails(entry.Details); details != "" {
+			value += "\n" + details
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("#%d — %s", entry.ID, entry.Action),
+			Value: value,
+		})
+	}
+
+	return embed
+}
+
+func (b *Bot) handleAdminAuditLog(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	action := ""
+	if opt := options["action"]; opt != nil {
+		action = opt.StringValue()
+	}
+	limit := auditLogDefaultLimit
+	if opt := options["limit"]; opt != nil {
+		limit = int(opt.IntValue())
+	}
+	if limit < 1 || limit > auditLogMaxLimit {
+		b.respondError(s, i, fmt.Sprintf("Limit must be between 1 and %d", auditLogMaxLimit))
+		return
+	}
+
+	ctx := context.Background()
+	entries, err := b.db.GetAuditLog(ctx, action, limit)
+	if err != nil {
+		log.Printf("Error getting audit log: %v", err)
+		b.respondError(s, i, "Failed to retrieve the audit log")
+		return
+	}
+
+	if len(entries) == 0 {
+		b.respondEphemeral(s, i, "No audit log entries found.")
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{buildAuditLogEmbed(entries, action)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// --- /admin-stats ---
+
+// formatWaitTime renders how long something took or has been waiting, to
+// the two largest units
+func formatWaitTime(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "under a minute"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// buildModerationStatsEmbed renders /admin-stats
+func buildModerationStatsEmbed(stats *database.ModerationStats) *discordgo.MessageEmbed {
+	pending := fmt.Sprintf("%d", stats.PendingReports)
+	if stats.PendingReports > 0 {
+		pending += fmt.Sprintf(" (oldest %s)", formatWaitTime(stats.OldestPendingAge))
+	}
+	timeToAction := "No reports actioned yet"
+	if stats.ActionedReports > 0 {
+		timeToAction = fmt.Sprintf("%s over %d report(s)", formatWaitTime(stats.AvgTimeToAction), stats.ActionedReports)
+	}
+
+	color := 0x2ecc71
+	if stats.PendingReports > 0 {
+		color = 0xf39c12
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "🛡️ Moderation Stats",
+		Description: "Weekly figures cover the last 7 days.",
+		Color:       color,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Pending Reports", Value: pending, Inline: true},
+			{Name: "Reports This Week", Value: fmt.Sprintf("%d", stats.ReportsThisWeek), Inline: true},
+			{Name: "Avg. Time to Action", Value: timeToAction, Inline: true},
+			{Name: "Active Bans", Value: fmt.Sprintf("%d", stats.ActiveBans), Inline: true},
+			{Name: "Bans This Week", Value: fmt.Sprintf("%d", stats.BansThisWeek), Inline: true},
+			{Name: "Moderator Actions This Week", Value: fmt.Sprintf("%d", stats.ActionsThisWeek), Inline: true},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+func (b *Bot) handleAdminStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !b.checkAdmin(s, i) {
+		return
+	}
+
+	stats, err := b.db.GetModerationStats(context.Background())
+	if err != nil {
+		log.Printf("Error getting moderation stats: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{buildModerationStatsEmbed(stats)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,16 +15,40 @@ import (
 
 // User Query Handlers
 
+const (
+	// priceDefaultMaxAgeHours hides /price rows older than this unless max-age is given
+	priceDefaultMaxAgeHours = 48
+	// priceStaleAfter is when a /price row is shown de-emphasized
+	priceStaleAfter = 24 * time.Hour
+)
+
+// formatPriceLine renders one /price row, italicizing prices older than priceStaleAfter
+func formatPriceLine(m database.Market, now time.Time) string {
+	age := now.Sub(m.SubmittedAt)
+	line := fmt.Sprintf("**%s**: %s (qty: %d, total %s) - %s",
+		m.Port.DisplayName, formatGold(int64(m.Price)), m.Quantity, formatGold(orderTotal(m.Price, m.Quantity)), formatAge(age))
+	if age > priceStaleAfter {
+		line = fmt.Sprintf("_%s (stale)_", line)
+	}
+	return line
+}
+
 func (b *Bot) handlePrice(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	options := parseOptions(i.ApplicationCommandData().Options)
 	itemName := options["item"].StringValue()
 
-	region := ""
+	var region *database.Region
 	minPrice := 0
 	maxPrice := 0
+	maxAgeHours := priceDefaultMaxAgeHours
+
+	ctx := context.Background()
 
 	if opt := options["region"]; opt != nil {
-		region = opt.StringValue()
+		var ok bool
+		if region, ok = b.resolveRegionOption(ctx, s, i, opt.StringValue()); !ok {
+			return
+		}
 	}
 	if opt := options["min-price"]; opt != nil {
 		minPrice = int(opt.IntValue())
@@ -31,8 +56,12 @@ func (b *Bot) handlePrice(s *discordgo.Session, i *discordgo.InteractionCreate)
 	if opt := options["max-price"]; opt != nil {
 		maxPrice = int(opt.IntValue())
 	}
-
-	ctx := context.Background()
+	if opt := options["max-age"]; opt != nil {
+		if maxAgeHours = int(opt.IntValue()); maxAgeHours < 0 {
+			b.respondError(s, i, "max-age can't be negative")
+			return
+		}
+	}
 
 	// Find item
 	matches, err := b.db.FindItemMatches(ctx, itemName, 1)
@@ -44,18 +73,25 @@ func (b *Bot) handlePrice(s *discordgo.Session, i *discordgo.InteractionCreate)
 	item := matches[0].Item
 
 	// Query prices
-	markets, err := b.db.GetPricesByItem(ctx, item.ID, nil, region, minPrice, maxPrice)
+	regionID := 0
+	if region != nil {
+		regionID = region.ID
+	}
+	markets, err := b.db.GetPricesByItem(ctx, item.ID, nil, regionID, minPrice, maxPrice, maxAgeHours)
 	if err != nil {
 		log.Printf("Error querying prices: %v", err)
-		b.respondError(s, i, "Database error")
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
 	if len(markets) == 0 {
 		filterInfo := ""
-		if region != "" || minPrice > 0 || maxPrice > 0 {
+		if region != nil || minPrice > 0 || maxPrice > 0 {
 			filterInfo = " (with current filters)"
 		}
+		if maxAgeHours > 0 {
+			filterInfo += fmt.Sprintf(" in the last %dh. Use `max-age:0` to include older prices", maxAgeHours)
+		}
 		b.respondError(s, i, fmt.Sprintf("No active orders found for '%s'%s", item.DisplayName, filterInfo))
 		return
 	}
@@ -72,14 +108,23 @@ func (b *Bot) handlePrice(s *discordgo.Session, i *discordgo.InteractionCreate)
 	}
 
 	description := fmt.Sprintf("Showing best prices across all ports")
-	if region != "" {
-		description += fmt.Sprintf(" (Region: %s)", region)
+	if region != nil {
+		description += fmt.Sprintf(" (Region: %s)", region.Name)
+	}
+	if maxAgeHours > 0 {
+		description += fmt.Sprintf("\nPrices from the last %dh", maxAgeHours)
+	}
+
+	// Tags only decorate the embed, so a lookup failure isn't fatal
+	tags, err := b.db.GetItemTags(ctx, item.ID)
+	if err != nil {
+		log.Printf("Error getting item tags: %v", err)
 	}
 
 	embed := &discordgo.MessageEmbed{
-		Title:       fmt.Sprintf("💰 Prices for: %s", item.DisplayName),
+		Title:       fmt.Sprintf("💰 Prices for: %s", tagIconName(item.DisplayName, tags)),
 		Description: description,
-		Color:       0x3498db,
+		Color:       tagEmbedColor(tags, 0x3498db),
 		Timestamp:   time.Now().Format(time.RFC3339),
 	}
 
@@ -89,9 +134,7 @@ func (b *Bot) handlePrice(s *discordgo.Session, i *discordgo.InteractionCreate)
 			if idx >= 5 {
 				break
 			}
-			age := time.Since(m.SubmittedAt)
-			buyText += fmt.Sprintf("**%s**: %d gold (qty: %d) - %s\n",
-				m.Port.DisplayName, m.Price, m.Quantity, formatAge(age))
+			buyText += formatPriceLine(m, time.Now()) + "\n"
 		}
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:  "Buy Orders",
@@ -105,9 +148,7 @@ func (b *Bot) handlePrice(s *discordgo.Session, i *discordgo.InteractionCreate)
 			if idx >= 5 {
 				break
 			}
-			age := time.Since(m.SubmittedAt)
-			sellText += fmt.Sprintf("**%s**: %d gold (qty: %d) - %s\n",
-				m.Port.DisplayName, m.Price, m.Quantity, formatAge(age))
+			sellText += formatPriceLine(m, time.Now()) + "\n"
 		}
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:  "Sell Orders",
@@ -123,9 +164,55 @@ func (b *Bot) handlePrice(s *discordgo.Session, i *discordgo.InteractionCreate)
 	})
 }
 
+// handleBestRoute finds the cheapest port to buy an item and the best port to sell it
+func (b *Bot) handleBestRoute(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := parseOptions(i.ApplicationCommandData().Options)
+	itemName := options["item"].StringValue()
+
+	ctx := context.Background()
+
+	matches, err := b.db.FindItemMatches(ctx, itemName, 1)
+	if err != nil || len(matches) == 0 {
+		b.respondError(s, i, fmt.Sprintf("Item not found: %s", itemName))
+		return
+	}
+	item := matches[0].Item
+
+	markets, err := b.db.GetPricesByItem(ctx, item.ID, nil, 0, 0, 0, 0)
+	if err != nil {
+		log.Printf("Error querying prices: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+	if len(markets) == 0 {
+		b.respondError(s, i, fmt.Sprintf("No active orders found for '%s'", item.DisplayName))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{buildBestRouteEmbed(item, findBestRoute(markets), time.Now())},
+		},
+	})
+}
+
+// portFilteredMaxFields is how many fields one order type may fill when
+// /port is filtered to it, keeping the embed under Discord's 6000-character
+// total. Unfiltered boards split this between buy and sell.
+const portFilteredMaxFields = 5
+
 func (b *Bot) handlePortView(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	options := parseOptions(i.ApplicationCommandData().Options)
 	portName := options["name"].StringValue()
+	orderType := ""
+	if opt := options["order-type"]; opt != nil {
+		orderType = opt.StringValue()
+	}
+	itemFilter := ""
+	if opt := options["item"]; opt != nil {
+		itemFilter = strings.TrimSpace(opt.StringValue())
+	}
 
 	ctx := context.Background()
 
@@ -139,30 +226,46 @@ func (b *Bot) handlePortView(s *discordgo.Session, i *discordgo.InteractionCreat
 	port := matches[0].Port
 
 	// Get orders
-	markets, err := b.db.GetOrdersByPort(ctx, port.ID)
+	markets, err := b.db.GetOrdersByPortFiltered(ctx, port.ID, orderType, itemFilter)
 	if err != nil {
 		log.Printf("Error querying port: %v", err)
-		b.respondError(s, i, "Database error")
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
 	if len(markets) == 0 {
-		b.respondError(s, i, fmt.Sprintf("No active orders found for port '%s'", port.DisplayName))
+		what := "active orders"
+		if orderType != "" {
+			what = fmt.Sprintf("active %s orders", orderType)
+		}
+		if itemFilter != "" {
+			what += fmt.Sprintf(" matching '%s'", itemFilter)
+		}
+		b.respondError(s, i, fmt.Sprintf("No %s found for port '%s'", what, port.DisplayName))
 		return
 	}
 
 	// Group by buy/sell
-	buyOrders := []database.Market{}
-	sellOrders := []database.Market{}
+	var buyLines, sellLines []string
 	for _, m := range markets {
+		line := fmt.Sprintf("**%s**: %s (qty: %d)", m.Item.DisplayName, formatGold(int64(m.Price)), m.Quantity)
 		if m.OrderType == "buy" {
-			buyOrders = append(buyOrders, m)
+			buyLines = append(buyLines, line)
 		} else {
-			sellOrders = append(sellOrders, m)
+			sellLines = append(sellLines, line)
 		}
 	}
 
 	description := "All active market orders"
+	switch orderType {
+	case "buy":
+		description = "Active buy orders"
+	case "sell":
+		description = "Active sell orders"
+	}
+	if itemFilter != "" {
+		description += fmt.Sprintf(" matching '%s'", itemFilter)
+	}
 	if port.Region != "" {
 		description += fmt.Sprintf(" (Region: %s)", port.Region)
 	}
@@ -174,127 +277,222 @@ func (b *Bot) handlePortView(s *discordgo.Session, i *discordgo.InteractionCreat
 		Timestamp:   time.Now().Format(time.RFC3339),
 	}
 
-	if len(buyOrders) > 0 {
-		buyText := ""
-		for _, m := range buyOrders {
-			buyText += fmt.Sprintf("**%s**: %d gold (qty: %d)\n", m.Item.DisplayName, m.Price, m.Quantity)
-		}
-		if len(buyText) > 1024 {
-			buyText = buyText[:1021] + "..."
+	// Both types share the embed's size limit; a single type gets all of it
+	maxFields := portFilteredMaxFields / 2
+	if orderType != "" {
+		maxFields = portFilteredMaxFields
+	}
+	truncated := false
+	addOrders := func(label string, lines []string) {
+		fields, cut := embedFieldChunks(label, lines, "\n", maxFields)
+		embed.Fields = append(embed.Fields, fields...)
+		truncated = truncated || cut
+	}
+	addOrders("Buy Orders", buyLines)
+	addOrders("Sell Orders", sellLines)
+
+	if truncated {
+		hint := "Some orders didn't fit. "
+		if orderType == "" {
+			hint += "Use order-type to see one side in full, or item to narrow the list."
+		} else {
+			hint += "Use item to narrow the list."
 		}
-		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-			Name:  "Buy Orders",
-			Value: buyText,
-		})
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: hint}
 	}
 
-	if len(sellOrders) > 0 {
-		sellText := ""
-		for _, m := range sellOrders {
-			sellText += fmt.Sprintf("**%s**: %d gold (qty: %d)\n", m.Item.DisplayName, m.Price, m.Quantity)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+}
+
+// handlePortCompare shows two ports' boards side by side
+func (b *Bot) handlePortCompare(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := parseOptions(i.ApplicationCommandData().Options)
+	ctx := context.Background()
+
+	var ports [2]*database.Port
+	var boards [2][]database.Market
+	for idx, key := range []string{"port-a", "port-b"} {
+		name := options[key].StringValue()
+		matches, err := b.db.FindPortMatches(ctx, name, 1)
+		if err != nil || len(matches) == 0 {
+			b.respondError(s, i, fmt.Sprintf("Port not found: %s", name))
+			return
 		}
-		if len(sellText) > 1024 {
-			sellText = sellText[:1021] + "..."
+		ports[idx] = matches[0].Port
+
+		boards[idx], err = b.db.GetOrdersByPort(ctx, ports[idx].ID)
+		if err != nil {
+			log.Printf("Error querying port: %v", err)
+			b.respondError(s, i, t(i.Locale, "error.database"))
+			return
 		}
-		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-			Name:  "Sell Orders",
-			Value: sellText,
-		})
+	}
+
+	if ports[0].ID == ports[1].ID {
+		b.respondError(s, i, "Pick two different ports to compare")
+		return
+	}
+	if len(boards[0]) == 0 && len(boards[1]) == 0 {
+		b.respondError(s, i, fmt.Sprintf("No active orders found for '%s' or '%s'", ports[0].DisplayName, ports[1].DisplayName))
+		return
 	}
 
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{embed},
+			Embeds: []*discordgo.MessageEmbed{buildPortCompareEmbed(ports[0], ports[1], boards[0], boards[1])},
 		},
 	})
 }
 
 func (b *Bot) handlePortsList(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	options := parseOptions(i.ApplicationCommandData().Options)
-	region := ""
+	ctx := context.Background()
+
+	var region *database.Region
 	if opt := options["region"]; opt != nil {
-		region = opt.StringValue()
+		var ok bool
+		if region, ok = b.resolveRegionOption(ctx, s, i, opt.StringValue()); !ok {
+			return
+		}
 	}
 
-	ctx := context.Background()
-	ports, err := b.db.GetAllPorts(ctx)
+	ports, err := b.portsInRegion(ctx, region)
 	if err != nil {
 		log.Printf("Error getting ports: %v", err)
-		b.respondError(s, i, "Database error")
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
-	if len(ports) == 0 {
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "No ports found",
-			},
-		})
+	regionID := 0
+	if region != nil {
+		regionID = region.ID
+	}
+	pages := buildPortsPages(ports, region)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{pages[0]},
+			Components: portsPageComponents(0, len(pages), regionID),
+		},
+	})
+}
+
+// handlePortsPage switches a /ports message to another page
+func (b *Bot) handlePortsPage(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) {
+	var regionID, page int
+	if _, err := fmt.Sscanf(strings.TrimPrefix(customID, "ports_page:"), "%d:%d", &regionID, &page); err != nil {
+		b.respondError(s, i, t(i.Locale, "error.invalid_page"))
 		return
 	}
 
-	// Filter by region if specified
-	if region != "" {
-		filtered := []database.Port{}
-		for _, port := range ports {
-			if strings.EqualFold(port.Region, region) {
-				filtered = append(filtered, port)
+	ctx := context.Background()
+	var region *database.Region
+	if regionID != 0 {
+		regions, err := b.db.GetAllRegions(ctx)
+		if err != nil {
+			log.Printf("Error getting regions: %v", err)
+			b.respondError(s, i, t(i.Locale, "error.database"))
+			return
+		}
+		for idx := range regions {
+			if regions[idx].ID == regionID {
+				region = &regions[idx]
 			}
 		}
-		ports = filtered
-	}
-
-	// Group by region
-	byRegion := make(map[string][]string)
-	for _, port := range ports {
-		reg := port.Region
-		if reg == "" {
-			reg = "Unknown"
+		if region == nil {
+			b.respondError(s, i, "That region no longer exists")
+			return
 		}
-		byRegion[reg] = append(byRegion[reg], port.DisplayName)
 	}
 
-	title := "🗺️ All Ports"
-	if region != "" {
-		title = fmt.Sprintf("🗺️ Ports in %s", region)
+	ports, err := b.portsInRegion(ctx, region)
+	if err != nil {
+		log.Printf("Error getting ports: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
 	}
 
-	embed := &discordgo.MessageEmbed{
-		Title:       title,
-		Description: fmt.Sprintf("Total: %d ports", len(ports)),
-		Color:       0x2ecc71,
+	// Ports may have changed since the list was sent; stay in range
+	pages := buildPortsPages(ports, region)
+	if page < 0 {
+		page = 0
 	}
-
-	for reg, portList := range byRegion {
-		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-			Name:   reg,
-			Value:  strings.Join(portList, ", "),
-			Inline: false,
-		})
+	if page >= len(pages) {
+		page = len(pages) - 1
 	}
 
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Type: discordgo.InteractionResponseUpdateMessage,
 		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{embed},
+			Embeds:     []*discordgo.MessageEmbed{pages[page]},
+			Components: portsPageComponents(page, len(pages), regionID),
 		},
 	})
 }
 
+// portsInRegion returns every port, or only region's when it isn't nil
+func (b *Bot) portsInRegion(ctx context.Context, region *database.Region) ([]database.Port, error) {
+	ports, err := b.db.GetAllPorts(ctx)
+	if err != nil || region == nil {
+		return ports, err
+	}
+
+	var filtered []database.Port
+	for _, port := range ports {
+		if port.RegionID == region.ID {
+			filtered = append(filtered, port)
+		}
+	}
+	return filtered, nil
+}
+
+// tagMatchLabel describes the /items tag mode for embeds
+func tagMatchLabel(matchAll bool) string {
+	if matchAll {
+		return "all of"
+	}
+	return "any of"
+}
+
 func (b *Bot) handleItemsList(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	options := parseOptions(i.ApplicationCommandData().Options)
 	tagsStr := ""
 	if opt := options["tags"]; opt != nil {
 		tagsStr = opt.StringValue()
 	}
+	matchAll := false
+	if opt := options["mode"]; opt != nil {
+		matchAll = opt.StringValue() == "all"
+	}
 
 	ctx := context.Background()
 
 	if tagsStr == "" {
-		// Show all items grouped by tags
-		b.respondError(s, i, "Tag filtering not yet fully implemented")
+		// Show all tags grouped by category as a browsable catalog
+		counts, err := b.db.GetItemCountsByTag(ctx)
+		if err != nil {
+			log.Printf("Error counting items by tag: %v", err)
+			b.respondError(s, i, t(i.Locale, "error.database"))
+			return
+		}
+		if len(counts) == 0 {
+			b.respondError(s, i, "No tags have been created yet")
+			return
+		}
+
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds:     []*discordgo.MessageEmbed{buildTagCatalogEmbed(counts)},
+				Components: tagSelectComponents(counts, 0),
+			},
+		})
 		return
 	}
 
@@ -304,7 +502,7 @@ func (b *Bot) handleItemsList(s *discordgo.Session, i *discordgo.InteractionCrea
 
 	allTags, err := b.db.GetAllTags(ctx, "")
 	if err != nil {
-		b.respondError(s, i, "Database error")
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
@@ -324,9 +522,9 @@ func (b *Bot) handleItemsList(s *discordgo.Session, i *discordgo.InteractionCrea
 	}
 
 	// Query items with these tags
-	markets, err := b.db.GetOrdersByTags(ctx, tagIDs, "")
+	markets, err := b.db.GetOrdersByTags(ctx, tagIDs, 0, matchAll)
 	if err != nil {
-		b.respondError(s, i, "Database error")
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
@@ -347,7 +545,7 @@ func (b *Bot) handleItemsList(s *discordgo.Session, i *discordgo.InteractionCrea
 
 	embed := &discordgo.MessageEmbed{
 		Title:       "📦 Items",
-		Description: fmt.Sprintf("Items tagged with: %s", tagsStr),
+		Description: fmt.Sprintf("Items tagged with %s: %s", tagMatchLabel(matchAll), tagsStr),
 		Color:       0xe74c3c,
 		Fields: []*discordgo.MessageEmbedField{
 			{
@@ -365,52 +563,273 @@ func (b *Bot) handleItemsList(s *discordgo.Session, i *discordgo.InteractionCrea
 	})
 }
 
+// handleItemInfo shows everything known about a single item
+func (b *Bot) handleItemInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := parseOptions(i.ApplicationCommandData().Options)
+	itemName := options["item"].StringValue()
+
+	ctx := context.Background()
+	matches, err := b.db.FindItemMatches(ctx, itemName, 1)
+	if err != nil || len(matches) == 0 {
+		b.respondError(s, i, fmt.Sprintf("Item not found: %s", itemName))
+		return
+	}
+	item := matches[0].Item
+
+	tags, err := b.db.GetItemTags(ctx, item.ID)
+	if err != nil {
+		log.Printf("Error getting item tags: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	aliases, err := b.db.GetItemAliases(ctx, item.ID)
+	if err != nil {
+		log.Printf("Error getting item aliases: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	markets, err := b.db.GetPricesByItem(ctx, item.ID, nil, 0, 0, 0, 0)
+	if err != nil {
+		log.Printf("Error querying prices: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+	bestBuy, bestSell := bestItemPrices(markets)
+
+	aliasText := "None"
+	if len(aliases) > 0 {
+		names := make([]string, len(aliases))
+		for idx, a := range aliases {
+			names[idx] = a.Alias
+		}
+		aliasText = strings.Join(names, ", ")
+	}
+
+	tagged := "No"
+	if item.IsTagged {
+		tagged = "Yes"
+	}
+
+	description := ""
+	if matches[0].MatchedVia == "fuzzy" {
+		description = fmt.Sprintf("Closest match for '%s'", itemName)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("📦 %s", tagIconName(item.DisplayName, tags)),
+		Description: description,
+		Color:       tagEmbedColor(tags, 0x3498db),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Tags", Value: formatTagList(tags), Inline: true},
+			{Name: "Aliases", Value: aliasText, Inline: true},
+			{Name: "Tagged", Value: tagged, Inline: true},
+			{Name: "Best Buy Order (sell here)", Value: formatBestPrice(bestBuy)},
+			{Name: "Best Sell Order (buy here)", Value: formatBestPrice(bestSell)},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+}
+
+// handleItemsTagSelect lists the items carrying the tag picked in the /items catalog
+func (b *Bot) handleItemsTagSelect(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.MessageComponentData()
+	if len(data.Values) == 0 {
+		return
+	}
+	tagID, err := strconv.Atoi(data.Values[0])
+	if err != nil {
+		b.respondError(s, i, "Invalid tag")
+		return
+	}
+
+	ctx := context.Background()
+	counts, err := b.db.GetItemCountsByTag(ctx)
+	if err != nil {
+		log.Printf("Error counting items by tag: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	var tag *database.Tag
+	for idx := range counts {
+		if counts[idx].Tag.ID == tagID {
+			tag = &counts[idx].Tag
+			break
+		}
+	}
+	if tag == nil {
+		b.respondError(s, i, "That tag no longer exists")
+		return
+	}
+
+	items, err := b.db.GetItemsByTag(ctx, tagID)
+	if err != nil {
+		log.Printf("Error getting items by tag: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	names := make([]string, len(items))
+	for idx, item := range items {
+		names[idx] = item.DisplayName
+	}
+	value := "No items"
+	if len(names) > 0 {
+		value = joinLimited(names, ", ", maxEmbedFieldValue)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("📦 %s", tagIconName(tag.Name, []database.Tag{*tag})),
+		Description: "Use `/item-info` for an item's details and prices.",
+		Color:       tagEmbedColor([]database.Tag{*tag}, 0xe74c3c),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: fmt.Sprintf("%d item(s)", len(items)), Value: value},
+		},
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: tagSelectComponents(counts, tagID),
+		},
+	})
+}
+
+// leaderboardPeriods maps the /leaderboard period choice to a window in days
+var leaderboardPeriods = map[string]int{"week": 7, "month": 30}
+
+// buildLeaderboardEmbed ranks contributors for /leaderboard
+func buildLeaderboardEmbed(contributors []database.Contributor, period string) *discordgo.MessageEmbed {
+	medals := []string{"🥇", "🥈", "🥉"}
+
+	var lines []string
+	for idx, c := range contributors {
+		rank := fmt.Sprintf("%d.", idx+1)
+		if idx < len(medals) {
+			rank = medals[idx]
+		}
+		plural := "s"
+		if c.Submissions == 1 {
+			plural = ""
+		}
+		lines = append(lines, fmt.Sprintf("%s <@%s> — %d submission%s", rank, c.UserID, c.Submissions, plural))
+	}
+
+	description := "No submissions yet. Use `/submit` to share a market screenshot!"
+	if len(lines) > 0 {
+		description = strings.Join(lines, "\n")
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🏆 Top Contributors This %s", strings.ToUpper(period[:1])+period[1:]),
+		Description: description,
+		Color:       0xf1c40f,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Counts market boards submitted with /submit"},
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+}
+
+func (b *Bot) handleLeaderboard(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := parseOptions(i.ApplicationCommandData().Options)
+	period := "week"
+	if opt := options["period"]; opt != nil {
+		period = opt.StringValue()
+	}
+	days, ok := leaderboardPeriods[period]
+	if !ok {
+		b.respondError(s, i, fmt.Sprintf("Unknown period: %s", period))
+		return
+	}
+
+	contributors, err := b.db.GetTopContributors(context.Background(), days)
+	if err != nil {
+		log.Printf("Error getting top contributors: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{buildLeaderboardEmbed(contributors, period)},
+		},
+	})
+}
+
+// statsFields are the /stats counters in display order
+var statsFields = []struct{ key, label string }{
+	{"total_orders", "Active Orders"},
+	{"unique_ports", "Ports Tracked"},
+	{"total_ports", "Total Ports"},
+	{"total_items", "Total Items"},
+	{"untagged_items", "Untagged Items"},
+	{"submissions_today", "Submissions Today"},
+	{"completed_trades", "Completed Trades"},
+}
+
 func (b *Bot) handleStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := parseOptions(i.ApplicationCommandData().Options)
 	ctx := context.Background()
-	stats, err := b.db.GetStats(ctx)
+
+	var region *database.Region
+	if opt := options["region"]; opt != nil {
+		var ok bool
+		if region, ok = b.resolveRegionOption(ctx, s, i, opt.StringValue()); !ok {
+			return
+		}
+	}
+
+	regionID := 0
+	title := "📊 Bot Statistics"
+	if region != nil {
+		regionID = region.ID
+		title = fmt.Sprintf("📊 Bot Statistics — Region: %s", region.Name)
+	}
+
+	stats, err := b.db.GetRegionStats(ctx, regionID)
 	if err != nil {
 		log.Printf("Error getting stats: %v", err)
-		b.respondError(s, i, "Database error")
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
 	embed := &discordgo.MessageEmbed{
-		Title:       "📊 Bot Statistics",
+		Title:       title,
 		Description: "World of Sea Battle Market Tracker",
 		Color:       0xe67e22,
-		Fields: []*discordgo.MessageEmbedField{
-			{
-				Name:   "Active Orders",
-				Value:  fmt.Sprintf("%d", stats["total_orders"]),
-				Inline: true,
-			},
-			{
-				Name:   "Ports Tracked",
-				Value:  fmt.Sprintf("%d", stats["unique_ports"]),
-				Inline: true,
-			},
-			{
-				Name:   "Total Ports",
-				Value:  fmt.Sprintf("%d", stats["total_ports"]),
-				Inline: true,
-			},
-			{
-				Name:   "Total Items",
-				Value:  fmt.Sprintf("%d", stats["total_items"]),
-				Inline: true,
-			},
-			{
-				Name:   "Untagged Items",
-				Value:  fmt.Sprintf("%d", stats["untagged_items"]),
-				Inline: true,
-			},
-			{
-				Name:   "Submissions Today",
-				Value:  fmt.Sprintf("%d", stats["submissions_today"]),
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	// Region stats only carry some of these, so add whichever are present
+	for _, f := range statsFields {
+		if value, ok := stats[f.key]; ok {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   f.label,
+				Value:  fmt.Sprintf("%d", value),
 				Inline: true,
-			},
-		},
-		Timestamp: time.Now().Format(time.RFC3339),
+			})
+		}
+	}
+
+	if stale, ok := stats["stale_ports"].(int); ok {
+		freshness := fmt.Sprintf("✅ Every tracked port was updated in the last %d days", database.StaleMarketDays)
+		if stale > 0 {
+			freshness = fmt.Sprintf("⚠️ %d port(s) need fresh screenshots (no update in %d days)", stale, database.StaleMarketDays)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Data Freshness",
+			Value: freshness,
+		})
 	}
 
 	if lastUpdate, ok := stats["last_update"].(time.Time); ok {
@@ -427,3 +846,143 @@ func (b *Bot) handleStats(s *discordgo.Session, i *discordgo.InteractionCreate)
 		},
 	})
 }
+
+// handlePortExport sends a port's current board as a file attachment
+func (b *Bot) handlePortExport(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := parseOptions(i.ApplicationCommandData().Options)
+	portName := options["port"].StringValue()
+	format := "text"
+	if opt := options["format"]; opt != nil {
+		format = opt.StringValue()
+	}
+	public := false
+	if opt := options["public"]; opt != nil {
+		public = opt.BoolValue()
+	}
+
+	ctx := context.Background()
+
+	matches, err := b.db.FindPortMatches(ctx, portName, 1)
+	if err != nil || len(matches) == 0 {
+		b.respondError(s, i, fmt.Sprintf("Port not found: %s", portName))
+		return
+	}
+	port := matches[0].Port
+
+	markets, err := b.db.GetOrdersByPort(ctx, port.ID)
+	if err != nil {
+		log.Printf("Error querying port: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+	if len(markets) == 0 {
+		b.respondError(s, i, fmt.Sprintf("No active orders found for port '%s'", port.DisplayName))
+		return
+	}
+
+	var content, ext, contentType string
+	switch format {
+	case "csv":
+		content, err = formatPortBoardCSV(*port, markets)
+		if err != nil {
+			log.Printf("Error formatting port export: %v", err)
+			b.respondError(s, i, "Failed to build export")
+			return
+		}
+		ext, contentType = "csv", "text/csv"
+	default:
+		content = formatPortBoardText(*port, markets, time.Now())
+		ext, contentType = "txt", "text/plain"
+	}
+
+	if len(content) > portExportMaxBytes {
+		b.respondError(s, i, "This board is too large to export as a Discord attachment")
+		return
+	}
+
+	var flags discordgo.MessageFlags
+	if !public {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("📄 %s board (%d orders)", port.DisplayName, len(markets)),
+			Files: []*discordgo.File{
+				{
+					Name:        fmt.Sprintf("%s_%s.%s", strings.ReplaceAll(port.Name, " ", "_"), time.Now().UTC().Format("20060102"), ext),
+					ContentType: contentType,
+					Reader:      strings.NewReader(content),
+				},
+			},
+			Flags: flags,
+		},
+	})
+}
+
+// handleOverview shows the first page of the cached market overview
+func (b *Bot) handleOverview(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	overview, err := b.overview.Get(context.Background())
+	if err != nil {
+		log.Printf("Error building market overview: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	pages := buildOverviewPages(overview)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{pages[0]},
+			Components: overviewPageComponents(0, len(pages)),
+		},
+	})
+}
+
+// handleMarketOverview shows the cached overview as a single dashboard embed
+func (b *Bot) handleMarketOverview(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	overview, err := b.overview.Get(context.Background())
+	if err != nil {
+		log.Printf("Error building market overview: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{buildMarketDashboard(overview)},
+		},
+	})
+}
+
+// handleOverviewPage switches the overview message to another page
+func (b *Bot) handleOverviewPage(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) {
+	page, err := strconv.Atoi(strings.TrimPrefix(customID, "overview_page:"))
+	if err != nil {
+		b.respondError(s, i, t(i.Locale, "error.invalid_page"))
+		return
+	}
+
+	overview, err := b.overview.Get(context.Background())
+	if err != nil {
+		log.Printf("Error building market overview: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	pages := buildOverviewPages(overview)
+	if page < 0 || page >= len(pages) {
+		b.respondError(s, i, t(i.Locale, "error.invalid_page"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{pages[page]},
+			Components: overviewPageComponents(page, len(pages)),
+		},
+	})
+}
@@ -2,6 +2,7 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -10,10 +11,143 @@ import (
 	"time"
 
 	"wosbTrade/internal/database"
+	"wosbTrade/internal/ocr"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+// duplicateSubmissionWindow is how far back identical screenshots trigger a warning
+const duplicateSubmissionWindow = 1 * time.Hour
+
+// submissionImageOptions are the /submit attachment options, in board order
+var submissionImageOptions = []string{"screenshot", "screenshot-2", "screenshot-3", "screenshot-4"}
+
+// submissionImageTypes are the attachment content types OCR can read
+var submissionImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+}
+
+// defaultMaxImageBytes caps /submit attachments unless SUBMISSION_MAX_IMAGE_MB
+// says otherwise; ocr.MaxImageBytes is the hard ceiling
+const defaultMaxImageBytes = 10 << 20
+
+// similarScreenshotMaxDistance is the largest perceptual hash distance (out of 64 bits)
+// at which two screenshots are considered near-duplicates
+const similarScreenshotMaxDistance = 6
+
+// respondSubmissionExpired answers an interaction whose submission is gone:
+// it timed out, was finished from another message, or the bot restarted and
+// lost it. Clicks on the submission's own message replace it so the stale
+// controls can't be used again; modals get an ephemeral reply.
+func (b *Bot) respondSubmissionExpired(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionMessageComponent {
+		b.respondEphemeral(s, i, t(i.Locale, "submit.expired"))
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    t(i.Locale, "submit.expired"),
+			Embeds:     []*discordgo.MessageEmbed{},
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+// notifySubmissionExpired tells a user their submission timed out. The
+// confirmation message is replaced so its controls go away; if that fails
+// (the interaction token only lasts 15 minutes) the user gets a DM instead.
+func notifySubmissionExpired(s *discordgo.Session, sub *PendingSubmission) {
+	var locale discordgo.Locale
+	if sub.Interaction != nil {
+		locale = sub.Interaction.Locale
+		content := t(locale, "submit.expired")
+		_, err := s.InteractionResponseEdit(sub.Interaction, &discordgo.WebhookEdit{
+			Content:    &content,
+			Embeds:     &[]*discordgo.MessageEmbed{},
+			Components: &[]discordgo.MessageComponent{},
+		})
+		if err == nil {
+			return
+		}
+		log.Printf("Error editing expired submission for %s: %v", sub.UserID, err)
+	}
+
+	ch, err := s.UserChannelCreate(sub.UserID)
+	if err != nil {
+		log.Printf("Error opening DM to %s: %v", sub.UserID, err)
+		return
+	}
+	if _, err := s.ChannelMessageSend(ch.ID, t(locale, "submit.timed_out_dm")); err != nil {
+		log.Printf("Error notifying %s of expired submission: %v", sub.UserID, err)
+	}
+}
+
+// validateSubmissionAttachment returns why an attachment can't be used as a
+// screenshot, or "" if it can
+func validateSubmissionAttachment(locale discordgo.Locale, att *discordgo.MessageAttachment, maxBytes int) string {
+	contentType := strings.ToLower(strings.TrimSpace(strings.SplitN(att.ContentType, ";", 2)[0]))
+	switch {
+	case contentType == "image/gif":
+		return t(locale, "submit.image_gif", att.Filename)
+	case !submissionImageTypes[contentType]:
+		return t(locale, "submit.image_type", att.Filename)
+	case att.Size > maxBytes:
+		return t(locale, "submit.image_size", att.Filename, formatMegabytes(att.Size), formatMegabytes(maxBytes))
+	}
+	return ""
+}
+
+// formatMegabytes renders a byte count as megabytes with one decimal
+func formatMegabytes(n int) string {
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
+
+// submissionImageErrorMessage explains why screenshot idx of count was
+// rejected before OCR
+func submissionImageErrorMessage(locale discordgo.Locale, idx, count int, err error) string {
+	which := t(locale, "submit.which_only")
+	if count > 1 {
+		which = t(locale, "submit.which_nth", idx+1)
+	}
+	switch {
+	case errors.Is(err, ocr.ErrImageTooSmall), errors.Is(err, ocr.ErrImageTooLarge):
+		return t(locale, "submit.image_unusable", which, err)
+	case errors.Is(err, ocr.ErrUnsupportedImage):
+		return t(locale, "submit.image_unreadable", which)
+	default:
+		return t(locale, "submit.image_failed", which)
+	}
+}
+
+// submissionInProgressMessage tells a user to finish their current submission
+// before starting another. existing may be nil if it just went away.
+func submissionInProgressMessage(locale discordgo.Locale, existing *PendingSubmission) string {
+	if existing == nil {
+		return t(locale, "submit.in_progress")
+	}
+	return t(locale, "submit.in_progress_until", existing.ExpiresAt.Unix())
+}
+
+// guildSubmissionTimeout is the guild's configured confirmation timeout, or 0
+// to use the bot's default
+func (b *Bot) guildSubmissionTimeout(ctx context.Context, guildID string) time.Duration {
+	if guildID == "" {
+		return 0
+	}
+	settings, err := b.db.GetGuildSettings(ctx, guildID)
+	if err != nil {
+		log.Printf("Error fetching guild settings: %v", err)
+		return 0
+	}
+	if settings == nil || settings.SubmissionTimeoutMinutes == 0 {
+		return 0
+	}
+	return clampSubmissionTimeout(time.Duration(settings.SubmissionTimeoutMinutes) * time.Minute)
+}
+
 // handleSubmit processes screenshot submissions with port and item confirmation
 func (b *Bot) handleSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	// Defer response to allow processing time
@@ -21,82 +155,472 @@ func (b *Bot) handleSubmit(s *discordgo.Session, i *discordgo.InteractionCreate)
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 	})
 
+	// One submission at a time: they share the user's confirmation state
+	userID := getUserID(i)
+	if existing, ok := b.submissionManager.Get(userID); ok {
+		b.followUpError(s, i, submissionInProgressMessage(i.Locale, existing))
+		return
+	}
+
 	options := parseOptions(i.ApplicationCommandData().Options)
 	orderType := options["order-type"].StringValue()
-	attachmentID := options["screenshot"].Value.(string)
 
-	// Get attachment
-	var attachment *discordgo.MessageAttachment
-	for _, att := range i.ApplicationCommandData().Resolved.Attachments {
-		if att.ID == attachmentID {
-			attachment = att
-			break
+	// Collect attachments in board order
+	var attachments []*discordgo.MessageAttachment
+	for _, name := range submissionImageOptions {
+		opt, ok := options[name]
+		if !ok {
+			continue
 		}
-	}
 
-	if attachment == nil {
-		b.followUpError(s, i, "Could not find attached image")
-		return
-	}
+		attachment := i.ApplicationCommandData().Resolved.Attachments[opt.Value.(string)]
+		if attachment == nil {
+			b.followUpError(s, i, t(i.Locale, "submit.image_missing"))
+			return
+		}
 
-	// Validate image type
-	if !strings.HasPrefix(attachment.ContentType, "image/") {
-		b.followUpError(s, i, "Attachment must be an image (PNG, JPEG, WebP)")
-		return
-	}
+		// Check type and size before downloading anything
+		if msg := validateSubmissionAttachment(i.Locale, attachment, b.maxImageBytes); msg != "" {
+			b.followUpError(s, i, msg)
+			return
+		}
 
-	// Download image
-	userID := i.Member.User.ID
-	imagePath := filepath.Join(b.imagePath, fmt.Sprintf("%s_%d_%s", userID, time.Now().Unix(), attachment.Filename))
+		attachments = append(attachments, attachment)
+	}
 
-	if err := downloadFile(attachment.URL, imagePath); err != nil {
-		log.Printf("Error downloading image: %v", err)
-		b.followUpError(s, i, "Failed to download image")
-		return
+	// Download images
+	var imagePaths []string
+	for idx, attachment := range attachments {
+		imagePath := filepath.Join(b.imagePath, fmt.Sprintf("%s_%d_%d_%s", userID, time.Now().Unix(), idx, attachment.Filename))
+		if err := downloadFile(attachment.URL, imagePath); err != nil {
+			log.Printf("Error downloading image: %v", err)
+			for _, path := range imagePaths {
+				os.Remove(path)
+			}
+			b.followUpError(s, i, t(i.Locale, "submit.image_download_failed"))
+			return
+		}
+		imagePaths = append(imagePaths, imagePath)
 	}
 
-	// Hash the image
+	// Hash the first image as uploaded; it identifies the submission for
+	// duplicate checks
+	imagePath := imagePaths[0]
 	imgHash, err := hashImage(imagePath)
 	if err != nil {
 		log.Printf("Error hashing image: %v", err)
 		imgHash = "unknown"
 	}
 
-	// Analyze with Claude
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
-
-	marketData, err := b.claudeClient.AnalyzeScreenshot(ctx, imagePath)
-	if err != nil {
-		log.Printf("Error analyzing screenshot: %v", err)
-		os.Remove(imagePath)
-		b.followUpError(s, i, fmt.Sprintf("Failed to analyze screenshot: %v", err))
-		return
+	// Reject unreadable screenshots and shrink oversized ones before OCR
+	for idx, path := range imagePaths {
+		if err := ocr.PrepareImage(path); err != nil {
+			log.Printf("Error preparing screenshot %d: %v", idx+1, err)
+			for _, p := range imagePaths {
+				os.Remove(p)
+			}
+			b.followUpError(s, i, submissionImageErrorMessage(i.Locale, idx, len(imagePaths), err))
+			return
+		}
 	}
 
-	// Validate order type matches detected type
-	if marketData.OrderType != orderType {
-		os.Remove(imagePath)
-		b.followUpError(s, i, fmt.Sprintf(
-			"Order type mismatch: you selected '%s' but the screenshot shows '%s' orders",
-			orderType, marketData.OrderType,
-		))
-		return
-	}
+	ctx := context.Background()
 
-	// Create pending submission
-	submission := b.submissionManager.Create(
+	// Create pending submission (OCR result is filled in after analysis)
+	submission, err := b.submissionManager.Create(
 		userID,
 		i.ChannelID,
 		i.Interaction.ID,
-		imagePath,
+		imagePaths,
 		imgHash,
 		orderType,
-		marketData,
+		nil,
+		b.guildSubmissionTimeout(ctx, i.GuildID),
 	)
+	if err != nil {
+		// Another /submit started while this one was downloading
+		for _, p := range imagePaths {
+			os.Remove(p)
+		}
+		existing, _ := b.submissionManager.Get(userID)
+		b.followUpError(s, i, submissionInProgressMessage(i.Locale, existing))
+		return
+	}
+	submission.Interaction = i.Interaction
+	if opt, ok := options["preview"]; ok {
+		submission.Preview = opt.BoolValue()
+	}
+
+	// Hash for near-duplicate checks now, so a submission pushed through the
+	// duplicate warning below is still stored with it
+	phash, err := ocr.PerceptualHash(imagePath)
+	if err != nil {
+		log.Printf("Error computing perceptual hash: %v", err)
+	}
+	submission.ScreenshotPHash = phash
+
+	// Warn if the identical screenshot was submitted recently
+	if imgHash != "unknown" {
+		duplicate, err := b.db.HasRecentSubmissionWithHash(ctx, imgHash, duplicateSubmissionWindow)
+		if err != nil {
+			log.Printf("Error checking for duplicate screenshot: %v", err)
+		} else if duplicate {
+			b.showDuplicateWarningUI(s, i, submission)
+			return
+		}
+	}
+
+	// Flag near-duplicates (re-encoded or cropped resubmissions) without blocking
+	if phash != "" {
+		similar, err := b.db.FindSimilarScreenshots(ctx, phash, similarScreenshotMaxDistance)
+		if err != nil {
+			log.Printf("Error finding similar screenshots: %v", err)
+		} else if len(similar) > 0 {
+			match := similar[0]
+			submission.Warnings = append(submission.Warnings, fmt.Sprintf(
+				"This screenshot looks very similar to a %s board for **%s** submitted <t:%d:R>.",
+				match.OrderType, match.PortName, match.SubmittedAt.Unix(),
+			))
+		}
+	}
+
+	b.analyzeSubmission(s, i, submission)
+}
+
+// analyzeSubmission runs OCR on each of the submission's images, merges the
+// results and starts port matching
+func (b *Bot) analyzeSubmission(s *discordgo.Session, i *discordgo.InteractionCreate, sub *PendingSubmission) {
+	sub.OCRResults = make([]*ocr.MarketData, len(sub.ImagePaths))
+	for idx, imagePath := range sub.ImagePaths {
+		// Analyze with Claude
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		result, err := b.claudeClient.AnalyzeScreenshot(ctx, imagePath)
+		cancel()
+
+		// Missing port, order type or items are resolved on the merged result
+		if err != nil && !errors.Is(err, ocr.ErrNoPort) && !errors.Is(err, ocr.ErrNoOrderType) && !errors.Is(err, ocr.ErrNoItems) {
+			log.Printf("Error analyzing screenshot %d: %v", idx+1, err)
+			b.submissionManager.Remove(sub.UserID)
+			sub.RemoveImages()
+			b.followUpError(s, i, t(i.Locale, "submit.analyze_failed_nth", idx+1, err))
+			return
+		}
+		sub.OCRResults[idx] = result
+	}
+
+	marketData, err := ocr.MergeMarketData(sub.OCRResults)
+	switch {
+	case errors.Is(err, ocr.ErrNoPort):
+		// Items were read but the port wasn't; ask the user for it
+		sub.OCRResult = marketData
+		b.showPortHintUI(s, i, sub)
+		return
+	case errors.Is(err, ocr.ErrNoOrderType):
+		// Items were read but not the board type; confirm the user's selection
+		sub.OCRResult = marketData
+		b.showOrderTypeConfirmUI(s, i, sub, "")
+		return
+	case errors.Is(err, ocr.ErrNoItems):
+		b.submissionManager.Remove(sub.UserID)
+		sub.RemoveImages()
+		b.followUpError(s, i, t(i.Locale, "submit.no_items"))
+		return
+	case err != nil:
+		log.Printf("Error analyzing screenshot: %v", err)
+		b.submissionManager.Remove(sub.UserID)
+		sub.RemoveImages()
+		b.followUpError(s, i, t(i.Locale, "submit.analyze_failed", err))
+		return
+	}
+
+	sub.OCRResult = marketData
+	b.continueAnalyzedSubmission(s, i, sub)
+}
+
+// continueAnalyzedSubmission validates a complete OCR result and starts port matching
+func (b *Bot) continueAnalyzedSubmission(s *discordgo.Session, i *discordgo.InteractionCreate, sub *PendingSubmission) {
+	marketData := sub.OCRResult
+
+	// The port may have been supplied by the user, but the board type is still unknown
+	if marketData.OrderType != "buy" && marketData.OrderType != "sell" {
+		b.showOrderTypeConfirmUI(s, i, sub, "")
+		return
+	}
+
+	// OCR sometimes flips buy and sell; let the user decide rather than
+	// throwing the rest of the result away
+	if marketData.OrderType != sub.OrderType {
+		b.showOrderTypeConfirmUI(s, i, sub, marketData.OrderType)
+		return
+	}
 
 	// Start port matching process
-	b.processPortMatching(s, i, submission)
+	b.processPortMatching(s, i, sub)
+}
+
+// showPortHintUI asks the user to name the port when OCR couldn't read it
+func (b *Bot) showPortHintUI(s *discordgo.Session, i *discordgo.InteractionCreate, sub *PendingSubmission) {
+	embed := &discordgo.MessageEmbed{
+		Title: "🏴‍☠️ Port Not Detected",
+		Description: fmt.Sprintf(
+			"Found %d items, but couldn't read the port name from the screenshot.\n\nPlease enter the port name to continue.",
+			len(sub.OCRResult.Items),
+		),
+		Color: 0xffa500,
+	}
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Enter Port Name",
+					Style:    discordgo.PrimaryButton,
+					CustomID: fmt.Sprintf("port_hint:%s", sub.UserID),
+				},
+				discordgo.Button{
+					Label:    "Cancel",
+					Style:    discordgo.DangerButton,
+					CustomID: fmt.Sprintf("submission_cancel:%s", sub.UserID),
+				},
+			},
+		},
+	}
+
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
+	})
+}
+
+// handlePortHint opens the port name modal
+func (b *Bot) handlePortHint(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	if _, ok := b.submissionManager.Get(userID); !ok {
+		b.respondSubmissionExpired(s, i)
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: fmt.Sprintf("port_hint_modal:%s", userID),
+			Title:    t(i.Locale, "submit.port_hint_title"),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "port_name",
+							Label:       t(i.Locale, "submit.port_name_label"),
+							Style:       discordgo.TextInputShort,
+							Placeholder: t(i.Locale, "submit.port_name_placeholder"),
+							Required:    true,
+							MaxLength:   100,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// handlePortHintModal applies the user's port name and resumes the submission
+func (b *Bot) handlePortHintModal(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	sub, ok := b.submissionManager.Get(userID)
+	if !ok || sub.OCRResult == nil {
+		b.respondSubmissionExpired(s, i)
+		return
+	}
+
+	var portName string
+	for _, row := range i.ModalSubmitData().Components {
+		for _, comp := range row.(*discordgo.ActionsRow).Components {
+			if textInput, ok := comp.(*discordgo.TextInput); ok && textInput.CustomID == "port_name" {
+				portName = strings.TrimSpace(textInput.Value)
+			}
+		}
+	}
+	if portName == "" {
+		b.respondError(s, i, t(i.Locale, "submit.port_required"))
+		return
+	}
+
+	sub.OCRResult.Port = portName
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	b.continueAnalyzedSubmission(s, i, sub)
+}
+
+// showOrderTypeConfirmUI asks the user which board type the screenshot shows.
+// detected is what OCR read when it disagrees with the user's selection, or
+// "" when it couldn't tell.
+func (b *Bot) showOrderTypeConfirmUI(s *discordgo.Session, i *discordgo.InteractionCreate, sub *PendingSubmission, detected string) {
+	embed := &discordgo.MessageEmbed{
+		Title: "❓ Order Type Not Detected",
+		Description: fmt.Sprintf(
+			"Couldn't tell whether this screenshot shows buy or sell orders.\n\nYou selected **%s** orders — is that correct?",
+			strings.ToUpper(sub.OrderType),
+		),
+		Color: 0xffa500,
+	}
+	if detected != "" {
+		embed.Title = "❓ Order Type Mismatch"
+		embed.Description = fmt.Sprintf(
+			"OCR thinks this is a **%s** board but you chose **%s** — which is correct?",
+			strings.ToUpper(detected), strings.ToUpper(sub.OrderType),
+		)
+	}
+
+	components := orderTypeConfirmComponents(sub.UserID, sub.OrderType, detected)
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
+	})
+}
+
+// orderTypeConfirmComponents returns the order type buttons: one confirming
+// the user's selection, one for the detected type if OCR disagreed, and cancel
+func orderTypeConfirmComponents(userID, selected, detected string) []discordgo.MessageComponent {
+	buttons := []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    fmt.Sprintf("Yes, %s orders", strings.ToUpper(selected)),
+			Style:    discordgo.SuccessButton,
+			CustomID: fmt.Sprintf("order_type_confirm:%s:%s", userID, selected),
+		},
+	}
+	if detected != "" && detected != selected {
+		buttons[0] = discordgo.Button{
+			Label:    fmt.Sprintf("%s (my choice)", strings.ToUpper(selected)),
+			Style:    discordgo.PrimaryButton,
+			CustomID: fmt.Sprintf("order_type_confirm:%s:%s", userID, selected),
+		}
+		buttons = append(buttons, discordgo.Button{
+			Label:    fmt.Sprintf("%s (detected)", strings.ToUpper(detected)),
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("order_type_confirm:%s:%s", userID, detected),
+		})
+	}
+	buttons = append(buttons, discordgo.Button{
+		Label:    "Cancel",
+		Style:    discordgo.DangerButton,
+		CustomID: fmt.Sprintf("submission_cancel:%s", userID),
+	})
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+}
+
+// handleOrderTypeConfirm applies the order type the user picked and resumes the submission
+func (b *Bot) handleOrderTypeConfirm(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	sub, ok := b.submissionManager.Get(userID)
+	if !ok || sub.OCRResult == nil {
+		b.respondSubmissionExpired(s, i)
+		return
+	}
+
+	// order_type_confirm:<userID>:<type>; buttons from before the type was
+	// included confirm the user's original selection
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	if len(parts) >= 3 && (parts[2] == "buy" || parts[2] == "sell") {
+		sub.OrderType = parts[2]
+	}
+	sub.OCRResult.OrderType = sub.OrderType
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	b.continueAnalyzedSubmission(s, i, sub)
+}
+
+// showDuplicateWarningUI asks the user to confirm resubmitting a recently seen screenshot
+func (b *Bot) showDuplicateWarningUI(s *discordgo.Session, i *discordgo.InteractionCreate, sub *PendingSubmission) {
+	embed := &discordgo.MessageEmbed{
+		Title: "⚠️ Duplicate Screenshot",
+		Description: fmt.Sprintf(
+			"This exact screenshot was already submitted in the last %d minutes.\n\n"+
+				"Resubmitting an old board resets its expiry without adding new data. "+
+				"Only continue if you're sure this is current.",
+			int(duplicateSubmissionWindow.Minutes()),
+		),
+		Color: 0xffa500,
+	}
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Submit Anyway",
+					Style:    discordgo.PrimaryButton,
+					CustomID: fmt.Sprintf("submission_override:%s", sub.UserID),
+				},
+				discordgo.Button{
+					Label:    "Cancel",
+					Style:    discordgo.DangerButton,
+					CustomID: fmt.Sprintf("submission_cancel:%s", sub.UserID),
+				},
+			},
+		},
+	}
+
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
+	})
+}
+
+// handleSubmissionOverride continues a submission after a duplicate warning
+func (b *Bot) handleSubmissionOverride(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	sub, ok := b.submissionManager.Get(userID)
+	if !ok || sub.OCRResult != nil {
+		b.respondSubmissionExpired(s, i)
+		return
+	}
+
+	// Acknowledge and show progress while OCR runs
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    t(i.Locale, "submit.analyzing", len(sub.ImagePaths)),
+			Embeds:     []*discordgo.MessageEmbed{},
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+
+	b.analyzeSubmission(s, i, sub)
+}
+
+// handleSubmissionCancel discards a pending submission and its image
+func (b *Bot) handleSubmissionCancel(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	if sub, ok := b.submissionManager.Remove(userID); ok {
+		sub.RemoveImages()
+
+		// Cancelled from /submit-status: clear the /submit message's controls too
+		if sub.Interaction != nil && i.Message != nil && i.Message.Interaction != nil &&
+			i.Message.Interaction.ID != sub.Interaction.ID {
+			content := t(sub.Interaction.Locale, "submit.cancelled")
+			if _, err := s.InteractionResponseEdit(sub.Interaction, &discordgo.WebhookEdit{
+				Content:    &content,
+				Embeds:     &[]*discordgo.MessageEmbed{},
+				Components: &[]discordgo.MessageComponent{},
+			}); err != nil {
+				log.Printf("Error clearing cancelled submission message: %v", err)
+			}
+		}
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    t(i.Locale, "submit.cancelled"),
+			Embeds:     []*discordgo.MessageEmbed{},
+			Components: []discordgo.MessageComponent{},
+		},
+	})
 }
 
 // processPortMatching handles port validation and confirmation
@@ -108,8 +632,8 @@ func (b *Bot) processPortMatching(s *discordgo.Session, i *discordgo.Interaction
 	if err != nil {
 		log.Printf("Error finding port matches: %v", err)
 		b.submissionManager.Remove(sub.UserID)
-		os.Remove(sub.ImagePath)
-		b.followUpError(s, i, "Database error during port matching")
+		sub.RemoveImages()
+		b.followUpError(s, i, t(i.Locale, "error.port_matching"))
 		return
 	}
 
@@ -134,6 +658,7 @@ func (b *Bot) showPortSelectionUI(s *discordgo.Session, i *discordgo.Interaction
 		Description: fmt.Sprintf("OCR detected port: **%s**\n\nPlease select the correct port or create a new one:", sub.OCRResult.Port),
 		Color:       0xffa500,
 	}
+	addSubmissionWarnings(embed, sub)
 
 	// Build select menu options
 	var options []discordgo.SelectMenuOption
@@ -193,9 +718,20 @@ func (b *Bot) showPortSelectionUI(s *discordgo.Session, i *discordgo.Interaction
 	})
 }
 
+// addSubmissionWarnings appends a submission's non-blocking warnings to an embed
+func addSubmissionWarnings(embed *discordgo.MessageEmbed, sub *PendingSubmission) {
+	if len(sub.Warnings) == 0 {
+		return
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:  "⚠️ Heads up",
+		Value: strings.Join(sub.Warnings, "\n"),
+	})
+}
+
 // handlePortSelect processes port selection from dropdown
 func (b *Bot) handlePortSelect(s *discordgo.Session, i *discordgo.InteractionCreate, parts []string) {
-	userID := i.Member.User.ID
+	userID := getUserID(i)
 	data := i.MessageComponentData()
 
 	if len(data.Values) == 0 {
@@ -207,14 +743,14 @@ func (b *Bot) handlePortSelect(s *discordgo.Session, i *discordgo.InteractionCre
 
 	// Confirm port
 	if !b.submissionManager.ConfirmPort(userID, portID) {
-		b.respondError(s, i, "Submission expired or not found")
+		b.respondSubmissionExpired(s, i)
 		return
 	}
 
 	// Get submission
 	sub, ok := b.submissionManager.Get(userID)
 	if !ok {
-		b.respondError(s, i, "Submission expired")
+		b.respondSubmissionExpired(s, i)
 		return
 	}
 
@@ -229,10 +765,10 @@ func (b *Bot) handlePortSelect(s *discordgo.Session, i *discordgo.InteractionCre
 
 // handlePortCreate shows modal for creating new port
 func (b *Bot) handlePortCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	userID := i.Member.User.ID
+	userID := getUserID(i)
 	sub, ok := b.submissionManager.Get(userID)
 	if !ok {
-		b.respondError(s, i, "Submission expired")
+		b.respondSubmissionExpired(s, i)
 		return
 	}
 
@@ -240,13 +776,13 @@ func (b *Bot) handlePortCreate(s *discordgo.Session, i *discordgo.InteractionCre
 		Type: discordgo.InteractionResponseModal,
 		Data: &discordgo.InteractionResponseData{
 			CustomID: fmt.Sprintf("create_port:%s", userID),
-			Title:    "Create New Port",
+			Title:    t(i.Locale, "submit.port_create_title"),
 			Components: []discordgo.MessageComponent{
 				discordgo.ActionsRow{
 					Components: []discordgo.MessageComponent{
 						discordgo.TextInput{
 							CustomID:    "port_name",
-							Label:       "Port Name",
+							Label:       t(i.Locale, "submit.port_name_label"),
 							Style:       discordgo.TextInputShort,
 							Placeholder: sub.OCRResult.Port,
 							Value:       sub.OCRResult.Port,
@@ -259,9 +795,9 @@ func (b *Bot) handlePortCreate(s *discordgo.Session, i *discordgo.InteractionCre
 					Components: []discordgo.MessageComponent{
 						discordgo.TextInput{
 							CustomID:    "port_region",
-							Label:       "Region",
+							Label:       t(i.Locale, "submit.port_region_label"),
 							Style:       discordgo.TextInputShort,
-							Placeholder: "e.g., Caribbean, Mediterranean",
+							Placeholder: t(i.Locale, "submit.port_region_placeholder"),
 							Required:    true,
 							MaxLength:   50,
 						},
@@ -271,9 +807,9 @@ func (b *Bot) handlePortCreate(s *discordgo.Session, i *discordgo.InteractionCre
 					Components: []discordgo.MessageComponent{
 						discordgo.TextInput{
 							CustomID:    "port_notes",
-							Label:       "Notes (optional)",
+							Label:       t(i.Locale, "submit.port_notes_label"),
 							Style:       discordgo.TextInputParagraph,
-							Placeholder: "Any additional information...",
+							Placeholder: t(i.Locale, "submit.port_notes_placeholder"),
 							Required:    false,
 							MaxLength:   500,
 						},
@@ -286,10 +822,10 @@ func (b *Bot) handlePortCreate(s *discordgo.Session, i *discordgo.InteractionCre
 
 // handleCreatePortModal processes the create port modal submission
 func (b *Bot) handleCreatePortModal(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	userID := i.Member.User.ID
+	userID := getUserID(i)
 	sub, ok := b.submissionManager.Get(userID)
 	if !ok {
-		b.respondError(s, i, "Submission expired")
+		b.respondSubmissionExpired(s, i)
 		return
 	}
 
@@ -313,9 +849,13 @@ func (b *Bot) handleCreatePortModal(s *discordgo.Session, i *discordgo.Interacti
 	// Create port in database
 	ctx := context.Background()
 	port, err := b.db.CreatePort(ctx, portName, portName, portRegion, userID)
+	if errors.Is(err, database.ErrUnknownRegion) {
+		b.respondError(s, i, b.unknownRegionMessage(ctx, portRegion))
+		return
+	}
 	if err != nil {
 		log.Printf("Error creating port: %v", err)
-		b.respondError(s, i, "Failed to create port")
+		b.respondError(s, i, t(i.Locale, "submit.port_create_failed"))
 		return
 	}
 
@@ -337,3 +877,69 @@ func (b *Bot) handleCreatePortModal(s *discordgo.Session, i *discordgo.Interacti
 }
 
 // Continued in handlers_submit_items.go...
+
+// buildSubmitStatusEmbed describes a pending submission for /submit-status
+func buildSubmitStatusEmbed(sub *PendingSubmission) *discordgo.MessageEmbed {
+	stage := sub.Stage()
+	embed := &discordgo.MessageEmbed{
+		Title:       "📋 Pending Submission",
+		Description: fmt.Sprintf("**%s**", stageLabels[stage]),
+		Color:       0x3498db,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Order Type", Value: strings.ToUpper(sub.OrderType), Inline: true},
+			{Name: "Screenshots", Value: fmt.Sprintf("%d", len(sub.ImagePaths)), Inline: true},
+			{Name: "Expires", Value: fmt.Sprintf("<t:%d:R>", sub.ExpiresAt.Unix()), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Continue from your /submit message, or cancel here to start over",
+		},
+	}
+
+	if sub.OCRResult != nil {
+		port := sub.OCRResult.Port
+		if port == "" {
+			port = "not detected"
+		}
+		if sub.PortConfirmed {
+			port += " ✅"
+		}
+		embed.Fields = append(embed.Fields,
+			&discordgo.MessageEmbedField{Name: "Port", Value: port, Inline: true},
+			&discordgo.MessageEmbedField{
+				Name:   "Items Confirmed",
+				Value:  fmt.Sprintf("%d/%d", len(sub.ItemMappings), len(sub.GetUniqueOCRItems())),
+				Inline: true,
+			},
+		)
+	}
+	return embed
+}
+
+// handleSubmitStatus shows the user's pending submission with a Cancel button
+func (b *Bot) handleSubmitStatus(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	sub, ok := b.submissionManager.Get(userID)
+	if !ok {
+		b.respondEphemeral(s, i, t(i.Locale, "submit.none_pending"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{buildSubmitStatusEmbed(sub)},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Label:    "Cancel Submission",
+							Style:    discordgo.DangerButton,
+							CustomID: fmt.Sprintf("submission_cancel:%s", userID),
+						},
+					},
+				},
+			},
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
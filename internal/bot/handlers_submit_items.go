@@ -2,9 +2,9 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os"
 	"strings"
 	"time"
 
@@ -35,8 +35,8 @@ func (b *Bot) processItemMatching(s *discordgo.Session, i *discordgo.Interaction
 	if err != nil {
 		log.Printf("Error finding item matches: %v", err)
 		b.submissionManager.Remove(sub.UserID)
-		os.Remove(sub.ImagePath)
-		b.followUpError(s, i, "Database error during item matching")
+		sub.RemoveImages()
+		b.followUpError(s, i, t(i.Locale, "error.item_matching"))
 		return
 	}
 
@@ -80,6 +80,7 @@ func (b *Bot) showItemConfirmationUI(s *discordgo.Session, i *discordgo.Interact
 		Description: fmt.Sprintf("**OCR detected**: `%s`\n\nProgress: %d/%d items confirmed", itemName, confirmedItems, totalItems),
 		Color:       0x3498db,
 	}
+	addSubmissionWarnings(embed, sub)
 
 	// Build select menu options
 	var options []discordgo.SelectMenuOption
@@ -89,11 +90,17 @@ func (b *Bot) showItemConfirmationUI(s *discordgo.Session, i *discordgo.Interact
 			break
 		}
 
-		label := match.Item.DisplayName
 		description := fmt.Sprintf("%.0f%% match", match.Score*100)
 
 		// Add tag info if available
 		tags, _ := b.db.GetItemTags(context.Background(), match.Item.ID)
+		label := tagIconName(match.Item.DisplayName, tags)
+
+		// The best match's tags color the embed
+		if idx == 0 {
+			embed.Color = tagEmbedColor(tags, embed.Color)
+		}
+
 		if len(tags) > 0 {
 			tagNames := []string{}
 			for _, tag := range tags {
@@ -153,7 +160,7 @@ func (b *Bot) handleItemConfirm(s *discordgo.Session, i *discordgo.InteractionCr
 		return
 	}
 
-	userID := i.Member.User.ID
+	userID := getUserID(i)
 	itemName := parts[2]
 	data := i.MessageComponentData()
 
@@ -163,7 +170,7 @@ func (b *Bot) handleItemConfirm(s *discordgo.Session, i *discordgo.InteractionCr
 
 	sub, ok := b.submissionManager.Get(userID)
 	if !ok {
-		b.respondError(s, i, "Submission expired")
+		b.respondSubmissionExpired(s, i)
 		return
 	}
 
@@ -177,9 +184,13 @@ func (b *Bot) handleItemConfirm(s *discordgo.Session, i *discordgo.InteractionCr
 		// Create new item
 		ctx := context.Background()
 		newItem, err := b.db.CreateItem(ctx, itemName, itemName, userID)
+		if errors.Is(err, database.ErrItemArchived) {
+			b.followUpError(s, i, t(i.Locale, "item.archived", itemName))
+			return
+		}
 		if err != nil {
 			log.Printf("Error creating item: %v", err)
-			b.followUpError(s, i, "Failed to create new item")
+			b.followUpError(s, i, t(i.Locale, "item.create_failed"))
 			return
 		}
 
@@ -199,30 +210,54 @@ func (b *Bot) handleItemConfirm(s *discordgo.Session, i *discordgo.InteractionCr
 	}
 }
 
+// newItemsMaxFields caps the fields the submission summary spends on newly
+// created items
+const newItemsMaxFields = 3
+
 // commitSubmission finalizes the submission and stores in database
 func (b *Bot) commitSubmission(s *discordgo.Session, i *discordgo.InteractionCreate, sub *PendingSubmission) {
 	ctx := context.Background()
 
+	// Every path here has mapped all items
+	b.submissionManager.MarkItemsConfirmed(sub.UserID)
+
 	// Build market orders
 	orders, err := b.submissionManager.GetMarketOrders(sub.UserID)
 	if err != nil || orders == nil {
 		log.Printf("Error building market orders: %v", err)
-		b.followUpError(s, i, "Failed to build market orders")
+		b.followUpError(s, i, t(i.Locale, "submit.build_failed"))
+		return
+	}
+
+	// Let the user check what OCR read before anything is written
+	if sub.Preview && !sub.PreviewConfirmed {
+		b.showSubmissionPreviewUI(s, i, sub)
+		return
+	}
+
+	// Replacing drops anything the screenshot missed, so preview that first
+	if diff, ok := b.needsReplaceConfirmation(sub, orders); ok {
+		b.showReplacePreviewUI(s, i, sub, diff)
 		return
 	}
 
 	// Commit to database
-	err = b.db.ReplacePortOrders(
+	write := b.db.ReplacePortOrders
+	if sub.OrderMode == orderModeMerge {
+		write = b.db.MergePortOrders
+	}
+	err = write(
 		ctx,
 		*sub.PortID,
 		sub.OrderType,
 		orders,
 		sub.UserID,
 		sub.ScreenshotHash,
+		sub.ScreenshotPHash,
 	)
 	if err != nil {
 		log.Printf("Error storing orders: %v", err)
-		b.followUpError(s, i, "Failed to store market data")
+		b.followUpError(s, i, t(i.Locale, "submit.store_failed"))
 		return
 	}
 
@@ -243,14 +278,32 @@ func (b *Bot) commitSubmission(s *discordgo.Session, i *discordgo.InteractionCre
 		_ = itemID // suppress unused warning
 	}
 
+	// Per-submission audit entry, so a contributor's history can be reviewed
+	err = b.db.LogSubmission(ctx, database.SubmissionRecord{
+		UserID:         sub.UserID,
+		PortID:         *sub.PortID,
+		PortName:       portName,
+		OrderType:      sub.OrderType,
+		TotalItems:     len(orders),
+		NewItems:       len(newItems),
+		ScreenshotHash: sub.ScreenshotHash,
+	})
+	if err != nil {
+		log.Printf("Error logging submission: %v", err)
+	}
+
 	// Cleanup
 	b.submissionManager.Remove(sub.UserID)
-	os.Remove(sub.ImagePath)
+	sub.RemoveImages()
 
 	// Success response
+	description := fmt.Sprintf("Successfully processed %s orders for **%s**", sub.OrderType, portName)
+	if sub.OrderMode == orderModeMerge {
+		description += "\nMerged into the existing board; items not in this screenshot were kept."
+	}
 	embed := &discordgo.MessageEmbed{
 		Title:       "✅ Market Data Updated",
-		Description: fmt.Sprintf("Successfully processed %s orders for **%s**", sub.OrderType, portName),
+		Description: description,
 		Color:       0x00ff00,
 		Fields: []*discordgo.MessageEmbedField{
 			{
@@ -275,18 +328,21 @@ func (b *Bot) commitSubmission(s *discordgo.Session, i *discordgo.InteractionCre
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	if len(newItems) > 0 {
-		newItemsList := strings.Join(newItems, ", ")
-		if len(newItemsList) > 1024 {
-			newItemsList = newItemsList[:1021] + "..."
-		}
+	addSubmissionWarnings(embed, sub)
 
+	if len(sub.Edits) > 0 {
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-			Name:  "ℹ️ New Items Added (Untagged)",
-			Value: newItemsList + "\n\nAdmins can tag these with `/admin-item-tag`",
+			Name:  fmt.Sprintf("✏️ Corrected Values (%d)", len(sub.Edits)),
+			Value: joinLimited(submissionEditLines(sub), "\n", maxEmbedFieldValue),
 		})
 	}
 
+	if len(newItems) > 0 {
+		fields, _ := embedFieldChunks("ℹ️ New Items Added (Untagged)", newItems, ", ", newItemsMaxFields)
+		embed.Fields = append(embed.Fields, fields...)
+		embed.Footer.Text += " • Admins can tag new items with /admin-item-tag"
+	}
+
 	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 		Embeds:     &[]*discordgo.MessageEmbed{embed},
 		Components: &[]discordgo.MessageComponent{}, // Clear components
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// encodeTestScreenshot renders a PNG large enough to pass PrepareImage
+func encodeTestScreenshot(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 400; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), uint8(x ^ y), 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode screenshot: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestSubmitDuplicateWarningKeepsPerceptualHash(t *testing.T) {
+	b, _ := setupTradeDraftBot(t)
+	b.submissionManager = NewSubmissionManager(time.Minute, nil)
+	b.imagePath = t.TempDir()
+	b.maxImageBytes = defaultMaxImageBytes
+	ctx := context.Background()
+
+	shot := encodeTestScreenshot(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(shot)
+	}))
+	defer server.Close()
+
+	// The same screenshot was stored a moment ago
+	shotPath := filepath.Join(b.imagePath, "earlier.png")
+	if err := os.WriteFile(shotPath, shot, 0o644); err != nil {
+		t.Fatalf("failed to write screenshot: %v", err)
+	}
+	hash, err := hashImage(shotPath)
+	if err != nil {
+		t.Fatalf("hashImage failed: %v", err)
+	}
+	port, err := b.db.CreatePort(ctx, "port royal", "Port Royal", "", "test")
+	if err != nil {
+		t.Fatalf("CreatePort failed: %v", err)
+	}
+	item, _ := b.db.GetItemByName(ctx, "cannon")
+	orders := []database.Market{{ItemID: item.ID, Price: 100, Quantity: 1}}
+	if err := b.db.ReplacePortOrders(ctx, port.ID, "buy", orders, "user0", hash, ""); err != nil {
+		t.Fatalf("ReplacePortOrders failed: %v", err)
+	}
+
+	s, _ := newTestSession(t)
+	i := newDMCommand("submit")
+	i.Data = discordgo.ApplicationCommandInteractionData{
+		Name: "submit",
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{
+			{Name: "order-type", Type: discordgo.ApplicationCommandOptionString, Value: "buy"},
+			{Name: "screenshot", Type: discordgo.ApplicationCommandOptionAttachment, Value: "att1"},
+		},
+		Resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+			Attachments: map[string]*discordgo.MessageAttachment{
+				"att1": {ID: "att1", Filename: "board.png", ContentType: "image/png", Size: len(shot), URL: server.URL},
+			},
+		},
+	}
+	b.handleSubmit(s, i)
+
+	// Stopped at the duplicate warning; Submit Anyway saves this submission
+	sub, ok := b.submissionManager.Get("user1")
+	if !ok {
+		t.Fatalf("Expected the submission to wait on the duplicate warning")
+	}
+	defer sub.RemoveImages()
+	if sub.ScreenshotPHash == "" {
+		t.Errorf("Expected a submission held for the duplicate warning to carry its perceptual hash")
+	}
+}
@@ -0,0 +1,386 @@
+package bot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// recordingTransport captures Discord API request bodies instead of sending them
+type recordingTransport struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+
+	rt.mu.Lock()
+	rt.bodies = append(rt.bodies, string(body))
+	rt.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: http.StatusNoContent,
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func newTestSession(t *testing.T) (*discordgo.Session, *recordingTransport) {
+	t.Helper()
+
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	rt := &recordingTransport{}
+	s.Client = &http.Client{Transport: rt}
+	return s, rt
+}
+
+// newDMCommand builds a slash command interaction as Discord sends it from a DM:
+// User is set and Member is nil
+func newDMCommand(name string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:    "interaction1",
+			AppID: "app1",
+			Token: "token1",
+			Type:  discordgo.InteractionApplicationCommand,
+			User:  &discordgo.User{ID: "user1"},
+			Data:  discordgo.ApplicationCommandInteractionData{Name: name},
+		},
+	}
+}
+
+func TestAdminCommandsWithNilMember(t *testing.T) {
+	b := &Bot{}
+
+	for _, cmd := range commands {
+		if !strings.HasPrefix(cmd.Name, "admin-") {
+			continue
+		}
+
+		t.Run(cmd.Name, func(t *testing.T) {
+			s, rt := newTestSession(t)
+
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("handler panicked with nil Member: %v", r)
+				}
+			}()
+			b.handleCommand(s, newDMCommand(cmd.Name))
+
+			if len(rt.bodies) != 1 || !strings.Contains(rt.bodies[0], "must be used in a server") {
+				t.Errorf("Expected a single 'must be used in a server' response, got %v", rt.bodies)
+			}
+		})
+	}
+}
+
+func TestIsAdminNilMember(t *testing.T) {
+	b := &Bot{adminRoleID: "role1"}
+	if b.isAdmin("", nil) {
+		t.Error("Expected nil member not to be admin")
+	}
+}
+
+func TestGetUserIDFromDM(t *testing.T) {
+	if got := getUserID(newDMCommand("trade-my-orders")); got != "user1" {
+		t.Errorf("Expected user1, got %q", got)
+	}
+}
+
+func TestFormatPriceLineMarksStaleRows(t *testing.T) {
+	now := time.Now()
+	m := database.Market{Price: 100, Quantity: 5, Port: &database.Port{DisplayName: "Tortuga"}}
+
+	m.SubmittedAt = now.Add(-2 * time.Hour)
+	if got := formatPriceLine(m, now); got != "**Tortuga**: 100 gold (qty: 5, total 500 gold) - 2h ago" {
+		t.Errorf("Unexpected fresh line %q", got)
+	}
+
+	m.SubmittedAt = now.Add(-30 * time.Hour)
+	if got := formatPriceLine(m, now); got != "_**Tortuga**: 100 gold (qty: 5, total 500 gold) - 1d ago (stale)_" {
+		t.Errorf("Unexpected stale line %q", got)
+	}
+}
+
+func TestFormatAuditDetails(t *testing.T) {
+	if got := formatAuditDetails(`{"port_id":3,"deleted":2}`); got != "```json\n{\n  \"port_id\": 3,\n  \"deleted\": 2\n}\n```" {
+		t.Errorf("Expected indented JSON, got %q", got)
+	}
+	if got := formatAuditDetails("not json"); got != "```json\nnot json\n```" {
+		t.Errorf("Expected raw text fallback, got %q", got)
+	}
+	if got := formatAuditDetails(""); got != "" {
+		t.Errorf("Expected no block for empty details, got %q", got)
+	}
+}
+
+func TestBuildAuditLogEmbedFitsDiscordLimits(t *testing.T) {
+	entries := make([]database.AuditLog, auditLogMaxLimit)
+	for idx := range entries {
+		entries[idx] = database.AuditLog{
+			ID:        100000 + idx,
+			Action:    "trade_report_action",
+			UserID:    "123456789012345678",
+			Timestamp: time.Now(),
+			Details:   `{"reason":"` + strings.Repeat("x", 500) + `"}`,
+		}
+	}
+
+	embed := buildAuditLogEmbed(entries, "trade_report_action")
+	total := len(embed.Title) + len(embed.Description)
+	for _, field := range embed.Fields {
+		if len(field.Value) > 1024 {
+			t.Errorf("Field %q is %d characters, over the 1024 limit", field.Name, len(field.Value))
+		}
+		total += len(field.Name) + len(field.Value)
+	}
+	if len(embed.Fields) != auditLogMaxLimit || total > 6000 {
+		t.Errorf("Expected %d fields within 6000 characters, got %d fields and %d characters", auditLogMaxLimit, len(embed.Fields), total)
+	}
+}
+
+func TestBuildLeaderboardEmbed(t *testing.T) {
+	embed := buildLeaderboardEmbed([]database.Contributor{
+		{UserID: "1", Submissions: 5},
+		{UserID: "2", Submissions: 3},
+		{UserID: "3", Submissions: 2},
+		{UserID: "4", Submissions: 1},
+	}, "week")
+
+	if embed.Title != "🏆 Top Contributors This Week" {
+		t.Errorf("Unexpected title %q", embed.Title)
+	}
+	lines := strings.Split(embed.Description, "\n")
+	if len(lines) != 4 || lines[0] != "🥇 <@1> — 5 submissions" || lines[3] != "4. <@4> — 1 submission" {
+		t.Errorf("Unexpected leaderboard %q", lines)
+	}
+
+	if empty := buildLeaderboardEmbed(nil, "month"); !strings.Contains(empty.Description, "No submissions yet") {
+		t.Errorf("Expected empty placeholder, got %q", empty.Description)
+	}
+}
+
+func TestNormalizeIngameName(t *testing.T) {
+	tests := map[string]string{
+		"  Jack   Sparrow ": "Jack Sparrow",
+		"Jack\tSparrow":     "Jack Sparrow",
+		"Elizabeth":         "Elizabeth",
+		"   ":               "",
+	}
+	for in, want := range tests {
+		if got := normalizeIngameName(in); got != want {
+			t.Errorf("normalizeIngameName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestValidateSubmissionAttachment(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		size        int
+		wantErr     string
+	}{
+		{"png", "image/png", 1 << 20, ""},
+		{"jpeg with params", "image/jpeg; charset=binary", 1 << 20, ""},
+		{"webp", "image/webp", 1 << 20, ""},
+		{"gif", "image/gif", 1 << 20, "GIF"},
+		{"bmp", "image/bmp", 1 << 20, "isn't a supported image"},
+		{"text", "text/plain", 100, "isn't a supported image"},
+		{"too big", "image/png", 50 << 20, "at most 10.0 MB"},
+	}
+	for _, tt := range tests {
+		att := &discordgo.MessageAttachment{Filename: "shot", ContentType: tt.contentType, Size: tt.size}
+		got := validateSubmissionAttachment(discordgo.EnglishUS, att, defaultMaxImageBytes)
+		if tt.wantErr == "" && got != "" {
+			t.Errorf("%s: expected no error, got %q", tt.name, got)
+		}
+		if tt.wantErr != "" && !strings.Contains(got, tt.wantErr) {
+			t.Errorf("%s: expected %q in %q", tt.name, tt.wantErr, got)
+		}
+	}
+}
+
+func TestOrderTypeConfirmComponents(t *testing.T) {
+	customIDs := func(components []discordgo.MessageComponent) []string {
+		var ids []string
+		for _, c := range components[0].(discordgo.ActionsRow).Components {
+			ids = append(ids, c.(discordgo.Button).CustomID)
+		}
+		return ids
+	}
+
+	undetected := customIDs(orderTypeConfirmComponents("u1", "buy", ""))
+	if strings.Join(undetected, ",") != "order_type_confirm:u1:buy,submission_cancel:u1" {
+		t.Errorf("unexpected buttons when OCR couldn't tell: %v", undetected)
+	}
+
+	mismatch := customIDs(orderTypeConfirmComponents("u1", "buy", "sell"))
+	if strings.Join(mismatch, ",") != "order_type_confirm:u1:buy,order_type_confirm:u1:sell,submission_cancel:u1" {
+		t.Errorf("unexpected buttons on a mismatch: %v", mismatch)
+	}
+}
+
+func TestOrderTotalDoesNotOverflow(t *testing.T) {
+	if got := orderTotal(2_000_000_000, 3); got != 6_000_000_000 {
+		t.Errorf("expected 6000000000, got %d", got)
+	}
+}
+
+func TestFormatGold(t *testing.T) {
+	cases := map[int64]string{
+		0:             "0 gold",
+		999:           "999 gold",
+		1000:          "1,000 gold",
+		1_250_000:     "1,250,000 gold",
+		-45_000:       "-45,000 gold",
+		math.MaxInt64: "9,223,372,036,854,775,807 gold",
+		math.MinInt64: "-9,223,372,036,854,775,808 gold",
+	}
+	for n, want := range cases {
+		if got := formatGold(n); got != want {
+			t.Errorf("formatGold(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestBuildModerationStatsEmbed(t *testing.T) {
+	embed := buildModerationStatsEmbed(&database.ModerationStats{
+		PendingReports:   2,
+		OldestPendingAge: 26 * time.Hour,
+		ActionedReports:  4,
+		AvgTimeToAction:  90 * time.Minute,
+	})
+	if embed.Fields[0].Value != "2 (oldest 1d 2h)" {
+		t.Errorf("unexpected pending field %q", embed.Fields[0].Value)
+	}
+	if embed.Fields[2].Value != "1h 30m over 4 report(s)" {
+		t.Errorf("unexpected time to action %q", embed.Fields[2].Value)
+	}
+
+	embed = buildModerationStatsEmbed(&database.ModerationStats{})
+	if embed.Fields[0].Value != "0" || embed.Fields[2].Value != "No reports actioned yet" {
+		t.Errorf("unexpected empty stats %q / %q", embed.Fields[0].Value, embed.Fields[2].Value)
+	}
+}
+
+func TestOrderErrorMessage(t *testing.T) {
+	wrapped := fmt.Errorf("cancel failed: %w", database.ErrNotOrderOwner)
+	if got := orderErrorMessage(discordgo.EnglishUS, 12, wrapped); got != "Order #12 isn't yours, so you can't cancel it." {
+		t.Errorf("unexpected message %q", got)
+	}
+	if got := orderErrorMessage(discordgo.EnglishUS, 12, database.ErrOrderExpired); got != "Order #12 has expired." {
+		t.Errorf("unexpected message %q", got)
+	}
+	if got := orderErrorMessage(discordgo.EnglishUS, 12, errors.New("disk full")); got != "" {
+		t.Errorf("expected no message for other errors, got %q", got)
+	}
+	if got := orderErrorMessage(discordgo.EnglishUS, 12, nil); got != "" {
+		t.Errorf("expected no message without an error, got %q", got)
+	}
+}
+
+func TestParseOrderStart(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	valid := map[string]time.Time{
+		"2h":               now.Add(2 * time.Hour),
+		"90m":              now.Add(90 * time.Minute),
+		" 3d ":             now.Add(72 * time.Hour),
+		"2026-10-20 18:00": time.Date(2026, 10, 20, 18, 0, 0, 0, time.UTC),
+	}
+	for text, want := range valid {
+		got, ok := parseOrderStart(text, now)
+		if !ok || !got.Equal(want) {
+			t.Errorf("parseOrderStart(%q) = %v, %v; want %v", text, got, ok, want)
+		}
+	}
+
+	for _, text := range []string{"", "soon", "0h", "-2h", "15d", "2026-10-17 11:00", "2026-11-30 12:00"} {
+		if got, ok := parseOrderStart(text, now); ok {
+			t.Errorf("parseOrderStart(%q) = %v, expected it rejected", text, got)
+		}
+	}
+}
+
+func TestTradeCancelAllComponents(t *testing.T) {
+	row := tradeCancelAllComponents(discordgo.SpanishES, "user1")[0].(discordgo.ActionsRow)
+	confirm := row.Components[0].(discordgo.Button)
+	if confirm.CustomID != "trade_cancel_all_confirm:user1" || confirm.Style != discordgo.DangerButton {
+		t.Errorf("unexpected confirm button %+v", confirm)
+	}
+	if confirm.Label != "Cancelar todas mis órdenes" {
+		t.Errorf("confirm label = %q, expected the Spanish catalog entry", confirm.Label)
+	}
+	if keep := row.Components[1].(discordgo.Button); keep.CustomID != "trade_cancel_all_keep" {
+		t.Errorf("unexpected keep button %+v", keep)
+	}
+}
+
+func TestBuildDuplicatesEmbedSuggestsArchive(t *testing.T) {
+	pairs := []database.DuplicatePair{{
+		Keep:      database.Item{Name: "rope", DisplayName: "Rope"},
+		Duplicate: database.Item{Name: "rop", DisplayName: "Rop"},
+		Score:     0.9,
+	}}
+	value := buildDuplicatesEmbed(pairs, 85).Fields[0].Value
+	if !strings.Contains(value, "`/admin-item-archive item:rop`") || strings.Contains(value, "merge") {
+		t.Errorf("expected an archive suggestion for the duplicate, got %q", value)
+	}
+}
+
+func TestAdminPurgeScopes(t *testing.T) {
+	for customID, want := range map[string]struct {
+		port  int
+		scope string
+	}{
+		"admin_purge_confirm:7:player": {7, purgeScopePlayer},
+		"admin_purge_confirm:7:both":   {7, purgeScopeBoth},
+		"admin_purge_confirm:7":        {7, purgeScopeMarket}, // buttons from before scopes
+	} {
+		port, scope, err := parseAdminPurgeConfirm(customID)
+		if err != nil || port != want.port || scope != want.scope {
+			t.Errorf("parseAdminPurgeConfirm(%q) = %d, %q, %v", customID, port, scope, err)
+		}
+	}
+	if _, _, err := parseAdminPurgeConfirm("admin_purge_confirm:x:both"); err == nil {
+		t.Error("expected an error for a bad port")
+	}
+
+	result := adminPurgeResult{Markets: 60, PlayerOrders: 3}
+	if got := result.summary("Tortuga", purgeScopeBoth); got != "✅ Purged port 'Tortuga': deleted 60 market orders, cancelled 3 player orders" {
+		t.Errorf("unexpected summary %q", got)
+	}
+	if got := adminPurgePrompt("Tortuga", purgeScopePlayer, 0, 55); got != "⚠️ This will cancel **55 player orders** at port 'Tortuga'. Are you sure?" {
+		t.Errorf("unexpected prompt %q", got)
+	}
+}
+
+func TestRemovedOrderMessage(t *testing.T) {
+	order := database.PlayerOrder{ID: 7, OrderType: "sell", Item: &database.Item{DisplayName: "Cannon"}}
+	got := removedOrderMessage(order, "Slur in notes")
+	if !strings.Contains(got, "#7 (SELL Cannon)") || !strings.Contains(got, "Reason: Slur in notes") {
+		t.Errorf("unexpected message %q", got)
+	}
+	if got := removedOrderMessage(order, ""); strings.Contains(got, "Reason") {
+		t.Errorf("expected no reason line, got %q", got)
+	}
+}
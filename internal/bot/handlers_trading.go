@@ -2,10 +2,13 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"wosbTrade/internal/database"
 
@@ -28,6 +31,32 @@ func parseTradeDuration(d string) time.Duration {
 	}
 }
 
+// maxOrderStartDelay is how far ahead /trade-create can schedule an order
+const maxOrderStartDelay = 14 * 24 * time.Hour
+
+// parseOrderStart reads /trade-create's start option: a delay such as "2h",
+// "90m" or "3d", or a UTC time written "2006-01-02 15:04". It must fall after
+// now and within maxOrderStartDelay.
+func parseOrderStart(text string, now time.Time) (time.Time, bool) {
+	text = strings.TrimSpace(text)
+
+	var start time.Time
+	if days, err := strconv.Atoi(strings.TrimSuffix(text, "d")); err == nil && strings.HasSuffix(text, "d") {
+		start = now.Add(time.Duration(days) * 24 * time.Hour)
+	} else if d, err := time.ParseDuration(text); err == nil {
+		start = now.Add(d)
+	} else if at, err := time.ParseInLocation("2006-01-02 15:04", text, time.UTC); err == nil {
+		start = at
+	} else {
+		return time.Time{}, false
+	}
+
+	if !start.After(now) || start.Sub(now) > maxOrderStartDelay {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
 // getUserID extracts user ID from an interaction, handling both guild and DM contexts
 func getUserID(i *discordgo.InteractionCreate) string {
 	if i.Member != nil {
@@ -41,30 +70,66 @@ func getUserID(i *discordgo.InteractionCreate) string {
 
 // --- /trade-set-name ---
 
+// normalizeIngameName trims a name and collapses internal whitespace, so
+// "Jack  Sparrow " and "Jack Sparrow" are the same name
+func normalizeIngameName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// validIngameName reports whether a normalized name is 2-50 characters
+func validIngameName(name string) bool {
+	n := utf8.RuneCountInString(name)
+	return n >= 2 && n <= maxNameLength
+}
+
 func (b *Bot) handleTradeSetName(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	options := parseOptions(i.ApplicationCommandData().Options)
-	name := strings.TrimSpace(options["name"].StringValue())
+	name := normalizeIngameName(options["name"].StringValue())
 
-	if len(name) < 2 || len(name) > 50 {
-		b.respondError(s, i, "In-game name must be between 2 and 50 characters")
+	if !validIngameName(name) {
+		b.respondError(s, i, t(i.Locale, "trade.name_length"))
+		return
+	}
+	if b.words.Match(name) != "" {
+		b.respondError(s, i, t(i.Locale, "filter.name_blocked"))
 		return
 	}
 
 	userID := getUserID(i)
 	ctx := context.Background()
 
-	err := b.db.SetPlayerProfile(ctx, userID, name)
+	// Names appear on trade embeds, so one player can't pose as another
+	taken, err := b.db.IsIngameNameTaken(ctx, name, userID)
+	if err != nil {
+		log.Printf("Error checking in-game name: %v", err)
+		b.respondError(s, i, t(i.Locale, "trade.name_save_failed"))
+		return
+	}
+	if taken {
+		b.respondError(s, i, t(i.Locale, "trade.name_taken", name))
+		return
+	}
+
+	err = b.db.SetPlayerProfile(ctx, userID, name)
 	if err != nil {
 		log.Printf("Error setting player profile: %v", err)
-		b.respondError(s, i, "Failed to save your in-game name")
+		b.respondError(s, i, t(i.Locale, "trade.name_save_failed"))
 		return
 	}
 
-	b.respondEphemeral(s, i, fmt.Sprintf("Your in-game name has been set to **%s**", name))
+	b.respondEphemeral(s, i, t(i.Locale, "trade.name_set", name))
 }
 
 // --- /trade-create ---
 
+const (
+	// defaultMaxActiveOrders is how many live orders a trader may have when
+	// the guild hasn't set its own cap with /config-set-max-orders
+	defaultMaxActiveOrders = 25
+	// maxActiveOrdersLimit bounds /config-set-max-orders
+	maxActiveOrdersLimit = 100
+)
+
 func (b *Bot) handleTradeCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	userID := getUserID(i)
 	ctx := context.Background()
@@ -72,23 +137,30 @@ func (b *Bot) handleTradeCreate(s *discordgo.Session, i *discordgo.InteractionCr
 	// Check player has set their name
 	profile, err := b.db.GetPlayerProfile(ctx, userID)
 	if err != nil || profile == nil {
-		b.respondError(s, i, "You need to set your in-game name first. Use `/trade-set-name`")
+		b.respondError(s, i, t(i.Locale, "trade.name_required"))
 		return
 	}
 
 	// Check if user is banned from trading
-	ban, err := b.db.IsUserBanned(ctx, userID)
+	ban, err := b.bans.IsUserBanned(ctx, userID)
 	if err != nil {
 		log.Printf("Error checking trade ban: %v", err)
-		b.respondError(s, i, "Failed to verify trading status")
+		b.respondError(s, i, t(i.Locale, "trade.status_check_failed"))
 		return
 	}
 	if ban != nil {
-		msg := fmt.Sprintf("You are banned from trading. Reason: %s", ban.Reason)
-		if ban.ExpiresAt != nil {
-			msg += fmt.Sprintf("\nBan expires: <t:%d:R>", ban.ExpiresAt.Unix())
-		}
-		b.respondError(s, i, msg)
+		b.respondError(s, i, tradeBanMessage(i.Locale, ban))
+		return
+	}
+
+	active, err := b.db.CountActivePlayerOrders(ctx, userID)
+	if err != nil {
+		log.Printf("Error counting active orders: %v", err)
+		b.respondError(s, i, t(i.Locale, "trade.status_check_failed"))
+		return
+	}
+	if limit := b.maxActiveOrders(ctx, i.GuildID); active >= limit {
+		b.respondError(s, i, t(i.Locale, "trade.order_limit", limit))
 		return
 	}
 
@@ -100,11 +172,11 @@ func (b *Bot) handleTradeCreate(s *discordgo.Session, i *discordgo.InteractionCr
 	duration := options["duration"].StringValue()
 
 	if price <= 0 {
-		b.respondError(s, i, "Price must be greater than 0")
+		b.respondError(s, i, t(i.Locale, "trade.price_invalid"))
 		return
 	}
 	if quantity <= 0 {
-		b.respondError(s, i, "Quantity must be greater than 0")
+		b.respondError(s, i, t(i.Locale, "trade.quantity_invalid"))
 		return
 	}
 
@@ -112,25 +184,16 @@ func (b *Bot) handleTradeCreate(s *discordgo.Session, i *discordgo.InteractionCr
 	matches, err := b.db.FindItemMatches(ctx, itemName, 5)
 	if err != nil {
 		log.Printf("Error finding item matches: %v", err)
-		b.respondError(s, i, "Database error during item search")
+		b.respondError(s, i, t(i.Locale, "error.item_search"))
 		return
 	}
 
+	// Without a confident match the user picks the item below
 	var itemID int
 	var itemDisplay string
 	if len(matches) > 0 && matches[0].Confidence >= database.ConfidenceMedium {
 		itemID = matches[0].Item.ID
 		itemDisplay = matches[0].Item.DisplayName
-	} else {
-		// Create new item
-		newItem, err := b.db.CreateItem(ctx, itemName, itemName, userID)
-		if err != nil {
-			log.Printf("Error creating item: %v", err)
-			b.respondError(s, i, "Failed to create new item")
-			return
-		}
-		itemID = newItem.ID
-		itemDisplay = itemName
 	}
 
 	// Optional port
@@ -144,7 +207,7 @@ func (b *Bot) handleTradeCreate(s *discordgo.Session, i *discordgo.InteractionCr
 			portID = &id
 			portDisplay = portMatches[0].Port.DisplayName
 		} else {
-			b.respondError(s, i, fmt.Sprintf("Port not found: '%s'. Ask an admin to add it with `/admin-port-add`, or omit the port.", portName))
+			b.respondError(s, i, t(i.Locale, "trade.port_not_found", portName))
 			return
 		}
 	}
@@ -154,68 +217,579 @@ func (b *Bot) handleTradeCreate(s *discordgo.Session, i *discordgo.InteractionCr
 	if opt := options["notes"]; opt != nil {
 		notes = opt.StringValue()
 	}
+	if b.words.Match(notes) != "" {
+		b.respondError(s, i, t(i.Locale, "filter.notes_blocked"))
+		return
+	}
+
+	// Optional scheduled start
+	var activatesAt *time.Time
+	if opt := options["start"]; opt != nil {
+		start, ok := parseOrderStart(opt.StringValue(), time.Now())
+		if !ok {
+			b.respondError(s, i, t(i.Locale, "trade.start_invalid",
+				time.Now().UTC().Add(24*time.Hour).Format("2006-01-02 15:04"), int(maxOrderStartDelay.Hours()/24)))
+			return
+		}
+		activatesAt = &start
+	}
 
-	// Calculate expiry
-	dur := parseTradeDuration(duration)
-	expiresAt := time.Now().Add(dur)
+	draft := TradeDraft{
+		Order: database.PlayerOrder{
+			UserID:      userID,
+			ItemID:      itemID,
+			OrderType:   orderType,
+			Price:       price,
+			Quantity:    quantity,
+			PortID:      portID,
+			Notes:       notes,
+			IngameName:  profile.IngameName,
+			ActivatesAt: activatesAt,
+		},
+		Duration:    parseTradeDuration(duration),
+		ItemDisplay: itemDisplay,
+		PortDisplay: portDisplay,
+	}
 
-	order := database.PlayerOrder{
-		UserID:     userID,
-		ItemID:     itemID,
-		OrderType:  orderType,
-		Price:      price,
-		Quantity:   quantity,
-		PortID:     portID,
-		Notes:      notes,
-		IngameName: profile.IngameName,
-		ExpiresAt:  expiresAt,
+	if itemID == 0 {
+		b.showTradeItemPicker(s, i, &draft, itemName)
+		return
+	}
+	draft.PriceWarning = b.checkTradePrice(ctx, itemID, price)
+
+	// Show a preview first unless the guild has turned it off. An unusual
+	// price always gets a preview so a typo can't go live with one command.
+	if draft.PriceWarning != "" || b.tradePreviewEnabled(ctx, i.GuildID) {
+		b.tradeDrafts.Put(&draft)
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds:     []*discordgo.MessageEmbed{tradeOrderEmbed(draft, nil)},
+				Components: tradePreviewComponents(userID),
+				Flags:      discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
 	}
 
-	created, err := b.db.CreatePlayerOrder(ctx, order)
+	created, err := b.createTradeOrder(ctx, draft)
 	if err != nil {
 		log.Printf("Error creating player order: %v", err)
-		b.respondError(s, i, "Failed to create order")
+		b.respondError(s, i, t(i.Locale, "trade.create_failed"))
 		return
 	}
 
-	typeEmoji := "📗"
-	if orderType == "sell" {
-		typeEmoji = "📕"
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{tradeOrderEmbed(draft, created)},
+		},
+	})
+}
+
+// tradePreviewEnabled reports whether /trade-create should preview orders in this guild.
+// Previews are on by default, including in DMs and unconfigured guilds.
+func (b *Bot) tradePreviewEnabled(ctx context.Context, guildID string) bool {
+	if guildID == "" {
+		return true
+	}
+
+	settings, err := b.db.GetGuildSettings(ctx, guildID)
+	if err != nil {
+		log.Printf("Error fetching guild settings: %v", err)
+		return true
+	}
+	if settings == nil {
+		return true
+	}
+	return settings.TradePreview
+}
+
+// tradeBanMessage tells a banned user why they can't trade, and until when
+func tradeBanMessage(locale discordgo.Locale, ban *database.TradeBan) string {
+	msg := t(locale, "trade.banned", ban.Reason)
+	if ban.ExpiresAt != nil {
+		msg += "\n" + t(locale, "trade.banned_until", ban.ExpiresAt.Unix())
+	}
+	return msg
+}
+
+// maxActiveOrders is how many live orders a trader may have in this guild.
+// DMs and unconfigured guilds use the default.
+func (b *Bot) maxActiveOrders(ctx context.Context, guildID string) int {
+	if guildID == "" {
+		return defaultMaxActiveOrders
+	}
+
+	settings, err := b.db.GetGuildSettings(ctx, guildID)
+	if err != nil {
+		log.Printf("Error fetching guild settings: %v", err)
+		return defaultMaxActiveOrders
+	}
+	if settings == nil || settings.MaxActiveOrders <= 0 {
+		return defaultMaxActiveOrders
+	}
+	return settings.MaxActiveOrders
+}
+
+// createTradeOrder stores a drafted order. Its expiry runs from when it
+// starts: now, or its scheduled start if that is still ahead.
+func (b *Bot) createTradeOrder(ctx context.Context, draft TradeDraft) (*database.PlayerOrder, error) {
+	order := draft.Order
+	now := time.Now()
+	start := draftStart(order, now)
+	if !start.After(now) {
+		order.ActivatesAt = nil
+	}
+	order.ExpiresAt = start.Add(draft.Duration)
+	return b.db.CreatePlayerOrder(ctx, order)
+}
+
+// draftStart is when a drafted order will go live: its scheduled start, or
+// now if it has none or the start has already passed
+func draftStart(order database.PlayerOrder, now time.Time) time.Time {
+	if order.ActivatesAt != nil && order.ActivatesAt.After(now) {
+		return *order.ActivatesAt
+	}
+	return now
+}
+
+// errTradeDraftNotFound is returned when confirming a preview that expired or was already used
+var errTradeDraftNotFound = errors.New("trade draft expired or not found")
+
+// errTradeOrderLimit is returned when confirming a preview would put the user
+// over their active order cap
+var errTradeOrderLimit = errors.New("active order limit reached")
+
+// confirmTradeDraft creates the user's previewed order if they have fewer than
+// limit active orders. The draft is consumed, so a second confirmation cannot
+// create a duplicate order; one refused for the limit is kept.
+func (b *Bot) confirmTradeDraft(ctx context.Context, userID string, limit int) (TradeDraft, *database.PlayerOrder, error) {
+	// An older preview's button mustn't confirm (or consume) a newer draft
+	// that is still waiting for its item to be picked
+	pending, ok := b.tradeDrafts.Get(userID)
+	if !ok || pending.Order.ItemID == 0 {
+		return TradeDraft{}, nil, errTradeDraftNotFound
+	}
+
+	// Other orders may have been created since the preview was shown
+	active, err := b.db.CountActivePlayerOrders(ctx, userID)
+	if err != nil {
+		return TradeDraft{}, nil, err
+	}
+	if active >= limit {
+		return TradeDraft{}, nil, errTradeOrderLimit
+	}
+
+	draft, ok := b.tradeDrafts.Take(userID)
+	if !ok {
+		return TradeDraft{}, nil, errTradeDraftNotFound
+	}
+
+	created, err := b.createTradeOrder(ctx, draft)
+	if err != nil {
+		return draft, nil, err
+	}
+	return draft, created, nil
+}
+
+// tradeOrderEmbed renders an order. A nil created order renders the preview.
+func tradeOrderEmbed(draft TradeDraft, created *database.PlayerOrder) *discordgo.MessageEmbed {
+	order := draft.Order
+
+	typeEmoji := orderTypeEmoji(order.OrderType)
+
+	startsAt := draftStart(order, time.Now())
+	scheduled := startsAt.After(time.Now())
+	expiresAt := startsAt.Add(draft.Duration)
+	if created != nil {
+		scheduled = created.ActivatesAt != nil
+		expiresAt = created.ExpiresAt
 	}
 
 	embed := &discordgo.MessageEmbed{
-		Title: fmt.Sprintf("%s Trade Order Created", typeEmoji),
-		Color: 0x2ecc71,
+		Title: fmt.Sprintf("%s Trade Order Preview", typeEmoji),
+		Color: 0xffa500,
 		Fields: []*discordgo.MessageEmbedField{
-			{Name: "Order ID", Value: fmt.Sprintf("#%d", created.ID), Inline: true},
-			{Name: "Type", Value: strings.ToUpper(orderType), Inline: true},
-			{Name: "Item", Value: itemDisplay, Inline: true},
-			{Name: "Price", Value: fmt.Sprintf("%d gold", price), Inline: true},
-			{Name: "Quantity", Value: fmt.Sprintf("%d", quantity), Inline: true},
+			{Name: "Type", Value: strings.ToUpper(order.OrderType), Inline: true},
+			{Name: "Item", Value: draft.ItemDisplay, Inline: true},
+			{Name: "Price", Value: formatGold(int64(order.Price)), Inline: true},
+			{Name: "Quantity", Value: fmt.Sprintf("%d", order.Quantity), Inline: true},
+			{Name: "Total", Value: formatGold(orderTotal(order.Price, order.Quantity)), Inline: true},
 			{Name: "Expires", Value: fmt.Sprintf("<t:%d:R>", expiresAt.Unix()), Inline: true},
-			{Name: "Trader", Value: profile.IngameName, Inline: true},
+			{Name: "Trader", Value: safeName(order.IngameName), Inline: true},
 		},
 		Footer: &discordgo.MessageEmbedFooter{
-			Text: "Other players can contact you about this order with /trade-contact",
+			Text: "Check the details, then Confirm to post this order",
 		},
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	if portDisplay != "" {
+	if created != nil {
+		embed.Title = fmt.Sprintf("%s Trade Order Created", typeEmoji)
+		embed.Color = 0x2ecc71
+		embed.Fields = append([]*discordgo.MessageEmbedField{
+			{Name: "Order ID", Value: fmt.Sprintf("#%d", created.ID), Inline: true},
+		}, embed.Fields...)
+		embed.Footer.Text = "Other players can contact you about this order with /trade-contact"
+		if scheduled {
+			embed.Footer.Text = "Other players can find and contact you about this order once it starts"
+		}
+	}
+
+	if scheduled {
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-			Name: "Port", Value: portDisplay, Inline: true,
+			Name: "Starts", Value: fmt.Sprintf("<t:%d:f> (<t:%d:R>)", startsAt.Unix(), startsAt.Unix()), Inline: true,
 		})
 	}
-	if notes != "" {
+
+	if draft.PortDisplay != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: "Port", Value: draft.PortDisplay, Inline: true,
+		})
+	}
+	if order.Notes != "" {
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-			Name: "Notes", Value: notes,
+			Name: "Notes", Value: sanitizeUserText(order.Notes, maxNotesLength),
 		})
 	}
+	if draft.PriceWarning != "" && created == nil {
+		embed.Color = 0xe74c3c
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "⚠️ Unusual Price",
+			Value: draft.PriceWarning + "\nDouble-check the price, or use Edit to fix it, before you Confirm.",
+		})
+	}
+
+	return embed
+}
+
+// tradePreviewComponents returns the Confirm/Edit/Cancel buttons for an order preview
+func tradePreviewComponents(userID string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Confirm",
+					Style:    discordgo.SuccessButton,
+					CustomID: fmt.Sprintf("trade_draft_confirm:%s", userID),
+				},
+				discordgo.Button{
+					Label:    "Edit",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("trade_draft_edit:%s", userID),
+				},
+				discordgo.Button{
+					Label:    "Cancel",
+					Style:    discordgo.DangerButton,
+					CustomID: fmt.Sprintf("trade_draft_cancel:%s", userID),
+				},
+			},
+		},
+	}
+}
+
+// handleTradeDraftConfirm creates the previewed order and posts it publicly
+func (b *Bot) handleTradeDraftConfirm(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	ctx := context.Background()
+
+	// The user may have been banned since the preview was shown
+	ban, err := b.bans.IsUserBanned(ctx, userID)
+	if err != nil {
+		log.Printf("Error checking trade ban: %v", err)
+		b.respondError(s, i, t(i.Locale, "trade.status_check_failed"))
+		return
+	}
+	if ban != nil {
+		b.tradeDrafts.Remove(userID)
+		b.respondError(s, i, tradeBanMessage(i.Locale, ban))
+		return
+	}
+
+	limit := b.maxActiveOrders(ctx, i.GuildID)
+	draft, created, err := b.confirmTradeDraft(ctx, userID, limit)
+	if err != nil {
+		if errors.Is(err, errTradeDraftNotFound) {
+			b.respondError(s, i, t(i.Locale, "trade.preview_expired"))
+			return
+		}
+		if errors.Is(err, errTradeOrderLimit) {
+			b.respondError(s, i, t(i.Locale, "trade.order_limit", limit))
+			return
+		}
+		log.Printf("Error creating player order: %v", err)
+		b.respondError(s, i, t(i.Locale, "trade.create_failed"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    t(i.Locale, "trade.created", created.ID),
+			Embeds:     []*discordgo.MessageEmbed{},
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+
+	if _, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{tradeOrderEmbed(draft, created)},
+	}); err != nil {
+		log.Printf("Error posting created order: %v", err)
+	}
+}
+
+// handleTradeDraftEdit opens a modal prefilled with the draft's editable fields
+func (b *Bot) handleTradeDraftEdit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	draft, ok := b.tradeDrafts.Get(userID)
+	if !ok {
+		b.respondError(s, i, t(i.Locale, "trade.preview_expired"))
+		return
+	}
 
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: fmt.Sprintf("trade_draft_modal:%s", userID),
+			Title:    t(i.Locale, "trade.edit_title"),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:  "price",
+							Label:     t(i.Locale, "trade.edit_price"),
+							Style:     discordgo.TextInputShort,
+							Value:     strconv.Itoa(draft.Order.Price),
+							Required:  true,
+							MaxLength: 10,
+						},
+					},
+				},
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:  "quantity",
+							Label:     t(i.Locale, "trade.edit_quantity"),
+							Style:     discordgo.TextInputShort,
+							Value:     strconv.Itoa(draft.Order.Quantity),
+							Required:  true,
+							MaxLength: 10,
+						},
+					},
+				},
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:  "notes",
+							Label:     t(i.Locale, "trade.edit_notes"),
+							Style:     discordgo.TextInputParagraph,
+							Value:     draft.Order.Notes,
+							Required:  false,
+							MaxLength: maxNotesLength,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// handleTradeDraftModal applies edits from the modal and refreshes the preview
+func (b *Bot) handleTradeDraftModal(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+
+	values := make(map[string]string)
+	for _, row := range i.ModalSubmitData().Components {
+		for _, comp := range row.(*discordgo.ActionsRow).Components {
+			if textInput, ok := comp.(*discordgo.TextInput); ok {
+				values[textInput.CustomID] = strings.TrimSpace(textInput.Value)
+			}
+		}
+	}
+
+	price, err := strconv.Atoi(values["price"])
+	if err != nil || price <= 0 {
+		b.respondError(s, i, t(i.Locale, "trade.price_whole"))
+		return
+	}
+	quantity, err := strconv.Atoi(values["quantity"])
+	if err != nil || quantity <= 0 {
+		b.respondError(s, i, t(i.Locale, "trade.quantity_whole"))
+		return
+	}
+
+	if b.words.Match(values["notes"]) != "" {
+		b.respondError(s, i, t(i.Locale, "filter.notes_blocked"))
+		return
+	}
+
+	draft, ok := b.tradeDrafts.Update(userID, price, quantity, values["notes"])
+	if !ok {
+		b.respondError(s, i, t(i.Locale, "trade.preview_expired"))
+		return
+	}
+	draft.PriceWarning = b.checkTradePrice(context.Background(), draft.Order.ItemID, price)
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{tradeOrderEmbed(draft, nil)},
+			Components: tradePreviewComponents(userID),
+		},
+	})
+}
+
+// handleTradeDraftCancel discards the previewed order
+func (b *Bot) handleTradeDraftCancel(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	b.tradeDrafts.Remove(getUserID(i))
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    t(i.Locale, "trade.draft_cancelled"),
+			Embeds:     []*discordgo.MessageEmbed{},
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+// tradeItemCandidateLimit caps the items offered by the /trade-create picker
+const tradeItemCandidateLimit = 5
+
+// showTradeItemPicker stores the draft and asks the user which item they
+// meant, rather than quietly creating a possible duplicate
+func (b *Bot) showTradeItemPicker(s *discordgo.Session, i *discordgo.InteractionCreate, draft *TradeDraft, itemName string) {
+	candidates, err := b.db.FindItemCandidates(context.Background(), itemName, tradeItemCandidateLimit)
+	if err != nil {
+		log.Printf("Error finding item candidates: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.item_search"))
+		return
+	}
+
+	draft.PendingItem = itemName
+	draft.ItemChoices = make(map[int]string)
+	for _, c := range candidates {
+		draft.ItemChoices[c.Item.ID] = c.Item.DisplayName
+	}
+	b.tradeDrafts.Put(draft)
+
+	embed, components := tradeItemPicker(draft.Order.UserID, itemName, candidates)
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{embed},
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// tradeItemPicker renders the closest items plus a "create new" option
+func tradeItemPicker(userID, itemName string, candidates []database.ItemMatch) (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	embed := &discordgo.MessageEmbed{
+		Title:       "🎯 Which item did you mean?",
+		Description: fmt.Sprintf("No item closely matches `%s`. Pick one below, or add it as a new item if it's really missing.", itemName),
+		Color:       0x3498db,
+	}
+	if len(candidates) == 0 {
+		embed.Description = fmt.Sprintf("No item resembles `%s`. Add it as a new item if it's really missing, or cancel and check the spelling.", itemName)
+	}
+
+	var options []discordgo.SelectMenuOption
+	for _, c := range candidates {
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       c.Item.DisplayName,
+			Value:       strconv.Itoa(c.Item.ID),
+			Description: fmt.Sprintf("%.0f%% match", c.Score*100),
+		})
+	}
+	newLabel := "✨ Add as new item: " + itemName
+	if utf8.RuneCountInString(newLabel) > 100 {
+		newLabel = string([]rune(newLabel)[:99]) + "…"
+	}
+	options = append(options, discordgo.SelectMenuOption{
+		Label:       newLabel,
+		Value:       "new",
+		Description: "This will create a new untagged item",
+	})
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					CustomID:    fmt.Sprintf("trade_item_select:%s", userID),
+					Placeholder: "Select matching item",
+					Options:     options,
+				},
+			},
+		},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Cancel",
+					Style:    discordgo.DangerButton,
+					CustomID: fmt.Sprintf("trade_draft_cancel:%s", userID),
+				},
+			},
+		},
+	}
+	return embed, components
+}
+
+// handleTradeItemSelect resolves the draft's item from the picker and shows
+// the order preview
+func (b *Bot) handleTradeItemSelect(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	values := i.MessageComponentData().Values
+	if len(values) == 0 {
+		return
+	}
+
+	draft, ok := b.tradeDrafts.Get(userID)
+	if !ok || draft.PendingItem == "" {
+		b.respondError(s, i, t(i.Locale, "trade.order_expired"))
+		return
+	}
+
+	ctx := context.Background()
+	var itemID int
+	var display string
+	if values[0] == "new" {
+		newItem, err := b.db.CreateItem(ctx, draft.PendingItem, draft.PendingItem, userID)
+		if errors.Is(err, database.ErrItemArchived) {
+			b.respondError(s, i, t(i.Locale, "item.archived", draft.PendingItem))
+			return
+		}
+		if err != nil {
+			log.Printf("Error creating item: %v", err)
+			b.respondError(s, i, t(i.Locale, "item.create_failed"))
+			return
+		}
+		itemID, display = newItem.ID, newItem.DisplayName
+	} else {
+		id, err := strconv.Atoi(values[0])
+		if err != nil || draft.ItemChoices[id] == "" {
+			b.respondError(s, i, t(i.Locale, "item.unknown_choice"))
+			return
+		}
+		itemID, display = id, draft.ItemChoices[id]
+	}
+
+	draft, ok = b.tradeDrafts.SetItem(userID, itemID, display)
+	if !ok {
+		b.respondError(s, i, t(i.Locale, "trade.order_expired"))
+		return
+	}
+	draft.PriceWarning = b.checkTradePrice(ctx, itemID, draft.Order.Price)
+	b.tradeDrafts.Put(&draft)
+
+	// Picking an item is already interactive, so always show the preview
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{tradeOrderEmbed(draft, nil)},
+			Components: tradePreviewComponents(userID),
 		},
 	})
 }
@@ -227,14 +801,15 @@ func (b *Bot) handleTradeSearch(s *discordgo.Session, i *discordgo.InteractionCr
 	ctx := context.Background()
 
 	var itemID, portID, minPrice, maxPrice int
-	var orderType string
+	var orderType, trader, sort string
+	var byReputation, availableOnly bool
 
 	if opt := options["item"]; opt != nil {
 		matches, err := b.db.FindItemMatches(ctx, opt.StringValue(), 1)
 		if err == nil && len(matches) > 0 {
 			itemID = matches[0].Item.ID
 		} else {
-			b.respondError(s, i, fmt.Sprintf("Item not found: '%s'", opt.StringValue()))
+			b.respondError(s, i, t(i.Locale, "search.item_not_found", opt.StringValue()))
 			return
 		}
 	}
@@ -249,27 +824,79 @@ func (b *Bot) handleTradeSearch(s *discordgo.Session, i *discordgo.InteractionCr
 	if opt := options["type"]; opt != nil {
 		orderType = opt.StringValue()
 	}
+	if opt := options["trader"]; opt != nil {
+		trader = strings.TrimSpace(opt.StringValue())
+	}
 	if opt := options["min-price"]; opt != nil {
 		minPrice = int(opt.IntValue())
 	}
 	if opt := options["max-price"]; opt != nil {
 		maxPrice = int(opt.IntValue())
 	}
+	// Reputation is ranked here; every other sort is done by the query
+	if opt := options["sort"]; opt != nil {
+		sort = opt.StringValue()
+		if sort == "reputation" {
+			byReputation, sort = true, ""
+		} else if _, ok := database.PlayerOrderSorts[sort]; !ok {
+			b.respondError(s, i, t(i.Locale, "search.unknown_sort", sort))
+			return
+		}
+	}
+	if opt := options["available-only"]; opt != nil {
+		availableOnly = opt.BoolValue()
+	}
 
-	orders, err := b.db.SearchPlayerOrders(ctx, itemID, orderType, portID, minPrice, maxPrice, 20)
+	// Fetch extra candidates when ranking or filtering so the best matches
+	// aren't cut off by the limit
+	limit := 20
+	if byReputation || availableOnly {
+		limit = 100
+	}
+
+	orders, err := b.db.SearchPlayerOrders(ctx, itemID, orderType, portID, trader, minPrice, maxPrice, sort, limit)
 	if err != nil {
 		log.Printf("Error searching player orders: %v", err)
-		b.respondError(s, i, "Database error")
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
+	var traderIDs []string
+	for _, o := range orders {
+		traderIDs = append(traderIDs, o.UserID)
+	}
+	ratings, err := b.db.GetAverageRatings(ctx, traderIDs)
+	if err != nil {
+		log.Printf("Error getting trader ratings: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	if byReputation || availableOnly {
+		orders = rankTradeOrders(orders, ratings, b.tradeConversations.HasActiveConversation, availableOnly, byReputation)
+		if len(orders) > 20 {
+			orders = orders[:20]
+		}
+	}
+
 	if len(orders) == 0 {
-		b.respondError(s, i, "No player orders found matching your criteria")
+		b.respondError(s, i, t(i.Locale, "search.no_results"))
+		return
+	}
+
+	orderIDs := make([]int, len(orders))
+	for idx, o := range orders {
+		orderIDs[idx] = o.ID
+	}
+	interest, err := b.db.GetOrderInterestCounts(ctx, orderIDs)
+	if err != nil {
+		log.Printf("Error getting order interest: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
 	embed := &discordgo.MessageEmbed{
-		Title:       "🔍 Player Trade Orders",
+		Title:       emojiSearch + " Player Trade Orders",
 		Description: fmt.Sprintf("Found %d order(s)", len(orders)),
 		Color:       0xf39c12,
 		Timestamp:   time.Now().Format(time.RFC3339),
@@ -285,22 +912,22 @@ func (b *Bot) handleTradeSearch(s *discordgo.Session, i *discordgo.InteractionCr
 
 	for idx := 0; idx < displayCount; idx++ {
 		o := orders[idx]
-		typeEmoji := "📗"
-		if o.OrderType == "sell" {
-			typeEmoji = "📕"
-		}
+		typeEmoji := orderTypeEmoji(o.OrderType)
 
 		portInfo := ""
 		if o.Port != nil {
 			portInfo = fmt.Sprintf(" @ %s", o.Port.DisplayName)
 		}
 
-		value := fmt.Sprintf("%s **%s** %s%s - %d gold x%d\nBy: **%s** | Expires <t:%d:R>",
+		value := fmt.Sprintf("%s **%s** %s%s - %s x%d (total %s)\nBy: **%s** (%s) | Expires <t:%d:R>",
 			typeEmoji, strings.ToUpper(o.OrderType), o.Item.DisplayName, portInfo,
-			o.Price, o.Quantity, o.IngameName, o.ExpiresAt.Unix())
+			formatGold(int64(o.Price)), o.Quantity, formatGold(orderTotal(o.Price, o.Quantity)), safeName(o.IngameName), formatRating(ratings[o.UserID]), o.ExpiresAt.Unix())
 
+		if shown := formatInterest(interest[o.ID]); shown != "" {
+			value += " | " + shown
+		}
 		if o.Notes != "" {
-			value += fmt.Sprintf("\n> %s", o.Notes)
+			value += fmt.Sprintf("\n> %s", sanitizeUserLine(o.Notes, maxNotesPreview))
 		}
 
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
@@ -309,8 +936,9 @@ func (b *Bot) handleTradeSearch(s *discordgo.Session, i *discordgo.InteractionCr
 		})
 	}
 
-	// Add contact buttons (max 5 per action row)
-	var buttons []discordgo.MessageComponent
+	// Add contact and interest buttons (max 5 per action row)
+	viewerID := getUserID(i)
+	var buttons, interestButtons []discordgo.MessageComponent
 	buttonCount := displayCount
 	if buttonCount > 5 {
 		buttonCount = 5
@@ -322,11 +950,14 @@ func (b *Bot) handleTradeSearch(s *discordgo.Session, i *discordgo.InteractionCr
 			Style:    discordgo.PrimaryButton,
 			CustomID: fmt.Sprintf("trade_contact_%d", o.ID),
 		})
+		interestButtons = append(interestButtons, orderInterestButton(o.ID, o.UserID == viewerID))
 	}
 
 	var components []discordgo.MessageComponent
 	if len(buttons) > 0 {
-		components = append(components, discordgo.ActionsRow{Components: buttons})
+		components = append(components,
+			discordgo.ActionsRow{Components: buttons},
+			discordgo.ActionsRow{Components: interestButtons})
 	}
 
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -347,12 +978,23 @@ func (b *Bot) handleTradeMyOrders(s *discordgo.Session, i *discordgo.Interaction
 	orders, err := b.db.GetPlayerOrdersByUser(ctx, userID)
 	if err != nil {
 		log.Printf("Error getting user orders: %v", err)
-		b.respondError(s, i, "Database error")
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
 	if len(orders) == 0 {
-		b.respondEphemeral(s, i, "You have no active trade orders. Create one with `/trade-create`")
+		b.respondEphemeral(s, i, t(i.Locale, "my_orders.none"))
+		return
+	}
+
+	orderIDs := make([]int, len(orders))
+	for idx, o := range orders {
+		orderIDs[idx] = o.ID
+	}
+	interest, err := b.db.GetOrderInterestCounts(ctx, orderIDs)
+	if err != nil {
+		log.Printf("Error getting order interest: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
 		return
 	}
 
@@ -364,22 +1006,102 @@ func (b *Bot) handleTradeMyOrders(s *discordgo.Session, i *discordgo.Interaction
 	}
 
 	for _, o := range orders {
-		typeEmoji := "📗"
-		if o.OrderType == "sell" {
-			typeEmoji = "📕"
+		typeEmoji := orderTypeEmoji(o.OrderType)
+
+		portInfo := "Any port"
+		if o.Port != nil {
+			portInfo = o.Port.DisplayName
+		}
+
+		expiry := fmt.Sprintf("Expires <t:%d:R>", o.ExpiresAt.Unix())
+		if o.ActivatesAt != nil && o.ActivatesAt.After(time.Now()) {
+			expiry = fmt.Sprintf("⏳ Starts <t:%d:R>, expires <t:%d:R>", o.ActivatesAt.Unix(), o.ExpiresAt.Unix())
+		}
+
+		value := fmt.Sprintf("%s %s | %s x%d (total %s) | Port: %s\n%s",
+			typeEmoji, o.Item.DisplayName, formatGold(int64(o.Price)), o.Quantity, formatGold(orderTotal(o.Price, o.Quantity)),
+			portInfo, expiry)
+		if shown := formatInterest(interest[o.ID]); shown != "" {
+			value += " | " + shown
+		}
+
+		if o.Notes != "" {
+			value += fmt.Sprintf("\n> %s", sanitizeUserLine(o.Notes, maxNotesPreview))
 		}
 
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("Order #%d", o.ID),
+			Value: value,
+		})
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// --- /trade-my-history ---
+
+// tradeHistoryLimit caps /trade-my-history at Discord's embed field limit
+const tradeHistoryLimit = 25
+
+func (b *Bot) handleTradeMyHistory(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	ctx := context.Background()
+
+	var statuses []string
+	options := parseOptions(i.ApplicationCommandData().Options)
+	if opt := options["status"]; opt != nil {
+		statuses = []string{opt.StringValue()}
+	}
+
+	orders, err := b.db.GetPlayerOrderHistory(ctx, userID, statuses, tradeHistoryLimit)
+	if err != nil {
+		log.Printf("Error getting order history: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	if len(orders) == 0 {
+		b.respondEphemeral(s, i, t(i.Locale, "history.none"))
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🗂️ Your Trade Order History",
+		Description: fmt.Sprintf("%d recent order(s)", len(orders)),
+		Color:       0x95a5a6,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	for _, o := range orders {
+		typeEmoji := orderTypeEmoji(o.OrderType)
+
 		portInfo := "Any port"
 		if o.Port != nil {
 			portInfo = o.Port.DisplayName
 		}
 
-		value := fmt.Sprintf("%s %s | %d gold x%d | Port: %s\nExpires <t:%d:R>",
-			typeEmoji, o.Item.DisplayName, o.Price, o.Quantity,
-			portInfo, o.ExpiresAt.Unix())
+		var outcome string
+		switch {
+		case o.Status == "completed" && o.CompletedAt != nil:
+			outcome = fmt.Sprintf("✅ Completed <t:%d:R>", o.CompletedAt.Unix())
+		case o.Status == "cancelled" && o.CancelledAt != nil:
+			outcome = fmt.Sprintf("❌ Cancelled <t:%d:R>", o.CancelledAt.Unix())
+		default:
+			outcome = fmt.Sprintf("⌛ Expired <t:%d:R>", o.ExpiresAt.Unix())
+		}
+
+		value := fmt.Sprintf("%s %s %s | %s x%d | Port: %s\nCreated <t:%d:d> | %s",
+			typeEmoji, strings.ToUpper(o.OrderType), o.Item.DisplayName, formatGold(int64(o.Price)), o.Quantity,
+			portInfo, o.CreatedAt.Unix(), outcome)
 
 		if o.Notes != "" {
-			value += fmt.Sprintf("\n> %s", o.Notes)
+			value += fmt.Sprintf("\n> %s", sanitizeUserLine(o.Notes, maxNotesPreview))
 		}
 
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
@@ -397,6 +1119,159 @@ func (b *Bot) handleTradeMyOrders(s *discordgo.Session, i *discordgo.Interaction
 	})
 }
 
+// --- /trade-profile ---
+
+func (b *Bot) handleTradeProfile(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	targetID := userID
+
+	options := parseOptions(i.ApplicationCommandData().Options)
+	if opt := options["user"]; opt != nil {
+		targetID = opt.UserValue(s).ID
+	}
+	self := targetID == userID
+
+	ctx := context.Background()
+	profile, err := b.db.GetPlayerProfile(ctx, targetID)
+	if err != nil {
+		log.Printf("Error getting player profile: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+	if profile == nil {
+		if self {
+			b.respondEphemeral(s, i, t(i.Locale, "profile.self_missing"))
+		} else {
+			b.respondEphemeral(s, i, t(i.Locale, "profile.missing", targetID))
+		}
+		return
+	}
+
+	activeOrders, err := b.db.CountActivePlayerOrders(ctx, targetID)
+	if err != nil {
+		log.Printf("Error counting active orders: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	rating, err := b.db.GetAverageRating(ctx, targetID)
+	if err != nil {
+		log.Printf("Error getting rating: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("🧭 %s", profile.IngameName),
+		Color: 0x3498db,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Discord", Value: fmt.Sprintf("<@%s>", targetID), Inline: true},
+			{Name: "In-Game Name", Value: safeName(profile.IngameName), Inline: true},
+			{Name: "Active Orders", Value: fmt.Sprintf("%d", activeOrders), Inline: true},
+			{Name: "Rating", Value: formatRating(rating), Inline: true},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	// Completed trades and standing are only shown to the profile owner
+	if self {
+		completed, err := b.db.CountCompletedPlayerOrders(ctx, targetID)
+		if err != nil {
+			log.Printf("Error counting completed orders: %v", err)
+			b.respondError(s, i, t(i.Locale, "error.database"))
+			return
+		}
+
+		ban, err := b.bans.IsUserBanned(ctx, targetID)
+		if err != nil {
+			log.Printf("Error checking trade ban: %v", err)
+			b.respondError(s, i, t(i.Locale, "trade.status_check_failed"))
+			return
+		}
+
+		reports, err := b.db.CountPendingReportsAgainst(ctx, targetID)
+		if err != nil {
+			log.Printf("Error counting pending reports: %v", err)
+			b.respondError(s, i, t(i.Locale, "error.database"))
+			return
+		}
+
+		standing := "✅ Good standing"
+		if ban != nil {
+			standing = fmt.Sprintf("🚫 Banned from trading: %s", ban.Reason)
+			if ban.ExpiresAt != nil {
+				standing += fmt.Sprintf("\nExpires <t:%d:R>", ban.ExpiresAt.Unix())
+			}
+			embed.Color = 0xe74c3c
+		} else if reports > 0 {
+			standing = fmt.Sprintf("⚠️ %d report(s) awaiting admin review", reports)
+			embed.Color = 0xf39c12
+		}
+
+		embed.Fields = append(embed.Fields,
+			&discordgo.MessageEmbedField{Name: "Completed Orders", Value: fmt.Sprintf("%d", completed), Inline: true},
+			&discordgo.MessageEmbedField{Name: "Standing", Value: standing},
+		)
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// orderErrorKeys maps why an order can't be used to the message explaining it
+var orderErrorKeys = []struct {
+	err error
+	key string
+}{
+	{database.ErrOrderNotFound, "order.not_found"},
+	{database.ErrOrderNotStarted, "order.not_started"},
+	{database.ErrOrderExpired, "order.expired"},
+	{database.ErrOrderCompleted, "order.completed"},
+	{database.ErrOrderCancelled, "order.cancelled"},
+	{database.ErrNotOrderOwner, "order.not_yours"},
+}
+
+// orderErrorMessage explains why an order ID can't be used, or returns "" if
+// err isn't one of the database's order errors
+func orderErrorMessage(locale discordgo.Locale, orderID int, err error) string {
+	for _, oe := range orderErrorKeys {
+		if errors.Is(err, oe.err) {
+			return t(locale, oe.key, orderID)
+		}
+	}
+	return ""
+}
+
+// lookupLiveOrder fetches the order a trade command names. If it can't be
+// used it returns nil and the reason to show the user.
+func (b *Bot) lookupLiveOrder(ctx context.Context, locale discordgo.Locale, orderID int) (*database.PlayerOrder, string) {
+	order, err := b.db.GetPlayerOrder(ctx, orderID)
+	if err != nil {
+		log.Printf("Error getting order %d: %v", orderID, err)
+		return nil, t(locale, "error.database")
+	}
+	if order != nil {
+		return order, ""
+	}
+
+	_, err = b.db.CheckPlayerOrder(ctx, orderID)
+	if msg := orderErrorMessage(locale, orderID, err); msg != "" {
+		return nil, msg
+	}
+	if err != nil {
+		log.Printf("Error checking order %d: %v", orderID, err)
+		return nil, t(locale, "error.database")
+	}
+	// It went live between the two queries, which only a scheduled start or
+	// a restore could do
+	return nil, t(locale, "order.not_found", orderID)
+}
+
 // --- /trade-cancel ---
 
 func (b *Bot) handleTradeCancel(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -406,13 +1281,159 @@ func (b *Bot) handleTradeCancel(s *discordgo.Session, i *discordgo.InteractionCr
 
 	ctx := context.Background()
 	err := b.db.CancelPlayerOrder(ctx, orderID, userID)
+	if msg := orderErrorMessage(i.Locale, orderID, err); msg != "" {
+		b.respondError(s, i, msg)
+		return
+	}
 	if err != nil {
 		log.Printf("Error cancelling order: %v", err)
-		b.respondError(s, i, "Failed to cancel order. Make sure the order ID is correct and belongs to you.")
+		b.respondError(s, i, t(i.Locale, "cancel.failed"))
+		return
+	}
+
+	b.respondEphemeral(s, i, t(i.Locale, "cancel.done", orderID))
+}
+
+// --- /trade-cancel-all ---
+
+// handleTradeCancelAll asks the user to confirm cancelling every order they
+// have open. Admins take a trader's orders down by banning them instead.
+func (b *Bot) handleTradeCancelAll(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+
+	active, err := b.db.CountActivePlayerOrders(context.Background(), userID)
+	if err != nil {
+		log.Printf("Error counting active orders: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+	if active == 0 {
+		b.respondEphemeral(s, i, t(i.Locale, "cancel_all.none"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:    t(i.Locale, "cancel_all.confirm", active),
+			Components: tradeCancelAllComponents(i.Locale, userID),
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// tradeCancelAllComponents returns the confirm/keep buttons for /trade-cancel-all
+func tradeCancelAllComponents(locale discordgo.Locale, userID string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    t(locale, "cancel_all.confirm_button"),
+					Style:    discordgo.DangerButton,
+					CustomID: fmt.Sprintf("trade_cancel_all_confirm:%s", userID),
+				},
+				discordgo.Button{
+					Label:    t(locale, "cancel_all.keep_button"),
+					Style:    discordgo.SecondaryButton,
+					CustomID: "trade_cancel_all_keep",
+				},
+			},
+		},
+	}
+}
+
+// handleTradeCancelAllConfirm cancels the user's orders once they confirm
+func (b *Bot) handleTradeCancelAllConfirm(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) {
+	userID := getUserID(i)
+	if strings.TrimPrefix(customID, "trade_cancel_all_confirm:") != userID {
+		b.respondError(s, i, t(i.Locale, "cancel_all.not_yours"))
+		return
+	}
+
+	cancelled, err := b.db.CancelAllUserOrders(context.Background(), userID)
+	if err != nil {
+		log.Printf("Error cancelling all orders for %s: %v", userID, err)
+		b.respondError(s, i, t(i.Locale, "cancel_all.failed"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    t(i.Locale, "cancel_all.done", cancelled),
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+// handleTradeCancelAllKeep dismisses the /trade-cancel-all prompt
+func (b *Bot) handleTradeCancelAllKeep(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    t(i.Locale, "cancel_all.kept"),
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+// --- /trade-relist ---
+
+func (b *Bot) handleTradeRelist(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	options := parseOptions(i.ApplicationCommandData().Options)
+	orderID := int(options["order-id"].IntValue())
+	duration := ""
+	if opt := options["duration"]; opt != nil {
+		duration = opt.StringValue()
+	}
+
+	ctx := context.Background()
+	expiresAt := time.Now().Add(parseTradeDuration(duration))
+	err := b.db.ExtendPlayerOrder(ctx, orderID, userID, expiresAt)
+	if errors.Is(err, database.ErrNotOrderOwner) {
+		b.respondError(s, i, t(i.Locale, "relist.not_yours", orderID))
+		return
+	}
+	if msg := orderErrorMessage(i.Locale, orderID, err); msg != "" {
+		b.respondError(s, i, msg)
+		return
+	}
+	if err != nil {
+		log.Printf("Error extending order: %v", err)
+		b.respondError(s, i, t(i.Locale, "relist.failed"))
+		return
+	}
+
+	b.respondEphemeral(s, i, t(i.Locale, "relist.done", orderID, expiresAt.Unix()))
+}
+
+// --- /trade-complete ---
+
+func (b *Bot) handleTradeComplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	options := parseOptions(i.ApplicationCommandData().Options)
+	orderID := int(options["order-id"].IntValue())
+
+	// If the user is talking to someone about this order, that's who they traded with
+	counterpartyID := ""
+	if conv, ok := b.tradeConversations.GetByUser(userID); ok && conv.OrderID == orderID {
+		counterpartyID, _ = conv.GetOtherParty(userID)
+	}
+
+	ctx := context.Background()
+	err := b.db.CompletePlayerOrder(ctx, orderID, userID, counterpartyID)
+	if err != nil {
+		log.Printf("Error completing order: %v", err)
+		b.respondError(s, i, t(i.Locale, "complete.failed"))
 		return
 	}
 
-	b.respondEphemeral(s, i, fmt.Sprintf("Order #%d has been cancelled.", orderID))
+	if counterpartyID != "" {
+		b.respondEphemeral(s, i, t(i.Locale, "complete.done_with", orderID, counterpartyID))
+		return
+	}
+	b.respondEphemeral(s, i, t(i.Locale, "complete.done", orderID))
 }
 
 // --- /trade-contact (slash command) ---
@@ -449,39 +1470,39 @@ func (b *Bot) initiateTradeContact(s *discordgo.Session, i *discordgo.Interactio
 	// Check user has a profile
 	profile, err := b.db.GetPlayerProfile(ctx, userID)
 	if err != nil || profile == nil {
-		b.respondError(s, i, "You need to set your in-game name first. Use `/trade-set-name`")
+		b.respondError(s, i, t(i.Locale, "trade.name_required"))
 		return
 	}
 
 	// Check if initiating user is banned from trading
-	ban, err := b.db.IsUserBanned(ctx, userID)
+	ban, err := b.bans.IsUserBanned(ctx, userID)
 	if err != nil {
 		log.Printf("Error checking trade ban: %v", err)
-		b.respondError(s, i, "Failed to verify trading status")
+		b.respondError(s, i, t(i.Locale, "trade.status_check_failed"))
 		return
 	}
 	if ban != nil {
-		b.respondError(s, i, "You are banned from trading and cannot contact other traders.")
+		b.respondError(s, i, t(i.Locale, "contact.banned"))
 		return
 	}
 
 	// Get the order
-	order, err := b.db.GetPlayerOrder(ctx, orderID)
-	if err != nil || order == nil {
-		b.respondError(s, i, "Order not found or has expired")
+	order, problem := b.lookupLiveOrder(ctx, i.Locale, orderID)
+	if order == nil {
+		b.respondError(s, i, problem)
 		return
 	}
 
 	// Check if order creator is banned (safety net)
-	creatorBan, _ := b.db.IsUserBanned(ctx, order.UserID)
+	creatorBan, _ := b.bans.IsUserBanned(ctx, order.UserID)
 	if creatorBan != nil {
-		b.respondError(s, i, "This order is no longer available.")
+		b.respondError(s, i, t(i.Locale, "contact.unavailable"))
 		return
 	}
 
 	// Can't contact yourself
 	if order.UserID == userID {
-		b.respondError(s, i, "You cannot contact yourself about your own order")
+		b.respondError(s, i, t(i.Locale, "contact.own_order"))
 		return
 	}
 
@@ -498,17 +1519,17 @@ func (b *Bot) initiateTradeContact(s *discordgo.Session, i *discordgo.Interactio
 	ac := &ActiveConversation{
 		OrderID:             orderID,
 		InitiatorUserID:     userID,
-		InitiatorIngameName: profile.IngameName,
+		InitiatorIngameName: safeName(profile.IngameName),
 		CreatorUserID:       order.UserID,
-		CreatorIngameName:   order.IngameName,
+		CreatorIngameName:   safeName(order.IngameName),
 	}
 
 	if !b.tradeConversations.TryRegister(ac) {
 		// Check which party is busy
 		if b.tradeConversations.HasActiveConversation(userID) {
-			b.respondError(s, i, "You already have an active trade conversation. End it with `/trade-end` first.")
+			b.respondError(s, i, t(i.Locale, "contact.busy_self"))
 		} else {
-			b.respondError(s, i, "The order creator is currently in another trade conversation. Try again later.")
+			b.respondError(s, i, t(i.Locale, "contact.busy_creator"))
 		}
 		return
 	}
@@ -517,7 +1538,7 @@ func (b *Bot) initiateTradeContact(s *discordgo.Session, i *discordgo.Interactio
 	if err != nil {
 		log.Printf("Error creating trade conversation: %v", err)
 		b.tradeConversations.Remove(ac) // Rollback in-memory registration
-		b.respondError(s, i, "Failed to start trade conversation")
+		b.respondError(s, i, t(i.Locale, "contact.failed"))
 		return
 	}
 
@@ -525,21 +1546,19 @@ func (b *Bot) initiateTradeContact(s *discordgo.Session, i *discordgo.Interactio
 	ac.ConversationID = created.ID
 
 	// Respond to the initiator
-	b.respondEphemeral(s, i, fmt.Sprintf(
-		"Trade conversation started! Check your DMs to chat with **%s** about order #%d (%s %s).\n\nUse `/trade-end` to close the conversation.",
-		order.IngameName, orderID, strings.ToUpper(order.OrderType), order.Item.DisplayName,
-	))
+	b.respondEphemeral(s, i, t(i.Locale, "contact.started",
+		ac.CreatorIngameName, orderID, strings.ToUpper(order.OrderType), order.Item.DisplayName))
 
 	// DM the initiator with instructions
 	initiatorCh, err := s.UserChannelCreate(userID)
 	if err == nil {
 		initiatorEmbed := &discordgo.MessageEmbed{
-			Title:       "🤝 Trade Conversation Started",
-			Description: fmt.Sprintf("You're now chatting with **%s** about order #%d", order.IngameName, orderID),
+			Title:       emojiHandshake + " Trade Conversation Started",
+			Description: fmt.Sprintf("You're now chatting with **%s** about order #%d", ac.CreatorIngameName, orderID),
 			Color:       0x2ecc71,
 			Fields: []*discordgo.MessageEmbedField{
-				{Name: "Order", Value: fmt.Sprintf("%s %s - %d gold x%d",
-					strings.ToUpper(order.OrderType), order.Item.DisplayName, order.Price, order.Quantity)},
+				{Name: "Order", Value: fmt.Sprintf("%s %s - %s x%d",
+					strings.ToUpper(order.OrderType), order.Item.DisplayName, formatGold(int64(order.Price)), order.Quantity)},
 				{Name: "How to chat", Value: "Type your messages here and they'll be relayed to the other trader."},
 				{Name: "To end", Value: "Use `/trade-end` to close this conversation."},
 			},
@@ -555,12 +1574,12 @@ func (b *Bot) initiateTradeContact(s *discordgo.Session, i *discordgo.Interactio
 	}
 
 	creatorEmbed := &discordgo.MessageEmbed{
-		Title:       "🤝 Trade Conversation Started",
-		Description: fmt.Sprintf("**%s** wants to discuss your order #%d", profile.IngameName, orderID),
+		Title:       emojiHandshake + " Trade Conversation Started",
+		Description: fmt.Sprintf("**%s** wants to discuss your order #%d", ac.InitiatorIngameName, orderID),
 		Color:       0x2ecc71,
 		Fields: []*discordgo.MessageEmbedField{
-			{Name: "Order", Value: fmt.Sprintf("%s %s - %d gold x%d",
-				strings.ToUpper(order.OrderType), order.Item.DisplayName, order.Price, order.Quantity)},
+			{Name: "Order", Value: fmt.Sprintf("%s %s - %s x%d",
+				strings.ToUpper(order.OrderType), order.Item.DisplayName, formatGold(int64(order.Price)), order.Quantity)},
 			{Name: "How to respond", Value: "Type your messages here and they'll be relayed to the other trader."},
 			{Name: "To end", Value: "Use `/trade-end` to close this conversation."},
 		},
@@ -576,7 +1595,7 @@ func (b *Bot) handleTradeEnd(s *discordgo.Session, i *discordgo.InteractionCreat
 
 	ac, ok := b.tradeConversations.GetByUser(userID)
 	if !ok {
-		b.respondError(s, i, "You don't have an active trade conversation")
+		b.respondError(s, i, t(i.Locale, "end.none"))
 		return
 	}
 
@@ -592,14 +1611,91 @@ func (b *Bot) handleTradeEnd(s *discordgo.Session, i *discordgo.InteractionCreat
 	b.tradeConversations.Remove(ac)
 
 	// Respond to the user who ended it
-	b.respondEphemeral(s, i, fmt.Sprintf("Trade conversation with **%s** has been ended.", otherIngameName))
+	b.respondEphemeral(s, i, t(i.Locale, "end.done", otherIngameName))
 
 	// Notify the other party via DM
 	otherCh, err := s.UserChannelCreate(otherUserID)
 	if err == nil {
-		s.ChannelMessageSend(otherCh.ID, fmt.Sprintf(
-			"**%s** has ended the trade conversation. You can browse more trades with `/trade-search`.",
-			myIngameName,
-		))
+		s.ChannelMessageSend(otherCh.ID, t(b.userLocale(otherUserID), "end.notice", myIngameName))
+	}
+
+	// Ask both parties to rate each other
+	b.sendRatingPrompt(s, userID, otherIngameName, ac.ConversationID)
+	b.sendRatingPrompt(s, otherUserID, myIngameName, ac.ConversationID)
+}
+
+// --- Trade ratings ---
+
+// sendRatingPrompt DMs a user buttons to rate their partner in a conversation
+func (b *Bot) sendRatingPrompt(s *discordgo.Session, userID, partnerName string, convID int) {
+	ch, err := s.UserChannelCreate(userID)
+	if err != nil {
+		log.Printf("Error opening DM for rating prompt: %v", err)
+		return
 	}
+
+	_, err = s.ChannelMessageSendComplex(ch.ID, &discordgo.MessageSend{
+		Content:    t(b.userLocale(userID), "rating.prompt", partnerName),
+		Components: ratingPromptComponents(convID),
+	})
+	if err != nil {
+		log.Printf("Error sending rating prompt: %v", err)
+	}
+}
+
+func ratingPromptComponents(convID int) []discordgo.MessageComponent {
+	var buttons []discordgo.MessageComponent
+	for stars := 1; stars <= 5; stars++ {
+		buttons = append(buttons, discordgo.Button{
+			Label:    fmt.Sprintf("%d ★", stars),
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("rate:%d:%d", convID, stars),
+		})
+	}
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+}
+
+// handleRateButton records a rating from a rate:<convID>:<stars> button
+func (b *Bot) handleRateButton(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) {
+	parts := strings.Split(customID, ":")
+	if len(parts) != 3 {
+		b.respondError(s, i, t(i.Locale, "trade.rating_invalid"))
+		return
+	}
+	convID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		b.respondError(s, i, t(i.Locale, "trade.rating_invalid"))
+		return
+	}
+	stars, err := strconv.Atoi(parts[2])
+	if err != nil {
+		b.respondError(s, i, t(i.Locale, "trade.rating_invalid"))
+		return
+	}
+
+	_, err = b.db.CreateTradeRating(context.Background(), database.TradeRating{
+		RaterUserID:    getUserID(i),
+		ConversationID: convID,
+		Stars:          stars,
+	})
+	switch {
+	case errors.Is(err, database.ErrAlreadyRated):
+		b.respondEphemeral(s, i, t(i.Locale, "rating.already"))
+		return
+	case errors.Is(err, database.ErrNotConversationParty):
+		b.respondError(s, i, t(i.Locale, "rating.not_party"))
+		return
+	case err != nil:
+		log.Printf("Error creating trade rating: %v", err)
+		b.respondError(s, i, t(i.Locale, "rating.save_failed"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    t(i.Locale, "rating.thanks", strings.Repeat("★", stars)+strings.Repeat("☆", 5-stars)),
+			Components: []discordgo.MessageComponent{},
+		},
+	})
 }
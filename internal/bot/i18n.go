@@ -0,0 +1,342 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// defaultLanguage is the catalog every key must exist in. Other languages
+// fall back to it for anything they haven't translated.
+const defaultLanguage = "en"
+
+// messages holds user-facing strings by language, then key. Languages are
+// the part of a Discord locale before the dash, so "en-GB" and "en-US" share
+// one catalog. Values are fmt formats filled from t's args.
+var messages = map[string]map[string]string{
+	"en": {
+		"error.database":                 "Database error",
+		"error.guild_only":               "This command must be used in a server",
+		"error.unknown_command":          "Unknown command",
+		"error.admin_required":           "This command requires the admin role",
+		"error.invalid_page":             "Invalid page",
+		"error.save_config":              "Failed to save configuration",
+		"error.item_search":              "Database error during item search",
+		"error.item_matching":            "Database error during item matching",
+		"error.port_matching":            "Database error during port matching",
+		"trade.status_check_failed":      "Failed to verify trading status",
+		"trade.preview_expired":          "This preview has expired. Run `/trade-create` again.",
+		"trade.order_expired":            "This order has expired. Run `/trade-create` again.",
+		"order.not_found":                "Order #%d doesn't exist. Check the ID with `/trade-search` or `/trade-my-orders`.",
+		"order.not_started":              "Order #%d hasn't started yet.",
+		"order.expired":                  "Order #%d has expired.",
+		"order.completed":                "Order #%d has already been completed.",
+		"order.cancelled":                "Order #%d was cancelled.",
+		"order.not_yours":                "Order #%d isn't yours, so you can't cancel it.",
+		"relist.not_yours":               "Order #%d isn't yours, so you can't extend it.",
+		"trade.create_failed":            "Failed to create order",
+		"trade.order_limit":              "You have the maximum of %d active orders; cancel one first with `/trade-cancel`.",
+		"trade.name_required":            "You need to set your in-game name first. Use `/trade-set-name`",
+		"trade.name_length":              "In-game name must be between 2 and 50 characters",
+		"trade.name_save_failed":         "Failed to save your in-game name",
+		"trade.name_update_failed":       "Failed to update in-game name",
+		"filter.name_blocked":            "That in-game name contains a word that isn't allowed here. Please choose another.",
+		"filter.notes_blocked":           "Your notes contain a word that isn't allowed here. Please reword them.",
+		"trade.rating_invalid":           "Invalid rating",
+		"tags.add_failed":                "Failed to add tags",
+		"report.duplicate":               "You already reported this trader; an admin will review it.",
+		"interest.banned":                "You are banned from trading.",
+		"interest.own_order":             "That's your own order.",
+		"cancel_all.none":                "You have no active orders to cancel.",
+		"cancel_all.confirm":             "⚠️ This will cancel all **%d** of your active orders, including any scheduled to start later. Cancelled orders can't be restored. Are you sure?",
+		"cancel_all.confirm_button":      "Cancel All My Orders",
+		"cancel_all.keep_button":         "Keep Them",
+		"cancel_all.not_yours":           "Only the person who ran `/trade-cancel-all` can confirm it",
+		"cancel_all.failed":              "Failed to cancel your orders",
+		"cancel_all.done":                "✅ Cancelled %d of your orders.",
+		"cancel_all.kept":                "Your orders were left as they are.",
+		"item.archived":                  "**%s** has been retired by the admins, so it can't be added again.",
+		"submit.expired":                 "⌛ This submission has expired. Please re-run `/submit` with your screenshot(s).",
+		"submit.timed_out_dm":            "⌛ Your submission timed out. Please re-run `/submit`.",
+		"submit.in_progress":             "You already have a submission in progress. Finish or cancel it first.",
+		"submit.in_progress_until":       "You already have a submission in progress. Finish or cancel it first (it expires <t:%d:R>).",
+		"submit.image_missing":           "Could not find attached image",
+		"submit.image_download_failed":   "Failed to download image",
+		"submit.no_items":                "No items found in the screenshots. Make sure the market list is fully visible and try again.",
+		"submit.store_failed":            "Failed to store market data",
+		"trade.name_taken":               "**%s** is already registered by another player. If it's yours, ask an admin for help.",
+		"trade.name_set":                 "Your in-game name has been set to **%s**",
+		"trade.banned":                   "You are banned from trading. Reason: %s",
+		"trade.banned_until":             "Ban expires: <t:%d:R>",
+		"trade.ban_expired":              "✅ Your trade ban has expired. You can use `/trade-create` and `/trade-contact` again.",
+		"trade.price_invalid":            "Price must be greater than 0",
+		"trade.quantity_invalid":         "Quantity must be greater than 0",
+		"trade.price_whole":              "Price must be a whole number greater than 0",
+		"trade.quantity_whole":           "Quantity must be a whole number greater than 0",
+		"trade.port_not_found":           "Port not found: '%s'. Ask an admin to add it with `/admin-port-add`, or omit the port.",
+		"trade.start_invalid":            "Start must be a delay like `2h` or `1d`, or a UTC time like `%s`, within the next %d days.",
+		"trade.created":                  "✅ Order #%d created.",
+		"trade.draft_cancelled":          "Order cancelled.",
+		"trade.edit_title":               "Edit Order",
+		"trade.edit_price":               "Price per unit (gold)",
+		"trade.edit_quantity":            "Quantity",
+		"trade.edit_notes":               "Notes",
+		"item.create_failed":             "Failed to create new item",
+		"item.unknown_choice":            "Unknown item selection",
+		"search.item_not_found":          "Item not found: '%s'",
+		"search.unknown_sort":            "Unknown sort '%s'",
+		"search.no_results":              "No player orders found matching your criteria",
+		"my_orders.none":                 "You have no active trade orders. Create one with `/trade-create`",
+		"history.none":                   "You have no past trade orders. Active orders are listed in `/trade-my-orders`",
+		"profile.self_missing":           "You haven't set an in-game name yet. Use `/trade-set-name`",
+		"profile.missing":                "<@%s> hasn't set up a trading profile.",
+		"cancel.failed":                  "Failed to cancel order",
+		"cancel.done":                    "Order #%d has been cancelled.",
+		"relist.failed":                  "Failed to extend order",
+		"relist.done":                    "Order #%d now expires <t:%d:R>.",
+		"complete.failed":                "Failed to complete order. Make sure the order ID is correct and belongs to you.",
+		"complete.done":                  "Order #%d has been marked as completed.",
+		"complete.done_with":             "Order #%d has been marked as completed. Traded with <@%s>.",
+		"interest.already":               "You've already shown interest in order #%d (👀 %d interested). Use Contact to talk to the trader.",
+		"interest.noted":                 "Interest in order #%d noted (👀 %d interested). The trader can see the count; use Contact when you're ready to trade.",
+		"interest.first_dm":              "👀 A trader is interested in your order #%d (%s %s). They can contact you with the order's Contact button; `/trade-view order-id:%d` shows how many are interested.",
+		"contact.banned":                 "You are banned from trading and cannot contact other traders.",
+		"contact.unavailable":            "This order is no longer available.",
+		"contact.own_order":              "You cannot contact yourself about your own order",
+		"contact.busy_self":              "You already have an active trade conversation. End it with `/trade-end` first.",
+		"contact.busy_creator":           "The order creator is currently in another trade conversation. Try again later.",
+		"contact.failed":                 "Failed to start trade conversation",
+		"contact.started":                "Trade conversation started! Check your DMs to chat with **%s** about order #%d (%s %s).\n\nUse `/trade-end` to close the conversation.",
+		"end.none":                       "You don't have an active trade conversation",
+		"end.done":                       "Trade conversation with **%s** has been ended.",
+		"end.notice":                     "**%s** has ended the trade conversation. You can browse more trades with `/trade-search`.",
+		"rating.prompt":                  "How was your trade with **%s**? Your rating appears on their profile and orders.",
+		"rating.already":                 "You've already rated this trade.",
+		"rating.not_party":               "You can only rate trades you took part in",
+		"rating.save_failed":             "Failed to save rating",
+		"rating.thanks":                  "Thanks! You rated this trade %s.",
+		"relay.no_conversation":          "You don't have an active trade conversation.\n\nUse `/trade-search` in a server to find orders, then `/trade-contact` to start chatting with a trader.",
+		"relay.too_long":                 "⚠️ That message is too long to relay. Please split it into shorter messages.",
+		"relay.shortened":                "✂️ That message was too long, so only the start of it was relayed.",
+		"relay.shortened_marker":         "*(shortened)*",
+		"relay.attachments_left_out":     "📎 Only %d attachments are relayed per message; %d were left out.",
+		"relay.shared":                   "**[%s]** shared:",
+		"relay.more_attachments":         "*(%d more not relayed)*",
+		"relay.edited":                   "**[%s]** edited a message:",
+		"relay.deleted":                  "**[%s]** deleted a message.",
+		"relay.closed_banned":            "Your trade conversation with **%s** has been closed because you are banned from trading.",
+		"relay.closed_partner_banned":    "Your trade conversation with **%s** has been closed because they are no longer allowed to trade. Messages will no longer be relayed.",
+		"relay.delivery_failed":          "Failed to deliver your message (%d/%d). The other trader may have DMs disabled.",
+		"relay.closed_undeliverable":     "Your last %d messages couldn't be delivered, so this trade conversation has been closed. Please arrange the trade with **%s** in-game instead.",
+		"relay.closed_inactive":          "Your trade conversation has been closed due to inactivity. Use `/trade-search` to find more trades.",
+		"submit.image_gif":               "**%s** is a GIF. Please upload a still PNG, JPEG or WebP screenshot.",
+		"submit.image_type":              "**%s** isn't a supported image. Screenshots must be PNG, JPEG or WebP.",
+		"submit.image_size":              "**%s** is %s; screenshots can be at most %s.",
+		"submit.which_only":              "The screenshot",
+		"submit.which_nth":               "Screenshot %d",
+		"submit.image_unusable":          "%s can't be used: %v. Please upload a full-size screenshot of the market.",
+		"submit.image_unreadable":        "%s couldn't be opened. Please upload a PNG, JPEG or WebP screenshot.",
+		"submit.image_failed":            "%s couldn't be processed. Please try again.",
+		"submit.analyze_failed":          "Failed to analyze screenshot: %v",
+		"submit.analyze_failed_nth":      "Failed to analyze screenshot %d: %v",
+		"submit.analyzing":               "⏳ Analyzing %d screenshot(s)...",
+		"submit.cancelled":               "Submission cancelled.",
+		"submit.none_pending":            "You have no pending submission. Use `/submit` to add market data.",
+		"submit.build_failed":            "Failed to build market orders",
+		"submit.port_hint_title":         "Which port is this?",
+		"submit.port_create_title":       "Create New Port",
+		"submit.port_name_label":         "Port Name",
+		"submit.port_name_placeholder":   "e.g., Port Royal",
+		"submit.port_region_label":       "Region",
+		"submit.port_region_placeholder": "e.g., Caribbean, Mediterranean",
+		"submit.port_notes_label":        "Notes (optional)",
+		"submit.port_notes_placeholder":  "Any additional information...",
+		"submit.port_required":           "Port name is required",
+		"submit.port_create_failed":      "Failed to create port",
+		"submit.edit_page_missing":       "That page of items no longer exists",
+		"submit.edit_rejected":           "Nothing was changed:\n%s",
+	},
+	"es": {
+		"error.database":                 "Error de la base de datos",
+		"error.guild_only":               "Este comando debe usarse en un servidor",
+		"error.unknown_command":          "Comando desconocido",
+		"error.admin_required":           "Este comando requiere el rol de administrador",
+		"error.invalid_page":             "Página no válida",
+		"error.save_config":              "No se pudo guardar la configuración",
+		"error.item_search":              "Error de la base de datos al buscar el objeto",
+		"error.item_matching":            "Error de la base de datos al identificar los objetos",
+		"error.port_matching":            "Error de la base de datos al identificar el puerto",
+		"trade.status_check_failed":      "No se pudo comprobar tu estado de comercio",
+		"trade.preview_expired":          "Esta vista previa ha caducado. Vuelve a ejecutar `/trade-create`.",
+		"trade.order_expired":            "Esta orden ha caducado. Vuelve a ejecutar `/trade-create`.",
+		"order.not_found":                "La orden #%d no existe. Comprueba el ID con `/trade-search` o `/trade-my-orders`.",
+		"order.not_started":              "La orden #%d todavía no ha empezado.",
+		"order.expired":                  "La orden #%d ha caducado.",
+		"order.completed":                "La orden #%d ya se ha completado.",
+		"order.cancelled":                "La orden #%d fue cancelada.",
+		"order.not_yours":                "La orden #%d no es tuya, así que no puedes cancelarla.",
+		"relist.not_yours":               "La orden #%d no es tuya, así que no puedes prolongarla.",
+		"trade.create_failed":            "No se pudo crear la orden",
+		"trade.order_limit":              "Ya tienes el máximo de %d órdenes activas; cancela una primero con `/trade-cancel`.",
+		"trade.name_required":            "Primero debes indicar tu nombre en el juego. Usa `/trade-set-name`",
+		"trade.name_length":              "El nombre en el juego debe tener entre 2 y 50 caracteres",
+		"trade.name_save_failed":         "No se pudo guardar tu nombre en el juego",
+		"trade.name_update_failed":       "No se pudo actualizar el nombre en el juego",
+		"filter.name_blocked":            "Ese nombre en el juego contiene una palabra que no está permitida aquí. Elige otro.",
+		"filter.notes_blocked":           "Tus notas contienen una palabra que no está permitida aquí. Reformúlalas.",
+		"trade.rating_invalid":           "Valoración no válida",
+		"tags.add_failed":                "No se pudieron añadir las etiquetas",
+		"report.duplicate":               "Ya denunciaste a este comerciante; un administrador lo revisará.",
+		"interest.banned":                "Tienes prohibido comerciar.",
+		"interest.own_order":             "Esa orden es tuya.",
+		"cancel_all.none":                "No tienes órdenes activas que cancelar.",
+		"cancel_all.confirm":             "⚠️ Esto cancelará las **%d** órdenes activas que tienes, incluidas las programadas para empezar más tarde. Las órdenes canceladas no se pueden recuperar. ¿Seguro?",
+		"cancel_all.confirm_button":      "Cancelar todas mis órdenes",
+		"cancel_all.keep_button":         "Conservarlas",
+		"cancel_all.not_yours":           "Solo quien ejecutó `/trade-cancel-all` puede confirmarlo",
+		"cancel_all.failed":              "No se pudieron cancelar tus órdenes",
+		"cancel_all.done":                "✅ Se cancelaron %d de tus órdenes.",
+		"cancel_all.kept":                "Tus órdenes se quedaron como estaban.",
+		"item.archived":                  "Los administradores retiraron **%s**, así que no se puede volver a añadir.",
+		"submit.expired":                 "⌛ Este envío ha caducado. Vuelve a ejecutar `/submit` con tus capturas.",
+		"submit.timed_out_dm":            "⌛ Tu envío ha caducado. Vuelve a ejecutar `/submit`.",
+		"submit.in_progress":             "Ya tienes un envío en curso. Termínalo o cancélalo primero.",
+		"submit.in_progress_until":       "Ya tienes un envío en curso. Termínalo o cancélalo primero (caduca <t:%d:R>).",
+		"submit.image_missing":           "No se encontró la imagen adjunta",
+		"submit.image_download_failed":   "No se pudo descargar la imagen",
+		"submit.no_items":                "No se encontraron objetos en las capturas. Asegúrate de que la lista del mercado se vea completa e inténtalo de nuevo.",
+		"submit.store_failed":            "No se pudieron guardar los datos del mercado",
+		"trade.name_taken":               "**%s** ya está registrado por otro jugador. Si es tuyo, pide ayuda a un administrador.",
+		"trade.name_set":                 "Tu nombre en el juego ahora es **%s**",
+		"trade.banned":                   "Tienes prohibido comerciar. Motivo: %s",
+		"trade.banned_until":             "La prohibición termina <t:%d:R>",
+		"trade.ban_expired":              "✅ Tu prohibición de comerciar ha terminado. Ya puedes volver a usar `/trade-create` y `/trade-contact`.",
+		"trade.price_invalid":            "El precio debe ser mayor que 0",
+		"trade.quantity_invalid":         "La cantidad debe ser mayor que 0",
+		"trade.price_whole":              "El precio debe ser un número entero mayor que 0",
+		"trade.quantity_whole":           "La cantidad debe ser un número entero mayor que 0",
+		"trade.port_not_found":           "Puerto no encontrado: '%s'. Pide a un administrador que lo añada con `/admin-port-add`, u omite el puerto.",
+		"trade.start_invalid":            "El inicio debe ser un retraso como `2h` o `1d`, o una hora UTC como `%s`, dentro de los próximos %d días.",
+		"trade.created":                  "✅ Orden #%d creada.",
+		"trade.draft_cancelled":          "Orden cancelada.",
+		"trade.edit_title":               "Editar orden",
+		"trade.edit_price":               "Precio por unidad (oro)",
+		"trade.edit_quantity":            "Cantidad",
+		"trade.edit_notes":               "Notas",
+		"item.create_failed":             "No se pudo crear el objeto nuevo",
+		"item.unknown_choice":            "Selección de objeto desconocida",
+		"search.item_not_found":          "Objeto no encontrado: '%s'",
+		"search.unknown_sort":            "Orden desconocido '%s'",
+		"search.no_results":              "No se encontraron órdenes de jugadores con esos criterios",
+		"my_orders.none":                 "No tienes órdenes activas. Crea una con `/trade-create`",
+		"history.none":                   "No tienes órdenes pasadas. Las órdenes activas aparecen en `/trade-my-orders`",
+		"profile.self_missing":           "Todavía no has indicado tu nombre en el juego. Usa `/trade-set-name`",
+		"profile.missing":                "<@%s> no ha creado un perfil de comercio.",
+		"cancel.failed":                  "No se pudo cancelar la orden",
+		"cancel.done":                    "La orden #%d ha sido cancelada.",
+		"relist.failed":                  "No se pudo prolongar la orden",
+		"relist.done":                    "La orden #%d ahora caduca <t:%d:R>.",
+		"complete.failed":                "No se pudo completar la orden. Comprueba que el ID es correcto y que la orden es tuya.",
+		"complete.done":                  "La orden #%d se ha marcado como completada.",
+		"complete.done_with":             "La orden #%d se ha marcado como completada. Comerciaste con <@%s>.",
+		"interest.already":               "Ya mostraste interés en la orden #%d (👀 %d interesados). Usa Contactar para hablar con el comerciante.",
+		"interest.noted":                 "Interés en la orden #%d registrado (👀 %d interesados). El comerciante ve el recuento; usa Contactar cuando quieras comerciar.",
+		"interest.first_dm":              "👀 Un comerciante está interesado en tu orden #%d (%s %s). Puede contactarte con el botón Contactar de la orden; `/trade-view order-id:%d` muestra cuántos están interesados.",
+		"contact.banned":                 "Tienes prohibido comerciar y no puedes contactar con otros comerciantes.",
+		"contact.unavailable":            "Esta orden ya no está disponible.",
+		"contact.own_order":              "No puedes contactarte a ti mismo por tu propia orden",
+		"contact.busy_self":              "Ya tienes una conversación de comercio activa. Termínala primero con `/trade-end`.",
+		"contact.busy_creator":           "El creador de la orden está en otra conversación de comercio. Inténtalo más tarde.",
+		"contact.failed":                 "No se pudo iniciar la conversación de comercio",
+		"contact.started":                "¡Conversación de comercio iniciada! Revisa tus mensajes directos para hablar con **%s** sobre la orden #%d (%s %s).\n\nUsa `/trade-end` para cerrar la conversación.",
+		"end.none":                       "No tienes ninguna conversación de comercio activa",
+		"end.done":                       "La conversación de comercio con **%s** ha terminado.",
+		"end.notice":                     "**%s** ha terminado la conversación de comercio. Puedes buscar más ofertas con `/trade-search`.",
+		"rating.prompt":                  "¿Qué tal fue tu comercio con **%s**? Tu valoración aparece en su perfil y en sus órdenes.",
+		"rating.already":                 "Ya valoraste este comercio.",
+		"rating.not_party":               "Solo puedes valorar comercios en los que participaste",
+		"rating.save_failed":             "No se pudo guardar la valoración",
+		"rating.thanks":                  "¡Gracias! Valoraste este comercio con %s.",
+		"relay.no_conversation":          "No tienes ninguna conversación de comercio activa.\n\nUsa `/trade-search` en un servidor para encontrar órdenes y luego `/trade-contact` para empezar a hablar con un comerciante.",
+		"relay.too_long":                 "⚠️ Ese mensaje es demasiado largo para reenviarlo. Divídelo en mensajes más cortos.",
+		"relay.shortened":                "✂️ Ese mensaje era demasiado largo, así que solo se reenvió el principio.",
+		"relay.shortened_marker":         "*(acortado)*",
+		"relay.attachments_left_out":     "📎 Solo se reenvían %d adjuntos por mensaje; %d se quedaron fuera.",
+		"relay.shared":                   "**[%s]** compartió:",
+		"relay.more_attachments":         "*(%d más sin reenviar)*",
+		"relay.edited":                   "**[%s]** editó un mensaje:",
+		"relay.deleted":                  "**[%s]** borró un mensaje.",
+		"relay.closed_banned":            "Tu conversación de comercio con **%s** se ha cerrado porque tienes prohibido comerciar.",
+		"relay.closed_partner_banned":    "Tu conversación de comercio con **%s** se ha cerrado porque ya no puede comerciar. Los mensajes ya no se reenviarán.",
+		"relay.delivery_failed":          "No se pudo entregar tu mensaje (%d/%d). Puede que el otro comerciante tenga los mensajes directos desactivados.",
+		"relay.closed_undeliverable":     "Tus últimos %d mensajes no se pudieron entregar, así que esta conversación de comercio se ha cerrado. Acuerda el comercio con **%s** dentro del juego.",
+		"relay.closed_inactive":          "Tu conversación de comercio se ha cerrado por inactividad. Usa `/trade-search` para encontrar más ofertas.",
+		"submit.image_gif":               "**%s** es un GIF. Sube una captura fija en PNG, JPEG o WebP.",
+		"submit.image_type":              "**%s** no es una imagen compatible. Las capturas deben ser PNG, JPEG o WebP.",
+		"submit.image_size":              "**%s** ocupa %s; las capturas pueden ocupar como máximo %s.",
+		"submit.which_only":              "La captura",
+		"submit.which_nth":               "La captura %d",
+		"submit.image_unusable":          "%s no se puede usar: %v. Sube una captura del mercado a tamaño completo.",
+		"submit.image_unreadable":        "%s no se pudo abrir. Sube una captura en PNG, JPEG o WebP.",
+		"submit.image_failed":            "%s no se pudo procesar. Inténtalo de nuevo.",
+		"submit.analyze_failed":          "No se pudo analizar la captura: %v",
+		"submit.analyze_failed_nth":      "No se pudo analizar la captura %d: %v",
+		"submit.analyzing":               "⏳ Analizando %d captura(s)...",
+		"submit.cancelled":               "Envío cancelado.",
+		"submit.none_pending":            "No tienes ningún envío pendiente. Usa `/submit` para añadir datos del mercado.",
+		"submit.build_failed":            "No se pudieron preparar las órdenes del mercado",
+		"submit.port_hint_title":         "¿Qué puerto es este?",
+		"submit.port_create_title":       "Crear puerto nuevo",
+		"submit.port_name_label":         "Nombre del puerto",
+		"submit.port_name_placeholder":   "p. ej., Port Royal",
+		"submit.port_region_label":       "Región",
+		"submit.port_region_placeholder": "p. ej., Caribbean, Mediterranean",
+		"submit.port_notes_label":        "Notas (opcional)",
+		"submit.port_notes_placeholder":  "Cualquier información adicional...",
+		"submit.port_required":           "El nombre del puerto es obligatorio",
+		"submit.port_create_failed":      "No se pudo crear el puerto",
+		"submit.edit_page_missing":       "Esa página de objetos ya no existe",
+		"submit.edit_rejected":           "No se cambió nada:\n%s",
+	},
+}
+
+// rememberLocale records the locale of a user's interaction for later DMs
+func (b *Bot) rememberLocale(i *discordgo.InteractionCreate) {
+	if userID := getUserID(i); userID != "" && i.Locale != "" {
+		b.locales.Store(userID, i.Locale)
+	}
+}
+
+// userLocale is the locale of the user's latest interaction. Users the bot
+// hasn't heard from since it started get "", which t treats as English.
+func (b *Bot) userLocale(userID string) discordgo.Locale {
+	if locale, ok := b.locales.Load(userID); ok {
+		return locale.(discordgo.Locale)
+	}
+	return ""
+}
+
+// localeLanguage is the catalog language for a Discord locale
+func localeLanguage(locale discordgo.Locale) string {
+	return strings.ToLower(strings.SplitN(string(locale), "-", 2)[0])
+}
+
+// t looks up a user-facing string in the locale's language, falling back to
+// English, and formats it with args. An unknown key is logged and returned
+// as is so it shows up in testing rather than as an empty message.
+func t(locale discordgo.Locale, key string, args ...interface{}) string {
+	format, ok := messages[localeLanguage(locale)][key]
+	if !ok {
+		format, ok = messages[defaultLanguage][key]
+	}
+	if !ok {
+		log.Printf("Missing message %q", key)
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// translate is t under a name the tests' *testing.T doesn't shadow
+var translate = t
+
+func TestMessageCatalogsMatchEnglish(t *testing.T) {
+	english := messages[defaultLanguage]
+	for lang, catalog := range messages {
+		for key, format := range catalog {
+			base, ok := english[key]
+			if !ok {
+				t.Errorf("%s: %q has no English message", lang, key)
+				continue
+			}
+			if strings.Count(format, "%") != strings.Count(base, "%") {
+				t.Errorf("%s: %q has different format verbs than English", lang, key)
+			}
+		}
+	}
+}
+
+func TestMessageCatalogsAreComplete(t *testing.T) {
+	for lang, catalog := range messages {
+		for key := range messages[defaultLanguage] {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("%s: %q is missing", lang, key)
+			}
+		}
+	}
+}
+
+func TestUserLocaleRemembersLatestInteraction(t *testing.T) {
+	b := &Bot{}
+	if got := b.userLocale("user1"); got != "" {
+		t.Errorf("expected no locale for an unseen user, got %q", got)
+	}
+
+	i := newDMCommand("trade-my-orders")
+	i.Locale = discordgo.SpanishES
+	b.rememberLocale(i)
+	if got := translate(b.userLocale("user1"), "relay.closed_inactive"); !strings.HasPrefix(got, "Tu conversación") {
+		t.Errorf("expected a Spanish DM for user1, got %q", got)
+	}
+}
+
+func TestTranslateFallsBackToEnglish(t *testing.T) {
+	if got := translate(discordgo.SpanishES, "error.database"); got != "Error de la base de datos" {
+		t.Errorf("expected Spanish, got %q", got)
+	}
+	if got := translate(discordgo.EnglishGB, "error.database"); got != "Database error" {
+		t.Errorf("expected English for en-GB, got %q", got)
+	}
+	if got := translate(discordgo.PortugueseBR, "error.database"); got != "Database error" {
+		t.Errorf("expected English fallback, got %q", got)
+	}
+	if got := translate(discordgo.SpanishES, "submit.in_progress_until", 1700000000); !strings.Contains(got, "<t:1700000000:R>") {
+		t.Errorf("expected timestamp in %q", got)
+	}
+	if got := translate(discordgo.EnglishUS, "no.such.key"); got != "no.such.key" {
+		t.Errorf("expected the key back, got %q", got)
+	}
+}
@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxSelectOptions is Discord's limit on options in a select menu
+	maxSelectOptions = 25
+	// maxEmbedFieldValue is Discord's limit on an embed field's value
+	maxEmbedFieldValue = 1024
+	// maxEmbedFields is Discord's limit on fields in one embed
+	maxEmbedFields = 25
+	// maxMessageContent is Discord's limit on a message's text
+	maxMessageContent = 2000
+	// maxEmbedDescription is Discord's limit on an embed's description
+	maxEmbedDescription = 4096
+)
+
+// buildTagCatalogEmbed lists tags grouped by category with their item counts
+func buildTagCatalogEmbed(counts []database.TagItemCount) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "📦 Item Catalog",
+		Description: "Items by tag. Pick a tag below to list its items, or use `/items tags:weapon,heavy` to see their orders.",
+		Color:       0xe74c3c,
+	}
+
+	var category string
+	var lines []string
+	flush := func() {
+		if len(lines) == 0 {
+			return
+		}
+		name := category
+		if name == "" {
+			name = "Uncategorized"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  name,
+			Value: joinLimited(lines, "\n", maxEmbedFieldValue),
+		})
+		lines = nil
+	}
+
+	// Counts arrive ordered by category, so each category is one run
+	for _, c := range counts {
+		if c.Tag.Category != category {
+			flush()
+			category = c.Tag.Category
+		}
+		lines = append(lines, fmt.Sprintf("%s — %d item(s)", tagIconName(c.Tag.Name, []database.Tag{c.Tag}), c.ItemCount))
+	}
+	flush()
+
+	return embed
+}
+
+// tagSelectComponents builds the tag picker for the catalog. Tags without
+// items are left out, and selectedID (if any) is shown as the default.
+func tagSelectComponents(counts []database.TagItemCount, selectedID int) []discordgo.MessageComponent {
+	var options []discordgo.SelectMenuOption
+	for _, c := range counts {
+		if c.ItemCount == 0 {
+			continue
+		}
+		if len(options) == maxSelectOptions {
+			break
+		}
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       tagIconName(c.Tag.Name, []database.Tag{c.Tag}),
+			Value:       strconv.Itoa(c.Tag.ID),
+			Description: fmt.Sprintf("%d item(s)", c.ItemCount),
+			Default:     c.Tag.ID == selectedID,
+		})
+	}
+	if len(options) == 0 {
+		return nil
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					CustomID:    "items_tag_select:",
+					Placeholder: "Choose a tag to list its items",
+					Options:     options,
+				},
+			},
+		},
+	}
+}
+
+// embedFieldChunks lays a list out as embed fields of at most
+// maxEmbedFieldValue bytes, numbering them "label (2/3)" when it takes more
+// than one. maxFields bounds how many are used (never more than an embed
+// holds); whatever doesn't fit is summarized in the last field and truncated
+// reports that happened.
+func embedFieldChunks(label string, parts []string, sep string, maxFields int) (fields []*discordgo.MessageEmbedField, truncated bool) {
+	if maxFields <= 0 || maxFields > maxEmbedFields {
+		maxFields = maxEmbedFields
+	}
+	values, truncated := splitFieldValues(parts, sep, maxEmbedFieldValue, maxFields)
+	for idx, value := range values {
+		name := label
+		if len(values) > 1 {
+			name = fmt.Sprintf("%s (%d/%d)", label, idx+1, len(values))
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: name, Value: value})
+	}
+	return fields, truncated
+}
+
+// splitFieldValues packs parts, joined by sep, into at most maxFields values
+// of up to limit bytes each. Parts that still don't fit are summarized at the
+// end of the last value, and truncated reports that happened.
+func splitFieldValues(parts []string, sep string, limit, maxFields int) (values []string, truncated bool) {
+	start := 0
+	for start < len(parts) && len(values) < maxFields-1 {
+		end, size := start, 0
+		for end < len(parts) {
+			add := len(parts[end])
+			if end > start {
+				add += len(sep)
+			}
+			if end > start && size+add > limit {
+				break
+			}
+			size += add
+			end++
+		}
+		values = append(values, strings.Join(parts[start:end], sep))
+		start = end
+	}
+	if start < len(parts) {
+		last := joinLimited(parts[start:], sep, limit)
+		truncated = last != strings.Join(parts[start:], sep)
+		values = append(values, last)
+	}
+	return values, truncated
+}
+
+// joinLimited joins parts with sep, ending with "…and N more" instead of
+// going over limit bytes
+func joinLimited(parts []string, sep string, limit int) string {
+	joined := strings.Join(parts, sep)
+	if len(joined) <= limit {
+		return joined
+	}
+
+	var b strings.Builder
+	for idx, part := range parts {
+		more := fmt.Sprintf("…and %d more", len(parts)-idx)
+		if b.Len()+2*len(sep)+len(part)+len(more) > limit {
+			if b.Len() > 0 {
+				b.WriteString(sep)
+			}
+			b.WriteString(more)
+			break
+		}
+		if idx > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func testTagCounts() []database.TagItemCount {
+	return []database.TagItemCount{
+		{Tag: database.Tag{ID: 3, Name: "misc"}, ItemCount: 1},
+		{Tag: database.Tag{ID: 1, Name: "cannon", Category: "weapon", Icon: "💥"}, ItemCount: 4},
+		{Tag: database.Tag{ID: 2, Name: "mortar", Category: "weapon"}, ItemCount: 0},
+	}
+}
+
+func TestBuildTagCatalogEmbedGroupsByCategory(t *testing.T) {
+	embed := buildTagCatalogEmbed(testTagCounts())
+
+	if len(embed.Fields) != 2 {
+		t.Fatalf("Expected 2 category fields, got %d", len(embed.Fields))
+	}
+	if embed.Fields[0].Name != "Uncategorized" || embed.Fields[1].Name != "weapon" {
+		t.Errorf("Expected Uncategorized then weapon, got %q and %q", embed.Fields[0].Name, embed.Fields[1].Name)
+	}
+	if want := "💥 cannon — 4 item(s)\nmortar — 0 item(s)"; embed.Fields[1].Value != want {
+		t.Errorf("Expected %q, got %q", want, embed.Fields[1].Value)
+	}
+}
+
+func TestTagSelectComponentsSkipsEmptyTags(t *testing.T) {
+	components := tagSelectComponents(testTagCounts(), 1)
+	if len(components) != 1 {
+		t.Fatalf("Expected one action row, got %d", len(components))
+	}
+
+	menu := components[0].(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu)
+	if len(menu.Options) != 2 {
+		t.Fatalf("Expected tags with items only, got %d options", len(menu.Options))
+	}
+	if menu.Options[1].Value != "1" || !menu.Options[1].Default {
+		t.Errorf("Expected cannon selected, got %+v", menu.Options[1])
+	}
+
+	if tagSelectComponents(nil, 0) != nil {
+		t.Error("Expected no components without tags")
+	}
+}
+
+func TestJoinLimited(t *testing.T) {
+	if got := joinLimited([]string{"a", "b"}, ", ", 100); got != "a, b" {
+		t.Errorf("Expected plain join under the limit, got %q", got)
+	}
+
+	parts := make([]string, 200)
+	for idx := range parts {
+		parts[idx] = "Long Cannon"
+	}
+	got := joinLimited(parts, ", ", maxEmbedFieldValue)
+	if len(got) > maxEmbedFieldValue {
+		t.Errorf("Expected at most %d bytes, got %d", maxEmbedFieldValue, len(got))
+	}
+	if !strings.Contains(got, "more") {
+		t.Errorf("Expected a truncation note, got %q", got[len(got)-30:])
+	}
+}
+
+func TestSplitFieldValues(t *testing.T) {
+	lines := make([]string, 300)
+	for idx := range lines {
+		lines[idx] = "**Long Cannon**: 100 gold (qty: 5)"
+	}
+
+	values, truncated := splitFieldValues(lines[:10], "\n", maxEmbedFieldValue, 3)
+	if len(values) != 1 || truncated {
+		t.Errorf("Expected a short list in one value, got %d values (truncated=%v)", len(values), truncated)
+	}
+
+	values, truncated = splitFieldValues(lines, "\n", maxEmbedFieldValue, 3)
+	if len(values) != 3 || !truncated || !strings.Contains(values[2], "more") {
+		t.Errorf("Expected the last value to summarize the rest, got %d values (truncated=%v)", len(values), truncated)
+	}
+}
+
+func TestEmbedFieldChunksKeepsEveryPart(t *testing.T) {
+	parts := make([]string, 150)
+	for idx := range parts {
+		parts[idx] = fmt.Sprintf("Item Number %03d", idx)
+	}
+
+	fields, truncated := embedFieldChunks("Buy Orders", parts, ", ", 0)
+	if truncated {
+		t.Fatal("Expected no truncation with room for every part")
+	}
+	if len(fields) < 2 {
+		t.Fatalf("Expected an oversized list to split, got %d field(s)", len(fields))
+	}
+
+	var got []string
+	for idx, f := range fields {
+		if len(f.Value) > maxEmbedFieldValue {
+			t.Errorf("Field %d is %d bytes, over the limit", idx, len(f.Value))
+		}
+		if want := fmt.Sprintf("Buy Orders (%d/%d)", idx+1, len(fields)); f.Name != want {
+			t.Errorf("Expected field name %q, got %q", want, f.Name)
+		}
+		got = append(got, strings.Split(f.Value, ", ")...)
+	}
+	if strings.Join(got, ", ") != strings.Join(parts, ", ") {
+		t.Error("Expected every part, in order, across the fields")
+	}
+
+	if fields, _ := embedFieldChunks("Sell Orders", parts[:3], ", ", 0); len(fields) != 1 || fields[0].Name != "Sell Orders" {
+		t.Errorf("Expected a short list as one unnumbered field, got %+v", fields)
+	}
+}
@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"wosbTrade/internal/database"
+)
+
+// bestItemPrices picks the highest buy order (best place to sell to) and the
+// lowest sell order (best place to buy from). Either may be nil.
+func bestItemPrices(markets []database.Market) (bestBuy, bestSell *database.Market) {
+	for idx := range markets {
+		m := &markets[idx]
+		switch m.OrderType {
+		case "buy":
+			if bestBuy == nil || m.Price > bestBuy.Price {
+				bestBuy = m
+			}
+		case "sell":
+			if bestSell == nil || m.Price < bestSell.Price {
+				bestSell = m
+			}
+		}
+	}
+	return bestBuy, bestSell
+}
+
+// formatTagList renders tags one per line with their icon, category and color
+func formatTagList(tags []database.Tag) string {
+	if len(tags) == 0 {
+		return "None"
+	}
+
+	lines := make([]string, len(tags))
+	for idx, tag := range tags {
+		line := tag.Name
+		if tag.Icon != "" {
+			line = tag.Icon + " " + line
+		}
+		if tag.Category != "" {
+			line += fmt.Sprintf(" (%s)", tag.Category)
+		}
+		if tag.Color != "" {
+			line += fmt.Sprintf(" `%s`", tag.Color)
+		}
+		lines[idx] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatBestPrice renders a best-price field value
+func formatBestPrice(m *database.Market) string {
+	if m == nil {
+		return "No active orders"
+	}
+	port := fmt.Sprintf("port #%d", m.PortID)
+	if m.Port != nil {
+		port = m.Port.DisplayName
+	}
+	return fmt.Sprintf("**%s** at %s (qty: %d)", formatGold(int64(m.Price)), port, m.Quantity)
+}
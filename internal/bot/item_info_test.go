@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"testing"
+
+	"wosbTrade/internal/database"
+)
+
+func TestBestItemPrices(t *testing.T) {
+	markets := []database.Market{
+		{ID: 1, OrderType: "buy", Price: 90},
+		{ID: 2, OrderType: "buy", Price: 120},
+		{ID: 3, OrderType: "sell", Price: 150},
+		{ID: 4, OrderType: "sell", Price: 130},
+	}
+
+	bestBuy, bestSell := bestItemPrices(markets)
+	if bestBuy == nil || bestBuy.ID != 2 {
+		t.Errorf("Expected highest buy order #2, got %+v", bestBuy)
+	}
+	if bestSell == nil || bestSell.ID != 4 {
+		t.Errorf("Expected lowest sell order #4, got %+v", bestSell)
+	}
+
+	bestBuy, bestSell = bestItemPrices(markets[2:])
+	if bestBuy != nil {
+		t.Errorf("Expected no buy order, got %+v", bestBuy)
+	}
+	if bestSell == nil {
+		t.Error("Expected a sell order")
+	}
+}
+
+func TestFormatTagList(t *testing.T) {
+	tags := []database.Tag{
+		{Name: "cannon", Category: "weapon", Color: "#FF5733", Icon: "💥"},
+		{Name: "heavy"},
+	}
+
+	want := "💥 cannon (weapon) `#FF5733`\nheavy"
+	if got := formatTagList(tags); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := formatTagList(nil); got != "None" {
+		t.Errorf("Expected None for no tags, got %q", got)
+	}
+}
@@ -0,0 +1,126 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// itemListPageSize is how many items each /admin-item-list-all page shows
+const itemListPageSize = 20
+
+// itemListFilter is what /admin-item-list-all was asked to show. It travels
+// in the page buttons' custom IDs so pages can be rebuilt on click.
+type itemListFilter struct {
+	TagID    int
+	TagName  string // for display only
+	Category string
+}
+
+// customID encodes the filter with a page number. The category goes last
+// because it is free text.
+func (f itemListFilter) customID(page int) string {
+	return fmt.Sprintf("item_list_page:%d:%d:%s", page, f.TagID, f.Category)
+}
+
+// parseItemListPage reads a page button's custom ID back into a page and
+// filter. The tag name isn't carried, so callers look it up if they need it.
+func parseItemListPage(customID string) (int, itemListFilter, error) {
+	parts := strings.SplitN(strings.TrimPrefix(customID, "item_list_page:"), ":", 3)
+	if len(parts) != 3 {
+		return 0, itemListFilter{}, fmt.Errorf("malformed item list page %q", customID)
+	}
+	page, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, itemListFilter{}, fmt.Errorf("bad page in %q: %w", customID, err)
+	}
+	tagID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, itemListFilter{}, fmt.Errorf("bad tag in %q: %w", customID, err)
+	}
+	return page, itemListFilter{TagID: tagID, Category: parts[2]}, nil
+}
+
+// itemListPageCount is how many pages a list of n items takes (at least one)
+func itemListPageCount(n int) int {
+	if n == 0 {
+		return 1
+	}
+	return (n + itemListPageSize - 1) / itemListPageSize
+}
+
+// buildItemListPage renders one page of the catalog with each item's tags
+func buildItemListPage(items []database.Item, page int, filter itemListFilter) *discordgo.MessageEmbed {
+	title := "📋 All Items"
+	switch {
+	case filter.TagName != "" && filter.Category != "":
+		title = fmt.Sprintf("📋 Items tagged %s in %s", filter.TagName, filter.Category)
+	case filter.TagName != "":
+		title = fmt.Sprintf("📋 Items tagged %s", filter.TagName)
+	case filter.Category != "":
+		title = fmt.Sprintf("📋 Items with a %s tag", filter.Category)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: title,
+		Color: 0xe67e22,
+	}
+	if len(items) == 0 {
+		embed.Description = "No items match."
+		return embed
+	}
+
+	start := page * itemListPageSize
+	end := start + itemListPageSize
+	if end > len(items) {
+		end = len(items)
+	}
+
+	var lines []string
+	for _, item := range items[start:end] {
+		var tags []string
+		for _, tag := range item.Tags {
+			tags = append(tags, tagIconName(tag.Name, []database.Tag{tag}))
+		}
+		tagText := "*untagged*"
+		if len(tags) > 0 {
+			tagText = strings.Join(tags, ", ")
+		}
+		lines = append(lines, fmt.Sprintf("**%s** — %s", item.DisplayName, tagText))
+	}
+
+	embed.Description = strings.Join(lines, "\n")
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: fmt.Sprintf("Page %d/%d • %d item(s)", page+1, itemListPageCount(len(items)), len(items)),
+	}
+	return embed
+}
+
+// itemListPageComponents returns Previous/Next buttons for a catalog page
+func itemListPageComponents(page, total int, filter itemListFilter) []discordgo.MessageComponent {
+	if total <= 1 {
+		return nil
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "◀ Previous",
+					Style:    discordgo.SecondaryButton,
+					CustomID: filter.customID(page - 1),
+					Disabled: page == 0,
+				},
+				discordgo.Button{
+					Label:    "Next ▶",
+					Style:    discordgo.SecondaryButton,
+					CustomID: filter.customID(page + 1),
+					Disabled: page >= total-1,
+				},
+			},
+		},
+	}
+}
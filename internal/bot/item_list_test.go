@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"wosbTrade/internal/database"
+)
+
+func TestItemListPageRoundTrip(t *testing.T) {
+	filter := itemListFilter{TagID: 7, TagName: "weapon", Category: "size:large"}
+
+	page, parsed, err := parseItemListPage(filter.customID(3))
+	if err != nil {
+		t.Fatalf("parseItemListPage failed: %v", err)
+	}
+	if page != 3 || parsed.TagID != 7 || parsed.Category != "size:large" {
+		t.Errorf("Expected page 3 with the filter intact, got %d %+v", page, parsed)
+	}
+
+	if _, _, err := parseItemListPage("item_list_page:x:1:"); err == nil {
+		t.Error("Expected an error for a bad page number")
+	}
+}
+
+func TestBuildItemListPage(t *testing.T) {
+	var items []database.Item
+	for idx := 0; idx < 45; idx++ {
+		items = append(items, database.Item{ID: idx + 1, DisplayName: fmt.Sprintf("Item %02d", idx)})
+	}
+	items[40].Tags = []database.Tag{{Name: "weapon", Icon: "⚔️"}, {Name: "heavy"}}
+
+	if got := itemListPageCount(len(items)); got != 3 {
+		t.Fatalf("Expected 3 pages for 45 items, got %d", got)
+	}
+
+	last := buildItemListPage(items, 2, itemListFilter{})
+	if lines := strings.Split(last.Description, "\n"); len(lines) != 5 {
+		t.Errorf("Expected 5 items on the last page, got %d", len(lines))
+	}
+	if !strings.Contains(last.Description, "**Item 40** — ⚔️ weapon, heavy") {
+		t.Errorf("Expected Item 40 with its tags, got %q", last.Description)
+	}
+	if !strings.Contains(last.Description, "**Item 41** — *untagged*") {
+		t.Errorf("Expected untagged items marked, got %q", last.Description)
+	}
+	if last.Footer == nil || !strings.HasPrefix(last.Footer.Text, "Page 3/3") {
+		t.Errorf("Expected a page footer, got %+v", last.Footer)
+	}
+
+	if components := itemListPageComponents(0, 1, itemListFilter{}); components != nil {
+		t.Error("Expected no buttons for a single page")
+	}
+}
@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"wosbTrade/internal/database"
+)
+
+// marketExportRecord is one /admin-export row, shared by the CSV and JSON formats
+type marketExportRecord struct {
+	Port        string    `json:"port"`
+	Region      string    `json:"region"`
+	OrderType   string    `json:"order_type"`
+	Item        string    `json:"item"`
+	Price       int       `json:"price"`
+	Quantity    int       `json:"quantity"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+var marketExportHeader = []string{"port", "region", "order_type", "item", "price", "quantity", "submitted_at", "expires_at"}
+
+func newMarketExportRecord(m database.Market) marketExportRecord {
+	record := marketExportRecord{
+		OrderType:   m.OrderType,
+		Item:        marketItemName(m),
+		Price:       m.Price,
+		Quantity:    m.Quantity,
+		SubmittedAt: m.SubmittedAt.UTC(),
+		ExpiresAt:   m.ExpiresAt.UTC(),
+	}
+	if m.Port != nil {
+		record.Port = m.Port.DisplayName
+		record.Region = m.Port.Region
+	}
+	return record
+}
+
+// buildMarketExport encodes markets as CSV or JSON, split into files of at
+// most maxBytes. Every CSV file repeats the header and every JSON file is a
+// complete array, so each part opens on its own.
+func buildMarketExport(markets []database.Market, format string, maxBytes int) ([][]byte, error) {
+	var prefix, sep, suffix string
+	rows := make([][]byte, 0, len(markets))
+
+	switch format {
+	case "json":
+		prefix, sep, suffix = "[\n", ",\n", "\n]\n"
+		for _, m := range markets {
+			row, err := json.Marshal(newMarketExportRecord(m))
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, row)
+		}
+	default:
+		header, err := csvLine(marketExportHeader)
+		if err != nil {
+			return nil, err
+		}
+		prefix = string(header)
+		for _, m := range markets {
+			r := newMarketExportRecord(m)
+			row, err := csvLine([]string{
+				r.Port,
+				r.Region,
+				r.OrderType,
+				r.Item,
+				strconv.Itoa(r.Price),
+				strconv.Itoa(r.Quantity),
+				r.SubmittedAt.Format(time.RFC3339),
+				r.ExpiresAt.Format(time.RFC3339),
+			})
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	var files [][]byte
+	var buf bytes.Buffer
+	count := 0
+	flush := func() {
+		buf.WriteString(suffix)
+		files = append(files, append([]byte(nil), buf.Bytes()...))
+		buf.Reset()
+		count = 0
+	}
+
+	buf.WriteString(prefix)
+	for _, row := range rows {
+		need := len(row) + len(suffix)
+		if count > 0 {
+			need += len(sep)
+		}
+		if count > 0 && buf.Len()+need > maxBytes {
+			flush()
+			buf.WriteString(prefix)
+		}
+		if count > 0 {
+			buf.WriteString(sep)
+		}
+		buf.Write(row)
+		count++
+	}
+	flush()
+
+	return files, nil
+}
+
+// csvLine encodes a single CSV record, including its line ending
+func csvLine(record []string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvSafeRecord(record)); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// csvSafeRecord returns record with any cell a spreadsheet would run as a
+// formula prefixed with a quote, so player-typed names open as plain text
+func csvSafeRecord(record []string) []string {
+	safe := make([]string, len(record))
+	for idx, cell := range record {
+		if cell != "" && strings.ContainsRune("=+-@", rune(cell[0])) {
+			cell = "'" + cell
+		}
+		safe[idx] = cell
+	}
+	return safe
+}
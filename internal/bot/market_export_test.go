@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"wosbTrade/internal/database"
+)
+
+func testExportMarkets() []database.Market {
+	port, markets := testPortBoard()
+	for idx := range markets {
+		markets[idx].Port = &port
+	}
+	return markets
+}
+
+func TestBuildMarketExportCSV(t *testing.T) {
+	files, err := buildMarketExport(testExportMarkets(), "csv", portExportMaxBytes)
+	if err != nil {
+		t.Fatalf("buildMarketExport failed: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Expected a single file, got %d", len(files))
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(files[0])).ReadAll()
+	if err != nil {
+		t.Fatalf("Export is not valid CSV: %v", err)
+	}
+	if len(records) != 4 || records[0][0] != "port" {
+		t.Fatalf("Expected header plus 3 rows, got %q", records)
+	}
+	if records[2][1] != "Caribbean" || records[2][3] != "Cannon, Long" || records[2][4] != "1200" {
+		t.Errorf("Expected quoted cannon row with region, got %q", records[2])
+	}
+}
+
+func TestBuildMarketExportSplitsFiles(t *testing.T) {
+	markets := testExportMarkets()
+	for _, format := range []string{"csv", "json"} {
+		// Room for the largest single row per file, but not two rows
+		limit := 0
+		for idx := range markets {
+			single, err := buildMarketExport(markets[idx:idx+1], format, portExportMaxBytes)
+			if err != nil {
+				t.Fatalf("buildMarketExport failed: %v", err)
+			}
+			if len(single[0]) > limit {
+				limit = len(single[0])
+			}
+		}
+
+		files, err := buildMarketExport(markets, format, limit)
+		if err != nil {
+			t.Fatalf("buildMarketExport failed: %v", err)
+		}
+		if len(files) != len(markets) {
+			t.Fatalf("%s: expected %d files, got %d", format, len(markets), len(files))
+		}
+
+		total := 0
+		for idx, data := range files {
+			if len(data) > limit {
+				t.Errorf("%s part %d exceeds the limit: %d bytes", format, idx+1, len(data))
+			}
+			switch format {
+			case "json":
+				var records []marketExportRecord
+				if err := json.Unmarshal(data, &records); err != nil {
+					t.Fatalf("json part %d is not a complete array: %v", idx+1, err)
+				}
+				total += len(records)
+			default:
+				records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+				if err != nil || records[0][0] != "port" {
+					t.Fatalf("csv part %d lacks a header: %q, %v", idx+1, records, err)
+				}
+				total += len(records) - 1
+			}
+		}
+		if total != len(markets) {
+			t.Errorf("%s: expected %d rows across parts, got %d", format, len(markets), total)
+		}
+	}
+}
+
+func TestCSVExportsNeutraliseFormulas(t *testing.T) {
+	markets := testExportMarkets()
+	markets[0].Item.DisplayName = "=HYPERLINK(\"http://evil\")"
+	markets[1].Item.DisplayName = "@SUM(A1)"
+	markets[2].Item.DisplayName = "-1+1"
+
+	files, err := buildMarketExport(markets, "csv", portExportMaxBytes)
+	if err != nil {
+		t.Fatalf("buildMarketExport failed: %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(files[0])).ReadAll()
+	if err != nil {
+		t.Fatalf("Export is not valid CSV: %v", err)
+	}
+
+	port, _ := testPortBoard()
+	board, err := formatPortBoardCSV(port, markets)
+	if err != nil {
+		t.Fatalf("formatPortBoardCSV failed: %v", err)
+	}
+	boardRecords, err := csv.NewReader(bytes.NewBufferString(board)).ReadAll()
+	if err != nil {
+		t.Fatalf("Board export is not valid CSV: %v", err)
+	}
+
+	var items []string
+	for _, record := range records[1:] {
+		items = append(items, record[3])
+	}
+	for _, record := range boardRecords[1:] {
+		items = append(items, record[2])
+	}
+	for _, item := range items {
+		if item[0] != '\'' {
+			t.Errorf("Expected formula cell %q to be quoted", item)
+		}
+	}
+	if records[1][4] != "15" {
+		t.Errorf("Plain cells should be left alone, got price %q", records[1][4])
+	}
+}
+
+func TestCSVSafeRecord(t *testing.T) {
+	got := csvSafeRecord([]string{"=1+1", "+cmd", "-2", "@x", "Rope", "", "a=b"})
+	want := []string{"'=1+1", "'+cmd", "'-2", "'@x", "Rope", "", "a=b"}
+	for idx := range want {
+		if got[idx] != want[idx] {
+			t.Errorf("csvSafeRecord cell %d = %q, expected %q", idx, got[idx], want[idx])
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// overviewCacheTTL is how long a computed /overview is reused
+	overviewCacheTTL = 1 * time.Minute
+	// overviewListLimit caps each ranked list in the overview
+	overviewListLimit = 10
+	// marketDashboardRows caps each list on the single-embed /market-overview
+	marketDashboardRows = 5
+)
+
+// overviewCache holds the most recent market overview so repeated /overview
+// calls within the TTL don't rerun the aggregate queries
+type overviewCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	load     func(ctx context.Context) (*database.MarketOverview, error)
+	now      func() time.Time
+	overview *database.MarketOverview
+	loadedAt time.Time
+}
+
+// newOverviewCache creates a cache that loads overviews with load
+func newOverviewCache(ttl time.Duration, load func(ctx context.Context) (*database.MarketOverview, error)) *overviewCache {
+	return &overviewCache{
+		ttl:  ttl,
+		load: load,
+		now:  time.Now,
+	}
+}
+
+// Get returns the cached overview, reloading it once the TTL has passed.
+// Callers arriving during a reload wait for it instead of starting their own.
+func (c *overviewCache) Get(ctx context.Context) (*database.MarketOverview, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.overview != nil && c.now().Sub(c.loadedAt) < c.ttl {
+		return c.overview, nil
+	}
+
+	overview, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.overview = overview
+	c.loadedAt = c.now()
+	return overview, nil
+}
+
+// buildOverviewPages renders an overview as one embed per section
+func buildOverviewPages(ov *database.MarketOverview) []*discordgo.MessageEmbed {
+	summary := &discordgo.MessageEmbed{
+		Title: "🌊 Market Overview",
+		Color: 0x3498db,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Market Orders", Value: fmt.Sprintf("%d", ov.ActiveMarketOrders), Inline: true},
+			{Name: "Player Orders", Value: fmt.Sprintf("%d", ov.ActivePlayerOrders), Inline: true},
+		},
+	}
+	if len(ov.TopPorts) > 0 {
+		summary.Fields = append(summary.Fields, &discordgo.MessageEmbedField{
+			Name: "Busiest Port", Value: fmt.Sprintf("%s (%d orders)", ov.TopPorts[0].PortName, ov.TopPorts[0].Orders),
+		})
+	}
+	if len(ov.MostRequested) > 0 {
+		summary.Fields = append(summary.Fields, &discordgo.MessageEmbedField{
+			Name: "Most Requested", Value: fmt.Sprintf("%s (%d buy orders)", ov.MostRequested[0].ItemName, ov.MostRequested[0].BuyOrders),
+		})
+	}
+
+	var ports []string
+	for idx, p := range ov.TopPorts {
+		ports = append(ports, fmt.Sprintf("%d. **%s** — %d orders", idx+1, p.PortName, p.Orders))
+	}
+
+	var spreads []string
+	for idx, sp := range ov.WidestSpreads {
+		spreads = append(spreads, fmt.Sprintf("%d. **%s** — %s to %s across %d ports",
+			idx+1, sp.ItemName, groupThousands(int64(sp.MinPrice)), formatGold(int64(sp.MaxPrice)), sp.Ports))
+	}
+
+	var requested []string
+	for idx, d := range ov.MostRequested {
+		requested = append(requested, fmt.Sprintf("%d. **%s** — %d buy orders", idx+1, d.ItemName, d.BuyOrders))
+	}
+
+	pages := []*discordgo.MessageEmbed{
+		summary,
+		{Title: "🏴‍☠️ Most Active Ports", Color: 0x3498db, Description: overviewList(ports)},
+		{Title: "💰 Widest Price Spreads", Color: 0x3498db, Description: overviewList(spreads)},
+		{Title: "📈 Most Requested Items", Color: 0x3498db, Description: overviewList(requested)},
+	}
+
+	for idx, page := range pages {
+		page.Footer = &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Page %d/%d • Refreshes every %d minute(s)", idx+1, len(pages), int(overviewCacheTTL.Minutes())),
+		}
+		page.Timestamp = ov.GeneratedAt.Format(time.RFC3339)
+	}
+
+	return pages
+}
+
+// buildMarketDashboard renders busiest ports, most traded items and the
+// largest buy/sell spreads as a single embed for /market-overview
+func buildMarketDashboard(ov *database.MarketOverview) *discordgo.MessageEmbed {
+	var ports []string
+	for idx, p := range ov.TopPorts {
+		if idx == marketDashboardRows {
+			break
+		}
+		ports = append(ports, fmt.Sprintf("%d. **%s** — %d orders", idx+1, p.PortName, p.Orders))
+	}
+
+	var items []string
+	for idx, it := range ov.TopItems {
+		if idx == marketDashboardRows {
+			break
+		}
+		items = append(items, fmt.Sprintf("%d. **%s** — %d orders", idx+1, it.ItemName, it.Orders))
+	}
+
+	var spreads []string
+	for idx, sp := range ov.LargestSpreads {
+		if idx == marketDashboardRows {
+			break
+		}
+		line := fmt.Sprintf("%d. **%s** — sell %d @ %s, buy %d @ %s",
+			idx+1, sp.ItemName, sp.MinSell, sp.MinSellPort, sp.MaxBuy, sp.MaxBuyPort)
+		if profit := -sp.Spread(); profit > 0 {
+			line += fmt.Sprintf(" (**+%d** profit/unit)", profit)
+		}
+		spreads = append(spreads, line)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "🌊 Market Overview",
+		Description: fmt.Sprintf("%d market orders • %d player orders", ov.ActiveMarketOrders, ov.ActivePlayerOrders),
+		Color:       0x3498db,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "🏴‍☠️ Busiest Ports", Value: overviewList(ports)},
+			{Name: "📦 Most Traded Items", Value: overviewList(items)},
+			{Name: "💰 Largest Buy/Sell Spreads", Value: overviewList(spreads)},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Spreads compare the cheapest sell order with the best buy order • Refreshes every %d minute(s)",
+				int(overviewCacheTTL.Minutes())),
+		},
+		Timestamp: ov.GeneratedAt.Format(time.RFC3339),
+	}
+}
+
+func overviewList(lines []string) string {
+	if len(lines) == 0 {
+		return "No data yet."
+	}
+	return strings.Join(lines, "\n")
+}
+
+// overviewPageComponents returns Previous/Next buttons for the given page
+func overviewPageComponents(page, total int) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "◀ Previous",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("overview_page:%d", page-1),
+					Disabled: page == 0,
+				},
+				discordgo.Button{
+					Label:    "Next ▶",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("overview_page:%d", page+1),
+					Disabled: page >= total-1,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"wosbTrade/internal/database"
+)
+
+func TestOverviewCacheReusesWithinTTL(t *testing.T) {
+	loads := 0
+	cache := newOverviewCache(time.Minute, func(ctx context.Context) (*database.MarketOverview, error) {
+		loads++
+		return &database.MarketOverview{ActiveMarketOrders: loads}, nil
+	})
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for n := 0; n < 5; n++ {
+		if _, err := cache.Get(ctx); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Expected 1 load within TTL, got %d", loads)
+	}
+
+	now = now.Add(time.Minute)
+	overview, err := cache.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if loads != 2 || overview.ActiveMarketOrders != 2 {
+		t.Errorf("Expected a reload after TTL, got %d loads", loads)
+	}
+}
+
+func TestOverviewCacheDoesNotCacheErrors(t *testing.T) {
+	fail := true
+	cache := newOverviewCache(time.Minute, func(ctx context.Context) (*database.MarketOverview, error) {
+		if fail {
+			return nil, errors.New("database is locked")
+		}
+		return &database.MarketOverview{}, nil
+	})
+
+	ctx := context.Background()
+	if _, err := cache.Get(ctx); err == nil {
+		t.Fatal("Expected error from failing load")
+	}
+
+	fail = false
+	if _, err := cache.Get(ctx); err != nil {
+		t.Errorf("Expected retry to succeed, got %v", err)
+	}
+}
+
+func TestBuildOverviewPages(t *testing.T) {
+	pages := buildOverviewPages(&database.MarketOverview{
+		ActiveMarketOrders: 12,
+		ActivePlayerOrders: 3,
+		TopPorts:           []database.PortActivity{{PortName: "Tortuga", Orders: 8}},
+		MostRequested:      []database.ItemDemand{{ItemName: "Rope", BuyOrders: 4}},
+		GeneratedAt:        time.Now(),
+	})
+
+	if len(pages) != 4 {
+		t.Fatalf("Expected 4 pages, got %d", len(pages))
+	}
+
+	var summary []string
+	for _, field := range pages[0].Fields {
+		summary = append(summary, field.Name+"="+field.Value)
+	}
+	joined := strings.Join(summary, ";")
+	for _, want := range []string{"Market Orders=12", "Player Orders=3", "Busiest Port=Tortuga (8 orders)", "Most Requested=Rope (4 buy orders)"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Expected summary to contain %q, got %s", want, joined)
+		}
+	}
+
+	if !strings.Contains(pages[1].Description, "Tortuga") {
+		t.Errorf("Expected ports page to list Tortuga, got %q", pages[1].Description)
+	}
+	if pages[2].Description != "No data yet." {
+		t.Errorf("Expected empty spreads page placeholder, got %q", pages[2].Description)
+	}
+	if !strings.HasPrefix(pages[3].Footer.Text, "Page 4/4") {
+		t.Errorf("Expected last page footer, got %q", pages[3].Footer.Text)
+	}
+}
+
+func TestBuildMarketDashboard(t *testing.T) {
+	ports := make([]database.PortActivity, overviewListLimit)
+	for idx := range ports {
+		ports[idx] = database.PortActivity{PortName: fmt.Sprintf("Port %d", idx), Orders: 10 - idx}
+	}
+
+	embed := buildMarketDashboard(&database.MarketOverview{
+		TopPorts: ports,
+		LargestSpreads: []database.BuySellSpread{
+			{ItemName: "Cannon", MinSell: 100, MinSellPort: "Port Royal", MaxBuy: 140, MaxBuyPort: "Tortuga"},
+			{ItemName: "Wood", MinSell: 50, MinSellPort: "Port Royal", MaxBuy: 45, MaxBuyPort: "Tortuga"},
+		},
+		GeneratedAt: time.Now(),
+	})
+
+	if len(embed.Fields) != 3 {
+		t.Fatalf("Expected 3 fields, got %d", len(embed.Fields))
+	}
+	if lines := strings.Split(embed.Fields[0].Value, "\n"); len(lines) != marketDashboardRows {
+		t.Errorf("Expected ports capped at %d, got %d", marketDashboardRows, len(lines))
+	}
+	if embed.Fields[1].Value != "No data yet." {
+		t.Errorf("Expected empty items placeholder, got %q", embed.Fields[1].Value)
+	}
+
+	// Only a buy price above the sell price is shown as profit
+	spreads := strings.Split(embed.Fields[2].Value, "\n")
+	if !strings.Contains(spreads[0], "+40") || strings.Contains(spreads[1], "profit") {
+		t.Errorf("Expected profit only on the Cannon line, got %q", spreads)
+	}
+}
@@ -0,0 +1,125 @@
+package bot
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// sharedOrder is one item with an order of the same type at both ports
+type sharedOrder struct {
+	A, B database.Market
+}
+
+// Delta is port B's price minus port A's
+func (o sharedOrder) Delta() int {
+	return o.B.Price - o.A.Price
+}
+
+// portComparison splits two ports' orders of one type into items listed at
+// both and items only one of them lists
+type portComparison struct {
+	Shared []sharedOrder
+	OnlyA  []database.Market
+	OnlyB  []database.Market
+}
+
+// comparePortOrders compares the orderType orders of two boards, each sorted
+// by item display name
+func comparePortOrders(a, b []database.Market, orderType string) portComparison {
+	inB := make(map[int]database.Market)
+	for _, m := range b {
+		if m.OrderType == orderType {
+			inB[m.ItemID] = m
+		}
+	}
+
+	var cmp portComparison
+	inA := make(map[int]bool)
+	for _, m := range a {
+		if m.OrderType != orderType {
+			continue
+		}
+		inA[m.ItemID] = true
+		if other, ok := inB[m.ItemID]; ok {
+			cmp.Shared = append(cmp.Shared, sharedOrder{A: m, B: other})
+		} else {
+			cmp.OnlyA = append(cmp.OnlyA, m)
+		}
+	}
+	for _, m := range b {
+		if m.OrderType == orderType && !inA[m.ItemID] {
+			cmp.OnlyB = append(cmp.OnlyB, m)
+		}
+	}
+
+	sort.Slice(cmp.Shared, func(x, y int) bool {
+		return cmp.Shared[x].A.Item.DisplayName < cmp.Shared[y].A.Item.DisplayName
+	})
+	byName := func(ms []database.Market) {
+		sort.Slice(ms, func(x, y int) bool { return ms[x].Item.DisplayName < ms[y].Item.DisplayName })
+	}
+	byName(cmp.OnlyA)
+	byName(cmp.OnlyB)
+	return cmp
+}
+
+// formatPriceDelta renders a price difference with its sign
+func formatPriceDelta(delta int) string {
+	if delta == 0 {
+		return "same"
+	}
+	return fmt.Sprintf("%+d", delta)
+}
+
+// buildPortCompareEmbed renders /port-compare: for each order type, the
+// items both ports list with their price difference, then each port's own
+func buildPortCompareEmbed(portA, portB *database.Port, a, b []database.Market) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("⚖️ %s vs %s", portA.DisplayName, portB.DisplayName),
+		Description: fmt.Sprintf("Prices at both ports are shown as %s → %s, with the difference.",
+			portA.DisplayName, portB.DisplayName),
+		Color:     0x9b59b6,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	addField := func(name string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  name,
+			Value: joinLimited(lines, "\n", maxEmbedFieldValue),
+		})
+	}
+	priceLines := func(ms []database.Market) []string {
+		var lines []string
+		for _, m := range ms {
+			lines = append(lines, fmt.Sprintf("**%s**: %s (qty: %d)", m.Item.DisplayName, formatGold(int64(m.Price)), m.Quantity))
+		}
+		return lines
+	}
+
+	for _, orderType := range []string{"buy", "sell"} {
+		label := "Buy Orders"
+		if orderType == "sell" {
+			label = "Sell Orders"
+		}
+		cmp := comparePortOrders(a, b, orderType)
+
+		var shared []string
+		for _, o := range cmp.Shared {
+			shared = append(shared, fmt.Sprintf("**%s**: %s → %s (%s)",
+				o.A.Item.DisplayName, groupThousands(int64(o.A.Price)), formatGold(int64(o.B.Price)), formatPriceDelta(o.Delta())))
+		}
+		addField(fmt.Sprintf("%s at Both", label), shared)
+		addField(fmt.Sprintf("%s only at %s", label, portA.DisplayName), priceLines(cmp.OnlyA))
+		addField(fmt.Sprintf("%s only at %s", label, portB.DisplayName), priceLines(cmp.OnlyB))
+	}
+
+	return embed
+}
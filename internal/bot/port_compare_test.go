@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"wosbTrade/internal/database"
+)
+
+func boardOrder(itemID int, item, orderType string, price int) database.Market {
+	return database.Market{
+		ItemID:    itemID,
+		OrderType: orderType,
+		Price:     price,
+		Quantity:  1,
+		Item:      &database.Item{ID: itemID, DisplayName: item},
+	}
+}
+
+func TestComparePortOrders(t *testing.T) {
+	a := []database.Market{
+		boardOrder(1, "Cannon", "sell", 100),
+		boardOrder(2, "Wood", "sell", 20),
+		boardOrder(3, "Rope", "buy", 5),
+	}
+	b := []database.Market{
+		boardOrder(1, "Cannon", "sell", 130),
+		boardOrder(3, "Rope", "sell", 7),
+		boardOrder(4, "Iron", "sell", 40),
+	}
+
+	cmp := comparePortOrders(a, b, "sell")
+	if len(cmp.Shared) != 1 || cmp.Shared[0].A.ItemID != 1 || cmp.Shared[0].Delta() != 30 {
+		t.Fatalf("expected Cannon shared with +30, got %+v", cmp.Shared)
+	}
+	if len(cmp.OnlyA) != 1 || cmp.OnlyA[0].Item.DisplayName != "Wood" {
+		t.Errorf("expected only Wood at A, got %+v", cmp.OnlyA)
+	}
+	// Rope is a buy order at A, so as a sell order it's only at B
+	if len(cmp.OnlyB) != 2 || cmp.OnlyB[0].Item.DisplayName != "Iron" || cmp.OnlyB[1].Item.DisplayName != "Rope" {
+		t.Errorf("expected Iron and Rope only at B, got %+v", cmp.OnlyB)
+	}
+}
+
+func TestBuildPortCompareEmbed(t *testing.T) {
+	portA := &database.Port{ID: 1, DisplayName: "Tortuga"}
+	portB := &database.Port{ID: 2, DisplayName: "Nassau"}
+	a := []database.Market{boardOrder(1, "Cannon", "sell", 100)}
+	b := []database.Market{boardOrder(1, "Cannon", "sell", 90)}
+
+	embed := buildPortCompareEmbed(portA, portB, a, b)
+	if len(embed.Fields) != 1 {
+		t.Fatalf("expected only the shared sell field, got %d fields", len(embed.Fields))
+	}
+	if !strings.Contains(embed.Fields[0].Value, "100 → 90 gold (-10)") {
+		t.Errorf("expected the price delta, got %q", embed.Fields[0].Value)
+	}
+}
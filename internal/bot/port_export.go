@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"wosbTrade/internal/database"
+)
+
+// portExportMaxBytes keeps exports under Discord's attachment limit for
+// servers without boosts
+const portExportMaxBytes = 8 * 1024 * 1024
+
+// formatPortBoardText renders a port's board as aligned plain text, one
+// section per order type. Markets are expected in GetOrdersByPort order.
+func formatPortBoardText(port database.Port, markets []database.Market, now time.Time) string {
+	var buf bytes.Buffer
+
+	title := port.DisplayName
+	if port.Region != "" {
+		title += fmt.Sprintf(" (%s)", port.Region)
+	}
+	fmt.Fprintf(&buf, "%s\n", title)
+	fmt.Fprintf(&buf, "Exported %s\n", now.UTC().Format("2006-01-02 15:04 MST"))
+
+	for _, orderType := range []string{"buy", "sell"} {
+		var rows []database.Market
+		for _, m := range markets {
+			if m.OrderType == orderType {
+				rows = append(rows, m)
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\n%s ORDERS\n", strings.ToUpper(orderType))
+		w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "Item\tPrice\tQuantity\t")
+		for _, m := range rows {
+			fmt.Fprintf(w, "%s\t%d\t%d\t\n", marketItemName(m), m.Price, m.Quantity)
+		}
+		w.Flush()
+	}
+
+	return buf.String()
+}
+
+// formatPortBoardCSV renders a port's board as CSV with one row per order
+func formatPortBoardCSV(port database.Port, markets []database.Market) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write([]string{"port", "order_type", "item", "price", "quantity", "submitted_at", "expires_at"}); err != nil {
+		return "", err
+	}
+	for _, m := range markets {
+		record := []string{
+			port.DisplayName,
+			m.OrderType,
+			marketItemName(m),
+			strconv.Itoa(m.Price),
+			strconv.Itoa(m.Quantity),
+			m.SubmittedAt.UTC().Format(time.RFC3339),
+			m.ExpiresAt.UTC().Format(time.RFC3339),
+		}
+		if err := w.Write(csvSafeRecord(record)); err != nil {
+			return "", err
+		}
+	}
+
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+func marketItemName(m database.Market) string {
+	if m.Item != nil {
+		return m.Item.DisplayName
+	}
+	return fmt.Sprintf("item #%d", m.ItemID)
+}
@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"wosbTrade/internal/database"
+)
+
+func testPortBoard() (database.Port, []database.Market) {
+	port := database.Port{ID: 1, Name: "port royal", DisplayName: "Port Royal", Region: "Caribbean"}
+	submitted := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	expires := submitted.AddDate(0, 0, 7)
+
+	markets := []database.Market{
+		{OrderType: "buy", Price: 15, Quantity: 500, SubmittedAt: submitted, ExpiresAt: expires,
+			Item: &database.Item{DisplayName: "Rope"}},
+		{OrderType: "sell", Price: 1200, Quantity: 3, SubmittedAt: submitted, ExpiresAt: expires,
+			Item: &database.Item{DisplayName: "Cannon, Long"}},
+		{OrderType: "sell", Price: 50, Quantity: 100, SubmittedAt: submitted, ExpiresAt: expires,
+			Item: &database.Item{DisplayName: "Wood"}},
+	}
+	return port, markets
+}
+
+func TestFormatPortBoardText(t *testing.T) {
+	port, markets := testPortBoard()
+	out := formatPortBoardText(port, markets, time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC))
+
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if lines[0] != "Port Royal (Caribbean)" {
+		t.Errorf("Expected title line, got %q", lines[0])
+	}
+	if lines[1] != "Exported 2026-03-02 09:30 UTC" {
+		t.Errorf("Expected export time line, got %q", lines[1])
+	}
+
+	buyAt := strings.Index(out, "BUY ORDERS")
+	sellAt := strings.Index(out, "SELL ORDERS")
+	if buyAt == -1 || sellAt == -1 || buyAt > sellAt {
+		t.Fatalf("Expected BUY then SELL sections, got:\n%s", out)
+	}
+
+	// Columns are right-aligned so prices line up
+	var sellRows []string
+	for _, line := range strings.Split(out[sellAt:], "\n")[2:] {
+		if line != "" {
+			sellRows = append(sellRows, line)
+		}
+	}
+	if len(sellRows) != 2 {
+		t.Fatalf("Expected 2 sell rows, got %q", sellRows)
+	}
+	if len(sellRows[0]) != len(sellRows[1]) {
+		t.Errorf("Expected aligned rows, got %q and %q", sellRows[0], sellRows[1])
+	}
+	if !strings.Contains(sellRows[0], "Cannon, Long") || !strings.Contains(sellRows[0], "1200") {
+		t.Errorf("Expected cannon row, got %q", sellRows[0])
+	}
+}
+
+func TestFormatPortBoardTextSkipsEmptySections(t *testing.T) {
+	port, markets := testPortBoard()
+	out := formatPortBoardText(port, markets[1:], time.Now())
+
+	if strings.Contains(out, "BUY ORDERS") {
+		t.Errorf("Expected no buy section without buy orders, got:\n%s", out)
+	}
+}
+
+func TestFormatPortBoardCSV(t *testing.T) {
+	port, markets := testPortBoard()
+	out, err := formatPortBoardCSV(port, markets)
+	if err != nil {
+		t.Fatalf("formatPortBoardCSV failed: %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("Export is not valid CSV: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("Expected header + 3 rows, got %d", len(records))
+	}
+
+	want := []string{"Port Royal", "sell", "Cannon, Long", "1200", "3", "2026-03-01T12:00:00Z", "2026-03-08T12:00:00Z"}
+	for idx, field := range want {
+		if records[2][idx] != field {
+			t.Errorf("Column %d: expected %q, got %q", idx, field, records[2][idx])
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"wosbTrade/internal/database"
+)
+
+// portImportMaxBytes and portImportMaxRows bound /admin-port-import files;
+// the game has far fewer ports than this
+const (
+	portImportMaxBytes = 256 * 1024
+	portImportMaxRows  = 500
+)
+
+// portImportColumns are the accepted CSV headers; name and region are required
+var portImportColumns = []string{"name", "region", "notes"}
+
+// parsePortImportCSV reads name,region,notes rows into ports. Headers are
+// matched case-insensitively in any order. Errors name the offending line.
+func parsePortImportCSV(r io.Reader, addedBy string) ([]database.Port, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read the header: %w", err)
+	}
+
+	// Spreadsheet exports often start with a UTF-8 byte order mark
+	columns := make(map[string]int)
+	for idx, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		known := false
+		for _, col := range portImportColumns {
+			known = known || name == col
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown column %q (expected %s)", name, strings.Join(portImportColumns, ","))
+		}
+		if _, dup := columns[name]; dup {
+			return nil, fmt.Errorf("column %q appears twice", name)
+		}
+		columns[name] = idx
+	}
+	for _, required := range []string{"name", "region"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing required column %q", required)
+		}
+	}
+
+	var ports []database.Port
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// csv.ParseError already names the line and the problem
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(col string) string {
+			idx, ok := columns[col]
+			if !ok {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		port := database.Port{
+			Name:        field("name"),
+			DisplayName: field("name"),
+			Region:      field("region"),
+			Notes:       field("notes"),
+			AddedBy:     addedBy,
+		}
+		if port.Name == "" && port.Region == "" && port.Notes == "" {
+			continue // blank line of empty fields
+		}
+		if port.Name == "" {
+			return nil, fmt.Errorf("line %d: port name is empty", line)
+		}
+		if port.Region == "" {
+			return nil, fmt.Errorf("line %d: region is empty for %s", line, port.Name)
+		}
+
+		ports = append(ports, port)
+		if len(ports) > portImportMaxRows {
+			return nil, fmt.Errorf("too many rows (max %d)", portImportMaxRows)
+		}
+	}
+
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("the file has no port rows")
+	}
+	return ports, nil
+}
@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePortImportCSV(t *testing.T) {
+	input := "\ufeffName, Region ,notes\n" +
+		"Port Royal,Caribbean,Capital\n" +
+		"\"Tortuga, Old Town\",carib,\n" +
+		",,\n"
+
+	ports, err := parsePortImportCSV(strings.NewReader(input), "admin1")
+	if err != nil {
+		t.Fatalf("parsePortImportCSV failed: %v", err)
+	}
+	if len(ports) != 2 {
+		t.Fatalf("Expected 2 ports, got %+v", ports)
+	}
+	if ports[0].Name != "Port Royal" || ports[0].Region != "Caribbean" || ports[0].Notes != "Capital" || ports[0].AddedBy != "admin1" {
+		t.Errorf("Unexpected first port: %+v", ports[0])
+	}
+	if ports[1].Name != "Tortuga, Old Town" || ports[1].Region != "carib" {
+		t.Errorf("Expected quoted name kept whole, got %+v", ports[1])
+	}
+}
+
+func TestParsePortImportCSVColumnOrder(t *testing.T) {
+	ports, err := parsePortImportCSV(strings.NewReader("region,name\nCaribbean,Nassau\n"), "admin1")
+	if err != nil {
+		t.Fatalf("parsePortImportCSV failed: %v", err)
+	}
+	if len(ports) != 1 || ports[0].Name != "Nassau" || ports[0].Region != "Caribbean" {
+		t.Errorf("Expected columns matched by header, got %+v", ports)
+	}
+}
+
+func TestParsePortImportCSVErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", "empty"},
+		{"unknown column", "name,region,owner\n", `unknown column "owner"`},
+		{"missing region column", "name,notes\nNassau,x\n", `missing required column "region"`},
+		{"duplicate column", "name,region,name\n", "appears twice"},
+		{"no rows", "name,region,notes\n", "no port rows"},
+		{"wrong field count", "name,region,notes\nNassau,Caribbean\n", "line 2"},
+		{"empty name", "name,region\nNassau,Caribbean\n,Caribbean\n", "line 3: port name is empty"},
+		{"empty region", "name,region\nNassau,\n", "line 2: region is empty for Nassau"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parsePortImportCSV(strings.NewReader(tt.input), "admin1")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"fmt"
+	"sort"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// portsPageMaxChars keeps each /ports page under Discord's 6000-character
+// embed total, leaving room for the title and footer
+const portsPageMaxChars = 5500
+
+// buildPortsPages lists ports grouped by region, one field per region (split
+// further when a region is long), spread over as many embeds as it takes.
+// region is the filter the user asked for, nil for all ports.
+func buildPortsPages(ports []database.Port, region *database.Region) []*discordgo.MessageEmbed {
+	title := "🗺️ All Ports"
+	if region != nil {
+		title = fmt.Sprintf("🗺️ Ports in %s", region.Name)
+	}
+
+	if len(ports) == 0 {
+		description := "No ports found"
+		if region != nil {
+			description = fmt.Sprintf("No ports in %s yet. Admins can add one with `/admin-port-add`.", region.Name)
+		}
+		return []*discordgo.MessageEmbed{{Title: title, Description: description, Color: 0x2ecc71}}
+	}
+
+	// Group by canonical region; ports without one go last
+	byRegion := make(map[int][]string)
+	regionNames := make(map[int]string)
+	for _, port := range ports {
+		byRegion[port.RegionID] = append(byRegion[port.RegionID], port.DisplayName)
+		regionNames[port.RegionID] = port.Region
+	}
+	regionNames[0] = "Unknown"
+
+	regionIDs := make([]int, 0, len(byRegion))
+	for id := range byRegion {
+		regionIDs = append(regionIDs, id)
+	}
+	sort.Slice(regionIDs, func(a, b int) bool {
+		if (regionIDs[a] == 0) != (regionIDs[b] == 0) {
+			return regionIDs[b] == 0
+		}
+		return regionNames[regionIDs[a]] < regionNames[regionIDs[b]]
+	})
+
+	var fields []*discordgo.MessageEmbedField
+	for _, id := range regionIDs {
+		chunks, _ := embedFieldChunks(regionNames[id], byRegion[id], ", ", 0)
+		fields = append(fields, chunks...)
+	}
+
+	// Fill each page up to the field and size limits
+	var pages []*discordgo.MessageEmbed
+	var page *discordgo.MessageEmbed
+	size := 0
+	for _, f := range fields {
+		fieldSize := len(f.Name) + len(f.Value)
+		if page == nil || len(page.Fields) == maxEmbedFields || size+fieldSize > portsPageMaxChars {
+			page = &discordgo.MessageEmbed{
+				Title:       title,
+				Description: fmt.Sprintf("Total: %d ports", len(ports)),
+				Color:       0x2ecc71,
+			}
+			pages = append(pages, page)
+			size = len(page.Title) + len(page.Description)
+		}
+		page.Fields = append(page.Fields, f)
+		size += fieldSize
+	}
+
+	if len(pages) > 1 {
+		for idx, p := range pages {
+			p.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Page %d/%d", idx+1, len(pages))}
+		}
+	}
+	return pages
+}
+
+// portsPageComponents returns Previous/Next buttons for a /ports page. The
+// region filter rides along so the page can be rebuilt on click.
+func portsPageComponents(page, total, regionID int) []discordgo.MessageComponent {
+	if total <= 1 {
+		return nil
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "◀ Previous",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("ports_page:%d:%d", regionID, page-1),
+					Disabled: page == 0,
+				},
+				discordgo.Button{
+					Label:    "Next ▶",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("ports_page:%d:%d", regionID, page+1),
+					Disabled: page >= total-1,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"wosbTrade/internal/database"
+)
+
+func TestBuildPortsPagesEmptyRegion(t *testing.T) {
+	region := &database.Region{ID: 3, Name: "North Sea"}
+
+	pages := buildPortsPages(nil, region)
+	if len(pages) != 1 {
+		t.Fatalf("Expected one page, got %d", len(pages))
+	}
+	if len(pages[0].Fields) != 0 || !strings.Contains(pages[0].Description, "No ports in North Sea") {
+		t.Errorf("Expected an explanation instead of an empty list, got %q", pages[0].Description)
+	}
+	if components := portsPageComponents(0, len(pages), region.ID); components != nil {
+		t.Error("Expected no page buttons for a single page")
+	}
+}
+
+func TestBuildPortsPagesSplitsManyRegions(t *testing.T) {
+	var ports []database.Port
+	for r := 1; r <= 40; r++ {
+		for p := 0; p < 20; p++ {
+			ports = append(ports, database.Port{
+				DisplayName: fmt.Sprintf("Port %02d-%02d", r, p),
+				Region:      fmt.Sprintf("Region %02d", r),
+				RegionID:    r,
+			})
+		}
+	}
+
+	pages := buildPortsPages(ports, nil)
+	if len(pages) < 2 {
+		t.Fatalf("Expected 40 regions to need more than one page, got %d", len(pages))
+	}
+
+	regions := 0
+	for idx, page := range pages {
+		if len(page.Fields) > maxEmbedFields {
+			t.Errorf("Page %d has %d fields", idx, len(page.Fields))
+		}
+		size := len(page.Title) + len(page.Description)
+		for _, f := range page.Fields {
+			size += len(f.Name) + len(f.Value)
+		}
+		if size > 6000 {
+			t.Errorf("Page %d is %d characters", idx, size)
+		}
+		regions += len(page.Fields)
+		if page.Footer == nil || page.Footer.Text != fmt.Sprintf("Page %d/%d", idx+1, len(pages)) {
+			t.Errorf("Expected a page footer on page %d", idx)
+		}
+	}
+	if regions != 40 {
+		t.Errorf("Expected all 40 regions across the pages, got %d", regions)
+	}
+}
@@ -0,0 +1,171 @@
+package bot
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// relayFlushInterval is how long a burst of DMs is collected before it
+	// is relayed as one message
+	relayFlushInterval = 750 * time.Millisecond
+	// relayMaxBatchLength keeps a combined relay under Discord's 2000
+	// character message limit
+	relayMaxBatchLength = 2000
+)
+
+// relayEntry is one relayed line. messageID is the sender's DM to react to
+// once delivered; edits and deletion notices have none.
+type relayEntry struct {
+	messageID string
+	text      string
+}
+
+// relayBatch is a run of entries from one sender, relayed as a single message
+type relayBatch struct {
+	senderID  string
+	channelID string // the sender's DM channel with the bot
+	conv      *ActiveConversation
+	entries   []relayEntry
+	length    int
+}
+
+// Text joins the batch into the message sent to the other party
+func (rb *relayBatch) Text() string {
+	lines := make([]string, len(rb.entries))
+	for i, e := range rb.entries {
+		lines[i] = e.text
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MessageIDs returns the sender's messages covered by the batch
+func (rb *relayBatch) MessageIDs() []string {
+	var ids []string
+	for _, e := range rb.entries {
+		if e.messageID != "" {
+			ids = append(ids, e.messageID)
+		}
+	}
+	return ids
+}
+
+// relaySender holds the batch being collected for one sender and the sealed
+// batches waiting to be delivered, oldest first
+type relaySender struct {
+	open    *relayBatch
+	timer   *time.Timer
+	ready   []*relayBatch
+	sending bool
+}
+
+// RelayQueue coalesces bursts of trade DMs so pasting many lines doesn't send
+// one message per line into Discord's per-channel rate limits. Batches from
+// the same sender are delivered one at a time, in order.
+type RelayQueue struct {
+	mu        sync.Mutex
+	deliver   func(batch *relayBatch)
+	senders   map[string]*relaySender
+	interval  time.Duration
+	maxLength int
+	inFlight  sync.WaitGroup
+}
+
+// NewRelayQueue creates a queue that hands finished batches to deliver
+func NewRelayQueue(deliver func(batch *relayBatch)) *RelayQueue {
+	return &RelayQueue{
+		deliver:   deliver,
+		senders:   make(map[string]*relaySender),
+		interval:  relayFlushInterval,
+		maxLength: relayMaxBatchLength,
+	}
+}
+
+// Add queues a line from senderID. The batch is sent once the flush interval
+// passes or it would grow past the length limit.
+func (q *RelayQueue) Add(conv *ActiveConversation, senderID, channelID string, entry relayEntry) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	s, ok := q.senders[senderID]
+	if !ok {
+		s = &relaySender{}
+		q.senders[senderID] = s
+	}
+
+	// Flush first if this line won't fit (or the conversation changed)
+	if s.open != nil && (s.open.conv != conv || s.open.length+1+len(entry.text) > q.maxLength) {
+		q.seal(senderID, s)
+	}
+
+	if s.open == nil {
+		batch := &relayBatch{senderID: senderID, channelID: channelID, conv: conv}
+		s.open = batch
+		s.timer = time.AfterFunc(q.interval, func() { q.flushBatch(senderID, batch) })
+	} else {
+		s.open.length++ // newline separator
+	}
+	s.open.entries = append(s.open.entries, entry)
+	s.open.length += len(entry.text)
+}
+
+// Flush sends every open batch now and waits for all deliveries to finish
+func (q *RelayQueue) Flush() {
+	q.mu.Lock()
+	for senderID, s := range q.senders {
+		if s.open != nil {
+			q.seal(senderID, s)
+		}
+	}
+	q.mu.Unlock()
+
+	q.inFlight.Wait()
+}
+
+// flushBatch seals batch when its timer fires, unless it was already sealed
+func (q *RelayQueue) flushBatch(senderID string, batch *relayBatch) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if s, ok := q.senders[senderID]; ok && s.open == batch {
+		q.seal(senderID, s)
+	}
+}
+
+// seal moves the open batch to the ready list and starts a sender if none is
+// running. Must be called with q.mu held.
+func (q *RelayQueue) seal(senderID string, s *relaySender) {
+	s.timer.Stop()
+	s.ready = append(s.ready, s.open)
+	s.open, s.timer = nil, nil
+
+	if !s.sending {
+		s.sending = true
+		q.inFlight.Add(1)
+		go q.send(senderID, s)
+	}
+}
+
+// send delivers ready batches in order until none are left
+func (q *RelayQueue) send(senderID string, s *relaySender) {
+	defer q.inFlight.Done()
+
+	for {
+		q.mu.Lock()
+		if len(s.ready) == 0 {
+			s.sending = false
+			if s.open == nil {
+				delete(q.senders, senderID)
+			}
+			q.mu.Unlock()
+			return
+		}
+		batch := s.ready[0]
+		s.ready = s.ready[1:]
+		q.mu.Unlock()
+
+		// Deliver without holding the lock so new lines aren't blocked on Discord
+		q.deliver(batch)
+	}
+}
@@ -0,0 +1,251 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// collectRelays returns a queue that records delivered batches
+func collectRelays(interval time.Duration, maxLength int) (*RelayQueue, func() []*relayBatch) {
+	var mu sync.Mutex
+	var delivered []*relayBatch
+	q := NewRelayQueue(func(batch *relayBatch) {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, batch)
+	})
+	q.interval = interval
+	q.maxLength = maxLength
+	return q, func() []*relayBatch {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*relayBatch(nil), delivered...)
+	}
+}
+
+func TestRelayQueueCoalescesBurst(t *testing.T) {
+	q, delivered := collectRelays(time.Hour, relayMaxBatchLength)
+	conv := &ActiveConversation{ConversationID: 1}
+
+	q.Add(conv, "a", "dm", relayEntry{messageID: "1", text: "one"})
+	q.Add(conv, "a", "dm", relayEntry{messageID: "2", text: "two"})
+	q.Add(conv, "a", "dm", relayEntry{text: "edited"})
+	if got := delivered(); len(got) != 0 {
+		t.Fatalf("Expected nothing sent before the flush, got %d batches", len(got))
+	}
+
+	q.Flush()
+	got := delivered()
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 batch, got %d", len(got))
+	}
+	if text := got[0].Text(); text != "one\ntwo\nedited" {
+		t.Errorf("Unexpected batch text %q", text)
+	}
+	if ids := got[0].MessageIDs(); strings.Join(ids, ",") != "1,2" {
+		t.Errorf("Expected message IDs 1,2, got %v", ids)
+	}
+}
+
+func TestRelayQueueSplitsAtMaxLength(t *testing.T) {
+	q, delivered := collectRelays(time.Hour, 10)
+	conv := &ActiveConversation{ConversationID: 1}
+
+	for _, text := range []string{"aaaa", "bbbb", "cccc"} {
+		q.Add(conv, "a", "dm", relayEntry{text: text})
+	}
+	q.Flush()
+
+	var texts []string
+	for _, batch := range delivered() {
+		texts = append(texts, batch.Text())
+	}
+	if strings.Join(texts, "|") != "aaaa\nbbbb|cccc" {
+		t.Errorf("Expected batches in order split at the limit, got %q", texts)
+	}
+}
+
+func TestRelayQueueFlushesAfterInterval(t *testing.T) {
+	q, delivered := collectRelays(10*time.Millisecond, relayMaxBatchLength)
+	q.Add(&ActiveConversation{ConversationID: 1}, "a", "dm", relayEntry{text: "hello"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(delivered()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the batch to flush on its own")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	q.Flush()
+
+	if got := delivered(); len(got) != 1 || got[0].Text() != "hello" {
+		t.Errorf("Expected one batch with hello, got %d", len(got))
+	}
+}
+
+func TestMessageCreateStopsRelayForBannedSender(t *testing.T) {
+	b, _ := setupTradeDraftBot(t)
+	b.tradeConversations = NewTradeConversationManager(time.Minute)
+	q, delivered := collectRelays(time.Hour, relayMaxBatchLength)
+	b.relayQueue = q
+
+	conv := &ActiveConversation{ConversationID: 1, InitiatorUserID: "a", CreatorUserID: "b"}
+	b.tradeConversations.Register(conv)
+	if _, err := b.db.CreateTradeBan(context.Background(), database.TradeBan{UserID: "a", Reason: "scam", BannedBy: "admin"}); err != nil {
+		t.Fatalf("failed to ban user: %v", err)
+	}
+
+	s, _ := newTestSession(t)
+	s.State.User = &discordgo.User{ID: "bot"}
+	b.messageCreate(s, &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID: "m1", ChannelID: "dm", Content: "hello", Author: &discordgo.User{ID: "a"},
+	}})
+
+	q.Flush()
+	if got := delivered(); len(got) != 0 {
+		t.Errorf("Expected a banned sender's message not to be relayed, got %d batches", len(got))
+	}
+	if b.tradeConversations.HasActiveConversation("b") {
+		t.Errorf("Expected the conversation to be closed for both parties")
+	}
+}
+
+func TestRelayTextLimits(t *testing.T) {
+	b := &Bot{words: NewWordFilter()}
+
+	text, shortened, leftOut := b.relayText("", "Jack", "ahoy", nil)
+	if text != "**[Jack]**: ahoy" || shortened || leftOut != 0 {
+		t.Errorf("unexpected relay %q (shortened %v, %d left out)", text, shortened, leftOut)
+	}
+
+	long := strings.Repeat("x", relayMaxContentLength+10)
+	text, shortened, _ = b.relayText("", "Jack", long, nil)
+	if !strings.HasSuffix(text, "… *(shortened)*") || !shortened {
+		t.Errorf("expected shortened text, got %d runes (shortened %v)", len([]rune(text)), shortened)
+	}
+	if strings.Count(text, "x") != relayMaxContentLength-1 {
+		t.Errorf("expected %d characters kept, got %d", relayMaxContentLength-1, strings.Count(text, "x"))
+	}
+
+	urls := []string{"https://cdn/1.png", "https://cdn/2.png", "https://cdn/3.png", "https://cdn/4.png", "https://cdn/5.png"}
+	text, _, leftOut = b.relayText("", "Jack", "", urls)
+	if strings.Contains(text, "4.png") || !strings.Contains(text, "3.png") || !strings.Contains(text, "*(2 more not relayed)*") {
+		t.Errorf("expected the first %d attachments only, got %q", relayMaxAttachments, text)
+	}
+	if leftOut != 2 {
+		t.Errorf("expected 2 attachments left out, got %d", leftOut)
+	}
+
+	// The recipient's locale decides the markers
+	text, _, _ = b.relayText(discordgo.SpanishES, "Jack", long, urls)
+	if !strings.Contains(text, "*(acortado)*") || !strings.Contains(text, "**[Jack]** compartió:") {
+		t.Errorf("expected Spanish markers, got %q", text[len(text)-120:])
+	}
+
+	if text, _, _ := b.relayText("", "Jack", "   ", nil); text != "" {
+		t.Errorf("expected nothing to relay for blank text, got %q", text)
+	}
+}
+
+func TestMessageCreateShortensMarkdownHeavyMessage(t *testing.T) {
+	b, _ := setupTradeDraftBot(t)
+	b.tradeConversations = NewTradeConversationManager(time.Minute)
+	q, delivered := collectRelays(time.Hour, relayMaxBatchLength)
+	b.relayQueue = q
+
+	conv := &ActiveConversation{ConversationID: 1, InitiatorUserID: "a", CreatorUserID: "b", InitiatorIngameName: "Jack"}
+	b.tradeConversations.Register(conv)
+
+	s, rt := newTestSession(t)
+	s.State.User = &discordgo.User{ID: "bot"}
+
+	// Under the cap as typed, but escaping doubles it past Discord's limit
+	content := strings.Repeat("*_", 550)
+	b.messageCreate(s, &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID: "m1", ChannelID: "dm", Content: content, Author: &discordgo.User{ID: "a"},
+	}})
+
+	q.Flush()
+	got := delivered()
+	if len(got) != 1 {
+		t.Fatalf("expected the message relayed, got %d batches", len(got))
+	}
+	text := got[0].Text()
+	if n := len([]rune(text)); n > relayMaxBatchLength || !strings.HasSuffix(text, "… *(shortened)*") {
+		t.Errorf("expected a shortened relay within the limit, got %d runes ending %q", n, text[len(text)-20:])
+	}
+	if strings.Contains(text, "\\…") {
+		t.Error("expected no dangling escape before the ellipsis")
+	}
+	if ids := got[0].MessageIDs(); len(ids) != 1 || ids[0] != "m1" {
+		t.Errorf("expected m1 to get its delivery reaction, got %v", ids)
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if len(rt.bodies) != 1 || !strings.Contains(rt.bodies[0], "only the start of it was relayed") {
+		t.Errorf("expected the sender told once, got %v", rt.bodies)
+	}
+}
+
+func TestTruncateEscaped(t *testing.T) {
+	for _, tc := range []struct {
+		text  string
+		limit int
+		want  string
+	}{
+		{`\*\*bold`, 20, `\*\*bold`},
+		{`\*\*bold`, 4, `\*…`},
+		{`\*\*bold`, 2, `…`},
+		{`\\\\x`, 4, `\\…`},
+	} {
+		if got := truncateEscaped(tc.text, tc.limit); got != tc.want {
+			t.Errorf("truncateEscaped(%q, %d) = %q, want %q", tc.text, tc.limit, got, tc.want)
+		}
+	}
+}
+
+func TestEditsAndDeletesStopRelayForBannedSender(t *testing.T) {
+	now := time.Now()
+	for name, send := range map[string]func(b *Bot, s *discordgo.Session){
+		"edit": func(b *Bot, s *discordgo.Session) {
+			b.messageUpdate(s, &discordgo.MessageUpdate{Message: &discordgo.Message{
+				ID: "m1", ChannelID: "dm", Content: "new terms", Author: &discordgo.User{ID: "a"}, EditedTimestamp: &now,
+			}})
+		},
+		"delete": func(b *Bot, s *discordgo.Session) {
+			b.messageDelete(s, &discordgo.MessageDelete{Message: &discordgo.Message{ID: "m1", ChannelID: "dm"}})
+		},
+	} {
+		b, _ := setupTradeDraftBot(t)
+		b.tradeConversations = NewTradeConversationManager(time.Minute)
+		q, delivered := collectRelays(time.Hour, relayMaxBatchLength)
+		b.relayQueue = q
+
+		conv := &ActiveConversation{ConversationID: 1, InitiatorUserID: "a", CreatorUserID: "b"}
+		b.tradeConversations.Register(conv)
+		if _, err := b.db.CreateTradeBan(context.Background(), database.TradeBan{UserID: "a", Reason: "scam", BannedBy: "admin"}); err != nil {
+			t.Fatalf("failed to ban user: %v", err)
+		}
+
+		s, _ := newTestSession(t)
+		s.State.User = &discordgo.User{ID: "bot"}
+		s.State.ChannelAdd(&discordgo.Channel{ID: "dm", Type: discordgo.ChannelTypeDM, Recipients: []*discordgo.User{{ID: "a"}}})
+		send(b, s)
+
+		q.Flush()
+		if got := delivered(); len(got) != 0 {
+			t.Errorf("%s: expected nothing relayed for a banned sender, got %d batches", name, len(got))
+		}
+		if b.tradeConversations.HasActiveConversation("b") {
+			t.Errorf("%s: expected the conversation to be closed", name)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxNotesLength is the longest order note shown in full, matching the
+	// preview's edit modal
+	maxNotesLength = 500
+	// maxNotesPreview is how much of a note order lists show, so a page of
+	// orders stays within Discord's field limit
+	maxNotesPreview = 150
+	// maxNameLength is the longest in-game name /trade-set-name accepts
+	maxNameLength = 50
+)
+
+// markdownChars are escaped wherever they appear in player text
+const markdownChars = "\\*_`~|[]"
+
+// lineStartMarkdown are escaped only at the start of a line, where they
+// would make a header, quote or list
+const lineStartMarkdown = "#>-"
+
+// mentionPattern finds mass mentions and user, role or channel mentions.
+// Embeds never ping, but relayed DMs would, and either way a fake mention
+// reads as though the bot wrote it.
+var mentionPattern = regexp.MustCompile(`(?i)@(everyone|here)|<(@[!&]?|#)(\d+)>`)
+
+// sanitizeUserText makes player-written text safe to show in an embed or a
+// relayed DM: markdown is escaped so it shows as typed, mentions are broken
+// with a zero-width space, and text over limit runes is cut short (0 keeps
+// it all). Line breaks are kept.
+func sanitizeUserText(text string, limit int) string {
+	text = strings.TrimSpace(text)
+	if limit > 0 {
+		text = truncateLabel(text, limit)
+	}
+
+	var b strings.Builder
+	lineStart := true
+	for _, r := range text {
+		if strings.ContainsRune(markdownChars, r) || (lineStart && strings.ContainsRune(lineStartMarkdown, r)) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+		if r == '\n' {
+			lineStart = true
+		} else if !unicode.IsSpace(r) {
+			lineStart = false
+		}
+	}
+
+	return mentionPattern.ReplaceAllStringFunc(b.String(), func(mention string) string {
+		return mention[:1] + "\u200b" + mention[1:]
+	})
+}
+
+// sanitizeUserLine is sanitizeUserText for text shown within a line, such
+// as a note under an order in a list: all whitespace, line breaks included,
+// becomes single spaces
+func sanitizeUserLine(text string, limit int) string {
+	return sanitizeUserText(strings.Join(strings.Fields(text), " "), limit)
+}
+
+// safeName is an in-game name ready to show in an embed or DM
+func safeName(name string) string {
+	return sanitizeUserLine(name, maxNameLength)
+}
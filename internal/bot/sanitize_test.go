@@ -0,0 +1,50 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeUserText(t *testing.T) {
+	for input, want := range map[string]string{
+		"Cheap cannons, bulk discount": "Cheap cannons, bulk discount",
+		"@everyone free gold":          "@\u200beveryone free gold",
+		"ping @HERE now":               "ping @\u200bHERE now",
+		"ask <@123456> or <@&789>":     "ask <\u200b@123456> or <\u200b@&789>",
+		"see <#42>":                    "see <\u200b#42>",
+		"**BEST PRICE** ~~scam~~":      `\*\*BEST PRICE\*\* \~\~scam\~\~`,
+		"```unclosed block":            "\\`\\`\\`unclosed block",
+		"||spoiler|| and __u__":        `\|\|spoiler\|\| and \_\_u\_\_`,
+		"[click](http://evil.example)": `\[click\](http://evil.example)`,
+		"# OFFICIAL\n> quote\n- list":  "\\# OFFICIAL\n\\> quote\n\\- list",
+		"price 5-10 #1 > others":       "price 5-10 #1 > others",
+		`back\slash`:                   `back\\slash`,
+		"  padded  ":                   "padded",
+	} {
+		if got := sanitizeUserText(input, 0); got != want {
+			t.Errorf("sanitizeUserText(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestSanitizeUserTextLimits(t *testing.T) {
+	long := strings.Repeat("a", maxNotesLength+100)
+	if got := sanitizeUserText(long, maxNotesLength); len([]rune(got)) != maxNotesLength || !strings.HasSuffix(got, "…") {
+		t.Errorf("expected %d runes ending in an ellipsis, got %d", maxNotesLength, len([]rune(got)))
+	}
+
+	// Escaping never pushes a full-length note past an embed field
+	worst := strings.Repeat("*", maxNotesLength)
+	if got := sanitizeUserText(worst, maxNotesLength); len([]rune(got)) > maxEmbedFieldValue {
+		t.Errorf("escaped note is %d runes, over the field limit", len([]rune(got)))
+	}
+}
+
+func TestSanitizeUserLine(t *testing.T) {
+	if got := sanitizeUserLine("line one\n# line two\n\n@here", 0); got != "line one # line two @\u200bhere" {
+		t.Errorf("unexpected line %q", got)
+	}
+	if got := safeName("**Captain**  Jack"); got != `\*\*Captain\*\* Jack` {
+		t.Errorf("unexpected name %q", got)
+	}
+}
@@ -0,0 +1,170 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// submissionDiffMaxNames caps how many item names each preview field lists
+const submissionDiffMaxNames = 15
+
+// portOrderDiff compares a port's current orders of one type with a new
+// submission, keyed by item
+type portOrderDiff struct {
+	Added   []int             // item IDs only in the submission
+	Removed []database.Market // current orders missing from the submission
+	Updated int               // items present in both
+}
+
+// diffPortOrders works out what ReplacePortOrders would change. existing may
+// hold both order types; only orderType is compared.
+func diffPortOrders(existing []database.Market, orderType string, incoming []database.Market) portOrderDiff {
+	current := make(map[int]bool)
+	for _, m := range existing {
+		if m.OrderType == orderType {
+			current[m.ItemID] = true
+		}
+	}
+
+	var diff portOrderDiff
+	seen := make(map[int]bool)
+	for _, m := range incoming {
+		if seen[m.ItemID] {
+			continue
+		}
+		seen[m.ItemID] = true
+		if current[m.ItemID] {
+			diff.Updated++
+		} else {
+			diff.Added = append(diff.Added, m.ItemID)
+		}
+	}
+
+	for _, m := range existing {
+		if m.OrderType == orderType && !seen[m.ItemID] {
+			diff.Removed = append(diff.Removed, m)
+		}
+	}
+	return diff
+}
+
+// needsReplaceConfirmation reports whether a submission still has to show
+// the replace preview: it wasn't confirmed yet and would drop current orders
+func (b *Bot) needsReplaceConfirmation(sub *PendingSubmission, orders []database.Market) (portOrderDiff, bool) {
+	if sub.ReplaceConfirmed || sub.OrderMode == orderModeMerge {
+		return portOrderDiff{}, false
+	}
+
+	existing, err := b.db.GetOrdersByPort(context.Background(), *sub.PortID)
+	if err != nil {
+		// The preview is a safeguard; don't block the submission on it
+		log.Printf("Error loading port orders for replace preview: %v", err)
+		return portOrderDiff{}, false
+	}
+
+	diff := diffPortOrders(existing, sub.OrderType, orders)
+	return diff, len(diff.Removed) > 0
+}
+
+// formatDiffNames joins names for an embed field, summarizing past the cap
+func formatDiffNames(names []string) string {
+	sort.Strings(names)
+	if len(names) > submissionDiffMaxNames {
+		extra := len(names) - submissionDiffMaxNames
+		names = append(names[:submissionDiffMaxNames:submissionDiffMaxNames], fmt.Sprintf("…and %d more", extra))
+	}
+	return strings.Join(names, ", ")
+}
+
+// showReplacePreviewUI asks the user to confirm a replace that would remove
+// orders their screenshot didn't include
+func (b *Bot) showReplacePreviewUI(s *discordgo.Session, i *discordgo.InteractionCreate, sub *PendingSubmission, diff portOrderDiff) {
+	// New orders only carry item IDs; name them by what OCR read
+	ocrNames := make(map[int]string)
+	for ocrName, itemID := range sub.ItemMappings {
+		ocrNames[itemID] = ocrName
+	}
+
+	var removed, added []string
+	for _, m := range diff.Removed {
+		removed = append(removed, m.Item.DisplayName)
+	}
+	for _, itemID := range diff.Added {
+		added = append(added, ocrNames[itemID])
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "⚠️ Replace Existing Orders?",
+		Description: fmt.Sprintf(
+			"Submitting replaces every %s order at this port. %d item(s) currently listed are not in your screenshot and will be removed.\n\n"+
+				"If your screenshot only shows part of the board, choose **Merge** to update just these items and keep the rest.",
+			sub.OrderType, len(diff.Removed),
+		),
+		Color: 0xffa500,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: fmt.Sprintf("Removed (%d)", len(removed)), Value: formatDiffNames(removed)},
+		},
+	}
+	if len(added) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: fmt.Sprintf("Added (%d)", len(added)), Value: formatDiffNames(added),
+		})
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name: "Updated", Value: fmt.Sprintf("%d", diff.Updated), Inline: true,
+	})
+	addSubmissionWarnings(embed, sub)
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Merge",
+					Style:    discordgo.PrimaryButton,
+					CustomID: fmt.Sprintf("submission_merge:%s", sub.UserID),
+				},
+				discordgo.Button{
+					Label:    "Replace",
+					Style:    discordgo.DangerButton,
+					CustomID: fmt.Sprintf("submission_replace:%s", sub.UserID),
+				},
+				discordgo.Button{
+					Label:    "Cancel",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("submission_cancel:%s", sub.UserID),
+				},
+			},
+		},
+	}
+
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
+	})
+}
+
+// handleSubmissionReplace commits a submission after the replace preview,
+// in the mode the user picked
+func (b *Bot) handleSubmissionReplace(s *discordgo.Session, i *discordgo.InteractionCreate, mode string) {
+	userID := getUserID(i)
+	sub, ok := b.submissionManager.Get(userID)
+	if !ok || !b.submissionManager.IsReady(userID) {
+		b.respondSubmissionExpired(s, i)
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	sub.OrderMode = mode
+	sub.ReplaceConfirmed = true
+	b.commitSubmission(s, i, sub)
+}
@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"wosbTrade/internal/database"
+)
+
+func TestDiffPortOrders(t *testing.T) {
+	existing := []database.Market{
+		{ItemID: 1, OrderType: "sell", Item: &database.Item{DisplayName: "Cannon"}},
+		{ItemID: 2, OrderType: "sell", Item: &database.Item{DisplayName: "Mortar"}},
+		{ItemID: 3, OrderType: "buy", Item: &database.Item{DisplayName: "Rope"}},
+	}
+	incoming := []database.Market{{ItemID: 1}, {ItemID: 4}, {ItemID: 4}}
+
+	diff := diffPortOrders(existing, "sell", incoming)
+	if diff.Updated != 1 {
+		t.Errorf("expected 1 updated item, got %d", diff.Updated)
+	}
+	if len(diff.Added) != 1 || diff.Added[0] != 4 {
+		t.Errorf("expected item 4 added once, got %v", diff.Added)
+	}
+	// The buy order for item 3 is untouched by a sell submission
+	if len(diff.Removed) != 1 || diff.Removed[0].ItemID != 2 {
+		t.Errorf("expected only item 2 removed, got %+v", diff.Removed)
+	}
+
+	if diff := diffPortOrders(nil, "sell", incoming); len(diff.Removed) != 0 || len(diff.Added) != 2 {
+		t.Errorf("expected a first submission to only add, got %+v", diff)
+	}
+}
+
+func TestFormatDiffNames(t *testing.T) {
+	var names []string
+	for i := 0; i < submissionDiffMaxNames+2; i++ {
+		names = append(names, strings.Repeat("x", i+1))
+	}
+	got := formatDiffNames(names)
+	if !strings.HasSuffix(got, "…and 2 more") {
+		t.Errorf("expected overflow summary, got %q", got)
+	}
+	if got := formatDiffNames([]string{"b", "a"}); got != "a, b" {
+		t.Errorf("expected sorted names, got %q", got)
+	}
+}
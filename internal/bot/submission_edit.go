@@ -0,0 +1,216 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// submissionEditPageSize is how many items one edit modal holds; Discord
+// allows five inputs per modal
+const submissionEditPageSize = 5
+
+// maxTextInputLabel is Discord's limit on a modal text input label
+const maxTextInputLabel = 45
+
+// valueEdit is one correction a user made to an OCR price or quantity
+type valueEdit struct {
+	Index                 int // into the submission's OCR items
+	Name                  string
+	OldPrice, OldQuantity int
+	NewPrice, NewQuantity int
+}
+
+// String renders the edit for the preview and success embeds
+func (e valueEdit) String() string {
+	return fmt.Sprintf("**%s**: %s × %d → %s × %d",
+		e.Name, formatGold(int64(e.OldPrice)), e.OldQuantity, formatGold(int64(e.NewPrice)), e.NewQuantity)
+}
+
+// submissionEditPages is how many edit modals n items need
+func submissionEditPages(n int) int {
+	return (n + submissionEditPageSize - 1) / submissionEditPageSize
+}
+
+// submissionEditRange returns the item indexes [start, end) on an edit page
+func submissionEditRange(n, page int) (int, int) {
+	start := page * submissionEditPageSize
+	end := start + submissionEditPageSize
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+// submissionEditComponents returns the control for opening the edit modal:
+// a button when every item fits on one page, otherwise a menu of pages
+func submissionEditComponents(sub *PendingSubmission) discordgo.MessageComponent {
+	items := sub.OCRResult.Items
+	pages := submissionEditPages(len(items))
+	if pages <= 1 {
+		return discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Edit values",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("submission_edit:%s:0", sub.UserID),
+				},
+			},
+		}
+	}
+
+	var options []discordgo.SelectMenuOption
+	for page := 0; page < pages && page < maxSelectOptions; page++ {
+		start, end := submissionEditRange(len(items), page)
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       fmt.Sprintf("Items %d-%d", start+1, end),
+			Value:       strconv.Itoa(page),
+			Description: truncateLabel(fmt.Sprintf("%s … %s", items[start].Name, items[end-1].Name), 100),
+		})
+	}
+	return discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				CustomID:    fmt.Sprintf("submission_edit:%s", sub.UserID),
+				Placeholder: "Edit values…",
+				Options:     options,
+			},
+		},
+	}
+}
+
+// submissionEditModal builds the modal for one page of items, each input
+// prefilled with "price x quantity"
+func submissionEditModal(sub *PendingSubmission, page int) *discordgo.InteractionResponseData {
+	items := sub.OCRResult.Items
+	start, end := submissionEditRange(len(items), page)
+
+	var rows []discordgo.MessageComponent
+	for idx := start; idx < end; idx++ {
+		item := items[idx]
+		rows = append(rows, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.TextInput{
+					CustomID:  fmt.Sprintf("item_%d", idx),
+					Label:     truncateLabel(fmt.Sprintf("%d. %s", idx+1, item.Name), maxTextInputLabel),
+					Style:     discordgo.TextInputShort,
+					Value:     fmt.Sprintf("%d x %d", item.Price, item.Quantity),
+					Required:  true,
+					MaxLength: 30,
+				},
+			},
+		})
+	}
+
+	return &discordgo.InteractionResponseData{
+		CustomID:   fmt.Sprintf("submission_edit_modal:%s:%d", sub.UserID, page),
+		Title:      fmt.Sprintf("Edit values (%d/%d)", page+1, submissionEditPages(len(items))),
+		Components: rows,
+	}
+}
+
+// parseEditedValues reads "price x quantity". The separator may be x, ×, *,
+// a comma or just spaces, and digit grouping commas aren't supported.
+func parseEditedValues(s string) (price, quantity int, err error) {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return r == 'x' || r == '×' || r == '*' || r == ',' || r == ' ' || r == '\t'
+	})
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("expected \"price x quantity\", got %q", s)
+	}
+	price, err = strconv.Atoi(fields[0])
+	if err != nil || price <= 0 {
+		return 0, 0, fmt.Errorf("price %q must be a positive whole number", fields[0])
+	}
+	quantity, err = strconv.Atoi(fields[1])
+	if err != nil || quantity <= 0 {
+		return 0, 0, fmt.Errorf("quantity %q must be a positive whole number", fields[1])
+	}
+	return price, quantity, nil
+}
+
+// applySubmissionEdits writes the modal's values (input custom ID -> text)
+// back into the submission's OCR result. Nothing changes unless every value
+// parses; the problems are returned instead.
+func applySubmissionEdits(sub *PendingSubmission, values map[string]string) []string {
+	items := sub.OCRResult.Items
+
+	type change struct {
+		idx             int
+		price, quantity int
+	}
+	var changes []change
+	var problems []string
+	for idx := range items {
+		value, ok := values[fmt.Sprintf("item_%d", idx)]
+		if !ok {
+			continue
+		}
+		price, quantity, err := parseEditedValues(value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", items[idx].Name, err))
+			continue
+		}
+		changes = append(changes, change{idx, price, quantity})
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+
+	for _, c := range changes {
+		item := &items[c.idx]
+		if item.Price == c.price && item.Quantity == c.quantity {
+			continue
+		}
+		recordSubmissionEdit(sub, c.idx, c.price, c.quantity)
+		item.Price = c.price
+		item.Quantity = c.quantity
+	}
+	return nil
+}
+
+// recordSubmissionEdit keeps one edit per item, against the value OCR read.
+// Editing an item back to what OCR read drops its edit.
+func recordSubmissionEdit(sub *PendingSubmission, idx, price, quantity int) {
+	item := sub.OCRResult.Items[idx]
+	for n, e := range sub.Edits {
+		if e.Index != idx {
+			continue
+		}
+		if e.OldPrice == price && e.OldQuantity == quantity {
+			sub.Edits = append(sub.Edits[:n], sub.Edits[n+1:]...)
+			return
+		}
+		sub.Edits[n].NewPrice = price
+		sub.Edits[n].NewQuantity = quantity
+		return
+	}
+	sub.Edits = append(sub.Edits, valueEdit{
+		Index:       idx,
+		Name:        item.Name,
+		OldPrice:    item.Price,
+		OldQuantity: item.Quantity,
+		NewPrice:    price,
+		NewQuantity: quantity,
+	})
+}
+
+// submissionEditLines lists a submission's corrections for an embed
+func submissionEditLines(sub *PendingSubmission) []string {
+	lines := make([]string, 0, len(sub.Edits))
+	for _, e := range sub.Edits {
+		lines = append(lines, e.String())
+	}
+	return lines
+}
+
+// truncateLabel shortens s to limit characters with an ellipsis
+func truncateLabel(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
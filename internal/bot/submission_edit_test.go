@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"fmt"
+	"testing"
+
+	"wosbTrade/internal/ocr"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestParseEditedValues(t *testing.T) {
+	tests := []struct {
+		in              string
+		price, quantity int
+		ok              bool
+	}{
+		{"120 x 5", 120, 5, true},
+		{"120x5", 120, 5, true},
+		{"120 × 5", 120, 5, true},
+		{"120, 5", 120, 5, true},
+		{" 120 5 ", 120, 5, true},
+		{"120", 0, 0, false},
+		{"abc x 5", 0, 0, false},
+		{"0 x 5", 0, 0, false},
+		{"120 x 5 x 2", 0, 0, false},
+	}
+	for _, tt := range tests {
+		price, quantity, err := parseEditedValues(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("parseEditedValues(%q) err = %v, want ok=%v", tt.in, err, tt.ok)
+			continue
+		}
+		if tt.ok && (price != tt.price || quantity != tt.quantity) {
+			t.Errorf("parseEditedValues(%q) = %d, %d; want %d, %d", tt.in, price, quantity, tt.price, tt.quantity)
+		}
+	}
+}
+
+func newEditTestSubmission() *PendingSubmission {
+	return &PendingSubmission{
+		UserID: "u1",
+		OCRResult: &ocr.MarketData{Items: []ocr.MarketItem{
+			{Name: "Cannon", Price: 12, Quantity: 5},
+			{Name: "Rope", Price: 10, Quantity: 50},
+		}},
+	}
+}
+
+func TestApplySubmissionEdits(t *testing.T) {
+	sub := newEditTestSubmission()
+
+	problems := applySubmissionEdits(sub, map[string]string{"item_0": "120 x 5", "item_1": "10 x 50"})
+	if len(problems) > 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	if sub.OCRResult.Items[0].Price != 120 {
+		t.Errorf("expected the corrected price, got %d", sub.OCRResult.Items[0].Price)
+	}
+	if len(sub.Edits) != 1 || sub.Edits[0].String() != "**Cannon**: 12 gold × 5 → 120 gold × 5" {
+		t.Errorf("expected one edit for Cannon, got %+v", sub.Edits)
+	}
+
+	// Editing again keeps one entry against the OCR value; reverting drops it
+	applySubmissionEdits(sub, map[string]string{"item_0": "125 x 5"})
+	if len(sub.Edits) != 1 || sub.Edits[0].OldPrice != 12 || sub.Edits[0].NewPrice != 125 {
+		t.Errorf("expected the edit updated in place, got %+v", sub.Edits)
+	}
+	applySubmissionEdits(sub, map[string]string{"item_0": "12 x 5"})
+	if len(sub.Edits) != 0 {
+		t.Errorf("expected reverting to drop the edit, got %+v", sub.Edits)
+	}
+}
+
+func TestApplySubmissionEditsAllOrNothing(t *testing.T) {
+	sub := newEditTestSubmission()
+
+	problems := applySubmissionEdits(sub, map[string]string{"item_0": "120 x 5", "item_1": "ten x 50"})
+	if len(problems) != 1 {
+		t.Fatalf("expected one problem, got %v", problems)
+	}
+	if sub.OCRResult.Items[0].Price != 12 || len(sub.Edits) != 0 {
+		t.Error("expected no values changed when any input is invalid")
+	}
+}
+
+func TestSubmissionEditModalPages(t *testing.T) {
+	sub := &PendingSubmission{UserID: "u1", OCRResult: &ocr.MarketData{}}
+	for n := 0; n < 12; n++ {
+		sub.OCRResult.Items = append(sub.OCRResult.Items, ocr.MarketItem{Name: fmt.Sprintf("Item %d", n), Price: n + 1, Quantity: 1})
+	}
+
+	if _, ok := submissionEditComponents(sub).(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu); !ok {
+		t.Fatal("expected a page menu for more than one page of items")
+	}
+
+	modal := submissionEditModal(sub, 2)
+	if modal.CustomID != "submission_edit_modal:u1:2" || len(modal.Components) != 2 {
+		t.Fatalf("expected the last page to hold 2 items, got %s with %d", modal.CustomID, len(modal.Components))
+	}
+	input := modal.Components[0].(discordgo.ActionsRow).Components[0].(discordgo.TextInput)
+	if input.CustomID != "item_10" || input.Value != "11 x 1" {
+		t.Errorf("unexpected first input on page 3: %+v", input)
+	}
+}
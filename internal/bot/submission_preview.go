@@ -0,0 +1,164 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// submissionPreviewMaxFields caps the fields the parsed item table spreads
+// over, leaving room for the summary and warnings
+const submissionPreviewMaxFields = 5
+
+// buildSubmissionPreviewEmbed shows everything OCR parsed from a submission,
+// in board order, so the user can check it before it is written
+func buildSubmissionPreviewEmbed(sub *PendingSubmission, portName string) *discordgo.MessageEmbed {
+	items := sub.OCRResult.Items
+
+	embed := &discordgo.MessageEmbed{
+		Title: "🔍 Review Submission",
+		Description: fmt.Sprintf(
+			"**%d** %s order(s) for **%s**. Check the prices and quantities below, then commit or discard.",
+			len(items), sub.OrderType, portName,
+		),
+		Color: 0x3498db,
+	}
+
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("**%s** — %s × %d", item.Name, formatGold(int64(item.Price)), item.Quantity))
+	}
+	fields, truncated := embedFieldChunks("Parsed Items", lines, "\n", submissionPreviewMaxFields)
+	embed.Fields = append(embed.Fields, fields...)
+	if truncated {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: "Some items didn't fit in the preview"}
+	}
+	if len(sub.Edits) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("✏️ Corrected (%d)", len(sub.Edits)),
+			Value: joinLimited(submissionEditLines(sub), "\n", maxEmbedFieldValue),
+		})
+	}
+
+	addSubmissionWarnings(embed, sub)
+	return embed
+}
+
+// submissionPreviewComponents returns the Commit and Discard buttons, with
+// the controls for correcting values above them
+func submissionPreviewComponents(sub *PendingSubmission) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		submissionEditComponents(sub),
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Commit",
+					Style:    discordgo.SuccessButton,
+					CustomID: fmt.Sprintf("submission_commit:%s", sub.UserID),
+				},
+				discordgo.Button{
+					Label:    "Discard",
+					Style:    discordgo.DangerButton,
+					CustomID: fmt.Sprintf("submission_cancel:%s", sub.UserID),
+				},
+			},
+		},
+	}
+}
+
+// showSubmissionPreviewUI shows the parsed orders for a /submit run with
+// preview on, once port and items are confirmed
+func (b *Bot) showSubmissionPreviewUI(s *discordgo.Session, i *discordgo.InteractionCreate, sub *PendingSubmission) {
+	portName := sub.OCRResult.Port
+	if port, err := b.db.GetPortByID(context.Background(), *sub.PortID); err != nil {
+		log.Printf("Error loading port for submission preview: %v", err)
+	} else if port != nil {
+		portName = port.DisplayName
+	}
+
+	embed := buildSubmissionPreviewEmbed(sub, portName)
+	components := submissionPreviewComponents(sub)
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
+	})
+}
+
+// handleSubmissionEdit opens the edit modal for a page of the preview, picked
+// by the button's custom ID or the page menu
+func (b *Bot) handleSubmissionEdit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	sub, ok := b.submissionManager.Get(userID)
+	if !ok || !b.submissionManager.IsReady(userID) {
+		b.respondSubmissionExpired(s, i)
+		return
+	}
+
+	// submission_edit:<userID>:<page> for the button, values for the menu
+	data := i.MessageComponentData()
+	pageText := ""
+	if parts := strings.Split(data.CustomID, ":"); len(parts) == 3 {
+		pageText = parts[2]
+	} else if len(data.Values) > 0 {
+		pageText = data.Values[0]
+	}
+	page, err := strconv.Atoi(pageText)
+	if err != nil || page < 0 || page >= submissionEditPages(len(sub.OCRResult.Items)) {
+		b.respondError(s, i, t(i.Locale, "submit.edit_page_missing"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: submissionEditModal(sub, page),
+	})
+}
+
+// handleSubmissionEditModal applies corrected values and refreshes the preview
+func (b *Bot) handleSubmissionEditModal(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	sub, ok := b.submissionManager.Get(userID)
+	if !ok || !b.submissionManager.IsReady(userID) {
+		b.respondSubmissionExpired(s, i)
+		return
+	}
+
+	values := make(map[string]string)
+	for _, row := range i.ModalSubmitData().Components {
+		for _, comp := range row.(*discordgo.ActionsRow).Components {
+			if textInput, ok := comp.(*discordgo.TextInput); ok {
+				values[textInput.CustomID] = textInput.Value
+			}
+		}
+	}
+	if problems := applySubmissionEdits(sub, values); len(problems) > 0 {
+		b.respondError(s, i, t(i.Locale, "submit.edit_rejected", strings.Join(problems, "\n")))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	b.showSubmissionPreviewUI(s, i, sub)
+}
+
+// handleSubmissionCommit accepts the preview and commits the submission
+func (b *Bot) handleSubmissionCommit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := getUserID(i)
+	sub, ok := b.submissionManager.Get(userID)
+	if !ok || !b.submissionManager.IsReady(userID) {
+		b.respondSubmissionExpired(s, i)
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	sub.PreviewConfirmed = true
+	b.commitSubmission(s, i, sub)
+}
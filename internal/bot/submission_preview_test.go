@@ -0,0 +1,53 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"wosbTrade/internal/ocr"
+)
+
+func TestBuildSubmissionPreviewEmbed(t *testing.T) {
+	sub := &PendingSubmission{
+		OrderType: "sell",
+		OCRResult: &ocr.MarketData{Items: []ocr.MarketItem{
+			{Name: "Cannon", Price: 120, Quantity: 5},
+			{Name: "Rope", Price: 10, Quantity: 50},
+		}},
+		Warnings: []string{"Looks familiar"},
+	}
+
+	embed := buildSubmissionPreviewEmbed(sub, "Tortuga")
+	if !strings.Contains(embed.Description, "**2** sell order(s) for **Tortuga**") {
+		t.Errorf("unexpected description: %s", embed.Description)
+	}
+	if len(embed.Fields) != 2 {
+		t.Fatalf("expected items and warnings fields, got %d", len(embed.Fields))
+	}
+	want := "**Cannon** — 120 gold × 5\n**Rope** — 10 gold × 50"
+	if embed.Fields[0].Value != want {
+		t.Errorf("expected items in board order:\n%s\ngot:\n%s", want, embed.Fields[0].Value)
+	}
+}
+
+func TestBuildSubmissionPreviewEmbedLongBoard(t *testing.T) {
+	var items []ocr.MarketItem
+	for n := 0; n < 200; n++ {
+		items = append(items, ocr.MarketItem{Name: fmt.Sprintf("Item with a long name %d", n), Price: n, Quantity: 1})
+	}
+	sub := &PendingSubmission{OrderType: "buy", OCRResult: &ocr.MarketData{Items: items}}
+
+	embed := buildSubmissionPreviewEmbed(sub, "Tortuga")
+	if len(embed.Fields) > submissionPreviewMaxFields {
+		t.Errorf("expected at most %d fields, got %d", submissionPreviewMaxFields, len(embed.Fields))
+	}
+	for _, f := range embed.Fields {
+		if len(f.Value) > maxEmbedFieldValue {
+			t.Errorf("field %q is %d characters", f.Name, len(f.Value))
+		}
+	}
+	if embed.Footer == nil {
+		t.Error("expected a footer noting the preview was cut short")
+	}
+}
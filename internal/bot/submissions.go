@@ -1,10 +1,42 @@
 package bot
 
 import (
+	"context"
+	"errors"
+	"os"
 	"sync"
 	"time"
 	"wosbTrade/internal/database"
 	"wosbTrade/internal/ocr"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Submission confirmation timeouts. Guild settings and SUBMISSION_TIMEOUT_MINUTES
+// are clamped to this range so abandoned screenshots don't pile up on disk.
+const (
+	defaultSubmissionTimeout = 5 * time.Minute
+	minSubmissionTimeout     = 2 * time.Minute
+	maxSubmissionTimeout     = 30 * time.Minute
+)
+
+// clampSubmissionTimeout keeps a configured timeout within the allowed range
+func clampSubmissionTimeout(d time.Duration) time.Duration {
+	if d < minSubmissionTimeout {
+		return minSubmissionTimeout
+	}
+	if d > maxSubmissionTimeout {
+		return maxSubmissionTimeout
+	}
+	return d
+}
+
+// Order modes decide how a submission is written to its port's board
+const (
+	// orderModeReplace swaps out every order of the submitted type (default)
+	orderModeReplace = "replace"
+	// orderModeMerge only updates the items in the submission
+	orderModeMerge = "merge"
 )
 
 // PendingSubmission represents a submission awaiting user confirmation
@@ -12,12 +44,19 @@ type PendingSubmission struct {
 	UserID          string
 	ChannelID       string
 	InteractionID   string
-	ImagePath       string
-	OCRResult       *ocr.MarketData
+	Interaction     *discordgo.Interaction // the /submit command, whose reply holds the confirmation UI
+	ImagePaths      []string
+	OCRResults      []*ocr.MarketData // per image, in submission order
+	OCRResult       *ocr.MarketData   // merged result of all images
 	CreatedAt       time.Time
 	ExpiresAt       time.Time
 	ScreenshotHash  string
+	ScreenshotPHash string
 	OrderType       string
+	OrderMode       string // orderModeReplace or orderModeMerge
+
+	// Non-blocking notices shown alongside the confirmation flow
+	Warnings []string
 
 	// Port confirmation state
 	PortConfirmed   bool
@@ -27,6 +66,16 @@ type PendingSubmission struct {
 	// This ensures we only ask once per unique item name
 	ItemMappings    map[string]int
 	ItemsConfirmed  bool
+
+	// Set once the user accepts a replace that removes existing orders
+	ReplaceConfirmed bool
+
+	// Preview asks for a review of the parsed orders before commit
+	Preview          bool
+	PreviewConfirmed bool
+
+	// Corrections the user made to OCR values from the preview
+	Edits []valueEdit
 }
 
 // SubmissionManager manages pending submissions
@@ -34,44 +83,63 @@ type SubmissionManager struct {
 	mu          sync.RWMutex
 	submissions map[string]*PendingSubmission // userID -> submission
 	timeout     time.Duration
+	onExpire    func(*PendingSubmission) // called for each submission cleanup drops; may be nil
 }
 
-// NewSubmissionManager creates a new submission manager
-func NewSubmissionManager(timeout time.Duration) *SubmissionManager {
+// NewSubmissionManager creates a new submission manager. onExpire, if set,
+// is told about each submission that times out so the user can be notified.
+func NewSubmissionManager(timeout time.Duration, onExpire func(*PendingSubmission)) *SubmissionManager {
 	sm := &SubmissionManager{
 		submissions: make(map[string]*PendingSubmission),
 		timeout:     timeout,
+		onExpire:    onExpire,
 	}
 
-	// Start cleanup goroutine
-	go sm.cleanupLoop()
-
 	return sm
 }
 
-// Create creates a new pending submission
-func (sm *SubmissionManager) Create(userID, channelID, interactionID, imagePath, screenshotHash, orderType string, ocrResult *ocr.MarketData) *PendingSubmission {
+// ErrSubmissionInProgress is returned by Create when the user already has a
+// submission that hasn't expired. Submissions are keyed by user, so a second
+// one would take over the first one's buttons and dropdowns.
+var ErrSubmissionInProgress = errors.New("submission already in progress")
+
+// Create creates a new pending submission that expires after timeout, or the
+// manager's default if timeout is 0. An expired submission the user still
+// had is replaced and its screenshots deleted.
+func (sm *SubmissionManager) Create(userID, channelID, interactionID string, imagePaths []string, screenshotHash, orderType string, ocrResult *ocr.MarketData, timeout time.Duration) (*PendingSubmission, error) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	old, exists := sm.submissions[userID]
+	if exists && !time.Now().After(old.ExpiresAt) {
+		return nil, ErrSubmissionInProgress
+	}
+
+	if timeout <= 0 {
+		timeout = sm.timeout
+	}
 	now := time.Now()
 	sub := &PendingSubmission{
 		UserID:         userID,
 		ChannelID:      channelID,
 		InteractionID:  interactionID,
-		ImagePath:      imagePath,
+		ImagePaths:     imagePaths,
 		OCRResult:      ocrResult,
 		CreatedAt:      now,
-		ExpiresAt:      now.Add(sm.timeout),
+		ExpiresAt:      now.Add(timeout),
 		ScreenshotHash: screenshotHash,
 		OrderType:      orderType,
+		OrderMode:      orderModeReplace,
 		PortConfirmed:  false,
 		ItemsConfirmed: false,
 		ItemMappings:   make(map[string]int),
 	}
 
+	if exists {
+		old.RemoveImages()
+	}
 	sm.submissions[userID] = sub
-	return sub
+	return sub, nil
 }
 
 // Get retrieves a pending submission
@@ -92,12 +160,15 @@ func (sm *SubmissionManager) Get(userID string) (*PendingSubmission, bool) {
 	return sub, true
 }
 
-// Remove removes a pending submission
-func (sm *SubmissionManager) Remove(userID string) {
+// Remove removes a pending submission and returns it, expired or not, so the
+// caller can clean up its images
+func (sm *SubmissionManager) Remove(userID string) (*PendingSubmission, bool) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	sub, ok := sm.submissions[userID]
 	delete(sm.submissions, userID)
+	return sub, ok
 }
 
 // ConfirmPort confirms the port for a submission
@@ -204,28 +275,87 @@ func (sm *SubmissionManager) GetMarketOrders(userID string) ([]database.Market,
 	return orders, nil
 }
 
-// cleanupLoop periodically removes expired submissions
-func (sm *SubmissionManager) cleanupLoop() {
+// cleanupLoop periodically removes expired submissions until ctx is done
+func (sm *SubmissionManager) cleanupLoop(ctx context.Context) {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		sm.cleanup()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sm.cleanup()
+		}
 	}
 }
 
 func (sm *SubmissionManager) cleanup() {
 	sm.mu.Lock()
-	defer sm.mu.Unlock()
-
+	var expired []*PendingSubmission
 	now := time.Now()
 	for userID, sub := range sm.submissions {
 		if now.After(sub.ExpiresAt) {
-			// TODO: Notify user that submission expired
-			// TODO: Clean up temp image file
+			expired = append(expired, sub)
 			delete(sm.submissions, userID)
 		}
 	}
+	sm.mu.Unlock()
+
+	// Notify outside the lock; it calls Discord
+	for _, sub := range expired {
+		sub.RemoveImages()
+		if sm.onExpire != nil {
+			sm.onExpire(sub)
+		}
+	}
+}
+
+// RemoveImages deletes the submission's downloaded screenshots
+func (sub *PendingSubmission) RemoveImages() {
+	for _, path := range sub.ImagePaths {
+		os.Remove(path)
+	}
+}
+
+// SubmissionStage is the step a pending submission is waiting on
+type SubmissionStage string
+
+const (
+	StageAnalyzing SubmissionStage = "analyzing"  // screenshots not read yet
+	StageOrderType SubmissionStage = "order_type" // buy/sell undetected or disputed
+	StagePort      SubmissionStage = "port"       // port not confirmed
+	StageItems     SubmissionStage = "items"      // OCR names still to match
+	StagePreview   SubmissionStage = "preview"    // waiting for Commit on the preview
+	StageCommit    SubmissionStage = "commit"     // ready; may be asking to replace
+)
+
+// stageLabels describe each stage to the user
+var stageLabels = map[SubmissionStage]string{
+	StageAnalyzing: "Reading screenshots",
+	StageOrderType: "Confirming buy or sell",
+	StagePort:      "Confirming the port",
+	StageItems:     "Confirming items",
+	StagePreview:   "Waiting for you to review and commit",
+	StageCommit:    "Ready to save",
+}
+
+// Stage reports which confirmation step the submission is on
+func (sub *PendingSubmission) Stage() SubmissionStage {
+	switch {
+	case sub.OCRResult == nil:
+		return StageAnalyzing
+	case sub.OCRResult.OrderType != sub.OrderType:
+		return StageOrderType
+	case !sub.PortConfirmed:
+		return StagePort
+	case !sub.IsComplete():
+		return StageItems
+	case sub.Preview && !sub.PreviewConfirmed:
+		return StagePreview
+	default:
+		return StageCommit
+	}
 }
 
 // GetUniqueOCRItems returns unique item names from OCR result
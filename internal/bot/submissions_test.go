@@ -0,0 +1,198 @@
+package bot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"wosbTrade/internal/ocr"
+)
+
+// writeTestImage creates a stand-in for a downloaded screenshot
+func writeTestImage(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte("png"), 0o644); err != nil {
+		t.Fatalf("failed to write image: %v", err)
+	}
+	return path
+}
+
+// mustCreateSubmission starts a pending buy submission for userID
+func mustCreateSubmission(t *testing.T, sm *SubmissionManager, userID string, imagePaths []string, timeout time.Duration) *PendingSubmission {
+	t.Helper()
+	sub, err := sm.Create(userID, "chan", "interaction", imagePaths, "hash", "buy", nil, timeout)
+	if err != nil {
+		t.Fatalf("Create(%s) failed: %v", userID, err)
+	}
+	return sub
+}
+
+func assertRemoved(t *testing.T, path string) {
+	t.Helper()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be deleted, stat err = %v", path, err)
+	}
+}
+
+func TestSubmissionCleanupRemovesExpiredImages(t *testing.T) {
+	sm := NewSubmissionManager(time.Minute, nil)
+	expired := writeTestImage(t, "expired.png")
+	pending := writeTestImage(t, "pending.png")
+
+	mustCreateSubmission(t, sm, "expired", []string{expired}, 0).ExpiresAt = time.Now().Add(-time.Second)
+	mustCreateSubmission(t, sm, "pending", []string{pending}, 0)
+
+	sm.cleanup()
+
+	assertRemoved(t, expired)
+	if _, ok := sm.Remove("expired"); ok {
+		t.Error("expired submission should have been dropped")
+	}
+	if _, err := os.Stat(pending); err != nil {
+		t.Errorf("pending submission's image should be kept: %v", err)
+	}
+	if _, ok := sm.Get("pending"); !ok {
+		t.Error("pending submission should be kept")
+	}
+}
+
+func TestSubmissionRemoveReturnsExpired(t *testing.T) {
+	sm := NewSubmissionManager(time.Minute, nil)
+	path := writeTestImage(t, "shot.png")
+	mustCreateSubmission(t, sm, "user", []string{path}, 0).ExpiresAt = time.Now().Add(-time.Second)
+
+	// Cancelling after expiry but before cleanup still finds the images
+	sub, ok := sm.Remove("user")
+	if !ok {
+		t.Fatal("expected Remove to return the expired submission")
+	}
+	sub.RemoveImages()
+	assertRemoved(t, path)
+}
+
+func TestSubmissionCreateReplacesExpired(t *testing.T) {
+	sm := NewSubmissionManager(time.Minute, nil)
+	first := writeTestImage(t, "first.png")
+	second := writeTestImage(t, "second.png")
+
+	mustCreateSubmission(t, sm, "user", []string{first}, 0).ExpiresAt = time.Now().Add(-time.Second)
+	mustCreateSubmission(t, sm, "user", []string{second}, 0)
+
+	assertRemoved(t, first)
+	if _, err := os.Stat(second); err != nil {
+		t.Errorf("new submission's image should be kept: %v", err)
+	}
+}
+
+func TestSubmissionCreateRefusesOverwrite(t *testing.T) {
+	sm := NewSubmissionManager(time.Minute, nil)
+	first := writeTestImage(t, "first.png")
+	second := writeTestImage(t, "second.png")
+
+	sub := mustCreateSubmission(t, sm, "user", []string{first}, 0)
+	sub.PortConfirmed = true
+	sm.AddItemMapping("user", "Cannon", 7)
+
+	if _, err := sm.Create("user", "chan", "i2", []string{second}, "h2", "sell", nil, 0); !errors.Is(err, ErrSubmissionInProgress) {
+		t.Fatalf("expected ErrSubmissionInProgress, got %v", err)
+	}
+
+	// The first flow's state and screenshot are untouched
+	got, ok := sm.Get("user")
+	if !ok || got != sub || got.OrderType != "buy" || !got.PortConfirmed {
+		t.Fatalf("expected the first submission to stay, got %+v", got)
+	}
+	if id, ok := sm.GetItemMapping("user", "Cannon"); !ok || id != 7 {
+		t.Errorf("expected the first submission's item mapping kept, got %d %v", id, ok)
+	}
+	if _, err := os.Stat(first); err != nil {
+		t.Errorf("first submission's image should be kept: %v", err)
+	}
+
+	// Other users aren't affected
+	mustCreateSubmission(t, sm, "other", nil, 0)
+}
+
+func TestSubmissionCleanupNotifiesExpired(t *testing.T) {
+	var notified []string
+	sm := NewSubmissionManager(time.Minute, func(sub *PendingSubmission) {
+		notified = append(notified, sub.UserID)
+	})
+	mustCreateSubmission(t, sm, "expired", nil, 0).ExpiresAt = time.Now().Add(-time.Second)
+	mustCreateSubmission(t, sm, "pending", nil, 0)
+
+	sm.cleanup()
+	sm.cleanup()
+
+	if len(notified) != 1 || notified[0] != "expired" {
+		t.Errorf("expected one notification for the expired submission, got %v", notified)
+	}
+}
+
+func TestSubmissionCreateTimeout(t *testing.T) {
+	sm := NewSubmissionManager(5*time.Minute, nil)
+
+	sub := mustCreateSubmission(t, sm, "default", nil, 0)
+	if got := sub.ExpiresAt.Sub(sub.CreatedAt); got != 5*time.Minute {
+		t.Errorf("expected the manager's 5 minute default, got %v", got)
+	}
+	sub = mustCreateSubmission(t, sm, "guild", nil, 20*time.Minute)
+	if got := sub.ExpiresAt.Sub(sub.CreatedAt); got != 20*time.Minute {
+		t.Errorf("expected 20 minutes, got %v", got)
+	}
+}
+
+func TestClampSubmissionTimeout(t *testing.T) {
+	tests := map[time.Duration]time.Duration{
+		time.Minute:      minSubmissionTimeout,
+		10 * time.Minute: 10 * time.Minute,
+		time.Hour:        maxSubmissionTimeout,
+	}
+	for in, want := range tests {
+		if got := clampSubmissionTimeout(in); got != want {
+			t.Errorf("clampSubmissionTimeout(%v) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestSubmissionStage(t *testing.T) {
+	sub := &PendingSubmission{OrderType: "buy", ItemMappings: make(map[string]int)}
+	if got := sub.Stage(); got != StageAnalyzing {
+		t.Errorf("before OCR: got %s", got)
+	}
+
+	sub.OCRResult = &ocr.MarketData{OrderType: "sell", Items: []ocr.MarketItem{{Name: "Cannon"}, {Name: "Rope"}}}
+	if got := sub.Stage(); got != StageOrderType {
+		t.Errorf("with a disputed order type: got %s", got)
+	}
+
+	sub.OCRResult.OrderType = "buy"
+	if got := sub.Stage(); got != StagePort {
+		t.Errorf("before port confirmation: got %s", got)
+	}
+
+	sub.PortConfirmed = true
+	sub.ItemMappings["Cannon"] = 1
+	if got := sub.Stage(); got != StageItems {
+		t.Errorf("with one item left: got %s", got)
+	}
+
+	sub.ItemMappings["Rope"] = 2
+	sub.Preview = true
+	if got := sub.Stage(); got != StagePreview {
+		t.Errorf("before preview commit: got %s", got)
+	}
+
+	sub.PreviewConfirmed = true
+	if got := sub.Stage(); got != StageCommit {
+		t.Errorf("when ready: got %s", got)
+	}
+	for stage := range map[SubmissionStage]bool{StageAnalyzing: true, StageOrderType: true, StagePort: true, StageItems: true, StagePreview: true, StageCommit: true} {
+		if stageLabels[stage] == "" {
+			t.Errorf("stage %s has no label", stage)
+		}
+	}
+}
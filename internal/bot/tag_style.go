@@ -0,0 +1,36 @@
+package bot
+
+import (
+	"strings"
+
+	"wosbTrade/internal/database"
+)
+
+// tagEmbedColor returns the color of the highest-priority tag that has one,
+// or fallback. Tags are prioritised in the order GetItemTags returns them
+// (by category, then name).
+func tagEmbedColor(tags []database.Tag, fallback int) int {
+	for _, tag := range tags {
+		if tag.Color == "" {
+			continue
+		}
+		if color, err := database.ParseTagColor(tag.Color); err == nil {
+			return color
+		}
+	}
+	return fallback
+}
+
+// tagIconName prefixes an item name with its tags' icons, e.g. "💥⚓ Cannon"
+func tagIconName(name string, tags []database.Tag) string {
+	var icons []string
+	for _, tag := range tags {
+		if tag.Icon != "" {
+			icons = append(icons, tag.Icon)
+		}
+	}
+	if len(icons) == 0 {
+		return name
+	}
+	return strings.Join(icons, "") + " " + name
+}
@@ -0,0 +1,38 @@
+package bot
+
+import (
+	"testing"
+
+	"wosbTrade/internal/database"
+)
+
+func TestTagEmbedColor(t *testing.T) {
+	tags := []database.Tag{
+		{Name: "heavy"},
+		{Name: "legacy", Color: "red"}, // stored before colors were validated
+		{Name: "weapon", Color: "#FF5733"},
+		{Name: "rare", Color: "#00FF00"},
+	}
+
+	if got := tagEmbedColor(tags, 0x3498db); got != 0xFF5733 {
+		t.Errorf("Expected first valid tag color 0xFF5733, got %#x", got)
+	}
+	if got := tagEmbedColor(tags[:2], 0x3498db); got != 0x3498db {
+		t.Errorf("Expected fallback without a valid color, got %#x", got)
+	}
+}
+
+func TestTagIconName(t *testing.T) {
+	tags := []database.Tag{
+		{Name: "weapon", Icon: "💥"},
+		{Name: "heavy"},
+		{Name: "naval", Icon: "⚓"},
+	}
+
+	if got := tagIconName("Cannon", tags); got != "💥⚓ Cannon" {
+		t.Errorf("Expected icons before name, got %q", got)
+	}
+	if got := tagIconName("Rope", nil); got != "Rope" {
+		t.Errorf("Expected bare name without icons, got %q", got)
+	}
+}
@@ -1,11 +1,13 @@
 package bot
 
 import (
+	"context"
 	"sync"
 	"time"
 )
 
-// ActiveConversation tracks an in-memory active trade conversation
+// ActiveConversation tracks an in-memory active trade conversation. Its
+// in-game names are sanitized, since they go into every relayed DM.
 type ActiveConversation struct {
 	ConversationID      int
 	OrderID             int
@@ -14,6 +16,9 @@ type ActiveConversation struct {
 	CreatorUserID       string
 	CreatorIngameName   string
 	LastActivity        time.Time
+
+	// DeliveryFailures counts consecutive relays that could not be delivered
+	DeliveryFailures int
 }
 
 // GetOtherParty returns the other participant's user ID and in-game name
@@ -45,7 +50,6 @@ func NewTradeConversationManager(timeout time.Duration) *TradeConversationManage
 		conversations: make(map[string]*ActiveConversation),
 		timeout:       timeout,
 	}
-	go tcm.cleanupLoop()
 	return tcm
 }
 
@@ -106,6 +110,23 @@ func (tcm *TradeConversationManager) Touch(userID string) {
 	}
 }
 
+// RecordDeliveryResult tracks consecutive relay failures for the user's
+// conversation and returns the current count; a delivery resets it
+func (tcm *TradeConversationManager) RecordDeliveryResult(userID string, delivered bool) int {
+	tcm.mu.Lock()
+	defer tcm.mu.Unlock()
+	conv, ok := tcm.conversations[userID]
+	if !ok {
+		return 0
+	}
+	if delivered {
+		conv.DeliveryFailures = 0
+	} else {
+		conv.DeliveryFailures++
+	}
+	return conv.DeliveryFailures
+}
+
 // Remove removes both participants from the in-memory lookup
 func (tcm *TradeConversationManager) Remove(conv *ActiveConversation) {
 	tcm.mu.Lock()
@@ -130,18 +151,40 @@ func (tcm *TradeConversationManager) HasActiveConversation(userID string) bool {
 	return time.Since(conv.LastActivity) <= tcm.timeout
 }
 
-// cleanupLoop periodically removes timed-out conversations
-func (tcm *TradeConversationManager) cleanupLoop() {
+// Count returns the number of conversations held, counting each once even
+// though both parties have an entry
+func (tcm *TradeConversationManager) Count() int {
+	tcm.mu.RLock()
+	defer tcm.mu.RUnlock()
+	seen := make(map[*ActiveConversation]bool)
+	for _, conv := range tcm.conversations {
+		seen[conv] = true
+	}
+	return len(seen)
+}
+
+// cleanupLoop periodically removes timed-out conversations until ctx is done
+func (tcm *TradeConversationManager) cleanupLoop(ctx context.Context) {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
-	for range ticker.C {
-		tcm.mu.Lock()
-		now := time.Now()
-		for userID, conv := range tcm.conversations {
-			if now.Sub(conv.LastActivity) > tcm.timeout {
-				delete(tcm.conversations, userID)
-			}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tcm.cleanup()
+		}
+	}
+}
+
+func (tcm *TradeConversationManager) cleanup() {
+	tcm.mu.Lock()
+	defer tcm.mu.Unlock()
+
+	now := time.Now()
+	for userID, conv := range tcm.conversations {
+		if now.Sub(conv.LastActivity) > tcm.timeout {
+			delete(tcm.conversations, userID)
 		}
-		tcm.mu.Unlock()
 	}
 }
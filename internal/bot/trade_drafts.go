@@ -0,0 +1,145 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"wosbTrade/internal/database"
+)
+
+// TradeDraft is a /trade-create order awaiting the user's confirmation
+type TradeDraft struct {
+	Order       database.PlayerOrder
+	Duration    time.Duration // expiry is calculated from confirmation, not from the preview
+	ItemDisplay string
+	PortDisplay string
+	ExpiresAt   time.Time
+
+	// PriceWarning flags a price far from the usual range; shown on the preview
+	PriceWarning string
+
+	// PendingItem is the typed item name when nothing matched confidently.
+	// Order.ItemID stays 0 until the user picks one of ItemChoices (item ID
+	// -> display name) or creates a new item.
+	PendingItem string
+	ItemChoices map[int]string
+}
+
+// TradeDraftManager holds pending trade order previews in memory
+type TradeDraftManager struct {
+	mu      sync.Mutex
+	drafts  map[string]*TradeDraft // userID -> draft
+	timeout time.Duration
+}
+
+// NewTradeDraftManager creates a new draft manager
+func NewTradeDraftManager(timeout time.Duration) *TradeDraftManager {
+	tdm := &TradeDraftManager{
+		drafts:  make(map[string]*TradeDraft),
+		timeout: timeout,
+	}
+	return tdm
+}
+
+// Put stores a draft for its user, replacing any previous one
+func (tdm *TradeDraftManager) Put(draft *TradeDraft) {
+	tdm.mu.Lock()
+	defer tdm.mu.Unlock()
+
+	draft.ExpiresAt = time.Now().Add(tdm.timeout)
+	tdm.drafts[draft.Order.UserID] = draft
+}
+
+// Get returns a copy of the user's draft
+func (tdm *TradeDraftManager) Get(userID string) (TradeDraft, bool) {
+	tdm.mu.Lock()
+	defer tdm.mu.Unlock()
+
+	draft, ok := tdm.drafts[userID]
+	if !ok || time.Now().After(draft.ExpiresAt) {
+		return TradeDraft{}, false
+	}
+	return *draft, true
+}
+
+// Update applies edited price, quantity and notes to the user's draft
+func (tdm *TradeDraftManager) Update(userID string, price, quantity int, notes string) (TradeDraft, bool) {
+	tdm.mu.Lock()
+	defer tdm.mu.Unlock()
+
+	draft, ok := tdm.drafts[userID]
+	if !ok || time.Now().After(draft.ExpiresAt) {
+		return TradeDraft{}, false
+	}
+
+	draft.Order.Price = price
+	draft.Order.Quantity = quantity
+	draft.Order.Notes = notes
+	return *draft, true
+}
+
+// SetItem resolves a draft's pending item
+func (tdm *TradeDraftManager) SetItem(userID string, itemID int, display string) (TradeDraft, bool) {
+	tdm.mu.Lock()
+	defer tdm.mu.Unlock()
+
+	draft, ok := tdm.drafts[userID]
+	if !ok || time.Now().After(draft.ExpiresAt) {
+		return TradeDraft{}, false
+	}
+
+	draft.Order.ItemID = itemID
+	draft.ItemDisplay = display
+	draft.PendingItem = ""
+	draft.ItemChoices = nil
+	return *draft, true
+}
+
+// Take removes and returns the user's draft, so a draft can only be confirmed once
+func (tdm *TradeDraftManager) Take(userID string) (TradeDraft, bool) {
+	tdm.mu.Lock()
+	defer tdm.mu.Unlock()
+
+	draft, ok := tdm.drafts[userID]
+	delete(tdm.drafts, userID)
+	if !ok || time.Now().After(draft.ExpiresAt) {
+		return TradeDraft{}, false
+	}
+	return *draft, true
+}
+
+// Remove discards the user's draft
+func (tdm *TradeDraftManager) Remove(userID string) {
+	tdm.mu.Lock()
+	defer tdm.mu.Unlock()
+
+	delete(tdm.drafts, userID)
+}
+
+// cleanupLoop periodically removes expired drafts until ctx is done
+func (tdm *TradeDraftManager) cleanupLoop(ctx context.Context) {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tdm.cleanup()
+		}
+	}
+}
+
+func (tdm *TradeDraftManager) cleanup() {
+	tdm.mu.Lock()
+	defer tdm.mu.Unlock()
+
+	now := time.Now()
+	for userID, draft := range tdm.drafts {
+		if now.After(draft.ExpiresAt) {
+			delete(tdm.drafts, userID)
+		}
+	}
+}
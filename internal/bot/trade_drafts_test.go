@@ -0,0 +1,267 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func setupTradeDraftBot(t *testing.T) (*Bot, int) {
+	t.Helper()
+
+	tmpfile, err := os.CreateTemp("", "test-*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp db: %v", err)
+	}
+	tmpfile.Close()
+
+	db, err := database.New(tmpfile.Name())
+	if err != nil {
+		os.Remove(tmpfile.Name())
+		t.Fatalf("failed to initialize database: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		os.Remove(tmpfile.Name())
+	})
+
+	item, err := db.CreateItem(context.Background(), "cannon", "Cannon", "test")
+	if err != nil {
+		t.Fatalf("failed to create item: %v", err)
+	}
+
+	return &Bot{db: db, tradeDrafts: NewTradeDraftManager(time.Minute), bans: NewBanCache(db.IsUserBanned), words: NewWordFilter()}, item.ID
+}
+
+func newTestDraft(userID string, itemID int) *TradeDraft {
+	return &TradeDraft{
+		Order: database.PlayerOrder{
+			UserID:     userID,
+			ItemID:     itemID,
+			OrderType:  "sell",
+			Price:      100,
+			Quantity:   10,
+			IngameName: "Captain",
+		},
+		Duration:    24 * time.Hour,
+		ItemDisplay: "Cannon",
+	}
+}
+
+func TestTradeDraftPreviewEditConfirm(t *testing.T) {
+	b, itemID := setupTradeDraftBot(t)
+	ctx := context.Background()
+
+	b.tradeDrafts.Put(newTestDraft("user1", itemID))
+
+	// Previewing must not create anything
+	orders, err := b.db.GetPlayerOrdersByUser(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetPlayerOrdersByUser failed: %v", err)
+	}
+	if len(orders) != 0 {
+		t.Fatalf("Expected no orders before confirm, got %d", len(orders))
+	}
+
+	// Fix a price typo through the edit modal
+	if _, ok := b.tradeDrafts.Update("user1", 120, 5, "bulk only"); !ok {
+		t.Fatal("Expected draft to be editable")
+	}
+
+	_, created, err := b.confirmTradeDraft(ctx, "user1", defaultMaxActiveOrders)
+	if err != nil {
+		t.Fatalf("confirmTradeDraft failed: %v", err)
+	}
+	if created.Price != 120 || created.Quantity != 5 || created.Notes != "bulk only" {
+		t.Errorf("Expected edited values 120/5/bulk only, got %d/%d/%s", created.Price, created.Quantity, created.Notes)
+	}
+	if time.Until(created.ExpiresAt) < 23*time.Hour {
+		t.Errorf("Expected expiry about a day out, got %v", created.ExpiresAt)
+	}
+
+	orders, err = b.db.GetPlayerOrdersByUser(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetPlayerOrdersByUser failed: %v", err)
+	}
+	if len(orders) != 1 {
+		t.Fatalf("Expected 1 order after confirm, got %d", len(orders))
+	}
+
+	// A second click on Confirm must not create a duplicate
+	if _, _, err := b.confirmTradeDraft(ctx, "user1", defaultMaxActiveOrders); !errors.Is(err, errTradeDraftNotFound) {
+		t.Errorf("Expected errTradeDraftNotFound on second confirm, got %v", err)
+	}
+}
+
+func TestTradeDraftConfirmRechecksOrderLimit(t *testing.T) {
+	b, itemID := setupTradeDraftBot(t)
+	ctx := context.Background()
+
+	// The preview was shown with room for one more, then another order filled it
+	b.tradeDrafts.Put(newTestDraft("user1", itemID))
+	if _, err := b.createTradeOrder(ctx, *newTestDraft("user1", itemID)); err != nil {
+		t.Fatalf("createTradeOrder failed: %v", err)
+	}
+
+	if _, _, err := b.confirmTradeDraft(ctx, "user1", 1); !errors.Is(err, errTradeOrderLimit) {
+		t.Fatalf("Expected errTradeOrderLimit, got %v", err)
+	}
+	if count, _ := b.db.CountActivePlayerOrders(ctx, "user1"); count != 1 {
+		t.Errorf("Expected no order past the cap, got %d active", count)
+	}
+
+	// The draft survives, so it can be confirmed once there is room
+	if _, _, err := b.confirmTradeDraft(ctx, "user1", 2); err != nil {
+		t.Errorf("Expected the kept draft to confirm under a higher cap, got %v", err)
+	}
+}
+
+func TestTradeDraftCancel(t *testing.T) {
+	b, itemID := setupTradeDraftBot(t)
+	ctx := context.Background()
+
+	b.tradeDrafts.Put(newTestDraft("user1", itemID))
+	b.tradeDrafts.Remove("user1")
+
+	if _, _, err := b.confirmTradeDraft(ctx, "user1", defaultMaxActiveOrders); !errors.Is(err, errTradeDraftNotFound) {
+		t.Errorf("Expected errTradeDraftNotFound after cancel, got %v", err)
+	}
+	if _, ok := b.tradeDrafts.Update("user1", 1, 1, ""); ok {
+		t.Error("Expected edit after cancel to fail")
+	}
+
+	orders, err := b.db.GetPlayerOrdersByUser(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetPlayerOrdersByUser failed: %v", err)
+	}
+	if len(orders) != 0 {
+		t.Errorf("Expected no orders after cancel, got %d", len(orders))
+	}
+}
+
+func TestTradePreviewGuildToggle(t *testing.T) {
+	b, _ := setupTradeDraftBot(t)
+	ctx := context.Background()
+
+	if !b.tradePreviewEnabled(ctx, "guild1") {
+		t.Error("Expected preview enabled for an unconfigured guild")
+	}
+
+	if err := b.db.SetGuildTradePreview(ctx, "guild1", false, "admin"); err != nil {
+		t.Fatalf("SetGuildTradePreview failed: %v", err)
+	}
+	if b.tradePreviewEnabled(ctx, "guild1") {
+		t.Error("Expected preview disabled after toggling off")
+	}
+
+	// Setting the admin role must not reset the toggle
+	if err := b.db.SetGuildAdminRole(ctx, "guild1", "role1", "admin"); err != nil {
+		t.Fatalf("SetGuildAdminRole failed: %v", err)
+	}
+	if b.tradePreviewEnabled(ctx, "guild1") {
+		t.Error("Expected preview to stay disabled after setting admin role")
+	}
+}
+
+func TestMaxActiveOrders(t *testing.T) {
+	b, _ := setupTradeDraftBot(t)
+	ctx := context.Background()
+
+	if got := b.maxActiveOrders(ctx, ""); got != defaultMaxActiveOrders {
+		t.Errorf("Expected the default cap in DMs, got %d", got)
+	}
+	if got := b.maxActiveOrders(ctx, "guild1"); got != defaultMaxActiveOrders {
+		t.Errorf("Expected the default cap for an unconfigured guild, got %d", got)
+	}
+
+	if err := b.db.SetGuildMaxActiveOrders(ctx, "guild1", 5, "admin"); err != nil {
+		t.Fatalf("SetGuildMaxActiveOrders failed: %v", err)
+	}
+	if got := b.maxActiveOrders(ctx, "guild1"); got != 5 {
+		t.Errorf("Expected the guild's cap of 5, got %d", got)
+	}
+
+	want := "You have the maximum of 5 active orders; cancel one first with `/trade-cancel`."
+	if got := translate(discordgo.EnglishGB, "trade.order_limit", 5); got != want {
+		t.Errorf("Unexpected limit message %q", got)
+	}
+}
+
+func TestCreateScheduledTradeOrder(t *testing.T) {
+	b, itemID := setupTradeDraftBot(t)
+	ctx := context.Background()
+
+	draft := newTestDraft("user1", itemID)
+	start := time.Now().Add(2 * time.Hour)
+	draft.Order.ActivatesAt = &start
+	created, err := b.createTradeOrder(ctx, *draft)
+	if err != nil {
+		t.Fatalf("createTradeOrder failed: %v", err)
+	}
+	// The order's duration runs from its start, not from creation
+	if got := created.ExpiresAt.Sub(start); got != draft.Duration {
+		t.Errorf("Expected expiry %v after the start, got %v", draft.Duration, got)
+	}
+
+	// A start that passed while the preview was open goes live straight away
+	draft = newTestDraft("user1", itemID)
+	past := time.Now().Add(-time.Minute)
+	draft.Order.ActivatesAt = &past
+	created, err = b.createTradeOrder(ctx, *draft)
+	if err != nil {
+		t.Fatalf("createTradeOrder failed: %v", err)
+	}
+	if created.ActivatesAt != nil {
+		t.Errorf("Expected no scheduled start, got %v", created.ActivatesAt)
+	}
+	if live, err := b.db.GetPlayerOrder(ctx, created.ID); err != nil || live == nil {
+		t.Errorf("Expected the order live at once, got %v (%v)", live, err)
+	}
+}
+
+func TestTradeDraftPendingItem(t *testing.T) {
+	b, itemID := setupTradeDraftBot(t)
+	ctx := context.Background()
+
+	draft := newTestDraft("user1", 0)
+	draft.PendingItem = "Canon"
+	draft.ItemChoices = map[int]string{itemID: "Cannon"}
+	b.tradeDrafts.Put(draft)
+
+	// A stale Confirm button can't post an order without an item, or eat the draft
+	if _, _, err := b.confirmTradeDraft(ctx, "user1", defaultMaxActiveOrders); !errors.Is(err, errTradeDraftNotFound) {
+		t.Fatalf("Expected errTradeDraftNotFound while the item is pending, got %v", err)
+	}
+
+	resolved, ok := b.tradeDrafts.SetItem("user1", itemID, "Cannon")
+	if !ok {
+		t.Fatal("Expected the pending draft to still exist")
+	}
+	if resolved.PendingItem != "" || resolved.ItemChoices != nil || resolved.ItemDisplay != "Cannon" {
+		t.Errorf("Expected the pending item to be cleared, got %+v", resolved)
+	}
+
+	_, created, err := b.confirmTradeDraft(ctx, "user1", defaultMaxActiveOrders)
+	if err != nil {
+		t.Fatalf("confirmTradeDraft failed: %v", err)
+	}
+	if created.ItemID != itemID {
+		t.Errorf("Expected order for item %d, got %d", itemID, created.ItemID)
+	}
+}
+
+func TestTradeItemPicker(t *testing.T) {
+	candidates := []database.ItemMatch{{Item: &database.Item{ID: 7, DisplayName: "Cannon"}, Score: 0.5}}
+	_, components := tradeItemPicker("user1", "Canon", candidates)
+
+	menu := components[0].(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu)
+	if len(menu.Options) != 2 || menu.Options[0].Value != "7" || menu.Options[1].Value != "new" {
+		t.Errorf("Expected the candidate then a create option, got %+v", menu.Options)
+	}
+}
@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// formatInterest shows how many traders clicked Interested on an order, or
+// "" if nobody has
+func formatInterest(count int) string {
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("👀 %d interested", count)
+}
+
+// orderInterestButton lets a trader show interest without starting a
+// conversation
+func orderInterestButton(orderID int, disabled bool) discordgo.Button {
+	return discordgo.Button{
+		Label:    fmt.Sprintf("Interested #%d", orderID),
+		Style:    discordgo.SecondaryButton,
+		CustomID: fmt.Sprintf("trade_interest:%d", orderID),
+		Emoji:    discordgo.ComponentEmoji{Name: "👀"},
+		Disabled: disabled,
+	}
+}
+
+// firstInterestMessage tells an order's creator someone is interested. The
+// interested trader stays anonymous until they make contact.
+func firstInterestMessage(locale discordgo.Locale, order *database.PlayerOrder) string {
+	return t(locale, "interest.first_dm", order.ID, strings.ToUpper(order.OrderType), order.Item.DisplayName, order.ID)
+}
+
+// handleTradeInterestButton records a click on an Interested button and DMs
+// the creator the first time anyone shows interest in their order
+func (b *Bot) handleTradeInterestButton(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) {
+	orderID, err := strconv.Atoi(strings.TrimPrefix(customID, "trade_interest:"))
+	if err != nil {
+		log.Printf("Malformed interest button %q", customID)
+		return
+	}
+	userID := getUserID(i)
+	ctx := context.Background()
+
+	ban, err := b.bans.IsUserBanned(ctx, userID)
+	if err != nil {
+		log.Printf("Error checking trade ban: %v", err)
+		b.respondError(s, i, t(i.Locale, "trade.status_check_failed"))
+		return
+	}
+	if ban != nil {
+		b.respondError(s, i, t(i.Locale, "interest.banned"))
+		return
+	}
+
+	order, problem := b.lookupLiveOrder(ctx, i.Locale, orderID)
+	if order == nil {
+		b.respondError(s, i, problem)
+		return
+	}
+	if order.UserID == userID {
+		b.respondError(s, i, t(i.Locale, "interest.own_order"))
+		return
+	}
+
+	added, count, err := b.db.AddOrderInterest(ctx, orderID, userID)
+	if err != nil {
+		log.Printf("Error adding order interest: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+	if !added {
+		b.respondEphemeral(s, i, t(i.Locale, "interest.already", orderID, count))
+		return
+	}
+	if count == 1 {
+		b.notifyFirstInterest(s, order)
+	}
+
+	b.respondEphemeral(s, i, t(i.Locale, "interest.noted", orderID, count))
+}
+
+// notifyFirstInterest DMs an order's creator that it has its first interest
+func (b *Bot) notifyFirstInterest(s *discordgo.Session, order *database.PlayerOrder) {
+	ch, err := s.UserChannelCreate(order.UserID)
+	if err != nil {
+		log.Printf("Error opening DM for interest notice: %v", err)
+		return
+	}
+	if _, err := s.ChannelMessageSend(ch.ID, firstInterestMessage(b.userLocale(order.UserID), order)); err != nil {
+		log.Printf("Error sending interest notice: %v", err)
+	}
+}
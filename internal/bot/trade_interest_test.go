@@ -0,0 +1,28 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"wosbTrade/internal/database"
+)
+
+func TestFormatInterest(t *testing.T) {
+	if got := formatInterest(0); got != "" {
+		t.Errorf("expected nothing for no interest, got %q", got)
+	}
+	if got := formatInterest(4); got != "👀 4 interested" {
+		t.Errorf("unexpected interest %q", got)
+	}
+}
+
+func TestFirstInterestMessageIsAnonymous(t *testing.T) {
+	order := &database.PlayerOrder{ID: 9, UserID: "seller1", OrderType: "buy", Item: &database.Item{DisplayName: "Rope"}}
+	msg := firstInterestMessage("", order)
+	if !strings.Contains(msg, "#9 (BUY Rope)") || !strings.Contains(msg, "/trade-view order-id:9") {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if strings.Contains(msg, "<@") {
+		t.Errorf("expected the interested trader not to be named, got %q", msg)
+	}
+}
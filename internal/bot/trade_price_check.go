@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+
+	"wosbTrade/internal/database"
+)
+
+const (
+	// tradePriceWarnFactor is how far from the market average (either way) a
+	// /trade-create price can be before it is flagged
+	tradePriceWarnFactor = 3
+	// tradePriceMinSamples is how many active market orders are needed before
+	// the average is trusted
+	tradePriceMinSamples = 3
+)
+
+// tradePriceWarning explains why a price looks like a mistake, or returns ""
+// if it looks fine. Admin bounds take precedence over the market average.
+func tradePriceWarning(price int, bounds *database.ItemPriceBounds, avg float64, samples int) string {
+	if bounds != nil {
+		if bounds.MinPrice > 0 && price < bounds.MinPrice {
+			return fmt.Sprintf("%s is below the %s minimum set for this item.", formatGold(int64(price)), formatGold(int64(bounds.MinPrice)))
+		}
+		if bounds.MaxPrice > 0 && price > bounds.MaxPrice {
+			return fmt.Sprintf("%s is above the %s maximum set for this item.", formatGold(int64(price)), formatGold(int64(bounds.MaxPrice)))
+		}
+	}
+
+	if samples < tradePriceMinSamples || avg <= 0 {
+		return ""
+	}
+	average := formatGold(int64(math.Round(avg)))
+	if float64(price) > avg*tradePriceWarnFactor {
+		return fmt.Sprintf("%s is more than %dx the current market average of %s.", formatGold(int64(price)), tradePriceWarnFactor, average)
+	}
+	if float64(price)*tradePriceWarnFactor < avg {
+		return fmt.Sprintf("%s is less than 1/%d of the current market average of %s.", formatGold(int64(price)), tradePriceWarnFactor, average)
+	}
+	return ""
+}
+
+// checkTradePrice looks up the item's bounds and market average for
+// tradePriceWarning. Lookup failures only skip the check; it never blocks an order.
+func (b *Bot) checkTradePrice(ctx context.Context, itemID, price int) string {
+	bounds, err := b.db.GetItemPriceBounds(ctx, itemID)
+	if err != nil {
+		log.Printf("Error getting price bounds: %v", err)
+	}
+	avg, samples, err := b.db.GetAverageMarketPrice(ctx, itemID)
+	if err != nil {
+		log.Printf("Error getting market average: %v", err)
+	}
+	return tradePriceWarning(price, bounds, avg, samples)
+}
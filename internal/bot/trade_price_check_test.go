@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"wosbTrade/internal/database"
+)
+
+func TestTradePriceWarning(t *testing.T) {
+	bounds := &database.ItemPriceBounds{MinPrice: 50, MaxPrice: 500}
+
+	tests := []struct {
+		name    string
+		price   int
+		bounds  *database.ItemPriceBounds
+		avg     float64
+		samples int
+		want    string
+	}{
+		{"within average", 120, nil, 100, 5, ""},
+		{"far above average", 1000, nil, 100, 5, "1,000 gold is more than 3x"},
+		{"average with separators", 1, nil, 12500, 5, "average of 12,500 gold"},
+		{"far below average", 1, nil, 100, 5, "less than 1/3"},
+		{"too few samples", 1, nil, 100, tradePriceMinSamples - 1, ""},
+		{"below admin minimum", 40, bounds, 45, 5, "below the 50 gold minimum"},
+		{"above admin maximum", 600, bounds, 550, 5, "above the 500 gold maximum"},
+		{"within bounds still checks average", 400, bounds, 100, 5, "more than 3x"},
+		{"open-ended bounds", 10000, &database.ItemPriceBounds{MinPrice: 50}, 0, 0, ""},
+	}
+
+	for _, tt := range tests {
+		got := tradePriceWarning(tt.price, tt.bounds, tt.avg, tt.samples)
+		if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+			t.Errorf("%s: tradePriceWarning(%d) = %q, want %q", tt.name, tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestTradeOrderEmbedShowsPriceWarning(t *testing.T) {
+	draft := TradeDraft{ItemDisplay: "Cannon", PriceWarning: "1 gold is less than 1/3 of the current market average of 100 gold."}
+
+	embed := tradeOrderEmbed(draft, nil)
+	last := embed.Fields[len(embed.Fields)-1]
+	if !strings.Contains(last.Name, "Unusual Price") || !strings.Contains(last.Value, draft.PriceWarning) {
+		t.Errorf("Expected a price warning field on the preview, got %+v", last)
+	}
+
+	// Once created, the order embed is public and shouldn't carry the warning
+	for _, field := range tradeOrderEmbed(draft, &database.PlayerOrder{ID: 1}).Fields {
+		if strings.Contains(field.Name, "Unusual Price") {
+			t.Error("Expected no price warning on the created order")
+		}
+	}
+}
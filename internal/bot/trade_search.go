@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"fmt"
+	"sort"
+
+	"wosbTrade/internal/database"
+)
+
+// neutralRating is the score given to traders nobody has rated yet, so they
+// rank between well-rated and poorly-rated traders instead of at either end
+const neutralRating = 3.0
+
+// reputationScore returns the rating used for ranking, neutral when unrated
+func reputationScore(r database.RatingSummary) float64 {
+	if r.Count == 0 {
+		return neutralRating
+	}
+	return r.Average
+}
+
+// formatRating renders a rating summary for embeds, e.g. "★ 4.5 (12)"
+func formatRating(r database.RatingSummary) string {
+	if r.Count == 0 {
+		return "no ratings yet"
+	}
+	return fmt.Sprintf("★ %.1f (%d)", r.Average, r.Count)
+}
+
+// rankTradeOrders filters and orders /trade-search results. With
+// availableOnly, orders from traders who are busy in another conversation are
+// dropped. With byReputation, contactable traders come first, then higher
+// scores, then more ratings; ties keep the incoming (newest first) order.
+func rankTradeOrders(orders []database.PlayerOrder, reputations map[string]database.RatingSummary,
+	busy func(userID string) bool, availableOnly, byReputation bool) []database.PlayerOrder {
+
+	busyUsers := make(map[string]bool)
+	ranked := make([]database.PlayerOrder, 0, len(orders))
+	for _, o := range orders {
+		if _, seen := busyUsers[o.UserID]; !seen {
+			busyUsers[o.UserID] = busy(o.UserID)
+		}
+		if availableOnly && busyUsers[o.UserID] {
+			continue
+		}
+		ranked = append(ranked, o)
+	}
+
+	if !byReputation {
+		return ranked
+	}
+
+	sort.SliceStable(ranked, func(a, b int) bool {
+		busyA, busyB := busyUsers[ranked[a].UserID], busyUsers[ranked[b].UserID]
+		if busyA != busyB {
+			return !busyA
+		}
+		repA, repB := reputations[ranked[a].UserID], reputations[ranked[b].UserID]
+		if scoreA, scoreB := reputationScore(repA), reputationScore(repB); scoreA != scoreB {
+			return scoreA > scoreB
+		}
+		return repA.Count > repB.Count
+	})
+	return ranked
+}
@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"testing"
+
+	"wosbTrade/internal/database"
+)
+
+// testSearchOrders returns orders newest first, as SearchPlayerOrders does
+func testSearchOrders() []database.PlayerOrder {
+	return []database.PlayerOrder{
+		{ID: 1, UserID: "busy-star"},
+		{ID: 2, UserID: "unrated"},
+		{ID: 3, UserID: "poor"},
+		{ID: 4, UserID: "good"},
+		{ID: 5, UserID: "good-veteran"},
+	}
+}
+
+var testReputations = map[string]database.RatingSummary{
+	"busy-star":    {Average: 5, Count: 10},
+	"poor":         {Average: 1.5, Count: 4},
+	"good":         {Average: 4.5, Count: 2},
+	"good-veteran": {Average: 4.5, Count: 30},
+}
+
+func testBusy(userID string) bool {
+	return userID == "busy-star"
+}
+
+func orderIDs(orders []database.PlayerOrder) []int {
+	ids := make([]int, len(orders))
+	for idx, o := range orders {
+		ids[idx] = o.ID
+	}
+	return ids
+}
+
+func assertOrderIDs(t *testing.T, got []database.PlayerOrder, want ...int) {
+	t.Helper()
+	ids := orderIDs(got)
+	if len(ids) != len(want) {
+		t.Fatalf("Expected orders %v, got %v", want, ids)
+	}
+	for idx := range want {
+		if ids[idx] != want[idx] {
+			t.Fatalf("Expected orders %v, got %v", want, ids)
+		}
+	}
+}
+
+func TestRankTradeOrdersByReputationAvailableOnly(t *testing.T) {
+	ranked := rankTradeOrders(testSearchOrders(), testReputations, testBusy, true, true)
+
+	// Busy trader dropped; unrated sits between good and poor; ties broken by rating count
+	assertOrderIDs(t, ranked, 5, 4, 2, 3)
+}
+
+func TestRankTradeOrdersByReputationRanksBusyLast(t *testing.T) {
+	ranked := rankTradeOrders(testSearchOrders(), testReputations, testBusy, false, true)
+
+	assertOrderIDs(t, ranked, 5, 4, 2, 3, 1)
+}
+
+func TestRankTradeOrdersAvailableOnlyKeepsNewestFirst(t *testing.T) {
+	ranked := rankTradeOrders(testSearchOrders(), testReputations, testBusy, true, false)
+
+	assertOrderIDs(t, ranked, 2, 3, 4, 5)
+}
+
+func TestRankTradeOrdersWithoutRatings(t *testing.T) {
+	ranked := rankTradeOrders(testSearchOrders(), nil, testBusy, false, true)
+
+	// Everyone is neutral, so only availability moves orders
+	assertOrderIDs(t, ranked, 2, 3, 4, 5, 1)
+}
+
+func TestFormatRating(t *testing.T) {
+	if got := formatRating(database.RatingSummary{}); got != "no ratings yet" {
+		t.Errorf("Expected unrated text, got %q", got)
+	}
+	if got := formatRating(database.RatingSummary{Average: 4.666, Count: 3}); got != "★ 4.7 (3)" {
+		t.Errorf("Expected rounded average with count, got %q", got)
+	}
+}
@@ -0,0 +1,103 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// buildTradeViewEmbed renders one order in full for /trade-view. interest is
+// how many traders clicked Interested on it.
+func buildTradeViewEmbed(order *database.PlayerOrder, rating database.RatingSummary, interest int, now time.Time) *discordgo.MessageEmbed {
+	port := "Any port"
+	if order.Port != nil {
+		port = order.Port.DisplayName
+		if order.Port.Region != "" {
+			port += fmt.Sprintf(" (%s)", order.Port.Region)
+		}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("%s Order #%d: %s %s", orderTypeEmoji(order.OrderType), order.ID,
+			strings.ToUpper(order.OrderType), order.Item.DisplayName),
+		Color: 0xf39c12,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Price", Value: formatGold(int64(order.Price)), Inline: true},
+			{Name: "Quantity", Value: fmt.Sprintf("%d", order.Quantity), Inline: true},
+			{Name: "Total", Value: formatGold(orderTotal(order.Price, order.Quantity)), Inline: true},
+			{Name: "Port", Value: port, Inline: true},
+			{Name: "Trader", Value: fmt.Sprintf("**%s** (%s)", safeName(order.IngameName), formatRating(rating)), Inline: true},
+			{Name: "Posted", Value: formatAge(now.Sub(order.CreatedAt)), Inline: true},
+			{Name: "Expires", Value: fmt.Sprintf("<t:%d:f> (<t:%d:R>)", order.ExpiresAt.Unix(), order.ExpiresAt.Unix()), Inline: true},
+		},
+		Timestamp: now.Format(time.RFC3339),
+	}
+	if shown := formatInterest(interest); shown != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Interest", Value: shown, Inline: true})
+	}
+	if order.Notes != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Notes", Value: sanitizeUserText(order.Notes, maxNotesLength)})
+	}
+	return embed
+}
+
+// tradeViewComponents offers the Contact and Interested buttons, disabled on
+// the viewer's own order
+func tradeViewComponents(order *database.PlayerOrder, viewerID string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    fmt.Sprintf("Contact #%d", order.ID),
+					Style:    discordgo.PrimaryButton,
+					CustomID: fmt.Sprintf("trade_contact_%d", order.ID),
+					Disabled: order.UserID == viewerID,
+				},
+				orderInterestButton(order.ID, order.UserID == viewerID),
+			},
+		},
+	}
+}
+
+// --- /trade-view ---
+
+func (b *Bot) handleTradeView(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := parseOptions(i.ApplicationCommandData().Options)
+	orderID := int(options["order-id"].IntValue())
+
+	ctx := context.Background()
+	order, problem := b.lookupLiveOrder(ctx, i.Locale, orderID)
+	if order == nil {
+		b.respondError(s, i, problem)
+		return
+	}
+
+	rating, err := b.db.GetAverageRating(ctx, order.UserID)
+	if err != nil {
+		log.Printf("Error getting trader rating: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	interest, err := b.db.GetOrderInterestCounts(ctx, []int{order.ID})
+	if err != nil {
+		log.Printf("Error getting order interest: %v", err)
+		b.respondError(s, i, t(i.Locale, "error.database"))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{buildTradeViewEmbed(order, rating, interest[order.ID], time.Now())},
+			Components: tradeViewComponents(order, getUserID(i)),
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
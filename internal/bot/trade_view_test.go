@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"wosbTrade/internal/database"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestBuildTradeViewEmbed(t *testing.T) {
+	now := time.Now()
+	order := &database.PlayerOrder{
+		ID:         7,
+		UserID:     "seller1",
+		OrderType:  "sell",
+		Price:      1500,
+		Quantity:   4,
+		IngameName: "Blackbeard",
+		Notes:      "Pickup only",
+		CreatedAt:  now.Add(-3 * time.Hour),
+		ExpiresAt:  now.Add(24 * time.Hour),
+		Item:       &database.Item{DisplayName: "Cannon"},
+		Port:       &database.Port{DisplayName: "Tortuga", Region: "Caribbean"},
+	}
+
+	embed := buildTradeViewEmbed(order, database.RatingSummary{Average: 4.5, Count: 2}, 3, now)
+	if !strings.Contains(embed.Title, "#7") || !strings.Contains(embed.Title, "SELL Cannon") {
+		t.Errorf("unexpected title %q", embed.Title)
+	}
+	fields := make(map[string]string)
+	for _, f := range embed.Fields {
+		fields[f.Name] = f.Value
+	}
+	want := map[string]string{
+		"Total":    "6,000 gold",
+		"Port":     "Tortuga (Caribbean)",
+		"Trader":   "**Blackbeard** (★ 4.5 (2))",
+		"Posted":   "3h ago",
+		"Notes":    "Pickup only",
+		"Interest": "👀 3 interested",
+	}
+	for name, value := range want {
+		if fields[name] != value {
+			t.Errorf("%s: expected %q, got %q", name, value, fields[name])
+		}
+	}
+
+	order.Port = nil
+	order.Notes = ""
+	embed = buildTradeViewEmbed(order, database.RatingSummary{}, 0, now)
+	for _, f := range embed.Fields {
+		if f.Name == "Notes" || f.Name == "Interest" {
+			t.Errorf("expected no %s field", f.Name)
+		}
+		if f.Name == "Port" && f.Value != "Any port" {
+			t.Errorf("expected Any port, got %q", f.Value)
+		}
+	}
+}
+
+func TestTradeViewContactDisabledForOwner(t *testing.T) {
+	order := &database.PlayerOrder{ID: 7, UserID: "seller1"}
+	button := func(viewer string) discordgo.Button {
+		row := tradeViewComponents(order, viewer)[0].(discordgo.ActionsRow)
+		return row.Components[0].(discordgo.Button)
+	}
+	if b := button("buyer1"); b.Disabled || b.CustomID != "trade_contact_7" {
+		t.Errorf("expected an enabled contact button, got %+v", b)
+	}
+	if !button("seller1").Disabled {
+		t.Error("expected the owner's contact button to be disabled")
+	}
+
+	row := tradeViewComponents(order, "seller1")[0].(discordgo.ActionsRow)
+	if interest := row.Components[1].(discordgo.Button); !interest.Disabled || interest.CustomID != "trade_interest:7" {
+		t.Errorf("expected a disabled interest button for the owner, got %+v", interest)
+	}
+}
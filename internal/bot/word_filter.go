@@ -0,0 +1,188 @@
+package bot
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	// filterWordMinLength and filterWordMaxLength bound /admin-filter-add
+	filterWordMinLength = 2
+	filterWordMaxLength = 50
+)
+
+// WordFilter holds the words barred from order notes, in-game names and
+// relayed DMs. Matching is case-insensitive and on whole words only, so a
+// barred word doesn't also catch the longer, innocent words containing it.
+//
+// Words come from two places: the list file named by WORD_FILTER_FILE,
+// fixed until restart, and words admins add, which are kept in the database.
+type WordFilter struct {
+	mu    sync.RWMutex
+	words map[string]bool // word -> came from the list file
+}
+
+// NewWordFilter creates an empty filter
+func NewWordFilter() *WordFilter {
+	return &WordFilter{words: make(map[string]bool)}
+}
+
+// LoadList adds the words from a list file
+func (f *WordFilter) LoadList(words []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, word := range words {
+		f.words[normalizeFilterWord(word)] = true
+	}
+}
+
+// Add bars a word added by an admin
+func (f *WordFilter) Add(word string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	word = normalizeFilterWord(word)
+	if _, ok := f.words[word]; !ok {
+		f.words[word] = false
+	}
+}
+
+// Remove lifts a word added by an admin. Words from the list file stay
+// barred; listed reports whether word is one of them.
+func (f *WordFilter) Remove(word string) (listed bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	word = normalizeFilterWord(word)
+	if f.words[word] {
+		return true
+	}
+	delete(f.words, word)
+	return false
+}
+
+// Words returns every barred word, sorted
+func (f *WordFilter) Words() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	words := make([]string, 0, len(f.words))
+	for word := range f.words {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// Match returns the first barred word in text, or "" if there is none
+func (f *WordFilter) Match(text string) string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, span := range wordSpans(text) {
+		if word := strings.ToLower(text[span[0]:span[1]]); f.hasWord(word) {
+			return word
+		}
+	}
+	return ""
+}
+
+// Mask replaces each barred word in text with escaped asterisks, one per
+// letter, so relayed DMs still read as a message
+func (f *WordFilter) Mask(text string) string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var b strings.Builder
+	last := 0
+	for _, span := range wordSpans(text) {
+		word := text[span[0]:span[1]]
+		if !f.hasWord(strings.ToLower(word)) {
+			continue
+		}
+		b.WriteString(text[last:span[0]])
+		b.WriteString(strings.Repeat(`\*`, len([]rune(word))))
+		last = span[1]
+	}
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// hasWord reports whether a lowercased word is barred. Callers hold mu.
+func (f *WordFilter) hasWord(word string) bool {
+	_, ok := f.words[word]
+	return ok
+}
+
+// wordSpans returns the byte ranges of each run of letters and digits in text
+func wordSpans(text string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+	return spans
+}
+
+// normalizeFilterWord is the form filter words are stored and compared in
+func normalizeFilterWord(word string) string {
+	return strings.ToLower(strings.TrimSpace(word))
+}
+
+// validFilterWord reports whether word can be filtered: one run of letters
+// and digits, since matching is on whole words
+func validFilterWord(word string) bool {
+	n := len([]rune(word))
+	if n < filterWordMinLength || n > filterWordMaxLength {
+		return false
+	}
+	spans := wordSpans(word)
+	return len(spans) == 1 && spans[0] == [2]int{0, len(word)}
+}
+
+// readWordList reads a filter list file: one word per line, with blank lines
+// and lines starting with # ignored
+func readWordList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open word list: %w", err)
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word := normalizeFilterWord(line)
+		if !validFilterWord(word) {
+			return nil, fmt.Errorf("invalid word %q in word list", line)
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read word list: %w", err)
+	}
+	return words, nil
+}
@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWordFilterMatchesWholeWords(t *testing.T) {
+	f := NewWordFilter()
+	f.Add("Scam")
+
+	for text, want := range map[string]string{
+		"total SCAM, avoid":  "scam",
+		"scam":               "scam",
+		"no scamming here":   "",
+		"scampi for sale":    "",
+		"":                   "",
+		"(scam) in brackets": "scam",
+	} {
+		if got := f.Match(text); got != want {
+			t.Errorf("Match(%q) = %q, want %q", text, got, want)
+		}
+	}
+
+	if got := f.Mask("a Scam! not scampi"); got != `a \*\*\*\*! not scampi` {
+		t.Errorf("unexpected mask %q", got)
+	}
+	if got := f.Mask("nothing to hide"); got != "nothing to hide" {
+		t.Errorf("expected clean text unchanged, got %q", got)
+	}
+}
+
+func TestWordFilterKeepsListedWords(t *testing.T) {
+	f := NewWordFilter()
+	f.LoadList([]string{"fraud"})
+	f.Add("scam")
+
+	if listed := f.Remove("fraud"); !listed || f.Match("fraud") == "" {
+		t.Error("expected a listed word to stay filtered")
+	}
+	if listed := f.Remove("SCAM"); listed || f.Match("scam") != "" {
+		t.Error("expected an admin's word to be removed")
+	}
+	if got := f.Words(); !reflect.DeepEqual(got, []string{"fraud"}) {
+		t.Errorf("expected [fraud], got %v", got)
+	}
+}
+
+func TestValidFilterWord(t *testing.T) {
+	for word, want := range map[string]bool{
+		"scam":      true,
+		"estafa123": true,
+		"s":         false,
+		"two words": false,
+		"scam!":     false,
+	} {
+		if got := validFilterWord(word); got != want {
+			t.Errorf("validFilterWord(%q) = %v, want %v", word, got, want)
+		}
+	}
+}
+
+func TestReadWordList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	os.WriteFile(path, []byte("# barred words\nScam\n\n  fraud  \n"), 0644)
+
+	words, err := readWordList(path)
+	if err != nil {
+		t.Fatalf("readWordList failed: %v", err)
+	}
+	if !reflect.DeepEqual(words, []string{"scam", "fraud"}) {
+		t.Errorf("expected [scam fraud], got %v", words)
+	}
+
+	os.WriteFile(path, []byte("two words\n"), 0644)
+	if _, err := readWordList(path); err == nil {
+		t.Error("expected an error for a line that isn't one word")
+	}
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// accentFolds maps accented Latin letters to their plain form. It covers the
+// letters players actually type; anything else is left as is.
+var accentFolds = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ß': "ss", 'ť': "t", 'ţ': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// canonicalItemName is the form items.name is stored and looked up in:
+// lowercased, accent-folded, with runs of whitespace collapsed. Display names
+// keep whatever the player typed.
+func canonicalItemName(name string) string {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+
+	var b strings.Builder
+	for _, r := range name {
+		if folded, ok := accentFolds[r]; ok {
+			b.WriteString(folded)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// canonicalizeItemNames rewrites existing item names into canonical form and
+// makes them unique regardless of case.
+//
+// The column keeps its original definition: rebuilding items would cascade
+// deletes into every table that references it, so uniqueness is enforced by
+// a NOCASE index instead. Items whose canonical names collide keep their own
+// row, suffixed with the item ID, so /admin-item-list-duplicates shows them.
+func canonicalizeItemNames(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `SELECT id, name FROM items ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("failed to read items: %w", err)
+	}
+	type itemName struct {
+		id   int
+		name string
+	}
+	var items []itemName
+	for rows.Next() {
+		var it itemName
+		if err := rows.Scan(&it.id, &it.name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan item: %w", err)
+		}
+		items = append(items, it)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read items: %w", err)
+	}
+
+	// Names already canonical claim their slot first so no rename below
+	// collides with a row that hasn't moved yet
+	claimed := make(map[string]int)
+	for _, it := range items {
+		if canonical := canonicalItemName(it.name); canonical == it.name {
+			if _, taken := claimed[canonical]; !taken {
+				claimed[canonical] = it.id
+			}
+		}
+	}
+
+	for _, it := range items {
+		canonical := canonicalItemName(it.name)
+		target := canonical
+		if owner, taken := claimed[canonical]; taken && owner != it.id {
+			target = fmt.Sprintf("%s (%d)", canonical, it.id)
+			log.Printf("Item %d %q duplicates item %d; renamed to %q", it.id, it.name, owner, target)
+		} else {
+			claimed[canonical] = it.id
+		}
+		if target == it.name {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE items SET name = ? WHERE id = ?`, target, it.id); err != nil {
+			return fmt.Errorf("failed to rename item %d: %w", it.id, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_items_name_nocase ON items(name COLLATE NOCASE)`,
+	); err != nil {
+		return fmt.Errorf("failed to create item name index: %w", err)
+	}
+	return nil
+}
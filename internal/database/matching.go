@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 	"unicode"
@@ -41,13 +43,46 @@ type PortMatch struct {
 	MatchedVia string
 }
 
+// ocrNoisePatterns match junk OCR picks up around an item name: quantity
+// tokens ("x50", "50x", "(x50)") and prices with a currency marker or
+// thousands separator ("1,250g"). Plain trailing numbers are kept since
+// some item names end in one.
+var ocrNoisePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\s*\(\s*[x×]?\s*[\d.,]+\s*(?:[x×]|pcs|g|gold)?\s*\)\s*$`),
+	regexp.MustCompile(`(?i)\s+[x×]\s*[\d.,]+$`),
+	regexp.MustCompile(`(?i)\s+[\d.,]+\s*[x×]$`),
+	regexp.MustCompile(`(?i)\s+[$¢💰🪙]?\s*[\d.,]+\s*(?:g|gold|gp|[$¢💰🪙])$`),
+	regexp.MustCompile(`\s+\d{1,3}(?:,\d{3})+$`),
+	regexp.MustCompile(`\s*[$¢💰🪙]+\s*`),
+}
+
+// stripOCRNoise removes quantity and price noise from an OCR'd item name.
+// Only matching uses the result; the raw name is kept for display.
+func stripOCRNoise(name string) string {
+	cleaned := strings.TrimSpace(name)
+	for {
+		before := cleaned
+		for _, pattern := range ocrNoisePatterns {
+			cleaned = strings.TrimSpace(pattern.ReplaceAllString(cleaned, " "))
+		}
+		if cleaned == before {
+			break
+		}
+	}
+	if cleaned == "" {
+		return strings.TrimSpace(name)
+	}
+	return cleaned
+}
+
 // FindItemMatches finds the best matching items for a given name
 func (db *DB) FindItemMatches(ctx context.Context, name string, limit int) ([]ItemMatch, error) {
+	name = stripOCRNoise(name)
 	normalized := normalize(name)
 
 	// Check for exact match on canonical name
 	exactItem, err := db.getItemByName(ctx, name)
-	if err == nil && exactItem != nil {
+	if err == nil && exactItem != nil && !exactItem.Archived {
 		return []ItemMatch{{
 			Item:       exactItem,
 			Score:      1.0,
@@ -67,19 +102,35 @@ func (db *DB) FindItemMatches(ctx context.Context, name string, limit int) ([]It
 		}}, nil
 	}
 
-	// Fuzzy search all items
-	items, err := db.getAllItems(ctx)
+	return db.fuzzyItemMatches(ctx, normalized, MediumConfidenceThreshold, limit)
+}
+
+// itemCandidateMinScore is the lowest score FindItemCandidates offers; below
+// it names share little more than a letter or two
+const itemCandidateMinScore = 0.3
+
+// FindItemCandidates returns the closest items to name even when none is a
+// confident match, for letting a user pick instead of creating a new item
+func (db *DB) FindItemCandidates(ctx context.Context, name string, limit int) ([]ItemMatch, error) {
+	return db.fuzzyItemMatches(ctx, normalize(stripOCRNoise(name)), itemCandidateMinScore, limit)
+}
+
+// fuzzyItemMatches scores every item against a normalized name and returns
+// the best limit scoring at least minScore
+func (db *DB) fuzzyItemMatches(ctx context.Context, normalized string, minScore float64, limit int) ([]ItemMatch, error) {
+	items, err := db.GetActiveItems(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var matches []ItemMatch
-	for _, item := range items {
+	for idx := range items {
+		item := &items[idx] // go 1.21 reuses the range variable, so don't take its address
 		score := calculateSimilarity(normalized, normalize(item.Name))
-		if score >= MediumConfidenceThreshold {
+		if score >= minScore {
 			confidence := getConfidence(score)
 			matches = append(matches, ItemMatch{
-				Item:       &item,
+				Item:       item,
 				Score:      score,
 				Confidence: confidence,
 				MatchedVia: "fuzzy",
@@ -113,6 +164,52 @@ func (db *DB) FindItemMatches(ctx context.Context, name string, limit int) ([]It
 	return matches, nil
 }
 
+// DuplicatePair is two items whose names are similar enough that one is
+// probably a copy of the other
+type DuplicatePair struct {
+	Keep      Item // tagged, or else the older of the two
+	Duplicate Item
+	Score     float64
+}
+
+// FindLikelyDuplicates compares every pair of item names and returns pairs
+// scoring at least threshold, best first
+func (db *DB) FindLikelyDuplicates(ctx context.Context, threshold float64) ([]DuplicatePair, error) {
+	items, err := db.GetActiveItems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+
+	normalized := make([]string, len(items))
+	for idx, item := range items {
+		normalized[idx] = normalize(item.Name)
+	}
+
+	var pairs []DuplicatePair
+	for a := 0; a < len(items); a++ {
+		for b := a + 1; b < len(items); b++ {
+			score := calculateSimilarity(normalized[a], normalized[b])
+			if score < threshold {
+				continue
+			}
+
+			keep, dup := items[a], items[b]
+			if (dup.IsTagged && !keep.IsTagged) || (dup.IsTagged == keep.IsTagged && dup.ID < keep.ID) {
+				keep, dup = dup, keep
+			}
+			pairs = append(pairs, DuplicatePair{Keep: keep, Duplicate: dup, Score: score})
+		}
+	}
+
+	sort.SliceStable(pairs, func(x, y int) bool {
+		if pairs[x].Score != pairs[y].Score {
+			return pairs[x].Score > pairs[y].Score
+		}
+		return pairs[x].Keep.DisplayName < pairs[y].Keep.DisplayName
+	})
+	return pairs, nil
+}
+
 // FindPortMatches finds the best matching ports for a given name
 func (db *DB) FindPortMatches(ctx context.Context, name string, limit int) ([]PortMatch, error) {
 	normalized := normalize(name)
@@ -146,12 +243,13 @@ func (db *DB) FindPortMatches(ctx context.Context, name string, limit int) ([]Po
 	}
 
 	var matches []PortMatch
-	for _, port := range ports {
+	for idx := range ports {
+		port := &ports[idx]
 		score := calculateSimilarity(normalized, normalize(port.Name))
 		if score >= MediumConfidenceThreshold {
 			confidence := getConfidence(score)
 			matches = append(matches, PortMatch{
-				Port:       &port,
+				Port:       port,
 				Score:      score,
 				Confidence: confidence,
 				MatchedVia: "fuzzy",
@@ -209,12 +307,22 @@ func normalize(s string) string {
 	return s
 }
 
+// calculateSimilarity scores two normalized names from 0 to 1, taking the
+// better of a character-level and a word-level comparison
 func calculateSimilarity(a, b string) float64 {
 	if a == b {
 		return 1.0
 	}
 
-	// Levenshtein distance
+	score := levenshteinSimilarity(a, b)
+	if tokenScore := tokenSetSimilarity(a, b); tokenScore > score {
+		score = tokenScore
+	}
+	return score
+}
+
+// levenshteinSimilarity is 1 minus the edit distance over the longer length
+func levenshteinSimilarity(a, b string) float64 {
 	distance := levenshtein(a, b)
 	maxLen := max(len(a), len(b))
 
@@ -225,6 +333,58 @@ func calculateSimilarity(a, b string) float64 {
 	return 1.0 - (float64(distance) / float64(maxLen))
 }
 
+// tokenSetSimilarity compares names word by word, so word order and extra
+// words cost less than they do character by character. It is the better of:
+//   - the Levenshtein similarity of the sorted, deduplicated words, which
+//     makes "iron cannon heavy" equal to "heavy iron cannon"
+//   - the share of characters in words both names contain, which gives
+//     "cannonball x200" vs "cannonball" partial credit without treating
+//     "heavy cannon" as "cannon"
+func tokenSetSimilarity(a, b string) float64 {
+	setA := tokenSet(a)
+	setB := tokenSet(b)
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0.0
+	}
+
+	sortScore := levenshteinSimilarity(strings.Join(sortedTokens(setA), " "), strings.Join(sortedTokens(setB), " "))
+
+	common, total := 0, 0
+	for token := range setA {
+		total += len(token)
+		if setB[token] {
+			common += len(token)
+		}
+	}
+	for token := range setB {
+		total += len(token)
+	}
+	overlap := 2 * float64(common) / float64(total)
+
+	if overlap > sortScore {
+		return overlap
+	}
+	return sortScore
+}
+
+// tokenSet splits a normalized name into its distinct words
+func tokenSet(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, token := range strings.Fields(s) {
+		set[token] = true
+	}
+	return set
+}
+
+func sortedTokens(set map[string]bool) []string {
+	tokens := make([]string, 0, len(set))
+	for token := range set {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
 func levenshtein(a, b string) int {
 	if len(a) == 0 {
 		return len(b)
@@ -305,11 +465,15 @@ func (db *DB) GetItemByName(ctx context.Context, name string) (*Item, error) {
 }
 
 func (db *DB) getItemByName(ctx context.Context, name string) (*Item, error) {
-	query := `SELECT id, name, display_name, is_tagged, added_at, added_by, notes FROM items WHERE name = ? COLLATE NOCASE`
+	query := `SELECT id, name, display_name, is_tagged, archived, added_at, added_by, COALESCE(notes, '') FROM items WHERE name = ? COLLATE NOCASE`
 	var item Item
 	var addedBy sql.NullString
-	err := db.conn.QueryRowContext(ctx, query, name).Scan(
-		&item.ID, &item.Name, &item.DisplayName, &item.IsTagged,
+	stmt, err := db.prepared(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	err = stmt.QueryRowContext(ctx, canonicalItemName(name)).Scan(
+		&item.ID, &item.Name, &item.DisplayName, &item.IsTagged, &item.Archived,
 		&item.AddedAt, &addedBy, &item.Notes,
 	)
 	if err != nil {
@@ -323,13 +487,17 @@ func (db *DB) getItemByName(ctx context.Context, name string) (*Item, error) {
 
 func (db *DB) getItemByAlias(ctx context.Context, alias string) (*Item, error) {
 	query := `
-		SELECT i.id, i.name, i.display_name, i.is_tagged, i.added_at, i.added_by, i.notes
+		SELECT i.id, i.name, i.display_name, i.is_tagged, i.added_at, COALESCE(i.added_by, ''), COALESCE(i.notes, '')
 		FROM items i
 		JOIN item_aliases a ON i.id = a.item_id
-		WHERE a.alias = ? COLLATE NOCASE
+		WHERE a.alias = ? COLLATE NOCASE AND i.archived = FALSE
 	`
 	var item Item
-	err := db.conn.QueryRowContext(ctx, query, alias).Scan(
+	stmt, err := db.prepared(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	err = stmt.QueryRowContext(ctx, alias).Scan(
 		&item.ID, &item.Name, &item.DisplayName, &item.IsTagged,
 		&item.AddedAt, &item.AddedBy, &item.Notes,
 	)
@@ -339,8 +507,9 @@ func (db *DB) getItemByAlias(ctx context.Context, alias string) (*Item, error) {
 	return &item, nil
 }
 
-func (db *DB) getAllItems(ctx context.Context) ([]Item, error) {
-	query := `SELECT id, name, display_name, is_tagged, added_at, added_by, notes FROM items`
+// GetActiveItems returns every item that hasn't been archived, for matching
+func (db *DB) GetActiveItems(ctx context.Context) ([]Item, error) {
+	query := `SELECT id, name, display_name, is_tagged, added_at, COALESCE(added_by, ''), COALESCE(notes, '') FROM items WHERE archived = FALSE`
 	rows, err := db.conn.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -360,9 +529,18 @@ func (db *DB) getAllItems(ctx context.Context) ([]Item, error) {
 	return items, rows.Err()
 }
 
+// GetItemAliases retrieves all aliases for an item (exported for handlers)
+func (db *DB) GetItemAliases(ctx context.Context, itemID int) ([]ItemAlias, error) {
+	return db.getItemAliases(ctx, itemID)
+}
+
 func (db *DB) getItemAliases(ctx context.Context, itemID int) ([]ItemAlias, error) {
 	query := `SELECT id, item_id, alias, added_at FROM item_aliases WHERE item_id = ?`
-	rows, err := db.conn.QueryContext(ctx, query, itemID)
+	stmt, err := db.prepared(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, itemID)
 	if err != nil {
 		return nil, err
 	}
@@ -385,13 +563,34 @@ func (db *DB) GetPortByName(ctx context.Context, name string) (*Port, error) {
 	return db.getPortByName(ctx, name)
 }
 
+// GetPortByID retrieves a port by ID, or nil if it doesn't exist
+func (db *DB) GetPortByID(ctx context.Context, id int) (*Port, error) {
+	query := `SELECT id, name, display_name, COALESCE(region, ''), COALESCE(region_id, 0), added_at, COALESCE(added_by, ''), COALESCE(notes, '') FROM ports WHERE id = ?`
+	var port Port
+	err := db.conn.QueryRowContext(ctx, query, id).Scan(
+		&port.ID, &port.Name, &port.DisplayName, &port.Region, &port.RegionID,
+		&port.AddedAt, &port.AddedBy, &port.Notes,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get port: %w", err)
+	}
+	return &port, nil
+}
+
 func (db *DB) getPortByName(ctx context.Context, name string) (*Port, error) {
-	query := `SELECT id, name, display_name, region, added_at, added_by, notes FROM ports WHERE name = ? COLLATE NOCASE`
+	query := `SELECT id, name, display_name, region, COALESCE(region_id, 0), added_at, added_by, COALESCE(notes, '') FROM ports WHERE name = ? COLLATE NOCASE`
 	var port Port
 	var addedBy sql.NullString
 	var region sql.NullString
-	err := db.conn.QueryRowContext(ctx, query, name).Scan(
-		&port.ID, &port.Name, &port.DisplayName, &region,
+	stmt, err := db.prepared(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	err = stmt.QueryRowContext(ctx, name).Scan(
+		&port.ID, &port.Name, &port.DisplayName, &region, &port.RegionID,
 		&port.AddedAt, &addedBy, &port.Notes,
 	)
 	if err != nil {
@@ -408,14 +607,18 @@ func (db *DB) getPortByName(ctx context.Context, name string) (*Port, error) {
 
 func (db *DB) getPortByAlias(ctx context.Context, alias string) (*Port, error) {
 	query := `
-		SELECT p.id, p.name, p.display_name, p.region, p.added_at, p.added_by, p.notes
+		SELECT p.id, p.name, p.display_name, COALESCE(p.region, ''), COALESCE(p.region_id, 0), p.added_at, COALESCE(p.added_by, ''), COALESCE(p.notes, '')
 		FROM ports p
 		JOIN port_aliases a ON p.id = a.port_id
 		WHERE a.alias = ? COLLATE NOCASE
 	`
 	var port Port
-	err := db.conn.QueryRowContext(ctx, query, alias).Scan(
-		&port.ID, &port.Name, &port.DisplayName, &port.Region,
+	stmt, err := db.prepared(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	err = stmt.QueryRowContext(ctx, alias).Scan(
+		&port.ID, &port.Name, &port.DisplayName, &port.Region, &port.RegionID,
 		&port.AddedAt, &port.AddedBy, &port.Notes,
 	)
 	if err != nil {
@@ -430,7 +633,7 @@ func (db *DB) GetAllPorts(ctx context.Context) ([]Port, error) {
 }
 
 func (db *DB) getAllPorts(ctx context.Context) ([]Port, error) {
-	query := `SELECT id, name, display_name, region, added_at, added_by, notes FROM ports ORDER BY name`
+	query := `SELECT id, name, display_name, region, COALESCE(region_id, 0), added_at, added_by, COALESCE(notes, '') FROM ports ORDER BY name`
 	rows, err := db.conn.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -442,7 +645,7 @@ func (db *DB) getAllPorts(ctx context.Context) ([]Port, error) {
 		var port Port
 		var addedBy sql.NullString
 		var region sql.NullString
-		err := rows.Scan(&port.ID, &port.Name, &port.DisplayName, &region,
+		err := rows.Scan(&port.ID, &port.Name, &port.DisplayName, &region, &port.RegionID,
 			&port.AddedAt, &addedBy, &port.Notes)
 		if err != nil {
 			return nil, err
@@ -460,7 +663,11 @@ func (db *DB) getAllPorts(ctx context.Context) ([]Port, error) {
 
 func (db *DB) getPortAliases(ctx context.Context, portID int) ([]PortAlias, error) {
 	query := `SELECT id, port_id, alias, added_at FROM port_aliases WHERE port_id = ?`
-	rows, err := db.conn.QueryContext(ctx, query, portID)
+	stmt, err := db.prepared(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, portID)
 	if err != nil {
 		return nil, err
 	}
@@ -478,8 +685,21 @@ func (db *DB) getPortAliases(ctx context.Context, portID int) ([]PortAlias, erro
 	return aliases, rows.Err()
 }
 
-// CreateItem creates a new item
+// CreateItem creates a new item. The name is stored in canonical form (see
+// canonicalItemName); if an item with the same canonical name exists it is
+// returned instead. An archived item gives ErrItemArchived, so players
+// can't undo an admin's archive by adding it again.
 func (db *DB) CreateItem(ctx context.Context, name, displayName, addedBy string) (*Item, error) {
+	name = canonicalItemName(name)
+	if existing, err := db.getItemByName(ctx, name); err == nil {
+		if existing.Archived {
+			return nil, ErrItemArchived
+		}
+		return existing, nil
+	} else if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to check for existing item: %w", err)
+	}
+
 	query := `INSERT INTO items (name, display_name, is_tagged, added_by) VALUES (?, ?, FALSE, ?)`
 	result, err := db.conn.ExecContext(ctx, query, name, displayName, addedBy)
 	if err != nil {
@@ -501,10 +721,16 @@ func (db *DB) CreateItem(ctx context.Context, name, displayName, addedBy string)
 	}, nil
 }
 
-// CreatePort creates a new port
+// CreatePort creates a new port. A non-empty region must resolve to a known
+// region (see ResolveRegion) and is stored under its canonical name.
 func (db *DB) CreatePort(ctx context.Context, name, displayName, region, addedBy string) (*Port, error) {
-	query := `INSERT INTO ports (name, display_name, region, added_by) VALUES (?, ?, ?, ?)`
-	result, err := db.conn.ExecContext(ctx, query, name, displayName, region, addedBy)
+	regionID, regionName, err := db.resolvePortRegion(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO ports (name, display_name, region, region_id, added_by) VALUES (?, ?, ?, ?, ?)`
+	result, err := db.conn.ExecContext(ctx, query, name, displayName, regionName, regionID, addedBy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create port: %w", err)
 	}
@@ -514,12 +740,99 @@ func (db *DB) CreatePort(ctx context.Context, name, displayName, region, addedBy
 		return nil, err
 	}
 
-	return &Port{
+	port := &Port{
 		ID:          int(id),
 		Name:        name,
 		DisplayName: displayName,
-		Region:      region,
+		Region:      regionName,
 		AddedAt:     time.Now(),
 		AddedBy:     addedBy,
-	}, nil
+	}
+	if regionID != nil {
+		port.RegionID = *regionID
+	}
+	return port, nil
+}
+
+// BulkCreatePorts adds ports in one transaction. Ports whose name already
+// exists (ignoring case), including repeats within ports, are skipped. An
+// unknown region fails the whole import so nothing is half-applied.
+func (db *DB) BulkCreatePorts(ctx context.Context, ports []Port) (created, skipped int, err error) {
+	regions, err := db.GetAllRegions(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, p := range ports {
+		var regionID *int
+		regionName := ""
+		if strings.TrimSpace(p.Region) != "" {
+			region := resolveRegion(p.Region, regions)
+			if region == nil {
+				return 0, 0, fmt.Errorf("port %s: %w: %s", p.Name, ErrUnknownRegion, p.Region)
+			}
+			regionID, regionName = &region.ID, region.Name
+		}
+
+		var exists int
+		err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM ports WHERE name = ? COLLATE NOCASE`, p.Name).Scan(&exists)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to check port %s: %w", p.Name, err)
+		}
+		if exists > 0 {
+			skipped++
+			continue
+		}
+
+		displayName := p.DisplayName
+		if displayName == "" {
+			displayName = p.Name
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO ports (name, display_name, region, region_id, added_by, notes) VALUES (?, ?, ?, ?, ?, ?)`,
+			p.Name, displayName, regionName, regionID, p.AddedBy, p.Notes,
+		)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to create port %s: %w", p.Name, err)
+		}
+		created++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit port import: %w", err)
+	}
+	return created, skipped, nil
+}
+
+// UpdatePort renames a port and/or moves it to another region. Empty
+// arguments leave that field unchanged; region must resolve to a known region.
+func (db *DB) UpdatePort(ctx context.Context, portID int, newName, region string) error {
+	if newName != "" {
+		_, err := db.conn.ExecContext(ctx,
+			`UPDATE ports SET name = ?, display_name = ? WHERE id = ?`, newName, newName, portID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to rename port: %w", err)
+		}
+	}
+
+	if region != "" {
+		regionID, regionName, err := db.resolvePortRegion(ctx, region)
+		if err != nil {
+			return err
+		}
+		_, err = db.conn.ExecContext(ctx,
+			`UPDATE ports SET region = ?, region_id = ? WHERE id = ?`, regionName, regionID, portID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update port region: %w", err)
+		}
+	}
+	return nil
 }
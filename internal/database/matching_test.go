@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCalculateSimilarityTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		wantConf MatchConfidence
+	}{
+		{"reordered words", "heavy iron cannon", "iron cannon heavy", ConfidenceExact},
+		{"reordered with typo", "heavy iron cannon", "iron canon heavy", ConfidenceHigh},
+		{"quantity suffix", "cannonball x200", "cannonball", ConfidenceMedium},
+		{"extra word is not a match", "heavy cannon", "cannon", ConfidenceMedium},
+		{"unrelated", "iron ore", "silk", ConfidenceLow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := calculateSimilarity(tt.a, tt.b)
+			if got := getConfidence(score); got != tt.wantConf {
+				t.Errorf("calculateSimilarity(%q, %q) = %.2f (confidence %d), want confidence %d",
+					tt.a, tt.b, score, got, tt.wantConf)
+			}
+			if reverse := calculateSimilarity(tt.b, tt.a); reverse != score {
+				t.Errorf("expected a symmetric score, got %.2f and %.2f", score, reverse)
+			}
+		})
+	}
+}
+
+func TestCalculateSimilarityNeverWorseThanLevenshtein(t *testing.T) {
+	pairs := [][2]string{
+		{"cannonball x200", "cannonball"},
+		{"iron ore", "iron ore s"},
+		{"bronze cannon", "brass cannon"},
+	}
+	for _, p := range pairs {
+		if got, lev := calculateSimilarity(p[0], p[1]), levenshteinSimilarity(p[0], p[1]); got < lev {
+			t.Errorf("%q vs %q: got %.2f, below Levenshtein %.2f", p[0], p[1], got, lev)
+		}
+	}
+}
+
+func TestFindItemMatchesReorderedName(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mustCreateItem(t, db, "Heavy Iron Cannon")
+	mustCreateItem(t, db, "Iron Ore")
+
+	matches, err := db.FindItemMatches(context.Background(), "Iron Cannon Heavy", 5)
+	if err != nil {
+		t.Fatalf("FindItemMatches failed: %v", err)
+	}
+	if len(matches) == 0 || matches[0].Item.DisplayName != "Heavy Iron Cannon" || matches[0].Confidence < ConfidenceHigh {
+		t.Errorf("expected a confident match on Heavy Iron Cannon, got %+v", matches)
+	}
+}
+
+func TestStripOCRNoise(t *testing.T) {
+	tests := map[string]string{
+		"Cannonball (x50)":    "Cannonball",
+		"Cannonball (50)":     "Cannonball",
+		"Cannonball x200":     "Cannonball",
+		"Cannonball 200x":     "Cannonball",
+		"Iron Ore 1,250g":     "Iron Ore",
+		"Iron Ore 1,250 gold": "Iron Ore",
+		"Iron Ore 1,250":      "Iron Ore",
+		"$ Silk":              "Silk",
+		"Silk (x5) 300g":      "Silk",
+		"Carronade 18":        "Carronade 18", // plain numbers may be part of the name
+		"Heavy Iron Cannon":   "Heavy Iron Cannon",
+		"x50":                 "x50", // nothing left to match on
+		"  Rope  ":            "Rope",
+	}
+	for in, want := range tests {
+		if got := stripOCRNoise(in); got != want {
+			t.Errorf("stripOCRNoise(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFindItemMatchesIgnoresOCRNoise(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mustCreateItem(t, db, "Cannonball")
+
+	matches, err := db.FindItemMatches(context.Background(), "Cannonball (x50)", 5)
+	if err != nil {
+		t.Fatalf("FindItemMatches failed: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Item.DisplayName != "Cannonball" || matches[0].Confidence != ConfidenceExact {
+		t.Errorf("expected an exact match on Cannonball, got %+v", matches)
+	}
+}
+
+func TestFindLikelyDuplicates(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	original := mustCreateItem(t, db, "Heavy Iron Cannon")
+	dup := mustCreateItem(t, db, "Heavy Iron Canon")
+	mustCreateItem(t, db, "Oak Planks")
+
+	pairs, err := db.FindLikelyDuplicates(context.Background(), 0.85)
+	if err != nil {
+		t.Fatalf("FindLikelyDuplicates failed: %v", err)
+	}
+	if len(pairs) != 1 {
+		t.Fatalf("expected 1 pair, got %d: %+v", len(pairs), pairs)
+	}
+	if pairs[0].Keep.ID != original.ID || pairs[0].Duplicate.ID != dup.ID {
+		t.Errorf("expected to keep the older item %d, got keep=%d duplicate=%d",
+			original.ID, pairs[0].Keep.ID, pairs[0].Duplicate.ID)
+	}
+
+	all, err := db.FindLikelyDuplicates(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("FindLikelyDuplicates failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected every pair at threshold 0, got %d", len(all))
+	}
+	for idx := 1; idx < len(all); idx++ {
+		if all[idx].Score > all[idx-1].Score {
+			t.Errorf("pairs not sorted by score: %v then %v", all[idx-1].Score, all[idx].Score)
+		}
+	}
+}
+
+func TestCanonicalItemName(t *testing.T) {
+	tests := map[string]string{
+		"Rum":             "rum",
+		"  Heavy   Rum ":  "heavy rum",
+		"Crème Brûlée":    "creme brulee",
+		"ÉPÉE":            "epee",
+		"Smoked Fish (L)": "smoked fish (l)",
+	}
+	for in, want := range tests {
+		if got := canonicalItemName(in); got != want {
+			t.Errorf("canonicalItemName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCreateItemReturnsExistingVariant(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	rum := mustCreateItem(t, db, "Rum")
+	if rum.Name != "rum" || rum.DisplayName != "Rum" {
+		t.Errorf("expected canonical name and typed display name, got %q / %q", rum.Name, rum.DisplayName)
+	}
+
+	for _, variant := range []string{"rum", "RUM", " Rúm "} {
+		item, err := db.CreateItem(ctx, variant, variant, "test")
+		if err != nil {
+			t.Fatalf("CreateItem(%q) failed: %v", variant, err)
+		}
+		if item.ID != rum.ID {
+			t.Errorf("CreateItem(%q) created item %d instead of returning %d", variant, item.ID, rum.ID)
+		}
+	}
+
+	if found, err := db.GetItemByName(ctx, "RÚM"); err != nil || found.ID != rum.ID {
+		t.Errorf("GetItemByName should fold case and accents, got %v, %v", found, err)
+	}
+
+	// The index backs this up even if a caller skips CreateItem
+	if _, err := db.conn.ExecContext(ctx,
+		`INSERT INTO items (name, display_name) VALUES ('RUM', 'RUM')`); err == nil {
+		t.Error("expected the NOCASE index to reject a case variant")
+	}
+}
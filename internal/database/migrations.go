@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migration is one step of schema evolution. Steps run in version order,
+// each inside its own transaction, and are recorded in schema_migrations so
+// they are applied exactly once per database.
+type migration struct {
+	version int
+	name    string
+	up      func(ctx context.Context, tx *sql.Tx) error
+}
+
+// migrations is the ordered schema history. Append new steps with the next
+// version number; never edit or reorder a step that has shipped.
+var migrations = []migration{
+	{1, "initial schema", migrateInitialSchema},
+	{2, "link ports to regions", backfillRegions},
+	{3, "item price bounds", createItemPriceBounds},
+	{4, "case-insensitive item names", canonicalizeItemNames},
+	{5, "item archival", addItemArchived},
+	{6, "submission timeout setting", addSubmissionTimeoutSetting},
+	{7, "report autoban setting", addReportAutobanSetting},
+	{8, "order interest", createOrderInterest},
+	{9, "max active orders setting", addMaxActiveOrdersSetting},
+	{10, "order expiry reminders", addOrderReminders},
+	{11, "scheduled orders", addOrderActivation},
+	{12, "word filter", createWordFilter},
+}
+
+const migrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+// latestSchemaVersion is the version a fully migrated database reports
+func latestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// migrate brings the database up to the latest schema version. A database
+// written by a newer build is refused rather than run against stale queries.
+func migrate(ctx context.Context, conn *sql.DB) error {
+	if _, err := conn.ExecContext(ctx, migrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	current, err := schemaVersion(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if latest := latestSchemaVersion(); current > latest {
+		return fmt.Errorf("database schema version %d is newer than this build supports (%d)", current, latest)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(ctx, conn, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigration runs a single step and records it in one transaction
+func applyMigration(ctx context.Context, conn *sql.DB, m migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d: %w", m.version, err)
+	}
+	defer tx.Rollback()
+
+	if err := m.up(ctx, tx); err != nil {
+		return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, name) VALUES (?, ?)`, m.version, m.name,
+	); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+	}
+	return tx.Commit()
+}
+
+// schemaVersion returns the highest applied migration, 0 for a new database
+func schemaVersion(ctx context.Context, conn *sql.DB) (int, error) {
+	var version int
+	err := conn.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// migrateInitialSchema creates every table as of the first versioned release.
+// Databases from before versioning already have some of these tables, so the
+// statements are idempotent and missing columns are added afterwards.
+func migrateInitialSchema(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("failed to create tables: %w", err)
+	}
+	return addMissingColumns(ctx, tx)
+}
+
+// addMissingColumns applies legacyColumns to tables that predate them
+func addMissingColumns(ctx context.Context, tx *sql.Tx) error {
+	for _, c := range legacyColumns {
+		exists, err := columnExists(ctx, tx, c.table, c.column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", c.table, c.column, err)
+		}
+	}
+	return nil
+}
+
+// columnExists checks whether a table has the given column
+func columnExists(ctx context.Context, tx *sql.Tx, table, column string) (bool, error) {
+	var count int
+	err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	return count > 0, nil
+}
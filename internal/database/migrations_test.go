@@ -0,0 +1,228 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func tempDBPath(t *testing.T) string {
+	t.Helper()
+	tmpfile, err := os.CreateTemp("", "test-*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp db: %v", err)
+	}
+	tmpfile.Close()
+	t.Cleanup(func() { os.Remove(tmpfile.Name()) })
+	return tmpfile.Name()
+}
+
+func countMigrations(t *testing.T, db *DB) int {
+	t.Helper()
+	var count int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&count); err != nil {
+		t.Fatalf("failed to count migrations: %v", err)
+	}
+	return count
+}
+
+func TestMigrationsAreOrdered(t *testing.T) {
+	for idx, m := range migrations {
+		if m.version != idx+1 {
+			t.Errorf("migration %q has version %d, expected %d", m.name, m.version, idx+1)
+		}
+	}
+}
+
+func TestMigrateFreshDatabase(t *testing.T) {
+	path := tempDBPath(t)
+	ctx := context.Background()
+
+	db, err := New(path)
+	if err != nil {
+		t.Fatalf("failed to initialize database: %v", err)
+	}
+	version, err := schemaVersion(ctx, db.conn)
+	if err != nil {
+		t.Fatalf("schemaVersion failed: %v", err)
+	}
+	if version != latestSchemaVersion() {
+		t.Errorf("expected version %d, got %d", latestSchemaVersion(), version)
+	}
+	db.Close()
+
+	// Reopening must not re-apply anything
+	db, err = New(path)
+	if err != nil {
+		t.Fatalf("failed to reopen database: %v", err)
+	}
+	defer db.Close()
+	if got := countMigrations(t, db); got != len(migrations) {
+		t.Errorf("expected %d recorded migrations, got %d", len(migrations), got)
+	}
+}
+
+func TestMigrateLegacyDatabase(t *testing.T) {
+	path := tempDBPath(t)
+
+	// A markets table as the earliest releases created it
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	_, err = conn.Exec(`
+		CREATE TABLE markets (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			port_id INTEGER NOT NULL,
+			item_id INTEGER NOT NULL,
+			order_type TEXT NOT NULL,
+			price INTEGER NOT NULL,
+			quantity INTEGER NOT NULL,
+			submitted_by TEXT NOT NULL,
+			submitted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMP NOT NULL,
+			screenshot_hash TEXT NOT NULL
+		);
+		INSERT INTO markets (port_id, item_id, order_type, price, quantity, submitted_by, expires_at, screenshot_hash)
+		VALUES (1, 1, 'buy', 100, 5, 'user123', datetime('now', '+1 day'), 'hash');
+	`)
+	conn.Close()
+	if err != nil {
+		t.Fatalf("failed to create legacy table: %v", err)
+	}
+
+	db, err := New(path)
+	if err != nil {
+		t.Fatalf("failed to migrate legacy database: %v", err)
+	}
+	defer db.Close()
+
+	var price int
+	var phash sql.NullString
+	err = db.conn.QueryRow(`SELECT price, screenshot_phash FROM markets`).Scan(&price, &phash)
+	if err != nil {
+		t.Fatalf("expected upgraded markets table: %v", err)
+	}
+	if price != 100 || phash.Valid {
+		t.Errorf("expected existing row kept with empty phash, got price %d phash %v", price, phash)
+	}
+}
+
+func TestMigrateRejectsNewerSchema(t *testing.T) {
+	path := tempDBPath(t)
+
+	db, err := New(path)
+	if err != nil {
+		t.Fatalf("failed to initialize database: %v", err)
+	}
+	_, err = db.conn.Exec(`INSERT INTO schema_migrations (version, name) VALUES (?, 'from the future')`,
+		latestSchemaVersion()+1)
+	db.Close()
+	if err != nil {
+		t.Fatalf("failed to record future migration: %v", err)
+	}
+
+	if db, err := New(path); err == nil {
+		db.Close()
+		t.Fatal("expected a newer schema version to be refused")
+	}
+}
+
+func TestApplyMigrationRollsBackOnFailure(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	failing := migration{
+		version: latestSchemaVersion() + 1,
+		name:    "half done",
+		up: func(ctx context.Context, tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, `CREATE TABLE half_done (id INTEGER)`); err != nil {
+				return err
+			}
+			return errors.New("boom")
+		},
+	}
+
+	if err := applyMigration(ctx, db.conn, failing); err == nil {
+		t.Fatal("expected failing migration to return an error")
+	}
+
+	exists, err := tableExists(db, "half_done")
+	if err != nil {
+		t.Fatalf("failed to inspect tables: %v", err)
+	}
+	if exists {
+		t.Error("expected the failed migration's table to be rolled back")
+	}
+	version, err := schemaVersion(ctx, db.conn)
+	if err != nil {
+		t.Fatalf("schemaVersion failed: %v", err)
+	}
+	if version != latestSchemaVersion() {
+		t.Errorf("expected version to stay at %d, got %d", latestSchemaVersion(), version)
+	}
+}
+
+func tableExists(db *DB, name string) (bool, error) {
+	var count int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&count)
+	return count > 0, err
+}
+
+func TestCanonicalizeItemNamesMigration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Names as they were stored before migration 4
+	if _, err := db.conn.ExecContext(ctx, `DROP INDEX idx_items_name_nocase`); err != nil {
+		t.Fatalf("failed to drop index: %v", err)
+	}
+	for _, name := range []string{"Rum", "Café Beans", "rum", "Oak"} {
+		if _, err := db.conn.ExecContext(ctx,
+			`INSERT INTO items (name, display_name) VALUES (?, ?)`, name, name); err != nil {
+			t.Fatalf("failed to insert %s: %v", name, err)
+		}
+	}
+
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin: %v", err)
+	}
+	if err := canonicalizeItemNames(ctx, tx); err != nil {
+		tx.Rollback()
+		t.Fatalf("canonicalizeItemNames failed: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	rows, err := db.conn.QueryContext(ctx, `SELECT id, name FROM items ORDER BY id`)
+	if err != nil {
+		t.Fatalf("failed to read items: %v", err)
+	}
+	defer rows.Close()
+	var names []string
+	var ids []int
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		ids = append(ids, id)
+		names = append(names, name)
+	}
+
+	// "rum" was already canonical, so the older "Rum" is the one set aside
+	want := []string{fmt.Sprintf("rum (%d)", ids[0]), "cafe beans", "rum", "oak"}
+	for idx := range want {
+		if names[idx] != want[idx] {
+			t.Errorf("item %d: expected %q, got %q", ids[idx], want[idx], names[idx])
+		}
+	}
+}
@@ -3,22 +3,67 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // ReplacePortOrders replaces all orders for a given port and order type
-// This is atomic - deletes old orders and inserts new ones in a transaction
-func (db *DB) ReplacePortOrders(ctx context.Context, portID int, orderType string, orders []Market, submittedBy, screenshotHash string) error {
+// This is atomic - deletes old orders and inserts new ones in a transaction.
+// Orders without an ExpiresAt expire 7 days from now; an expiry that is
+// already past is rejected before anything is deleted.
+func (db *DB) ReplacePortOrders(ctx context.Context, portID int, orderType string, orders []Market, submittedBy, screenshotHash, screenshotPHash string) error {
+	deleteQuery := `DELETE FROM markets WHERE port_id = ? AND order_type = ?`
+	return db.writePortOrders(ctx, "replace_orders", deleteQuery, []interface{}{portID, orderType},
+		portID, orderType, orders, submittedBy, screenshotHash, screenshotPHash)
+}
+
+// MergePortOrders upserts orders by item: only items in orders have their
+// previous orders of this type replaced, everything else at the port is kept.
+// This lets several screenshots of one board be submitted separately.
+func (db *DB) MergePortOrders(ctx context.Context, portID int, orderType string, orders []Market, submittedBy, screenshotHash, screenshotPHash string) error {
+	args := []interface{}{portID, orderType}
+	seen := make(map[int]bool)
+	for _, order := range orders {
+		if !seen[order.ItemID] {
+			seen[order.ItemID] = true
+			args = append(args, order.ItemID)
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+
+	deleteQuery := `DELETE FROM markets WHERE port_id = ? AND order_type = ? AND item_id IN (?` + repeatPlaceholders(len(seen)-1) + `)`
+	return db.writePortOrders(ctx, "merge_orders", deleteQuery, args,
+		portID, orderType, orders, submittedBy, screenshotHash, screenshotPHash)
+}
+
+// writePortOrders runs deleteQuery and inserts orders in one transaction,
+// logging the result under action
+func (db *DB) writePortOrders(ctx context.Context, action, deleteQuery string, deleteArgs []interface{}, portID int, orderType string, orders []Market, submittedBy, screenshotHash, screenshotPHash string) error {
+	defaultExpiry := time.Now().AddDate(0, 0, 7) // 7 days from now
+	for _, order := range orders {
+		if order.ExpiresAt.IsZero() {
+			continue
+		}
+		if err := checkExpiry(order.ExpiresAt); err != nil {
+			return fmt.Errorf("invalid expiry for item_id %d: %w", order.ItemID, err)
+		}
+	}
+
 	tx, err := db.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	// Delete existing orders for this port and order type
-	deleteQuery := `DELETE FROM markets WHERE port_id = ? AND order_type = ?`
-	result, err := tx.ExecContext(ctx, deleteQuery, portID, orderType)
+	// Delete the existing orders being replaced
+	result, err := tx.ExecContext(ctx, deleteQuery, deleteArgs...)
 	if err != nil {
 		return fmt.Errorf("failed to delete old orders: %w", err)
 	}
@@ -27,13 +72,16 @@ func (db *DB) ReplacePortOrders(ctx context.Context, portID int, orderType strin
 
 	// Insert new orders
 	insertQuery := `
-		INSERT INTO markets (port_id, item_id, order_type, price, quantity, submitted_by, expires_at, screenshot_hash)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO markets (port_id, item_id, order_type, price, quantity, submitted_by, expires_at, screenshot_hash, screenshot_phash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	expiresAt := time.Now().AddDate(0, 0, 7) // 7 days from now
-
 	for _, order := range orders {
+		expiresAt := order.ExpiresAt
+		if expiresAt.IsZero() {
+			expiresAt = defaultExpiry
+		}
+
 		_, err := tx.ExecContext(ctx, insertQuery,
 			portID,
 			order.ItemID,
@@ -41,8 +89,9 @@ func (db *DB) ReplacePortOrders(ctx context.Context, portID int, orderType strin
 			order.Price,
 			order.Quantity,
 			submittedBy,
-			expiresAt,
+			expiresAt.UTC(),
 			screenshotHash,
+			sql.NullString{String: screenshotPHash, Valid: screenshotPHash != ""},
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert order for item_id %d: %w", order.ItemID, err)
@@ -57,7 +106,7 @@ func (db *DB) ReplacePortOrders(ctx context.Context, portID int, orderType strin
 	details := fmt.Sprintf(`{"port_id":%d,"order_type":"%s","deleted":%d,"inserted":%d}`,
 		portID, orderType, rowsDeleted, len(orders))
 
-	_, err = tx.ExecContext(ctx, auditQuery, "replace_orders", submittedBy, details)
+	_, err = tx.ExecContext(ctx, auditQuery, action, submittedBy, details)
 	if err != nil {
 		return fmt.Errorf("failed to log action: %w", err)
 	}
@@ -69,13 +118,46 @@ func (db *DB) ReplacePortOrders(ctx context.Context, portID int, orderType strin
 	return nil
 }
 
-// GetPricesByItem returns best buy and sell prices for an item across all ports
-func (db *DB) GetPricesByItem(ctx context.Context, itemID int, tagIDs []int, region string, minPrice, maxPrice int) ([]Market, error) {
+// pricesPerSide caps how many orders of each type GetPricesByItem returns
+const pricesPerSide = 10
+
+// GetPricesByItem returns best buy and sell prices for an item across all ports,
+// best first on each side: highest buy offers, then cheapest sell offers.
+// maxAgeHours > 0 drops orders submitted longer ago than that.
+func (db *DB) GetPricesByItem(ctx context.Context, itemID int, tagIDs []int, regionID int, minPrice, maxPrice, maxAgeHours int) ([]Market, error) {
+	query, args := pricesByItemQuery(itemID, regionID, minPrice, maxPrice, maxAgeHours)
+
+	stmt, err := db.prepared(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prices: %w", err)
+	}
+	defer rows.Close()
+
+	return scanMarketsWithJoins(rows)
+}
+
+// pricesByItemQuery builds GetPricesByItem's SQL and arguments for the
+// filters in use
+func pricesByItemQuery(itemID, regionID, minPrice, maxPrice, maxAgeHours int) (string, []interface{}) {
+	// Each side is ranked separately so a long list of buy orders can't
+	// push the sell side out of the result
 	query := `
+		SELECT id, port_id, item_id, order_type, price, quantity,
+		       submitted_by, submitted_at, expires_at, screenshot_hash,
+		       port_name, port_display, region, item_name, item_display
+		FROM (
 		SELECT m.id, m.port_id, m.item_id, m.order_type, m.price, m.quantity,
 		       m.submitted_by, m.submitted_at, m.expires_at, m.screenshot_hash,
 		       p.name as port_name, p.display_name as port_display, p.region,
-		       i.name as item_name, i.display_name as item_display
+		       i.name as item_name, i.display_name as item_display,
+		       ROW_NUMBER() OVER (
+		           PARTITION BY m.order_type
+		           ORDER BY CASE WHEN m.order_type = 'buy' THEN -m.price ELSE m.price END, m.submitted_at DESC
+		       ) AS side_rank
 		FROM markets m
 		JOIN ports p ON m.port_id = p.id
 		JOIN items i ON m.item_id = i.id
@@ -85,9 +167,9 @@ func (db *DB) GetPricesByItem(ctx context.Context, itemID int, tagIDs []int, reg
 	args := []interface{}{itemID}
 
 	// Add region filter
-	if region != "" {
-		query += ` AND p.region = ?`
-		args = append(args, region)
+	if regionID != 0 {
+		query += ` AND p.region_id = ?`
+		args = append(args, regionID)
 	}
 
 	// Add price range filter
@@ -100,19 +182,26 @@ func (db *DB) GetPricesByItem(ctx context.Context, itemID int, tagIDs []int, reg
 		args = append(args, maxPrice)
 	}
 
-	query += ` ORDER BY m.order_type, m.price ASC LIMIT 20`
-
-	rows, err := db.conn.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query prices: %w", err)
+	// Add freshness filter
+	if maxAgeHours > 0 {
+		query += ` AND m.submitted_at > datetime('now', ?)`
+		args = append(args, fmt.Sprintf("-%d hours", maxAgeHours))
 	}
-	defer rows.Close()
 
-	return scanMarketsWithJoins(rows)
+	query += `) WHERE side_rank <= ? ORDER BY order_type, side_rank`
+	args = append(args, pricesPerSide)
+
+	return query, args
 }
 
 // GetOrdersByPort returns all active orders for a specific port
 func (db *DB) GetOrdersByPort(ctx context.Context, portID int) ([]Market, error) {
+	return db.GetOrdersByPortFiltered(ctx, portID, "", "")
+}
+
+// GetOrdersByPortFiltered returns a port's active orders of one type ("" for
+// both) whose item display name contains itemFilter, ignoring case ("" for all)
+func (db *DB) GetOrdersByPortFiltered(ctx context.Context, portID int, orderType, itemFilter string) ([]Market, error) {
 	query := `
 		SELECT m.id, m.port_id, m.item_id, m.order_type, m.price, m.quantity,
 		       m.submitted_by, m.submitted_at, m.expires_at, m.screenshot_hash,
@@ -122,10 +211,23 @@ func (db *DB) GetOrdersByPort(ctx context.Context, portID int) ([]Market, error)
 		JOIN ports p ON m.port_id = p.id
 		JOIN items i ON m.item_id = i.id
 		WHERE m.port_id = ? AND m.expires_at > datetime('now')
-		ORDER BY m.order_type, i.name ASC
 	`
+	args := []interface{}{portID}
+	if orderType != "" {
+		query += ` AND m.order_type = ?`
+		args = append(args, orderType)
+	}
+	if itemFilter != "" {
+		query += ` AND instr(lower(i.display_name), lower(?)) > 0`
+		args = append(args, itemFilter)
+	}
+	query += ` ORDER BY m.order_type, i.name ASC`
 
-	rows, err := db.conn.QueryContext(ctx, query, portID)
+	stmt, err := db.prepared(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query port orders: %w", err)
 	}
@@ -134,34 +236,75 @@ func (db *DB) GetOrdersByPort(ctx context.Context, portID int) ([]Market, error)
 	return scanMarketsWithJoins(rows)
 }
 
-// GetOrdersByTags returns orders for items with specified tags
-func (db *DB) GetOrdersByTags(ctx context.Context, tagIDs []int, region string) ([]Market, error) {
+// ExportMarkets returns every active order with port and item names, grouped
+// by port for spreadsheet exports
+func (db *DB) ExportMarkets(ctx context.Context) ([]Market, error) {
+	query := `
+		SELECT m.id, m.port_id, m.item_id, m.order_type, m.price, m.quantity,
+		       m.submitted_by, m.submitted_at, m.expires_at, m.screenshot_hash,
+		       p.name as port_name, p.display_name as port_display, COALESCE(p.region, ''),
+		       i.name as item_name, i.display_name as item_display
+		FROM markets m
+		JOIN ports p ON m.port_id = p.id
+		JOIN items i ON m.item_id = i.id
+		WHERE m.expires_at > datetime('now')
+		ORDER BY p.display_name, m.order_type, i.display_name
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export markets: %w", err)
+	}
+	defer rows.Close()
+
+	return scanMarketsWithJoins(rows)
+}
+
+// GetOrdersByTags returns orders for items with specified tags. With all
+// false an item matches if it has any of the tags; with all true it must
+// have every one of them.
+func (db *DB) GetOrdersByTags(ctx context.Context, tagIDs []int, regionID int, all bool) ([]Market, error) {
 	if len(tagIDs) == 0 {
 		return nil, fmt.Errorf("no tags specified")
 	}
 
-	// Build query with tag filters
+	// Duplicates would make the AND count unreachable
+	seen := make(map[int]bool)
+	var args []interface{}
+	for _, id := range tagIDs {
+		if !seen[id] {
+			seen[id] = true
+			args = append(args, id)
+		}
+	}
+
+	// Items qualify by how many of the requested tags they carry
+	required := 1
+	if all {
+		required = len(args)
+	}
+
 	query := `
-		SELECT DISTINCT m.id, m.port_id, m.item_id, m.order_type, m.price, m.quantity,
+		SELECT m.id, m.port_id, m.item_id, m.order_type, m.price, m.quantity,
 		       m.submitted_by, m.submitted_at, m.expires_at, m.screenshot_hash,
 		       p.name as port_name, p.display_name as port_display, p.region,
 		       i.name as item_name, i.display_name as item_display
 		FROM markets m
 		JOIN ports p ON m.port_id = p.id
 		JOIN items i ON m.item_id = i.id
-		JOIN item_tags it ON i.id = it.item_id
-		WHERE it.tag_id IN (?` + repeatPlaceholders(len(tagIDs)-1) + `)
+		WHERE m.item_id IN (
+			SELECT item_id FROM item_tags
+			WHERE tag_id IN (?` + repeatPlaceholders(len(args)-1) + `)
+			GROUP BY item_id
+			HAVING COUNT(DISTINCT tag_id) >= ?
+		)
 		  AND m.expires_at > datetime('now')
 	`
+	args = append(args, required)
 
-	args := make([]interface{}, len(tagIDs))
-	for i, id := range tagIDs {
-		args[i] = id
-	}
-
-	if region != "" {
-		query += ` AND p.region = ?`
-		args = append(args, region)
+	if regionID != 0 {
+		query += ` AND p.region_id = ?`
+		args = append(args, regionID)
 	}
 
 	query += ` ORDER BY m.order_type, m.price ASC LIMIT 50`
@@ -175,6 +318,89 @@ func (db *DB) GetOrdersByTags(ctx context.Context, tagIDs []int, region string)
 	return scanMarketsWithJoins(rows)
 }
 
+// HasRecentSubmissionWithHash reports whether a screenshot with the given hash
+// was submitted within the given window
+func (db *DB) HasRecentSubmissionWithHash(ctx context.Context, hash string, within time.Duration) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM markets
+			WHERE screenshot_hash = ?
+			  AND submitted_at > datetime('now', ?)
+		)
+	`
+	modifier := fmt.Sprintf("-%d seconds", int64(within.Seconds()))
+
+	var exists bool
+	if err := db.conn.QueryRowContext(ctx, query, hash, modifier).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check screenshot hash: %w", err)
+	}
+	return exists, nil
+}
+
+// SimilarScreenshot is a recent submission whose perceptual hash is close to another
+type SimilarScreenshot struct {
+	ScreenshotHash string
+	PHash          string
+	PortID         int
+	PortName       string
+	OrderType      string
+	SubmittedBy    string
+	SubmittedAt    time.Time
+	Distance       int
+}
+
+// FindSimilarScreenshots returns active submissions whose perceptual hash is
+// within maxDistance bits of phash, closest first
+func (db *DB) FindSimilarScreenshots(ctx context.Context, phash string, maxDistance int) ([]SimilarScreenshot, error) {
+	target, err := strconv.ParseUint(phash, 16, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid perceptual hash %q: %w", phash, err)
+	}
+
+	query := `
+		SELECT m.screenshot_hash, m.screenshot_phash, m.port_id, p.display_name,
+		       m.order_type, m.submitted_by, m.submitted_at
+		FROM markets m
+		JOIN ports p ON m.port_id = p.id
+		WHERE m.screenshot_phash IS NOT NULL
+		  AND m.expires_at > datetime('now')
+		GROUP BY m.screenshot_hash
+	`
+	rows, err := db.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query screenshot hashes: %w", err)
+	}
+	defer rows.Close()
+
+	var similar []SimilarScreenshot
+	for rows.Next() {
+		var sc SimilarScreenshot
+		err := rows.Scan(&sc.ScreenshotHash, &sc.PHash, &sc.PortID, &sc.PortName,
+			&sc.OrderType, &sc.SubmittedBy, &sc.SubmittedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan screenshot hash: %w", err)
+		}
+
+		candidate, err := strconv.ParseUint(sc.PHash, 16, 64)
+		if err != nil {
+			continue
+		}
+		sc.Distance = bits.OnesCount64(target ^ candidate)
+		if sc.Distance <= maxDistance {
+			similar = append(similar, sc)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(similar, func(a, b int) bool {
+		return similar[a].Distance < similar[b].Distance
+	})
+
+	return similar, nil
+}
+
 // DeleteExpiredOrders removes all orders past their expiry date
 func (db *DB) DeleteExpiredOrders(ctx context.Context) (int64, error) {
 	query := `DELETE FROM markets WHERE expires_at <= datetime('now')`
@@ -202,6 +428,16 @@ func (db *DB) DeleteExpiredOrders(ctx context.Context) (int64, error) {
 	return rowsDeleted, nil
 }
 
+// CountOrdersByPort returns how many orders, active or expired, PurgePort would delete
+func (db *DB) CountOrdersByPort(ctx context.Context, portID int) (int, error) {
+	var count int
+	err := db.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM markets WHERE port_id = ?`, portID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count port orders: %w", err)
+	}
+	return count, nil
+}
+
 // PurgePort removes all orders for a specific port
 func (db *DB) PurgePort(ctx context.Context, portID int, adminUserID string) (int64, error) {
 	query := `DELETE FROM markets WHERE port_id = ?`
@@ -227,13 +463,34 @@ func (db *DB) PurgePort(ctx context.Context, portID int, adminUserID string) (in
 	return rowsDeleted, nil
 }
 
+// StaleMarketDays is how old a port's newest order can get before the port
+// counts as stale in stats
+const StaleMarketDays = 3
+
 // GetStats returns bot statistics
 func (db *DB) GetStats(ctx context.Context) (map[string]interface{}, error) {
+	return db.GetRegionStats(ctx, 0)
+}
+
+// GetRegionStats returns bot statistics for one region, or for everything
+// when regionID is 0. A region only has market and port figures; item, trade
+// and submission counts are left out because they aren't tied to a port.
+func (db *DB) GetRegionStats(ctx context.Context, regionID int) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
+	// Markets are joined to their port so the same queries serve a region
+	marketFilter := `FROM markets m JOIN ports p ON m.port_id = p.id WHERE 1=1`
+	portFilter := `FROM ports p WHERE 1=1`
+	var args []interface{}
+	if regionID != 0 {
+		marketFilter += ` AND p.region_id = ?`
+		portFilter += ` AND p.region_id = ?`
+		args = append(args, regionID)
+	}
+
 	// Total active orders
 	var totalOrders int
-	err := db.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM markets WHERE expires_at > datetime('now')`).Scan(&totalOrders)
+	err := db.conn.QueryRowContext(ctx, `SELECT COUNT(*) `+marketFilter+` AND m.expires_at > datetime('now')`, args...).Scan(&totalOrders)
 	if err != nil {
 		return nil, err
 	}
@@ -241,15 +498,50 @@ func (db *DB) GetStats(ctx context.Context) (map[string]interface{}, error) {
 
 	// Unique ports
 	var uniquePorts int
-	err = db.conn.QueryRowContext(ctx, `SELECT COUNT(DISTINCT port_id) FROM markets WHERE expires_at > datetime('now')`).Scan(&uniquePorts)
+	err = db.conn.QueryRowContext(ctx, `SELECT COUNT(DISTINCT m.port_id) `+marketFilter+` AND m.expires_at > datetime('now')`, args...).Scan(&uniquePorts)
 	if err != nil {
 		return nil, err
 	}
 	stats["unique_ports"] = uniquePorts
 
+	// Total ports
+	var totalPorts int
+	err = db.conn.QueryRowContext(ctx, `SELECT COUNT(*) `+portFilter, args...).Scan(&totalPorts)
+	if err != nil {
+		return nil, err
+	}
+	stats["total_ports"] = totalPorts
+
+	// Last update (selected as a column rather than MAX() so the driver parses the timestamp)
+	var lastUpdate time.Time
+	err = db.conn.QueryRowContext(ctx, `SELECT m.submitted_at `+marketFilter+` ORDER BY m.submitted_at DESC LIMIT 1`, args...).Scan(&lastUpdate)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err == nil {
+		stats["last_update"] = lastUpdate
+	}
+
+	// Stale ports: still listed, but nobody has submitted them in a while
+	var stalePorts int
+	err = db.conn.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT m.port_id `+marketFilter+`
+			GROUP BY m.port_id
+			HAVING MAX(m.submitted_at) < datetime('now', ?)
+		)`, append(args, fmt.Sprintf("-%d days", StaleMarketDays))...).Scan(&stalePorts)
+	if err != nil {
+		return nil, err
+	}
+	stats["stale_ports"] = stalePorts
+
+	if regionID != 0 {
+		return stats, nil
+	}
+
 	// Untagged items count
 	var untaggedItems int
-	err = db.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM items WHERE is_tagged = FALSE`).Scan(&untaggedItems)
+	err = db.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM items WHERE is_tagged = FALSE AND archived = FALSE`).Scan(&untaggedItems)
 	if err != nil {
 		return nil, err
 	}
@@ -257,35 +549,25 @@ func (db *DB) GetStats(ctx context.Context) (map[string]interface{}, error) {
 
 	// Total items
 	var totalItems int
-	err = db.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM items`).Scan(&totalItems)
+	err = db.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM items WHERE archived = FALSE`).Scan(&totalItems)
 	if err != nil {
 		return nil, err
 	}
 	stats["total_items"] = totalItems
 
-	// Total ports
-	var totalPorts int
-	err = db.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM ports`).Scan(&totalPorts)
-	if err != nil {
-		return nil, err
-	}
-	stats["total_ports"] = totalPorts
-
-	// Last update
-	var lastUpdate sql.NullTime
-	err = db.conn.QueryRowContext(ctx, `SELECT MAX(submitted_at) FROM markets`).Scan(&lastUpdate)
+	// Lifetime completed player trades
+	var completedTrades int
+	err = db.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM player_orders WHERE status = 'completed'`).Scan(&completedTrades)
 	if err != nil {
 		return nil, err
 	}
-	if lastUpdate.Valid {
-		stats["last_update"] = lastUpdate.Time
-	}
+	stats["completed_trades"] = completedTrades
 
 	// Total submissions today
 	var submissionsToday int
 	err = db.conn.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM audit_log
-		WHERE action = 'replace_orders'
+		WHERE action IN ('replace_orders', 'merge_orders')
 		AND timestamp > datetime('now', '-1 day')
 	`).Scan(&submissionsToday)
 	if err != nil {
@@ -296,12 +578,52 @@ func (db *DB) GetStats(ctx context.Context) (map[string]interface{}, error) {
 	return stats, nil
 }
 
+// topContributorsLimit caps GetTopContributors
+const topContributorsLimit = 10
+
+// Contributor is a user and how many market boards they submitted
+type Contributor struct {
+	UserID      string
+	Submissions int
+}
+
+// GetTopContributors ranks users by board submissions (replaced or merged)
+// in the last sinceDays days
+func (db *DB) GetTopContributors(ctx context.Context, sinceDays int) ([]Contributor, error) {
+	query := `
+		SELECT user_id, COUNT(*) AS submissions
+		FROM audit_log
+		WHERE action IN ('replace_orders', 'merge_orders')
+		  AND timestamp > datetime('now', ?)
+		GROUP BY user_id
+		ORDER BY submissions DESC, MAX(timestamp) DESC
+		LIMIT ?
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query, fmt.Sprintf("-%d days", sinceDays), topContributorsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top contributors: %w", err)
+	}
+	defer rows.Close()
+
+	var contributors []Contributor
+	for rows.Next() {
+		var c Contributor
+		if err := rows.Scan(&c.UserID, &c.Submissions); err != nil {
+			return nil, fmt.Errorf("failed to scan contributor: %w", err)
+		}
+		contributors = append(contributors, c)
+	}
+
+	return contributors, rows.Err()
+}
+
 // GetUntaggedItems returns all items that need tagging
 func (db *DB) GetUntaggedItems(ctx context.Context, limit int) ([]Item, error) {
 	query := `
-		SELECT id, name, display_name, is_tagged, added_at, added_by, notes
+		SELECT id, name, display_name, is_tagged, added_at, COALESCE(added_by, ''), COALESCE(notes, '')
 		FROM items
-		WHERE is_tagged = FALSE
+		WHERE is_tagged = FALSE AND archived = FALSE
 		ORDER BY added_at DESC
 	`
 	if limit > 0 {
@@ -328,30 +650,67 @@ func (db *DB) GetUntaggedItems(ctx context.Context, limit int) ([]Item, error) {
 	return items, rows.Err()
 }
 
+// FindUntaggedItemsByPattern returns active untagged items whose display
+// name contains pattern, ignoring case
+func (db *DB) FindUntaggedItemsByPattern(ctx context.Context, pattern string) ([]Item, error) {
+	query := `
+		SELECT id, name, display_name, is_tagged, added_at, COALESCE(added_by, ''), COALESCE(notes, '')
+		FROM items
+		WHERE is_tagged = FALSE AND archived = FALSE
+		  AND instr(lower(display_name), lower(?)) > 0
+		ORDER BY display_name
+	`
+	rows, err := db.conn.QueryContext(ctx, query, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find untagged items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []Item
+	for rows.Next() {
+		var item Item
+		err := rows.Scan(&item.ID, &item.Name, &item.DisplayName, &item.IsTagged,
+			&item.AddedAt, &item.AddedBy, &item.Notes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
 // AddTagsToItem adds tags to an item and marks it as tagged
 func (db *DB) AddTagsToItem(ctx context.Context, itemID int, tagIDs []int) error {
+	return db.AddTagsToItems(ctx, []int{itemID}, tagIDs)
+}
+
+// AddTagsToItems adds the same tags to several items in one transaction and
+// marks them all as tagged
+func (db *DB) AddTagsToItems(ctx context.Context, itemIDs, tagIDs []int) error {
 	tx, err := db.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// Insert item_tags
-	for _, tagID := range tagIDs {
-		query := `INSERT OR IGNORE INTO item_tags (item_id, tag_id) VALUES (?, ?)`
-		_, err := tx.ExecContext(ctx, query, itemID, tagID)
+	for _, itemID := range itemIDs {
+		// Insert item_tags
+		for _, tagID := range tagIDs {
+			query := `INSERT OR IGNORE INTO item_tags (item_id, tag_id) VALUES (?, ?)`
+			_, err := tx.ExecContext(ctx, query, itemID, tagID)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Mark item as tagged
+		updateQuery := `UPDATE items SET is_tagged = TRUE WHERE id = ?`
+		_, err = tx.ExecContext(ctx, updateQuery, itemID)
 		if err != nil {
 			return err
 		}
 	}
 
-	// Mark item as tagged
-	updateQuery := `UPDATE items SET is_tagged = TRUE WHERE id = ?`
-	_, err = tx.ExecContext(ctx, updateQuery, itemID)
-	if err != nil {
-		return err
-	}
-
 	return tx.Commit()
 }
 
@@ -396,8 +755,29 @@ func (db *DB) GetItemTags(ctx context.Context, itemID int) ([]Tag, error) {
 	return tags, rows.Err()
 }
 
-// CreateTag creates a new tag
+// ErrInvalidTagColor is returned for tag colors that aren't "#RRGGBB" hex
+var ErrInvalidTagColor = errors.New("tag color must be a hex color like #FF5733")
+
+// ParseTagColor parses a tag's "#RRGGBB" color into an embed color value
+func ParseTagColor(color string) (int, error) {
+	if len(color) != 7 || color[0] != '#' {
+		return 0, fmt.Errorf("%w (got %q)", ErrInvalidTagColor, color)
+	}
+	value, err := strconv.ParseUint(color[1:], 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w (got %q)", ErrInvalidTagColor, color)
+	}
+	return int(value), nil
+}
+
+// CreateTag creates a new tag. Color is optional but must be "#RRGGBB" when set.
 func (db *DB) CreateTag(ctx context.Context, name, category, color, icon string) (*Tag, error) {
+	if color != "" {
+		if _, err := ParseTagColor(color); err != nil {
+			return nil, err
+		}
+	}
+
 	query := `INSERT INTO tags (name, category, color, icon) VALUES (?, ?, ?, ?)`
 	result, err := db.conn.ExecContext(ctx, query, name, category, color, icon)
 	if err != nil {
@@ -419,6 +799,95 @@ func (db *DB) CreateTag(ctx context.Context, name, category, color, icon string)
 	}, nil
 }
 
+// ErrTagNameTaken is returned when another tag already has a name
+var ErrTagNameTaken = errors.New("another tag already has that name")
+
+// GetTagByName finds a tag by name, ignoring case
+func (db *DB) GetTagByName(ctx context.Context, name string) (*Tag, error) {
+	var tag Tag
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT id, name, COALESCE(category, ''), COALESCE(color, ''), COALESCE(icon, ''), created_at
+		FROM tags WHERE name = ? COLLATE NOCASE
+	`, strings.TrimSpace(name)).Scan(&tag.ID, &tag.Name, &tag.Category, &tag.Color, &tag.Icon, &tag.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// RenameTag gives a tag a new name. Items keep the tag, since they link to
+// its ID rather than its name.
+func (db *DB) RenameTag(ctx context.Context, tagID int, newName string) (*Tag, error) {
+	return db.UpdateTag(ctx, tagID, &newName, nil, nil, nil)
+}
+
+// UpdateTag changes the given fields of a tag, leaving nil ones as they are.
+// The tag keeps its ID, so items tagged with it stay tagged.
+func (db *DB) UpdateTag(ctx context.Context, tagID int, name, category, color, icon *string) (*Tag, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var tag Tag
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, name, COALESCE(category, ''), COALESCE(color, ''), COALESCE(icon, ''), created_at
+		FROM tags WHERE id = ?
+	`, tagID).Scan(&tag.ID, &tag.Name, &tag.Category, &tag.Color, &tag.Icon, &tag.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tag %d not found", tagID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	if name != nil {
+		newName := strings.TrimSpace(*name)
+		if newName == "" {
+			return nil, fmt.Errorf("tag name can't be empty")
+		}
+		var taken int
+		err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM tags WHERE name = ? COLLATE NOCASE AND id != ?`, newName, tagID,
+		).Scan(&taken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check tag name: %w", err)
+		}
+		if taken > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrTagNameTaken, newName)
+		}
+		tag.Name = newName
+	}
+	if category != nil {
+		tag.Category = *category
+	}
+	if color != nil {
+		if *color != "" {
+			if _, err := ParseTagColor(*color); err != nil {
+				return nil, err
+			}
+		}
+		tag.Color = *color
+	}
+	if icon != nil {
+		tag.Icon = *icon
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE tags SET name = ?, category = ?, color = ?, icon = ? WHERE id = ?`,
+		tag.Name, tag.Category, tag.Color, tag.Icon, tagID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tag update: %w", err)
+	}
+	return &tag, nil
+}
+
 // GetAllTags returns all tags, optionally filtered by category
 func (db *DB) GetAllTags(ctx context.Context, category string) ([]Tag, error) {
 	query := `SELECT id, name, category, color, icon, created_at FROM tags`
@@ -450,6 +919,128 @@ func (db *DB) GetAllTags(ctx context.Context, category string) ([]Tag, error) {
 	return tags, rows.Err()
 }
 
+// TagItemCount is a tag with the number of items carrying it
+type TagItemCount struct {
+	Tag       Tag
+	ItemCount int
+}
+
+// GetItemCountsByTag returns every tag with its item count, ordered by category and name
+func (db *DB) GetItemCountsByTag(ctx context.Context) ([]TagItemCount, error) {
+	query := `
+		SELECT t.id, t.name, COALESCE(t.category, ''), COALESCE(t.color, ''), COALESCE(t.icon, ''), t.created_at,
+		       COUNT(CASE WHEN i.archived = FALSE THEN 1 END)
+		FROM tags t
+		LEFT JOIN item_tags it ON t.id = it.tag_id
+		LEFT JOIN items i ON i.id = it.item_id
+		GROUP BY t.id
+		ORDER BY t.category, t.name
+	`
+	rows, err := db.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count items by tag: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []TagItemCount
+	for rows.Next() {
+		var c TagItemCount
+		err := rows.Scan(&c.Tag.ID, &c.Tag.Name, &c.Tag.Category, &c.Tag.Color, &c.Tag.Icon,
+			&c.Tag.CreatedAt, &c.ItemCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// GetItemsWithTags returns active items with their tags, ordered by name.
+// tagID or category (0 or "" for none) keeps only items carrying that tag or
+// any tag in that category; each item still lists all of its tags.
+func (db *DB) GetItemsWithTags(ctx context.Context, tagID int, category string) ([]Item, error) {
+	query := `
+		SELECT i.id, i.name, i.display_name, i.is_tagged, i.added_at,
+		       COALESCE(i.added_by, ''), COALESCE(i.notes, ''),
+		       t.id, COALESCE(t.name, ''), COALESCE(t.category, ''), COALESCE(t.color, ''), COALESCE(t.icon, '')
+		FROM items i
+		LEFT JOIN item_tags it ON i.id = it.item_id
+		LEFT JOIN tags t ON t.id = it.tag_id
+		WHERE i.archived = FALSE
+	`
+	var args []interface{}
+	if tagID != 0 {
+		query += ` AND i.id IN (SELECT item_id FROM item_tags WHERE tag_id = ?)`
+		args = append(args, tagID)
+	}
+	if category != "" {
+		query += ` AND i.id IN (
+			SELECT it2.item_id FROM item_tags it2 JOIN tags t2 ON t2.id = it2.tag_id
+			WHERE t2.category = ? COLLATE NOCASE
+		)`
+		args = append(args, category)
+	}
+	query += ` ORDER BY i.display_name, i.id, t.category, t.name`
+
+	rows, err := db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items with tags: %w", err)
+	}
+	defer rows.Close()
+
+	// Rows come one per item/tag pair; fold them into one item each
+	var items []Item
+	for rows.Next() {
+		var item Item
+		var tagID sql.NullInt64
+		var tag Tag
+		err := rows.Scan(&item.ID, &item.Name, &item.DisplayName, &item.IsTagged,
+			&item.AddedAt, &item.AddedBy, &item.Notes,
+			&tagID, &tag.Name, &tag.Category, &tag.Color, &tag.Icon)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan item: %w", err)
+		}
+		if len(items) == 0 || items[len(items)-1].ID != item.ID {
+			items = append(items, item)
+		}
+		if tagID.Valid {
+			tag.ID = int(tagID.Int64)
+			last := &items[len(items)-1]
+			last.Tags = append(last.Tags, tag)
+		}
+	}
+	return items, rows.Err()
+}
+
+// GetItemsByTag returns all items carrying a tag, ordered by name
+func (db *DB) GetItemsByTag(ctx context.Context, tagID int) ([]Item, error) {
+	query := `
+		SELECT i.id, i.name, i.display_name, i.is_tagged, i.added_at,
+		       COALESCE(i.added_by, ''), COALESCE(i.notes, '')
+		FROM items i
+		JOIN item_tags it ON i.id = it.item_id
+		WHERE it.tag_id = ? AND i.archived = FALSE
+		ORDER BY i.display_name
+	`
+	rows, err := db.conn.QueryContext(ctx, query, tagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items by tag: %w", err)
+	}
+	defer rows.Close()
+
+	var items []Item
+	for rows.Next() {
+		var item Item
+		err := rows.Scan(&item.ID, &item.Name, &item.DisplayName, &item.IsTagged,
+			&item.AddedAt, &item.AddedBy, &item.Notes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
 // Helper functions
 
 func scanMarketsWithJoins(rows *sql.Rows) ([]Market, error) {
@@ -505,25 +1096,43 @@ func repeatPlaceholders(count int) string {
 type GuildSettings struct {
 	GuildID       string
 	AdminRoleID   string
+	TradePreview  bool
+	LogChannelID  string
 	ConfiguredAt  time.Time
 	ConfiguredBy  string
 	UpdatedAt     time.Time
+
+	// SubmissionTimeoutMinutes is how long /submit confirmations stay open;
+	// 0 means the bot's default
+	SubmissionTimeoutMinutes int
+	// ReportAutobanThreshold is how many users must report someone before
+	// they're banned automatically; 0 disables it
+	ReportAutobanThreshold int
+	// MaxActiveOrders caps how many live orders each trader may have;
+	// 0 means the bot's default
+	MaxActiveOrders int
 }
 
 // GetGuildSettings retrieves settings for a specific guild
 func (db *DB) GetGuildSettings(ctx context.Context, guildID string) (*GuildSettings, error) {
 	query := `
-		SELECT guild_id, admin_role_id, configured_at, configured_by, updated_at
+		SELECT guild_id, admin_role_id, trade_preview, log_channel_id, submission_timeout_minutes, report_autoban_threshold, max_active_orders, configured_at, configured_by, updated_at
 		FROM guild_settings
 		WHERE guild_id = ?
 	`
 
 	var settings GuildSettings
-	var adminRoleID sql.NullString
+	var adminRoleID, logChannelID sql.NullString
+	var submissionTimeout, autobanThreshold, maxActiveOrders sql.NullInt64
 
 	err := db.conn.QueryRowContext(ctx, query, guildID).Scan(
 		&settings.GuildID,
 		&adminRoleID,
+		&settings.TradePreview,
+		&logChannelID,
+		&submissionTimeout,
+		&autobanThreshold,
+		&maxActiveOrders,
 		&settings.ConfiguredAt,
 		&settings.ConfiguredBy,
 		&settings.UpdatedAt,
@@ -539,6 +1148,12 @@ func (db *DB) GetGuildSettings(ctx context.Context, guildID string) (*GuildSetti
 	if adminRoleID.Valid {
 		settings.AdminRoleID = adminRoleID.String
 	}
+	if logChannelID.Valid {
+		settings.LogChannelID = logChannelID.String
+	}
+	settings.SubmissionTimeoutMinutes = int(submissionTimeout.Int64)
+	settings.ReportAutobanThreshold = int(autobanThreshold.Int64)
+	settings.MaxActiveOrders = int(maxActiveOrders.Int64)
 
 	return &settings, nil
 }
@@ -561,10 +1176,163 @@ func (db *DB) SetGuildAdminRole(ctx context.Context, guildID, adminRoleID, confi
 	return nil
 }
 
+// SetGuildTradePreview enables or disables the /trade-create preview step for a guild
+func (db *DB) SetGuildTradePreview(ctx context.Context, guildID string, enabled bool, configuredBy string) error {
+	query := `
+		INSERT INTO guild_settings (guild_id, trade_preview, configured_by, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(guild_id) DO UPDATE SET
+			trade_preview = excluded.trade_preview,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := db.conn.ExecContext(ctx, query, guildID, enabled, configuredBy)
+	if err != nil {
+		return fmt.Errorf("failed to set guild trade preview: %w", err)
+	}
+
+	return nil
+}
+
+// SetGuildLogChannel sets the moderation log channel for a guild. An empty
+// channelID clears it.
+func (db *DB) SetGuildLogChannel(ctx context.Context, guildID, channelID, configuredBy string) error {
+	query := `
+		INSERT INTO guild_settings (guild_id, log_channel_id, configured_by, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(guild_id) DO UPDATE SET
+			log_channel_id = excluded.log_channel_id,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	var logChannelID sql.NullString
+	if channelID != "" {
+		logChannelID = sql.NullString{String: channelID, Valid: true}
+	}
+
+	_, err := db.conn.ExecContext(ctx, query, guildID, logChannelID, configuredBy)
+	if err != nil {
+		return fmt.Errorf("failed to set guild log channel: %w", err)
+	}
+
+	return nil
+}
+
+// SetGuildSubmissionTimeout sets how many minutes /submit confirmations stay
+// open in a guild. 0 goes back to the bot's default.
+func (db *DB) SetGuildSubmissionTimeout(ctx context.Context, guildID string, minutes int, configuredBy string) error {
+	query := `
+		INSERT INTO guild_settings (guild_id, submission_timeout_minutes, configured_by, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(guild_id) DO UPDATE SET
+			submission_timeout_minutes = excluded.submission_timeout_minutes,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	var timeout sql.NullInt64
+	if minutes > 0 {
+		timeout = sql.NullInt64{Int64: int64(minutes), Valid: true}
+	}
+
+	_, err := db.conn.ExecContext(ctx, query, guildID, timeout, configuredBy)
+	if err != nil {
+		return fmt.Errorf("failed to set guild submission timeout: %w", err)
+	}
+
+	return nil
+}
+
+// addSubmissionTimeoutSetting lets guilds choose how long /submit
+// confirmations stay open. NULL keeps the bot's default.
+func addSubmissionTimeoutSetting(ctx context.Context, tx *sql.Tx) error {
+	exists, err := columnExists(ctx, tx, "guild_settings", "submission_timeout_minutes")
+	if err != nil || exists {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE guild_settings ADD COLUMN submission_timeout_minutes INTEGER`); err != nil {
+		return fmt.Errorf("failed to add guild_settings.submission_timeout_minutes: %w", err)
+	}
+	return nil
+}
+
+// SetGuildReportAutobanThreshold sets how many users must report someone
+// before they're banned automatically in a guild. 0 disables it.
+func (db *DB) SetGuildReportAutobanThreshold(ctx context.Context, guildID string, threshold int, configuredBy string) error {
+	query := `
+		INSERT INTO guild_settings (guild_id, report_autoban_threshold, configured_by, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(guild_id) DO UPDATE SET
+			report_autoban_threshold = excluded.report_autoban_threshold,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	var value sql.NullInt64
+	if threshold > 0 {
+		value = sql.NullInt64{Int64: int64(threshold), Valid: true}
+	}
+
+	_, err := db.conn.ExecContext(ctx, query, guildID, value, configuredBy)
+	if err != nil {
+		return fmt.Errorf("failed to set guild report autoban threshold: %w", err)
+	}
+
+	return nil
+}
+
+// addReportAutobanSetting lets guilds ban repeatedly reported users
+// automatically. NULL leaves it off.
+func addReportAutobanSetting(ctx context.Context, tx *sql.Tx) error {
+	exists, err := columnExists(ctx, tx, "guild_settings", "report_autoban_threshold")
+	if err != nil || exists {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE guild_settings ADD COLUMN report_autoban_threshold INTEGER`); err != nil {
+		return fmt.Errorf("failed to add guild_settings.report_autoban_threshold: %w", err)
+	}
+	return nil
+}
+
+// SetGuildMaxActiveOrders sets how many live orders each trader may have in
+// a guild. 0 goes back to the bot's default.
+func (db *DB) SetGuildMaxActiveOrders(ctx context.Context, guildID string, limit int, configuredBy string) error {
+	query := `
+		INSERT INTO guild_settings (guild_id, max_active_orders, configured_by, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(guild_id) DO UPDATE SET
+			max_active_orders = excluded.max_active_orders,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	var value sql.NullInt64
+	if limit > 0 {
+		value = sql.NullInt64{Int64: int64(limit), Valid: true}
+	}
+
+	_, err := db.conn.ExecContext(ctx, query, guildID, value, configuredBy)
+	if err != nil {
+		return fmt.Errorf("failed to set guild max active orders: %w", err)
+	}
+
+	return nil
+}
+
+// addMaxActiveOrdersSetting lets guilds choose how many live orders each
+// trader may have. NULL keeps the bot's default.
+func addMaxActiveOrdersSetting(ctx context.Context, tx *sql.Tx) error {
+	exists, err := columnExists(ctx, tx, "guild_settings", "max_active_orders")
+	if err != nil || exists {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE guild_settings ADD COLUMN max_active_orders INTEGER`); err != nil {
+		return fmt.Errorf("failed to add guild_settings.max_active_orders: %w", err)
+	}
+	return nil
+}
+
 // GetAllGuildSettings retrieves all configured guilds
 func (db *DB) GetAllGuildSettings(ctx context.Context) ([]GuildSettings, error) {
 	query := `
-		SELECT guild_id, admin_role_id, configured_at, configured_by, updated_at
+		SELECT guild_id, admin_role_id, trade_preview, log_channel_id, submission_timeout_minutes, report_autoban_threshold, max_active_orders, configured_at, configured_by, updated_at
 		FROM guild_settings
 		ORDER BY updated_at DESC
 	`
@@ -578,11 +1346,17 @@ func (db *DB) GetAllGuildSettings(ctx context.Context) ([]GuildSettings, error)
 	var settings []GuildSettings
 	for rows.Next() {
 		var s GuildSettings
-		var adminRoleID sql.NullString
+		var adminRoleID, logChannelID sql.NullString
+		var submissionTimeout, autobanThreshold, maxActiveOrders sql.NullInt64
 
 		err := rows.Scan(
 			&s.GuildID,
 			&adminRoleID,
+			&s.TradePreview,
+			&logChannelID,
+			&submissionTimeout,
+			&autobanThreshold,
+			&maxActiveOrders,
 			&s.ConfiguredAt,
 			&s.ConfiguredBy,
 			&s.UpdatedAt,
@@ -594,6 +1368,12 @@ func (db *DB) GetAllGuildSettings(ctx context.Context) ([]GuildSettings, error)
 		if adminRoleID.Valid {
 			s.AdminRoleID = adminRoleID.String
 		}
+		if logChannelID.Valid {
+			s.LogChannelID = logChannelID.String
+		}
+		s.SubmissionTimeoutMinutes = int(submissionTimeout.Int64)
+		s.ReportAutobanThreshold = int(autobanThreshold.Int64)
+		s.MaxActiveOrders = int(maxActiveOrders.Int64)
 
 		settings = append(settings, s)
 	}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// createWordFilter adds the filtered_words table: words admins have barred
+// from order notes, in-game names and relayed DMs
+func createWordFilter(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS filtered_words (
+			word TEXT PRIMARY KEY COLLATE NOCASE,
+			added_by TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create filtered_words: %w", err)
+	}
+	return nil
+}
+
+// GetFilteredWords returns every word added with AddFilteredWord, sorted
+func (db *DB) GetFilteredWords(ctx context.Context) ([]string, error) {
+	rows, err := db.conn.QueryContext(ctx, `SELECT word FROM filtered_words ORDER BY word`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get filtered words: %w", err)
+	}
+	defer rows.Close()
+
+	var words []string
+	for rows.Next() {
+		var word string
+		if err := rows.Scan(&word); err != nil {
+			return nil, fmt.Errorf("failed to scan filtered word: %w", err)
+		}
+		words = append(words, word)
+	}
+	return words, rows.Err()
+}
+
+// AddFilteredWord bars a word and audit-logs it. added is false if the word
+// was already filtered.
+func (db *DB) AddFilteredWord(ctx context.Context, word, adminID string) (added bool, err error) {
+	return db.changeFilteredWord(ctx, "word_filter_add", word, adminID,
+		`INSERT OR IGNORE INTO filtered_words (word, added_by) VALUES (?, ?)`, word, adminID)
+}
+
+// RemoveFilteredWord lifts a filtered word and audit-logs it. removed is
+// false if the word wasn't filtered.
+func (db *DB) RemoveFilteredWord(ctx context.Context, word, adminID string) (removed bool, err error) {
+	return db.changeFilteredWord(ctx, "word_filter_remove", word, adminID,
+		`DELETE FROM filtered_words WHERE word = ?`, word)
+}
+
+// changeFilteredWord runs one filter edit and, if it changed anything, logs
+// action in the same transaction
+func (db *DB) changeFilteredWord(ctx context.Context, action, word, adminID, query string, args ...interface{}) (bool, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to update filtered words: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return false, nil
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{"word": word})
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO audit_log (action, user_id, details) VALUES (?, ?, ?)`,
+		action, adminID, string(details),
+	); err != nil {
+		return false, fmt.Errorf("failed to log action: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFilteredWords(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if added, err := db.AddFilteredWord(ctx, "scam", "admin1"); err != nil || !added {
+		t.Fatalf("expected the word added, got %v (%v)", added, err)
+	}
+	if added, err := db.AddFilteredWord(ctx, "SCAM", "admin1"); err != nil || added {
+		t.Errorf("expected a case-insensitive duplicate to be ignored, got %v (%v)", added, err)
+	}
+	db.AddFilteredWord(ctx, "fraud", "admin1")
+
+	words, err := db.GetFilteredWords(ctx)
+	if err != nil {
+		t.Fatalf("GetFilteredWords failed: %v", err)
+	}
+	if len(words) != 2 || words[0] != "fraud" || words[1] != "scam" {
+		t.Errorf("expected [fraud scam], got %v", words)
+	}
+
+	if removed, err := db.RemoveFilteredWord(ctx, "Scam", "admin2"); err != nil || !removed {
+		t.Errorf("expected the word removed, got %v (%v)", removed, err)
+	}
+	if removed, _ := db.RemoveFilteredWord(ctx, "scam", "admin2"); removed {
+		t.Error("expected removing it again to do nothing")
+	}
+
+	added, _ := db.GetAuditLog(ctx, "word_filter_add", 10)
+	removed, _ := db.GetAuditLog(ctx, "word_filter_remove", 10)
+	if len(added) != 2 || len(removed) != 1 || removed[0].UserID != "admin2" {
+		t.Errorf("expected only real changes audited, got %d adds and %d removes", len(added), len(removed))
+	}
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// createOrderInterest adds the order_interest table: one row per user who
+// clicked Interested on an order
+func createOrderInterest(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS order_interest (
+			order_id INTEGER NOT NULL REFERENCES player_orders(id) ON DELETE CASCADE,
+			user_id TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (order_id, user_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create order_interest: %w", err)
+	}
+	return nil
+}
+
+// AddOrderInterest records that userID is interested in an order. Each user
+// counts once; added is false if they had already registered interest.
+// count is the order's total afterwards.
+func (db *DB) AddOrderInterest(ctx context.Context, orderID int, userID string) (added bool, count int, err error) {
+	result, err := db.conn.ExecContext(ctx,
+		`INSERT OR IGNORE INTO order_interest (order_id, user_id) VALUES (?, ?)`, orderID, userID)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to add order interest: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+
+	err = db.conn.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM order_interest WHERE order_id = ?`, orderID).Scan(&count)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to count order interest: %w", err)
+	}
+	return rows > 0, count, nil
+}
+
+// GetOrderInterestCounts returns how many users are interested in each of
+// several orders. Orders nobody is interested in are omitted from the map.
+func (db *DB) GetOrderInterestCounts(ctx context.Context, orderIDs []int) (map[int]int, error) {
+	counts := make(map[int]int)
+	if len(orderIDs) == 0 {
+		return counts, nil
+	}
+
+	args := make([]interface{}, len(orderIDs))
+	for idx, id := range orderIDs {
+		args[idx] = id
+	}
+
+	query := `
+		SELECT order_id, COUNT(*)
+		FROM order_interest
+		WHERE order_id IN (?` + repeatPlaceholders(len(orderIDs)-1) + `)
+		GROUP BY order_id
+	`
+	rows, err := db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order interest: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID, count int
+		if err := rows.Scan(&orderID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan order interest: %w", err)
+		}
+		counts[orderID] = count
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestOrderInterest(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+	expiry := time.Now().Add(time.Hour)
+	order := mustCreatePlayerOrder(t, db, "seller1", item.ID, expiry)
+	quiet := mustCreatePlayerOrder(t, db, "seller1", item.ID, expiry)
+
+	add := func(userID string) (bool, int) {
+		t.Helper()
+		added, count, err := db.AddOrderInterest(ctx, order.ID, userID)
+		if err != nil {
+			t.Fatalf("AddOrderInterest failed: %v", err)
+		}
+		return added, count
+	}
+	if added, count := add("buyer1"); !added || count != 1 {
+		t.Errorf("expected first interest to be added with count 1, got %v, %d", added, count)
+	}
+	if added, count := add("buyer1"); added || count != 1 {
+		t.Errorf("expected a repeat click not to count, got %v, %d", added, count)
+	}
+	if added, count := add("buyer2"); !added || count != 2 {
+		t.Errorf("expected a second user to count, got %v, %d", added, count)
+	}
+
+	counts, err := db.GetOrderInterestCounts(ctx, []int{order.ID, quiet.ID})
+	if err != nil {
+		t.Fatalf("GetOrderInterestCounts failed: %v", err)
+	}
+	if len(counts) != 1 || counts[order.ID] != 2 {
+		t.Errorf("expected only the first order with 2, got %v", counts)
+	}
+	if counts, err := db.GetOrderInterestCounts(ctx, nil); err != nil || len(counts) != 0 {
+		t.Errorf("expected an empty map for no orders, got %v, %v", counts, err)
+	}
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrItemArchived is returned by CreateItem when the name belongs to an
+// archived item. Only admins can bring one back, with RestoreItem.
+var ErrItemArchived = errors.New("item is archived")
+
+// addItemArchived lets items be retired without deleting them. Deleting an
+// item cascades to its markets, orders and aliases, so retired items are
+// archived instead and keep their history.
+func addItemArchived(ctx context.Context, tx *sql.Tx) error {
+	exists, err := columnExists(ctx, tx, "items", "archived")
+	if err != nil || exists {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE items ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE`); err != nil {
+		return fmt.Errorf("failed to add items.archived: %w", err)
+	}
+	return nil
+}
+
+// ArchiveItem retires an item: it stops matching OCR names and drops out of
+// item listings, but its markets and trade orders are kept
+func (db *DB) ArchiveItem(ctx context.Context, itemID int, adminUserID string) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var name string
+	var archived bool
+	err = tx.QueryRowContext(ctx, `SELECT display_name, archived FROM items WHERE id = ?`, itemID).Scan(&name, &archived)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("item %d not found", itemID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get item: %w", err)
+	}
+	if archived {
+		return fmt.Errorf("%s is already archived", name)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE items SET archived = TRUE WHERE id = ?`, itemID); err != nil {
+		return fmt.Errorf("failed to archive item: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"item_id": itemID,
+		"item":    name,
+	})
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO audit_log (action, user_id, details) VALUES (?, ?, ?)`,
+		"archive_item", adminUserID, string(details),
+	); err != nil {
+		return fmt.Errorf("failed to log archive: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// RestoreItem brings an archived item back into use
+func (db *DB) RestoreItem(ctx context.Context, itemID int, adminUserID string) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var name string
+	var archived bool
+	err = tx.QueryRowContext(ctx, `SELECT display_name, archived FROM items WHERE id = ?`, itemID).Scan(&name, &archived)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("item %d not found", itemID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get item: %w", err)
+	}
+	if !archived {
+		return fmt.Errorf("%s isn't archived", name)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE items SET archived = FALSE WHERE id = ?`, itemID); err != nil {
+		return fmt.Errorf("failed to restore item: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"item_id": itemID,
+		"item":    name,
+	})
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO audit_log (action, user_id, details) VALUES (?, ?, ?)`,
+		"restore_item", adminUserID, string(details),
+	); err != nil {
+		return fmt.Errorf("failed to log restore: %w", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestArchiveItemKeepsHistory(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	rum := mustCreateItem(t, db, "Rum")
+	mustCreateItem(t, db, "Rope")
+	order := mustCreatePlayerOrder(t, db, "user1", rum.ID, time.Now().Add(time.Hour))
+
+	if err := db.ArchiveItem(ctx, rum.ID, "admin"); err != nil {
+		t.Fatalf("ArchiveItem failed: %v", err)
+	}
+	if err := db.ArchiveItem(ctx, rum.ID, "admin"); err == nil {
+		t.Error("expected archiving twice to fail")
+	}
+
+	if _, err := db.GetPlayerOrder(ctx, order.ID); err != nil {
+		t.Errorf("archiving should keep the item's orders: %v", err)
+	}
+
+	active, err := db.GetActiveItems(ctx)
+	if err != nil {
+		t.Fatalf("GetActiveItems failed: %v", err)
+	}
+	if len(active) != 1 || active[0].DisplayName != "Rope" {
+		t.Errorf("expected only Rope to be active, got %+v", active)
+	}
+
+	matches, err := db.FindItemMatches(ctx, "Rum", 5)
+	if err != nil {
+		t.Fatalf("FindItemMatches failed: %v", err)
+	}
+	for _, m := range matches {
+		if m.Item.ID == rum.ID {
+			t.Errorf("archived item matched via %s", m.MatchedVia)
+		}
+	}
+
+	entries, err := db.GetAuditLog(ctx, "archive_item", 10)
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected 1 archive_item entry, got %d", len(entries))
+	}
+
+	// Players adding it again can't bring it back
+	if _, err := db.CreateItem(ctx, "rum", "rum", "user2"); !errors.Is(err, ErrItemArchived) {
+		t.Fatalf("expected ErrItemArchived, got %v", err)
+	}
+	if active, _ := db.GetActiveItems(ctx); len(active) != 1 {
+		t.Errorf("expected the item to stay archived, got %d active items", len(active))
+	}
+
+	if err := db.RestoreItem(ctx, rum.ID, "admin1"); err != nil {
+		t.Fatalf("RestoreItem failed: %v", err)
+	}
+	if active, _ := db.GetActiveItems(ctx); len(active) != 2 {
+		t.Errorf("expected 2 active items after restore, got %d", len(active))
+	}
+	if err := db.RestoreItem(ctx, rum.ID, "admin1"); err == nil {
+		t.Error("expected an error restoring an item that isn't archived")
+	}
+	if entries, _ := db.GetAuditLog(ctx, "restore_item", 10); len(entries) != 1 || entries[0].UserID != "admin1" {
+		t.Errorf("expected 1 restore_item entry by admin1, got %+v", entries)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 )
 
@@ -114,9 +116,13 @@ func (db *DB) GetActiveTradeBans(ctx context.Context) ([]TradeBan, error) {
 	return scanTradeBans(rows)
 }
 
-// CancelAllUserOrders cancels all active player orders for a user.
+// CancelAllUserOrders cancels all of a user's open player orders, including
+// scheduled ones, and returns how many it cancelled. Bans use it to take a
+// trader's orders down and /trade-cancel-all lets traders clear their own.
+// Orders already past their expiry are left for the expiry job so they stay
+// "expired" in history.
 func (db *DB) CancelAllUserOrders(ctx context.Context, userID string) (int64, error) {
-	query := `UPDATE player_orders SET status = 'cancelled' WHERE user_id = ? AND status = 'active'`
+	query := `UPDATE player_orders AS po SET status = 'cancelled', cancelled_at = CURRENT_TIMESTAMP WHERE po.user_id = ? AND ` + openPlayerOrder
 	result, err := db.conn.ExecContext(ctx, query, userID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to cancel user orders: %w", err)
@@ -124,21 +130,284 @@ func (db *DB) CancelAllUserOrders(ctx context.Context, userID string) (int64, er
 	return result.RowsAffected()
 }
 
+// AdminCancelPlayerOrder cancels an open order whoever owns it, for when a
+// single listing breaks the rules and banning its owner would be too much.
+// It returns the order (with item and port) so the owner can be told, and
+// keeps its notes in the audit log in case the removal is questioned. A
+// closed or missing order gives one of CheckPlayerOrder's errors.
+func (db *DB) AdminCancelPlayerOrder(ctx context.Context, orderID int, adminID, reason string) (*PlayerOrder, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT po.id, po.user_id, po.item_id, po.order_type, po.price, po.quantity,
+		       po.port_id, po.notes, po.ingame_name, po.status, po.created_at, po.expires_at, po.activates_at,
+		       i.name, i.display_name,
+		       p.name, p.display_name, p.region
+		FROM player_orders po
+		JOIN items i ON po.item_id = i.id
+		LEFT JOIN ports p ON po.port_id = p.id
+		WHERE po.id = ? AND ` + openPlayerOrder
+	rows, err := tx.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player order: %w", err)
+	}
+	orders, err := scanPlayerOrdersWithJoins(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		if _, err := db.CheckPlayerOrder(ctx, orderID); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("order %d could not be removed", orderID)
+	}
+	order := orders[0]
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE player_orders SET status = 'cancelled', cancelled_at = CURRENT_TIMESTAMP WHERE id = ?`, orderID,
+	); err != nil {
+		return nil, fmt.Errorf("failed to cancel order: %w", err)
+	}
+	order.Status = "cancelled"
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"order_id":    orderID,
+		"target_user": order.UserID,
+		"item":        order.Item.DisplayName,
+		"notes":       order.Notes,
+		"reason":      reason,
+	})
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO audit_log (action, user_id, details) VALUES (?, ?, ?)`,
+		"admin_remove_order", adminID, string(details),
+	); err != nil {
+		return nil, fmt.Errorf("failed to log action: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &order, nil
+}
+
+// ExpireBans deactivates active bans whose expiry has passed, logging one
+// trade_ban_expired audit entry per ban. It returns only the bans that leave
+// their user free to trade, one per user: a user who was banned again before
+// the old ban was swept still has the newer ban, so callers mustn't clear
+// their cached ban or tell them it's over.
+func (db *DB) ExpireBans(ctx context.Context) ([]TradeBan, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, user_id, reason, banned_by, banned_at, expires_at, active
+		FROM trade_bans
+		WHERE active = TRUE AND expires_at IS NOT NULL AND expires_at <= datetime('now')
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired trade bans: %w", err)
+	}
+	bans, err := scanTradeBans(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range bans {
+		ban := &bans[idx]
+		if _, err := tx.ExecContext(ctx, `UPDATE trade_bans SET active = FALSE WHERE id = ?`, ban.ID); err != nil {
+			return nil, fmt.Errorf("failed to expire trade ban: %w", err)
+		}
+		ban.Active = false
+
+		details, _ := json.Marshal(map[string]interface{}{
+			"ban_id":      ban.ID,
+			"banned_user": ban.UserID,
+			"expires_at":  ban.ExpiresAt,
+		})
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO audit_log (action, user_id, details) VALUES (?, ?, ?)`,
+			"trade_ban_expired", SystemUserID, string(details),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to log action: %w", err)
+		}
+	}
+
+	// Bans are in ID order, so the last lapsed ban per user is kept
+	var lifted []TradeBan
+	seen := make(map[string]int)
+	for _, ban := range bans {
+		var stillBanned bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS(
+				SELECT 1 FROM trade_bans
+				WHERE user_id = ? AND active = TRUE
+				  AND (expires_at IS NULL OR expires_at > datetime('now'))
+			)`, ban.UserID).Scan(&stillBanned)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check remaining trade bans: %w", err)
+		}
+		if stillBanned {
+			continue
+		}
+		if idx, ok := seen[ban.UserID]; ok {
+			lifted[idx] = ban
+			continue
+		}
+		seen[ban.UserID] = len(lifted)
+		lifted = append(lifted, ban)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return lifted, nil
+}
+
+// --- Player Profile Moderation ---
+
+// AdminSetPlayerProfile overwrites a user's in-game name on behalf of an
+// admin. Their active orders are renamed too, since trade embeds show the
+// name stored on the order.
+func (db *DB) AdminSetPlayerProfile(ctx context.Context, userID, ingameName, adminUserID string) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous sql.NullString
+	err = tx.QueryRowContext(ctx, `SELECT ingame_name FROM player_profiles WHERE user_id = ?`, userID).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get player profile: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO player_profiles (user_id, ingame_name)
+		VALUES (?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			ingame_name = excluded.ingame_name,
+			updated_at = CURRENT_TIMESTAMP
+	`, userID, ingameName)
+	if err != nil {
+		return fmt.Errorf("failed to set player profile: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE player_orders SET ingame_name = ? WHERE user_id = ? AND status = 'active'`,
+		ingameName, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to rename player orders: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"target_user": userID,
+		"old_name":    previous.String,
+		"new_name":    ingameName,
+	})
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO audit_log (action, user_id, details) VALUES (?, ?, ?)`,
+		"admin_set_name", adminUserID, string(details),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to log action: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ClearPlayerProfile removes a user's in-game name so they have to set a new
+// one before trading again. Returns an error if they have none.
+func (db *DB) ClearPlayerProfile(ctx context.Context, userID, adminUserID string) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous string
+	err = tx.QueryRowContext(ctx, `SELECT ingame_name FROM player_profiles WHERE user_id = ?`, userID).Scan(&previous)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("user has no in-game name set")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get player profile: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM player_profiles WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to clear player profile: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"target_user": userID,
+		"old_name":    previous,
+	})
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO audit_log (action, user_id, details) VALUES (?, ?, ?)`,
+		"admin_clear_name", adminUserID, string(details),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to log action: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // --- Trade Report Operations ---
 
-// CreateTradeReport inserts a new report and logs the action.
-func (db *DB) CreateTradeReport(ctx context.Context, report TradeReport) (*TradeReport, error) {
+// SystemUserID is recorded as the acting user for things the bot does on its
+// own, such as expiring or automatically issuing bans
+const SystemUserID = "system"
+
+// ReportAutoban is when CreateTradeReport bans a reported user by itself:
+// once Threshold different users have pending or upheld reports against them
+// since their last ban. A zero Threshold turns it off.
+type ReportAutoban struct {
+	Threshold int
+	BanFor    time.Duration
+}
+
+// ReportEscalation is an automatic ban and the pending reports it closed
+type ReportEscalation struct {
+	Ban       TradeBan
+	Reporters int
+	ReportIDs []int
+}
+
+// CreateTradeReport inserts a new report and logs the action. If the report
+// brings the reported user to the autoban threshold they are banned for
+// autoban.BanFor, their pending reports are marked reviewed and the
+// escalation is returned. A failed escalation is logged but doesn't fail the
+// report.
+func (db *DB) CreateTradeReport(ctx context.Context, report TradeReport, autoban ReportAutoban) (*TradeReport, *ReportEscalation, error) {
 	query := `INSERT INTO trade_reports (reporter_user_id, reported_user_id, order_id, reason) VALUES (?, ?, ?, ?)`
 	result, err := db.conn.ExecContext(ctx, query,
 		report.ReporterUserID, report.ReportedUserID, report.OrderID, report.Reason,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create trade report: %w", err)
+		return nil, nil, fmt.Errorf("failed to create trade report: %w", err)
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get report ID: %w", err)
+		return nil, nil, fmt.Errorf("failed to get report ID: %w", err)
 	}
 
 	report.ID = int(id)
@@ -157,7 +426,131 @@ func (db *DB) CreateTradeReport(ctx context.Context, report TradeReport) (*Trade
 		"trade_report", report.ReporterUserID, string(details),
 	)
 
-	return &report, nil
+	if autoban.Threshold <= 0 {
+		return &report, nil, nil
+	}
+	escalation, err := db.escalateReports(ctx, report.ReportedUserID, autoban)
+	if err != nil {
+		log.Printf("Error checking report autoban for %s: %v", report.ReportedUserID, err)
+		return &report, nil, nil
+	}
+	return &report, escalation, nil
+}
+
+// escalateReports bans userID if enough different users have reported them
+// since their last ban. Only pending and reviewed reports count, so
+// dismissed ones never add up to a ban. Returns nil if no ban was needed.
+func (db *DB) escalateReports(ctx context.Context, userID string, autoban ReportAutoban) (*ReportEscalation, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var banned int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM trade_bans
+		WHERE user_id = ? AND active = TRUE AND (expires_at IS NULL OR expires_at > datetime('now'))
+	`, userID).Scan(&banned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check trade ban: %w", err)
+	}
+	if banned > 0 {
+		return nil, nil
+	}
+
+	var reporters int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT reporter_user_id) FROM trade_reports
+		WHERE reported_user_id = ? AND status IN ('pending', 'reviewed')
+		  AND created_at > COALESCE((SELECT MAX(banned_at) FROM trade_bans WHERE user_id = ?), '')
+	`, userID, userID).Scan(&reporters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reports: %w", err)
+	}
+	if reporters < autoban.Threshold {
+		return nil, nil
+	}
+
+	escalation := ReportEscalation{Reporters: reporters}
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id FROM trade_reports WHERE reported_user_id = ? AND status = 'pending' ORDER BY id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending reports: %w", err)
+	}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+		escalation.ReportIDs = append(escalation.ReportIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find pending reports: %w", err)
+	}
+
+	expiresAt := time.Now().Add(autoban.BanFor)
+	ban := TradeBan{
+		UserID:    userID,
+		Reason:    fmt.Sprintf("Automatic ban: reported by %d users", reporters),
+		BannedBy:  SystemUserID,
+		ExpiresAt: &expiresAt,
+		BannedAt:  time.Now(),
+		Active:    true,
+	}
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO trade_bans (user_id, reason, banned_by, expires_at) VALUES (?, ?, ?, ?)`,
+		ban.UserID, ban.Reason, ban.BannedBy, ban.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trade ban: %w", err)
+	}
+	banID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ban ID: %w", err)
+	}
+	ban.ID = int(banID)
+	escalation.Ban = ban
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE trade_reports SET status = 'reviewed', reviewed_by = ?, reviewed_at = CURRENT_TIMESTAMP
+		WHERE reported_user_id = ? AND status = 'pending'
+	`, SystemUserID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update trade reports: %w", err)
+	}
+
+	banDetails, _ := json.Marshal(map[string]interface{}{
+		"banned_user": ban.UserID,
+		"reason":      ban.Reason,
+		"banned_by":   ban.BannedBy,
+		"expires_at":  ban.ExpiresAt,
+		"report_ids":  escalation.ReportIDs,
+	})
+	reportDetails, _ := json.Marshal(map[string]interface{}{
+		"report_ids":  escalation.ReportIDs,
+		"action":      "reviewed",
+		"reviewed_by": SystemUserID,
+	})
+	for _, entry := range []struct{ action, details string }{
+		{"trade_ban", string(banDetails)},
+		{"trade_report_action", string(reportDetails)},
+	} {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO audit_log (action, user_id, details) VALUES (?, ?, ?)`,
+			entry.action, SystemUserID, entry.details,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to log action: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &escalation, nil
 }
 
 // GetTradeReports returns reports filtered by status.
@@ -215,6 +608,33 @@ func (db *DB) GetTradeReport(ctx context.Context, reportID int) (*TradeReport, e
 	return &report, nil
 }
 
+// CountPendingReportsAgainst returns how many unreviewed reports name the user
+func (db *DB) CountPendingReportsAgainst(ctx context.Context, userID string) (int, error) {
+	query := `SELECT COUNT(*) FROM trade_reports WHERE reported_user_id = ? AND status = 'pending'`
+	var count int
+	if err := db.conn.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count pending reports: %w", err)
+	}
+	return count, nil
+}
+
+// HasExistingPendingReport reports whether reporterID already has an
+// unreviewed report against reportedID, for orderID if given or any order
+// otherwise
+func (db *DB) HasExistingPendingReport(ctx context.Context, reporterID, reportedID string, orderID *int) (bool, error) {
+	query := `SELECT COUNT(*) FROM trade_reports WHERE reporter_user_id = ? AND reported_user_id = ? AND status = 'pending'`
+	args := []interface{}{reporterID, reportedID}
+	if orderID != nil {
+		query += ` AND order_id = ?`
+		args = append(args, *orderID)
+	}
+	var count int
+	if err := db.conn.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check existing reports: %w", err)
+	}
+	return count > 0, nil
+}
+
 // UpdateTradeReportStatus sets a report's status and reviewer info.
 func (db *DB) UpdateTradeReportStatus(ctx context.Context, reportID int, status string, reviewedBy string) error {
 	query := `UPDATE trade_reports SET status = ?, reviewed_by = ?, reviewed_at = CURRENT_TIMESTAMP WHERE id = ?`
@@ -237,8 +657,158 @@ func (db *DB) UpdateTradeReportStatus(ctx context.Context, reportID int, status
 	return nil
 }
 
+// --- Audit Log Operations ---
+
+// GetAuditLog returns the most recent audit entries, newest first. An empty
+// action returns entries of every action.
+func (db *DB) GetAuditLog(ctx context.Context, action string, limit int) ([]AuditLog, error) {
+	query := `SELECT id, action, user_id, timestamp, details FROM audit_log`
+	var args []interface{}
+	if action != "" {
+		query += ` WHERE action = ?`
+		args = append(args, action)
+	}
+	query += ` ORDER BY timestamp DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+	defer rows.Close()
+	return scanAuditLogs(rows)
+}
+
+// LogSubmission records a /submit in the audit log under the submitter's ID
+func (db *DB) LogSubmission(ctx context.Context, record SubmissionRecord) error {
+	details, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode submission: %w", err)
+	}
+	_, err = db.conn.ExecContext(ctx,
+		`INSERT INTO audit_log (action, user_id, details) VALUES (?, ?, ?)`,
+		"submission", record.UserID, string(details),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to log submission: %w", err)
+	}
+	return nil
+}
+
+// GetSubmissionsByUser returns a contributor's submissions, newest first
+func (db *DB) GetSubmissionsByUser(ctx context.Context, userID string) ([]SubmissionRecord, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT id, action, user_id, timestamp, details FROM audit_log
+		WHERE action = 'submission' AND user_id = ?
+		ORDER BY timestamp DESC, id DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submissions: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := scanAuditLogs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]SubmissionRecord, 0, len(entries))
+	for _, entry := range entries {
+		var record SubmissionRecord
+		if err := json.Unmarshal([]byte(entry.Details), &record); err != nil {
+			return nil, fmt.Errorf("failed to decode submission %d: %w", entry.ID, err)
+		}
+		record.UserID = entry.UserID
+		record.SubmittedAt = entry.Timestamp
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// --- Moderation Stats ---
+
+// moderatorActions are the audit actions an admin takes by hand, as opposed
+// to ones the bot records on its own (expiry, submissions)
+var moderatorActions = []string{"trade_ban", "trade_unban", "trade_report_action", "admin_set_name", "admin_clear_name", "admin_remove_order"}
+
+// ModerationStats summarizes the moderation workload for /admin-stats
+type ModerationStats struct {
+	PendingReports   int
+	ReportsThisWeek  int
+	ActiveBans       int
+	BansThisWeek     int
+	ActionsThisWeek  int           // moderator entries in the audit log
+	ActionedReports  int           // reports reviewed or dismissed, ever
+	AvgTimeToAction  time.Duration // from report to review, 0 if none yet
+	OldestPendingAge time.Duration // 0 if nothing is pending
+}
+
+// GetModerationStats aggregates reports, bans and moderator audit entries.
+// "This week" is the last 7 days.
+func (db *DB) GetModerationStats(ctx context.Context) (*ModerationStats, error) {
+	var stats ModerationStats
+	var avgSeconds, oldestSeconds sql.NullFloat64
+
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT
+			COUNT(CASE WHEN status = 'pending' THEN 1 END),
+			COUNT(CASE WHEN created_at > datetime('now', '-7 days') THEN 1 END),
+			COUNT(reviewed_at),
+			AVG((julianday(reviewed_at) - julianday(created_at)) * 86400),
+			MAX(CASE WHEN status = 'pending' THEN (julianday('now') - julianday(created_at)) * 86400 END)
+		FROM trade_reports
+	`).Scan(&stats.PendingReports, &stats.ReportsThisWeek, &stats.ActionedReports, &avgSeconds, &oldestSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count trade reports: %w", err)
+	}
+	if avgSeconds.Valid {
+		stats.AvgTimeToAction = time.Duration(avgSeconds.Float64 * float64(time.Second))
+	}
+	if oldestSeconds.Valid {
+		stats.OldestPendingAge = time.Duration(oldestSeconds.Float64 * float64(time.Second))
+	}
+
+	err = db.conn.QueryRowContext(ctx, `
+		SELECT
+			COUNT(CASE WHEN active = TRUE AND (expires_at IS NULL OR expires_at > datetime('now')) THEN 1 END),
+			COUNT(CASE WHEN banned_at > datetime('now', '-7 days') THEN 1 END)
+		FROM trade_bans
+	`).Scan(&stats.ActiveBans, &stats.BansThisWeek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count trade bans: %w", err)
+	}
+
+	query := `SELECT COUNT(*) FROM audit_log
+		WHERE timestamp > datetime('now', '-7 days') AND user_id != ? AND action IN (?` +
+		strings.Repeat(", ?", len(moderatorActions)-1) + `)`
+	args := []interface{}{SystemUserID}
+	for _, action := range moderatorActions {
+		args = append(args, action)
+	}
+	if err := db.conn.QueryRowContext(ctx, query, args...).Scan(&stats.ActionsThisWeek); err != nil {
+		return nil, fmt.Errorf("failed to count moderator actions: %w", err)
+	}
+
+	return &stats, nil
+}
+
 // --- Helpers ---
 
+func scanAuditLogs(rows *sql.Rows) ([]AuditLog, error) {
+	var entries []AuditLog
+	for rows.Next() {
+		var entry AuditLog
+		var details sql.NullString
+
+		if err := rows.Scan(&entry.ID, &entry.Action, &entry.UserID, &entry.Timestamp, &details); err != nil {
+			return nil, fmt.Errorf("failed to scan audit log entry: %w", err)
+		}
+		entry.Details = details.String
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
 func scanTradeBans(rows *sql.Rows) ([]TradeBan, error) {
 	var bans []TradeBan
 	for rows.Next() {
@@ -0,0 +1,269 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MarketOverview summarizes the whole market for the /overview command
+type MarketOverview struct {
+	ActiveMarketOrders int
+	ActivePlayerOrders int
+	TopPorts           []PortActivity
+	WidestSpreads      []ItemSpread
+	MostRequested      []ItemDemand
+	TopItems           []ItemActivity
+	LargestSpreads     []BuySellSpread
+	GeneratedAt        time.Time
+}
+
+// PortActivity is the number of active market orders at a port
+type PortActivity struct {
+	PortName string
+	Orders   int
+}
+
+// ItemSpread is the range of active sell prices for an item across ports
+type ItemSpread struct {
+	ItemName string
+	MinPrice int
+	MaxPrice int
+	Ports    int
+}
+
+// ItemActivity is the number of active market orders (buy and sell) for an item
+type ItemActivity struct {
+	ItemName string
+	Orders   int
+}
+
+// BuySellSpread compares an item's cheapest sell order with its best buy
+// order across all ports
+type BuySellSpread struct {
+	ItemName    string
+	MinSell     int
+	MinSellPort string
+	MaxBuy      int
+	MaxBuyPort  string
+}
+
+// Spread is the lowest sell price minus the highest buy price. A negative
+// spread means the item can be bought at one port and sold at a profit at another.
+func (s BuySellSpread) Spread() int {
+	return s.MinSell - s.MaxBuy
+}
+
+// ItemDemand is the number of active buy orders (market and player) for an item
+type ItemDemand struct {
+	ItemName  string
+	BuyOrders int
+}
+
+// GetMarketOverview assembles the market-wide aggregates. Each list is capped at limit rows.
+func (db *DB) GetMarketOverview(ctx context.Context, limit int) (*MarketOverview, error) {
+	overview := &MarketOverview{GeneratedAt: time.Now()}
+
+	err := db.conn.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM markets WHERE expires_at > datetime('now')`,
+	).Scan(&overview.ActiveMarketOrders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count market orders: %w", err)
+	}
+
+	err = db.conn.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM player_orders po WHERE `+livePlayerOrder,
+	).Scan(&overview.ActivePlayerOrders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count player orders: %w", err)
+	}
+
+	if overview.TopPorts, err = db.GetTopPortsByOrderCount(ctx, limit); err != nil {
+		return nil, err
+	}
+	if overview.WidestSpreads, err = db.getWidestSpreads(ctx, limit); err != nil {
+		return nil, err
+	}
+	if overview.MostRequested, err = db.getMostRequested(ctx, limit); err != nil {
+		return nil, err
+	}
+	if overview.TopItems, err = db.GetTopItemsByOrderCount(ctx, limit); err != nil {
+		return nil, err
+	}
+	if overview.LargestSpreads, err = db.GetLargestSpreads(ctx, limit); err != nil {
+		return nil, err
+	}
+
+	return overview, nil
+}
+
+// GetTopPortsByOrderCount returns the ports with the most active market orders
+func (db *DB) GetTopPortsByOrderCount(ctx context.Context, limit int) ([]PortActivity, error) {
+	query := `
+		SELECT p.display_name, COUNT(*) AS orders
+		FROM markets m
+		JOIN ports p ON m.port_id = p.id
+		WHERE m.expires_at > datetime('now')
+		GROUP BY m.port_id
+		ORDER BY orders DESC, p.display_name
+		LIMIT ?
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top ports: %w", err)
+	}
+	defer rows.Close()
+
+	var ports []PortActivity
+	for rows.Next() {
+		var p PortActivity
+		if err := rows.Scan(&p.PortName, &p.Orders); err != nil {
+			return nil, fmt.Errorf("failed to scan port activity: %w", err)
+		}
+		ports = append(ports, p)
+	}
+
+	return ports, rows.Err()
+}
+
+func (db *DB) getWidestSpreads(ctx context.Context, limit int) ([]ItemSpread, error) {
+	query := `
+		SELECT i.display_name, MIN(m.price), MAX(m.price), COUNT(DISTINCT m.port_id) AS ports
+		FROM markets m
+		JOIN items i ON m.item_id = i.id
+		WHERE m.order_type = 'sell' AND m.expires_at > datetime('now')
+		GROUP BY m.item_id
+		HAVING ports > 1
+		ORDER BY MAX(m.price) - MIN(m.price) DESC, i.display_name
+		LIMIT ?
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price spreads: %w", err)
+	}
+	defer rows.Close()
+
+	var spreads []ItemSpread
+	for rows.Next() {
+		var s ItemSpread
+		if err := rows.Scan(&s.ItemName, &s.MinPrice, &s.MaxPrice, &s.Ports); err != nil {
+			return nil, fmt.Errorf("failed to scan price spread: %w", err)
+		}
+		spreads = append(spreads, s)
+	}
+
+	return spreads, rows.Err()
+}
+
+func (db *DB) getMostRequested(ctx context.Context, limit int) ([]ItemDemand, error) {
+	query := `
+		SELECT i.display_name, COUNT(*) AS buy_orders
+		FROM (
+			SELECT item_id FROM markets
+			WHERE order_type = 'buy' AND expires_at > datetime('now')
+			UNION ALL
+			SELECT po.item_id FROM player_orders po
+			WHERE po.order_type = 'buy' AND ` + livePlayerOrder + `
+		) demand
+		JOIN items i ON demand.item_id = i.id
+		GROUP BY demand.item_id
+		ORDER BY buy_orders DESC, i.display_name
+		LIMIT ?
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query requested items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []ItemDemand
+	for rows.Next() {
+		var d ItemDemand
+		if err := rows.Scan(&d.ItemName, &d.BuyOrders); err != nil {
+			return nil, fmt.Errorf("failed to scan item demand: %w", err)
+		}
+		items = append(items, d)
+	}
+
+	return items, rows.Err()
+}
+
+// GetTopItemsByOrderCount returns the items with the most active market orders
+func (db *DB) GetTopItemsByOrderCount(ctx context.Context, limit int) ([]ItemActivity, error) {
+	query := `
+		SELECT i.display_name, COUNT(*) AS orders
+		FROM markets m
+		JOIN items i ON m.item_id = i.id
+		WHERE m.expires_at > datetime('now')
+		GROUP BY m.item_id
+		ORDER BY orders DESC, i.display_name
+		LIMIT ?
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []ItemActivity
+	for rows.Next() {
+		var a ItemActivity
+		if err := rows.Scan(&a.ItemName, &a.Orders); err != nil {
+			return nil, fmt.Errorf("failed to scan item activity: %w", err)
+		}
+		items = append(items, a)
+	}
+
+	return items, rows.Err()
+}
+
+// GetLargestSpreads returns items with both active buy and sell orders,
+// ordered by how far the best buy price exceeds the cheapest sell price, so
+// arbitrage opportunities come first. Ties on price name the first port alphabetically.
+func (db *DB) GetLargestSpreads(ctx context.Context, limit int) ([]BuySellSpread, error) {
+	query := `
+		WITH live AS (
+			SELECT m.item_id, m.order_type, m.price, p.display_name AS port
+			FROM markets m
+			JOIN ports p ON m.port_id = p.id
+			WHERE m.expires_at > datetime('now')
+		),
+		sells AS (
+			SELECT item_id, MIN(price) AS price FROM live WHERE order_type = 'sell' GROUP BY item_id
+		),
+		buys AS (
+			SELECT item_id, MAX(price) AS price FROM live WHERE order_type = 'buy' GROUP BY item_id
+		)
+		SELECT i.display_name,
+			s.price,
+			(SELECT MIN(port) FROM live l WHERE l.item_id = s.item_id AND l.order_type = 'sell' AND l.price = s.price),
+			b.price,
+			(SELECT MIN(port) FROM live l WHERE l.item_id = b.item_id AND l.order_type = 'buy' AND l.price = b.price)
+		FROM sells s
+		JOIN buys b ON b.item_id = s.item_id
+		JOIN items i ON i.id = s.item_id
+		ORDER BY b.price - s.price DESC, i.display_name
+		LIMIT ?
+	`
+
+	rows, err := db.conn.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query buy/sell spreads: %w", err)
+	}
+	defer rows.Close()
+
+	var spreads []BuySellSpread
+	for rows.Next() {
+		var s BuySellSpread
+		if err := rows.Scan(&s.ItemName, &s.MinSell, &s.MinSellPort, &s.MaxBuy, &s.MaxBuyPort); err != nil {
+			return nil, fmt.Errorf("failed to scan buy/sell spread: %w", err)
+		}
+		spreads = append(spreads, s)
+	}
+
+	return spreads, rows.Err()
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ItemPriceBounds are admin-set sanity limits for an item's price. Zero
+// means no limit on that side.
+type ItemPriceBounds struct {
+	ItemID    int
+	MinPrice  int
+	MaxPrice  int
+	SetBy     string
+	UpdatedAt time.Time
+}
+
+// createItemPriceBounds adds the item_price_bounds table
+func createItemPriceBounds(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS item_price_bounds (
+			item_id INTEGER PRIMARY KEY REFERENCES items(id) ON DELETE CASCADE,
+			min_price INTEGER NOT NULL DEFAULT 0,
+			max_price INTEGER NOT NULL DEFAULT 0,
+			set_by TEXT NOT NULL,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create item_price_bounds: %w", err)
+	}
+	return nil
+}
+
+// SetItemPriceBounds sets or replaces an item's price limits. Passing zero
+// for both removes them.
+func (db *DB) SetItemPriceBounds(ctx context.Context, itemID, minPrice, maxPrice int, setBy string) error {
+	if minPrice < 0 || maxPrice < 0 {
+		return fmt.Errorf("price bounds can't be negative")
+	}
+	if maxPrice > 0 && minPrice > maxPrice {
+		return fmt.Errorf("minimum price %d is above maximum price %d", minPrice, maxPrice)
+	}
+
+	if minPrice == 0 && maxPrice == 0 {
+		if _, err := db.conn.ExecContext(ctx, `DELETE FROM item_price_bounds WHERE item_id = ?`, itemID); err != nil {
+			return fmt.Errorf("failed to clear price bounds: %w", err)
+		}
+		return nil
+	}
+
+	_, err := db.conn.ExecContext(ctx, `
+		INSERT INTO item_price_bounds (item_id, min_price, max_price, set_by)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(item_id) DO UPDATE SET
+			min_price = excluded.min_price,
+			max_price = excluded.max_price,
+			set_by = excluded.set_by,
+			updated_at = CURRENT_TIMESTAMP
+	`, itemID, minPrice, maxPrice, setBy)
+	if err != nil {
+		return fmt.Errorf("failed to set price bounds: %w", err)
+	}
+	return nil
+}
+
+// GetItemPriceBounds returns an item's price limits, or nil if none are set
+func (db *DB) GetItemPriceBounds(ctx context.Context, itemID int) (*ItemPriceBounds, error) {
+	var bounds ItemPriceBounds
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT item_id, min_price, max_price, set_by, updated_at
+		FROM item_price_bounds WHERE item_id = ?
+	`, itemID).Scan(&bounds.ItemID, &bounds.MinPrice, &bounds.MaxPrice, &bounds.SetBy, &bounds.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price bounds: %w", err)
+	}
+	return &bounds, nil
+}
+
+// GetAverageMarketPrice averages an item's active market orders, buy and
+// sell alike. count is 0 when there are none.
+func (db *DB) GetAverageMarketPrice(ctx context.Context, itemID int) (avg float64, count int, err error) {
+	var average sql.NullFloat64
+	err = db.conn.QueryRowContext(ctx, `
+		SELECT AVG(price), COUNT(*) FROM markets
+		WHERE item_id = ? AND expires_at > datetime('now')
+	`, itemID).Scan(&average, &count)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to average market price: %w", err)
+	}
+	return average.Float64, count, nil
+}
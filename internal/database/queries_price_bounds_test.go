@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"testing"
+)
+
+func TestItemPriceBounds(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cannon := mustCreateItem(t, db, "Cannon")
+
+	if bounds, err := db.GetItemPriceBounds(ctx, cannon.ID); err != nil || bounds != nil {
+		t.Fatalf("expected no bounds initially, got %+v, %v", bounds, err)
+	}
+
+	if err := db.SetItemPriceBounds(ctx, cannon.ID, 50, 500, "admin1"); err != nil {
+		t.Fatalf("SetItemPriceBounds failed: %v", err)
+	}
+	if err := db.SetItemPriceBounds(ctx, cannon.ID, 60, 0, "admin2"); err != nil {
+		t.Fatalf("SetItemPriceBounds update failed: %v", err)
+	}
+	bounds, err := db.GetItemPriceBounds(ctx, cannon.ID)
+	if err != nil || bounds == nil {
+		t.Fatalf("GetItemPriceBounds failed: %+v, %v", bounds, err)
+	}
+	if bounds.MinPrice != 60 || bounds.MaxPrice != 0 || bounds.SetBy != "admin2" {
+		t.Errorf("expected updated bounds, got %+v", bounds)
+	}
+
+	if err := db.SetItemPriceBounds(ctx, cannon.ID, 500, 50, "admin1"); err == nil {
+		t.Error("expected min above max to be rejected")
+	}
+
+	// Zero for both clears the bounds
+	if err := db.SetItemPriceBounds(ctx, cannon.ID, 0, 0, "admin1"); err != nil {
+		t.Fatalf("clearing bounds failed: %v", err)
+	}
+	if bounds, _ := db.GetItemPriceBounds(ctx, cannon.ID); bounds != nil {
+		t.Errorf("expected bounds to be cleared, got %+v", bounds)
+	}
+}
+
+func TestGetAverageMarketPrice(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	port := mustCreatePort(t, db, "Port Royal")
+	cannon := mustCreateItem(t, db, "Cannon")
+
+	if avg, count, err := db.GetAverageMarketPrice(ctx, cannon.ID); err != nil || avg != 0 || count != 0 {
+		t.Fatalf("expected no average without orders, got %v, %d, %v", avg, count, err)
+	}
+
+	for orderType, price := range map[string]int{"buy": 80, "sell": 120} {
+		orders := []Market{{ItemID: cannon.ID, Price: price, Quantity: 1}}
+		if err := db.ReplacePortOrders(ctx, port.ID, orderType, orders, "user123", "hash", ""); err != nil {
+			t.Fatalf("failed to insert orders: %v", err)
+		}
+	}
+
+	avg, count, err := db.GetAverageMarketPrice(ctx, cannon.ID)
+	if err != nil {
+		t.Fatalf("GetAverageMarketPrice failed: %v", err)
+	}
+	if avg != 100 || count != 2 {
+		t.Errorf("expected an average of 100 over 2 orders, got %v over %d", avg, count)
+	}
+}
@@ -0,0 +1,212 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrUnknownRegion is returned when a region name doesn't match any known region
+var ErrUnknownRegion = errors.New("unknown region")
+
+// ErrRegionExists is returned when a new region duplicates (or nearly duplicates) a known one
+var ErrRegionExists = errors.New("region already exists")
+
+// minRegionPrefix is the shortest input that may resolve to a region by prefix ("carib")
+const minRegionPrefix = 3
+
+// --- Region Operations ---
+
+// CreateRegion adds a region to the known set. Names that normalize to, or
+// are a likely misspelling of, an existing region are rejected so regions
+// can't fragment again.
+func (db *DB) CreateRegion(ctx context.Context, name, addedBy string) (*Region, error) {
+	name = strings.TrimSpace(name)
+	if normalize(name) == "" {
+		return nil, fmt.Errorf("region name is empty")
+	}
+
+	regions, err := db.GetAllRegions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range regions {
+		if calculateSimilarity(normalize(name), normalize(r.Name)) >= HighConfidenceThreshold {
+			return nil, fmt.Errorf("%w: %s", ErrRegionExists, r.Name)
+		}
+	}
+
+	result, err := db.conn.ExecContext(ctx,
+		`INSERT INTO regions (name, added_by) VALUES (?, ?)`, name, addedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create region: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Region{
+		ID:      int(id),
+		Name:    name,
+		AddedAt: time.Now(),
+		AddedBy: addedBy,
+	}, nil
+}
+
+// GetAllRegions returns every known region ordered by name
+func (db *DB) GetAllRegions(ctx context.Context) ([]Region, error) {
+	return loadRegions(ctx, db.conn)
+}
+
+// loadRegions reads regions through a connection or transaction
+func loadRegions(ctx context.Context, q interface {
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+}) ([]Region, error) {
+	rows, err := q.QueryContext(ctx, `SELECT id, name, added_at, COALESCE(added_by, '') FROM regions ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get regions: %w", err)
+	}
+	defer rows.Close()
+
+	var regions []Region
+	for rows.Next() {
+		var r Region
+		if err := rows.Scan(&r.ID, &r.Name, &r.AddedAt, &r.AddedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan region: %w", err)
+		}
+		regions = append(regions, r)
+	}
+	return regions, rows.Err()
+}
+
+// ResolveRegion maps free-text input ("Caribbean", "caribean", "carib") to a
+// known region. Returns ErrUnknownRegion when nothing matches confidently.
+func (db *DB) ResolveRegion(ctx context.Context, name string) (*Region, error) {
+	regions, err := db.GetAllRegions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r := resolveRegion(name, regions); r != nil {
+		return r, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownRegion, name)
+}
+
+// resolveRegion matches by normalized name, then unique prefix, then the
+// closest name above the high-confidence threshold
+func resolveRegion(name string, regions []Region) *Region {
+	normalized := normalize(name)
+	if normalized == "" {
+		return nil
+	}
+
+	for idx := range regions {
+		if normalize(regions[idx].Name) == normalized {
+			return &regions[idx]
+		}
+	}
+
+	if len(normalized) >= minRegionPrefix {
+		var prefixMatch *Region
+		for idx := range regions {
+			if strings.HasPrefix(normalize(regions[idx].Name), normalized) {
+				if prefixMatch != nil {
+					prefixMatch = nil
+					break
+				}
+				prefixMatch = &regions[idx]
+			}
+		}
+		if prefixMatch != nil {
+			return prefixMatch
+		}
+	}
+
+	var best *Region
+	bestScore := HighConfidenceThreshold
+	for idx := range regions {
+		if score := calculateSimilarity(normalized, normalize(regions[idx].Name)); score >= bestScore {
+			best, bestScore = &regions[idx], score
+		}
+	}
+	return best
+}
+
+// resolvePortRegion resolves a port's region input to its canonical ID and
+// name. Empty input means no region.
+func (db *DB) resolvePortRegion(ctx context.Context, region string) (*int, string, error) {
+	if strings.TrimSpace(region) == "" {
+		return nil, "", nil
+	}
+	r, err := db.ResolveRegion(ctx, region)
+	if err != nil {
+		return nil, "", err
+	}
+	return &r.ID, r.Name, nil
+}
+
+// backfillRegions (migration 2) links ports that only have a free-text
+// region to a known region, creating regions from the most common spellings
+// first so variants like "caribean" fold into "Caribbean". Ports keep the
+// canonical name in their region column for display.
+func backfillRegions(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT region FROM ports
+		WHERE region_id IS NULL AND TRIM(COALESCE(region, '')) != ''
+		GROUP BY region
+		ORDER BY COUNT(*) DESC, region
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to find unlinked port regions: %w", err)
+	}
+	var spellings []string
+	for rows.Next() {
+		var region string
+		if err := rows.Scan(&region); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan port region: %w", err)
+		}
+		spellings = append(spellings, region)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(spellings) == 0 {
+		return nil
+	}
+
+	regions, err := loadRegions(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	for _, spelling := range spellings {
+		region := resolveRegion(spelling, regions)
+		if region == nil {
+			name := strings.TrimSpace(spelling)
+			result, err := tx.ExecContext(ctx, `INSERT INTO regions (name, added_by) VALUES (?, 'backfill')`, name)
+			if err != nil {
+				return fmt.Errorf("failed to create region %q: %w", name, err)
+			}
+			id, _ := result.LastInsertId()
+			regions = append(regions, Region{ID: int(id), Name: name, AddedBy: "backfill"})
+			region = &regions[len(regions)-1]
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`UPDATE ports SET region_id = ?, region = ? WHERE region = ? AND region_id IS NULL`,
+			region.ID, region.Name, spelling,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to link ports to region %q: %w", region.Name, err)
+		}
+	}
+	return nil
+}
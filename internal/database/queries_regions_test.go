@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestResolveRegion(t *testing.T) {
+	regions := []Region{
+		{ID: 1, Name: "Caribbean"},
+		{ID: 2, Name: "Mediterranean"},
+		{ID: 3, Name: "North Sea"},
+		{ID: 4, Name: "North Atlantic"},
+	}
+
+	tests := []struct {
+		input  string
+		wantID int
+	}{
+		{"Caribbean", 1},
+		{"  caribbean ", 1},
+		{"caribean", 1},
+		{"carib", 1},
+		{"Mediteranean", 2},
+		{"north sea", 3},
+		{"nor", 0}, // ambiguous prefix
+		{"ca", 0},  // too short for a prefix match
+		{"Pacific", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		got := resolveRegion(tt.input, regions)
+		gotID := 0
+		if got != nil {
+			gotID = got.ID
+		}
+		if gotID != tt.wantID {
+			t.Errorf("resolveRegion(%q) = region %d, want %d", tt.input, gotID, tt.wantID)
+		}
+	}
+}
+
+func TestCreatePortNormalizesRegion(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if _, err := db.CreatePort(ctx, "Port Royal", "Port Royal", "Caribbean", "test"); !errors.Is(err, ErrUnknownRegion) {
+		t.Fatalf("expected ErrUnknownRegion before the region exists, got %v", err)
+	}
+
+	region, err := db.CreateRegion(ctx, "Caribbean", "admin")
+	if err != nil {
+		t.Fatalf("CreateRegion failed: %v", err)
+	}
+	if _, err := db.CreateRegion(ctx, "caribean", "admin"); !errors.Is(err, ErrRegionExists) {
+		t.Errorf("expected near-duplicate region to be rejected, got %v", err)
+	}
+
+	port, err := db.CreatePort(ctx, "Port Royal", "Port Royal", "carib", "test")
+	if err != nil {
+		t.Fatalf("CreatePort failed: %v", err)
+	}
+	if port.RegionID != region.ID || port.Region != "Caribbean" {
+		t.Errorf("expected canonical Caribbean region, got %d %q", port.RegionID, port.Region)
+	}
+
+	if _, err := db.CreateRegion(ctx, "Mediterranean", "admin"); err != nil {
+		t.Fatalf("CreateRegion failed: %v", err)
+	}
+	if err := db.UpdatePort(ctx, port.ID, "", "mediteranean"); err != nil {
+		t.Fatalf("UpdatePort failed: %v", err)
+	}
+	updated, err := db.GetPortByName(ctx, "Port Royal")
+	if err != nil {
+		t.Fatalf("GetPortByName failed: %v", err)
+	}
+	if updated.Region != "Mediterranean" {
+		t.Errorf("expected port moved to Mediterranean, got %q", updated.Region)
+	}
+	if err := db.UpdatePort(ctx, port.ID, "", "Atlantis"); !errors.Is(err, ErrUnknownRegion) {
+		t.Errorf("expected ErrUnknownRegion for unknown region, got %v", err)
+	}
+}
+
+func TestBackfillRegions(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test-*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp db: %v", err)
+	}
+	tmpfile.Close()
+	defer os.Remove(tmpfile.Name())
+
+	db, err := New(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to initialize database: %v", err)
+	}
+
+	// Ports as older versions stored them: free-text regions only
+	ctx := context.Background()
+	for name, region := range map[string]string{
+		"Port Royal": "Caribbean",
+		"Tortuga":    "Caribbean",
+		"Havana":     "caribean",
+		"Nassau":     "carib",
+		"Malta":      "Mediterranean",
+	} {
+		_, err := db.conn.ExecContext(ctx,
+			`INSERT INTO ports (name, display_name, region) VALUES (?, ?, ?)`, name, name, region)
+		if err != nil {
+			t.Fatalf("failed to insert port: %v", err)
+		}
+	}
+	// ...in a database that predates schema versioning
+	if _, err := db.conn.ExecContext(ctx, `DROP TABLE schema_migrations`); err != nil {
+		t.Fatalf("failed to drop schema_migrations: %v", err)
+	}
+	db.Close()
+
+	db, err = New(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("failed to reopen database: %v", err)
+	}
+	defer db.Close()
+
+	regions, err := db.GetAllRegions(ctx)
+	if err != nil {
+		t.Fatalf("GetAllRegions failed: %v", err)
+	}
+	if len(regions) != 2 || regions[0].Name != "Caribbean" || regions[1].Name != "Mediterranean" {
+		t.Fatalf("expected Caribbean and Mediterranean, got %+v", regions)
+	}
+
+	ports, err := db.GetAllPorts(ctx)
+	if err != nil {
+		t.Fatalf("GetAllPorts failed: %v", err)
+	}
+	for _, p := range ports {
+		want := "Caribbean"
+		if p.Name == "Malta" {
+			want = "Mediterranean"
+		}
+		if p.Region != want || p.RegionID == 0 {
+			t.Errorf("%s: expected linked region %s, got %q (id %d)", p.Name, want, p.Region, p.RegionID)
+		}
+	}
+}
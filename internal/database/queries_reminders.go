@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// addOrderReminders adds player_orders.reminded_at, set once the owner has
+// been warned their order is about to expire
+func addOrderReminders(ctx context.Context, tx *sql.Tx) error {
+	exists, err := columnExists(ctx, tx, "player_orders", "reminded_at")
+	if err != nil || exists {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE player_orders ADD COLUMN reminded_at TIMESTAMP`); err != nil {
+		return fmt.Errorf("failed to add player_orders.reminded_at: %w", err)
+	}
+	return nil
+}
+
+// GetOrdersExpiringSoon returns live orders (with item and port) that expire
+// within the given window and whose owner hasn't been reminded yet, ordered
+// by owner
+func (db *DB) GetOrdersExpiringSoon(ctx context.Context, within time.Duration) ([]PlayerOrder, error) {
+	query := `
+		SELECT po.id, po.user_id, po.item_id, po.order_type, po.price, po.quantity,
+		       po.port_id, po.notes, po.ingame_name, po.status, po.created_at, po.expires_at, po.activates_at,
+		       i.name, i.display_name,
+		       p.name, p.display_name, p.region
+		FROM player_orders po
+		JOIN items i ON po.item_id = i.id
+		LEFT JOIN ports p ON po.port_id = p.id
+		WHERE ` + livePlayerOrder + `
+		  AND po.expires_at <= datetime('now', ?)
+		  AND po.reminded_at IS NULL
+		ORDER BY po.user_id, po.expires_at
+	`
+	rows, err := db.conn.QueryContext(ctx, query, fmt.Sprintf("+%d seconds", int(within.Seconds())))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orders expiring soon: %w", err)
+	}
+	defer rows.Close()
+	return scanPlayerOrdersWithJoins(rows)
+}
+
+// MarkOrdersReminded records that the owners of these orders have been told
+// they expire soon, so GetOrdersExpiringSoon skips them
+func (db *DB) MarkOrdersReminded(ctx context.Context, orderIDs []int) error {
+	if len(orderIDs) == 0 {
+		return nil
+	}
+	args := make([]interface{}, len(orderIDs))
+	for idx, id := range orderIDs {
+		args[idx] = id
+	}
+	query := `UPDATE player_orders SET reminded_at = CURRENT_TIMESTAMP WHERE id IN (?` + repeatPlaceholders(len(args)-1) + `)`
+	if _, err := db.conn.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to mark orders reminded: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetOrdersExpiringSoon(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+
+	soon := mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().Add(2*time.Hour))
+	mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().Add(48*time.Hour))
+	expired := mustCreatePlayerOrder(t, db, "seller2", item.ID, time.Now().Add(time.Hour))
+	expirePlayerOrderNow(t, db, expired.ID)
+	cancelled := mustCreatePlayerOrder(t, db, "seller2", item.ID, time.Now().Add(time.Hour))
+	if err := db.CancelPlayerOrder(ctx, cancelled.ID, "seller2"); err != nil {
+		t.Fatalf("CancelPlayerOrder failed: %v", err)
+	}
+
+	orders, err := db.GetOrdersExpiringSoon(ctx, 6*time.Hour)
+	if err != nil {
+		t.Fatalf("GetOrdersExpiringSoon failed: %v", err)
+	}
+	if len(orders) != 1 || orders[0].ID != soon.ID {
+		t.Fatalf("expected only order %d, got %+v", soon.ID, orders)
+	}
+	if orders[0].Item == nil || orders[0].Item.DisplayName != "Cannon" {
+		t.Errorf("expected the item joined, got %+v", orders[0].Item)
+	}
+
+	// Reminded orders aren't returned again
+	if err := db.MarkOrdersReminded(ctx, []int{soon.ID}); err != nil {
+		t.Fatalf("MarkOrdersReminded failed: %v", err)
+	}
+	orders, err = db.GetOrdersExpiringSoon(ctx, 6*time.Hour)
+	if err != nil {
+		t.Fatalf("GetOrdersExpiringSoon failed: %v", err)
+	}
+	if len(orders) != 0 {
+		t.Errorf("expected no orders after reminding, got %d", len(orders))
+	}
+}
+
+func TestExtendPlayerOrder(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+	order := mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().Add(time.Hour))
+	if err := db.MarkOrdersReminded(ctx, []int{order.ID}); err != nil {
+		t.Fatalf("MarkOrdersReminded failed: %v", err)
+	}
+
+	newExpiry := time.Now().Add(3 * 24 * time.Hour)
+	if err := db.ExtendPlayerOrder(ctx, order.ID, "seller1", newExpiry); err != nil {
+		t.Fatalf("ExtendPlayerOrder failed: %v", err)
+	}
+	got, err := db.GetPlayerOrder(ctx, order.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetPlayerOrder failed: %v", err)
+	}
+	if got.ExpiresAt.Sub(newExpiry).Abs() > time.Second {
+		t.Errorf("expected expiry %v, got %v", newExpiry, got.ExpiresAt)
+	}
+
+	// Extending re-arms the reminder for when the new expiry gets close
+	orders, err := db.GetOrdersExpiringSoon(ctx, 4*24*time.Hour)
+	if err != nil {
+		t.Fatalf("GetOrdersExpiringSoon failed: %v", err)
+	}
+	if len(orders) != 1 {
+		t.Errorf("expected the extended order to be remindable again, got %d", len(orders))
+	}
+
+	if err := db.ExtendPlayerOrder(ctx, order.ID, "seller2", newExpiry); !errors.Is(err, ErrNotOrderOwner) {
+		t.Errorf("expected ErrNotOrderOwner, got %v", err)
+	}
+	expirePlayerOrderNow(t, db, order.ID)
+	if err := db.ExtendPlayerOrder(ctx, order.ID, "seller1", newExpiry); !errors.Is(err, ErrOrderExpired) {
+		t.Errorf("expected ErrOrderExpired, got %v", err)
+	}
+	if err := db.ExtendPlayerOrder(ctx, order.ID, "seller1", time.Now().Add(-time.Hour)); !errors.Is(err, ErrExpiryNotInFuture) {
+		t.Errorf("expected ErrExpiryNotInFuture, got %v", err)
+	}
+}
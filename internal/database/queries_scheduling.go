@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// addOrderActivation adds player_orders.activates_at for orders scheduled to
+// start later. The status column keeps its original CHECK: rebuilding
+// player_orders would cascade deletes into conversations and interest, so
+// "pending_start" is derived from activates_at the way "expired" is derived
+// from expires_at.
+func addOrderActivation(ctx context.Context, tx *sql.Tx) error {
+	exists, err := columnExists(ctx, tx, "player_orders", "activates_at")
+	if err != nil || exists {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE player_orders ADD COLUMN activates_at TIMESTAMP`); err != nil {
+		return fmt.Errorf("failed to add player_orders.activates_at: %w", err)
+	}
+	return nil
+}
+
+// ActivateScheduledPlayerOrders moves scheduled orders whose start time has
+// passed from pending_start to active, and returns them (with item and port)
+// so owners can be told they're live
+func (db *DB) ActivateScheduledPlayerOrders(ctx context.Context) ([]PlayerOrder, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT po.id, po.user_id, po.item_id, po.order_type, po.price, po.quantity,
+		       po.port_id, po.notes, po.ingame_name, po.status, po.created_at, po.expires_at, po.activates_at,
+		       i.name, i.display_name,
+		       p.name, p.display_name, p.region
+		FROM player_orders po
+		JOIN items i ON po.item_id = i.id
+		LEFT JOIN ports p ON po.port_id = p.id
+		WHERE po.activates_at IS NOT NULL AND ` + livePlayerOrder + `
+		ORDER BY po.user_id, po.id
+	`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find scheduled player orders: %w", err)
+	}
+	orders, err := scanPlayerOrdersWithJoins(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+
+	// Update exactly the rows found above so the result matches what changed
+	args := make([]interface{}, len(orders))
+	for idx := range orders {
+		orders[idx].ActivatesAt = nil
+		args[idx] = orders[idx].ID
+	}
+	update := `UPDATE player_orders SET activates_at = NULL WHERE id IN (?` + repeatPlaceholders(len(args)-1) + `)`
+	if _, err := tx.ExecContext(ctx, update, args...); err != nil {
+		return nil, fmt.Errorf("failed to activate player orders: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return orders, nil
+}
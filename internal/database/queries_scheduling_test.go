@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustCreateScheduledOrder(t *testing.T, db *DB, userID string, itemID int, startsIn time.Duration) *PlayerOrder {
+	t.Helper()
+	activatesAt := time.Now().Add(startsIn)
+	order, err := db.CreatePlayerOrder(context.Background(), PlayerOrder{
+		UserID:      userID,
+		ItemID:      itemID,
+		OrderType:   "sell",
+		Price:       100,
+		Quantity:    10,
+		IngameName:  "Captain " + userID,
+		ExpiresAt:   activatesAt.Add(24 * time.Hour),
+		ActivatesAt: &activatesAt,
+	})
+	if err != nil {
+		t.Fatalf("failed to create scheduled order: %v", err)
+	}
+	return order
+}
+
+func TestScheduledOrderPendingStart(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+	order := mustCreateScheduledOrder(t, db, "seller1", item.ID, time.Hour)
+
+	results, err := db.SearchPlayerOrders(ctx, 0, "", 0, "", 0, 0, "", 10)
+	if err != nil {
+		t.Fatalf("SearchPlayerOrders failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected a scheduled order to be hidden from search, got %d", len(results))
+	}
+	if got, err := db.GetPlayerOrder(ctx, order.ID); err != nil || got != nil {
+		t.Errorf("expected no live order before the start, got %+v (%v)", got, err)
+	}
+	if _, err := db.CheckPlayerOrder(ctx, order.ID); !errors.Is(err, ErrOrderNotStarted) {
+		t.Errorf("expected ErrOrderNotStarted, got %v", err)
+	}
+
+	// The owner still sees it, it counts against their cap, and it stays out of history
+	mine, err := db.GetPlayerOrdersByUser(ctx, "seller1")
+	if err != nil {
+		t.Fatalf("GetPlayerOrdersByUser failed: %v", err)
+	}
+	if len(mine) != 1 || mine[0].ActivatesAt == nil {
+		t.Errorf("expected the owner to see the scheduled order with its start, got %+v", mine)
+	}
+	if active, err := db.CountActivePlayerOrders(ctx, "seller1"); err != nil || active != 1 {
+		t.Errorf("expected 1 active order, got %d (%v)", active, err)
+	}
+	history, err := db.GetPlayerOrderHistory(ctx, "seller1", nil, 10)
+	if err != nil {
+		t.Fatalf("GetPlayerOrderHistory failed: %v", err)
+	}
+	if len(history) != 0 {
+		t.Errorf("expected no history for a pending order, got %+v", history)
+	}
+	if expired, err := db.DeleteExpiredPlayerOrders(ctx); err != nil || len(expired) != 0 {
+		t.Errorf("expected the expiry job to leave it alone, got %d (%v)", len(expired), err)
+	}
+
+	// Nothing is due yet
+	if activated, err := db.ActivateScheduledPlayerOrders(ctx); err != nil || len(activated) != 0 {
+		t.Errorf("expected nothing to activate, got %d (%v)", len(activated), err)
+	}
+
+	if err := db.CancelPlayerOrder(ctx, order.ID, "seller2"); !errors.Is(err, ErrNotOrderOwner) {
+		t.Errorf("expected ErrNotOrderOwner, got %v", err)
+	}
+	if err := db.CancelPlayerOrder(ctx, order.ID, "seller1"); err != nil {
+		t.Errorf("expected the owner to cancel before the start, got %v", err)
+	}
+}
+
+func TestActivateScheduledPlayerOrders(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+	order := mustCreateScheduledOrder(t, db, "seller1", item.ID, time.Hour)
+	mustCreatePlayerOrder(t, db, "seller2", item.ID, time.Now().Add(time.Hour))
+
+	if _, err := db.conn.ExecContext(ctx,
+		`UPDATE player_orders SET activates_at = datetime('now', '-1 second') WHERE id = ?`, order.ID,
+	); err != nil {
+		t.Fatalf("failed to backdate start: %v", err)
+	}
+
+	// Reads don't wait for the activation job
+	results, err := db.SearchPlayerOrders(ctx, 0, "", 0, "", 0, 0, "", 10)
+	if err != nil {
+		t.Fatalf("SearchPlayerOrders failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("expected the started order in search, got %d orders", len(results))
+	}
+
+	activated, err := db.ActivateScheduledPlayerOrders(ctx)
+	if err != nil {
+		t.Fatalf("ActivateScheduledPlayerOrders failed: %v", err)
+	}
+	if len(activated) != 1 || activated[0].ID != order.ID || activated[0].ActivatesAt != nil {
+		t.Fatalf("expected order %d activated, got %+v", order.ID, activated)
+	}
+	if activated[0].Item == nil || activated[0].Item.DisplayName != "Cannon" {
+		t.Errorf("expected the item joined, got %+v", activated[0].Item)
+	}
+
+	if again, err := db.ActivateScheduledPlayerOrders(ctx); err != nil || len(again) != 0 {
+		t.Errorf("expected each order activated once, got %d (%v)", len(again), err)
+	}
+}
+
+func TestCreateScheduledOrderRejectsExpiryBeforeStart(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	item := mustCreateItem(t, db, "Cannon")
+	activatesAt := time.Now().Add(48 * time.Hour)
+	_, err := db.CreatePlayerOrder(context.Background(), PlayerOrder{
+		UserID:      "seller1",
+		ItemID:      item.ID,
+		OrderType:   "sell",
+		Price:       100,
+		Quantity:    1,
+		IngameName:  "Captain",
+		ExpiresAt:   time.Now().Add(24 * time.Hour),
+		ActivatesAt: &activatesAt,
+	})
+	if !errors.Is(err, ErrExpiryNotInFuture) {
+		t.Errorf("expected ErrExpiryNotInFuture, got %v", err)
+	}
+}
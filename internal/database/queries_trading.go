@@ -3,10 +3,59 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
+// ErrExpiryNotInFuture is returned when an order would be inserted already expired.
+// Such an order is invisible to every read but would still count against
+// duplicate checks until the expiry job removed it.
+var ErrExpiryNotInFuture = errors.New("expiry must be in the future")
+
+// checkExpiry rejects zero and non-future expiry times
+func checkExpiry(expiresAt time.Time) error {
+	if expiresAt.IsZero() || !expiresAt.After(time.Now()) {
+		return fmt.Errorf("%w (got %v)", ErrExpiryNotInFuture, expiresAt)
+	}
+	return nil
+}
+
+// ErrAlreadyRated is returned when a user rates the same conversation twice
+var ErrAlreadyRated = errors.New("conversation already rated")
+
+// ErrNotConversationParty is returned when a user rates a conversation they weren't part of
+var ErrNotConversationParty = errors.New("not a party to this conversation")
+
+// Errors explaining why a trade command can't use an order. GetPlayerOrder
+// only returns live orders; CheckPlayerOrder tells these cases apart.
+var (
+	ErrOrderNotFound   = errors.New("order not found")
+	ErrOrderNotStarted = errors.New("order hasn't started yet")
+	ErrOrderExpired    = errors.New("order has expired")
+	ErrOrderCompleted  = errors.New("order is already completed")
+	ErrOrderCancelled  = errors.New("order was cancelled")
+	ErrNotOrderOwner   = errors.New("order belongs to another user")
+)
+
+// openPlayerOrder is an order that hasn't expired, been completed or been
+// cancelled. Unlike livePlayerOrder it includes scheduled orders that haven't
+// started yet, which their owner can still see and cancel.
+const openPlayerOrder = `po.status = 'active' AND po.expires_at > datetime('now')`
+
+// pendingStartOrder is an open order scheduled to start later. Nothing
+// changes in storage when the time comes, so reads never depend on the
+// activation job having run.
+const pendingStartOrder = `po.activates_at > datetime('now')`
+
+// livePlayerOrder is the single definition of an order that is still tradeable.
+// Reads, status changes and the expiry job all use it (or its negation) so an
+// order can never be shown as active after its expiry has passed, or before
+// its scheduled start.
+const livePlayerOrder = openPlayerOrder + ` AND (po.activates_at IS NULL OR po.activates_at <= datetime('now'))`
+
 // --- Player Profile Operations ---
 
 // GetPlayerProfile retrieves a player's profile by Discord user ID
@@ -42,17 +91,45 @@ func (db *DB) SetPlayerProfile(ctx context.Context, userID, ingameName string) e
 	return nil
 }
 
+// IsIngameNameTaken reports whether another user already uses name,
+// compared case-insensitively
+func (db *DB) IsIngameNameTaken(ctx context.Context, name, excludeUserID string) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM player_profiles
+			WHERE ingame_name = ? COLLATE NOCASE AND user_id != ?
+		)
+	`
+	var taken bool
+	if err := db.conn.QueryRowContext(ctx, query, name, excludeUserID).Scan(&taken); err != nil {
+		return false, fmt.Errorf("failed to check in-game name: %w", err)
+	}
+	return taken, nil
+}
+
 // --- Player Order Operations ---
 
 // CreatePlayerOrder inserts a new player trade order
 func (db *DB) CreatePlayerOrder(ctx context.Context, order PlayerOrder) (*PlayerOrder, error) {
+	if err := checkExpiry(order.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	var activatesAt sql.NullTime
+	if order.ActivatesAt != nil {
+		if !order.ExpiresAt.After(*order.ActivatesAt) {
+			return nil, fmt.Errorf("%w (got %v, starting %v)", ErrExpiryNotInFuture, order.ExpiresAt, *order.ActivatesAt)
+		}
+		activatesAt = sql.NullTime{Time: order.ActivatesAt.UTC(), Valid: true}
+	}
+
 	query := `
-		INSERT INTO player_orders (user_id, item_id, order_type, price, quantity, port_id, notes, ingame_name, expires_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO player_orders (user_id, item_id, order_type, price, quantity, port_id, notes, ingame_name, expires_at, activates_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	result, err := db.conn.ExecContext(ctx, query,
 		order.UserID, order.ItemID, order.OrderType, order.Price, order.Quantity,
-		order.PortID, order.Notes, order.IngameName, order.ExpiresAt,
+		order.PortID, order.Notes, order.IngameName, order.ExpiresAt.UTC(), activatesAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create player order: %w", err)
@@ -69,28 +146,56 @@ func (db *DB) CreatePlayerOrder(ctx context.Context, order PlayerOrder) (*Player
 	return &order, nil
 }
 
+// CheckPlayerOrder returns who owns an order if it is still live, or
+// ErrOrderNotFound, ErrOrderNotStarted, ErrOrderExpired, ErrOrderCompleted
+// or ErrOrderCancelled
+func (db *DB) CheckPlayerOrder(ctx context.Context, orderID int) (string, error) {
+	query := `SELECT po.user_id, ` + historyStatus + ` FROM player_orders po WHERE po.id = ?`
+	var ownerID, status string
+	err := db.conn.QueryRowContext(ctx, query, orderID).Scan(&ownerID, &status)
+	if err == sql.ErrNoRows {
+		return "", ErrOrderNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to check player order: %w", err)
+	}
+
+	switch status {
+	case "active":
+		return ownerID, nil
+	case "pending_start":
+		return ownerID, ErrOrderNotStarted
+	case "completed":
+		return ownerID, ErrOrderCompleted
+	case "cancelled":
+		return ownerID, ErrOrderCancelled
+	default:
+		return ownerID, ErrOrderExpired
+	}
+}
+
 // GetPlayerOrder retrieves a single order by ID (with item/port joins)
 func (db *DB) GetPlayerOrder(ctx context.Context, orderID int) (*PlayerOrder, error) {
 	query := `
 		SELECT po.id, po.user_id, po.item_id, po.order_type, po.price, po.quantity,
-		       po.port_id, po.notes, po.ingame_name, po.status, po.created_at, po.expires_at,
+		       po.port_id, po.notes, po.ingame_name, po.status, po.created_at, po.expires_at, po.activates_at,
 		       i.name, i.display_name,
 		       p.name, p.display_name, p.region
 		FROM player_orders po
 		JOIN items i ON po.item_id = i.id
 		LEFT JOIN ports p ON po.port_id = p.id
-		WHERE po.id = ? AND po.status = 'active' AND po.expires_at > datetime('now')
-	`
+		WHERE po.id = ? AND ` + livePlayerOrder
 
 	var po PlayerOrder
 	var portID sql.NullInt64
 	var notes sql.NullString
+	var activatesAt sql.NullTime
 	var itemName, itemDisplay string
 	var portName, portDisplay, portRegion sql.NullString
 
 	err := db.conn.QueryRowContext(ctx, query, orderID).Scan(
 		&po.ID, &po.UserID, &po.ItemID, &po.OrderType, &po.Price, &po.Quantity,
-		&portID, &notes, &po.IngameName, &po.Status, &po.CreatedAt, &po.ExpiresAt,
+		&portID, &notes, &po.IngameName, &po.Status, &po.CreatedAt, &po.ExpiresAt, &activatesAt,
 		&itemName, &itemDisplay,
 		&portName, &portDisplay, &portRegion,
 	)
@@ -110,20 +215,24 @@ func (db *DB) GetPlayerOrder(ctx context.Context, orderID int) (*PlayerOrder, er
 	if notes.Valid {
 		po.Notes = notes.String
 	}
+	if activatesAt.Valid {
+		po.ActivatesAt = &activatesAt.Time
+	}
 	return &po, nil
 }
 
-// GetPlayerOrdersByUser retrieves all active orders for a specific user
+// GetPlayerOrdersByUser retrieves all active orders for a specific user,
+// including scheduled ones that haven't started yet
 func (db *DB) GetPlayerOrdersByUser(ctx context.Context, userID string) ([]PlayerOrder, error) {
 	query := `
 		SELECT po.id, po.user_id, po.item_id, po.order_type, po.price, po.quantity,
-		       po.port_id, po.notes, po.ingame_name, po.status, po.created_at, po.expires_at,
+		       po.port_id, po.notes, po.ingame_name, po.status, po.created_at, po.expires_at, po.activates_at,
 		       i.name, i.display_name,
 		       p.name, p.display_name, p.region
 		FROM player_orders po
 		JOIN items i ON po.item_id = i.id
 		LEFT JOIN ports p ON po.port_id = p.id
-		WHERE po.user_id = ? AND po.status = 'active' AND po.expires_at > datetime('now')
+		WHERE po.user_id = ? AND ` + openPlayerOrder + `
 		ORDER BY po.created_at DESC
 	`
 	rows, err := db.conn.QueryContext(ctx, query, userID)
@@ -134,18 +243,134 @@ func (db *DB) GetPlayerOrdersByUser(ctx context.Context, userID string) ([]Playe
 	return scanPlayerOrdersWithJoins(rows)
 }
 
-// SearchPlayerOrders searches orders with optional filters
-func (db *DB) SearchPlayerOrders(ctx context.Context, itemID int, orderType string, portID int, minPrice int, maxPrice int, limit int) ([]PlayerOrder, error) {
+// historyStatus derives an order's final status for history views. The
+// expiry job cancels orders without setting cancelled_at, which is how an
+// expired order is told apart from one its owner cancelled. Open orders are
+// "active", or "pending_start" until their scheduled start.
+const historyStatus = `
+	CASE
+		WHEN po.status = 'completed' THEN 'completed'
+		WHEN po.status = 'cancelled' AND po.cancelled_at IS NOT NULL THEN 'cancelled'
+		WHEN ` + openPlayerOrder + ` AND ` + pendingStartOrder + ` THEN 'pending_start'
+		WHEN ` + livePlayerOrder + ` THEN 'active'
+		ELSE 'expired'
+	END`
+
+// GetPlayerOrderHistory retrieves a user's orders that are no longer active,
+// most recently closed first. statuses filters by final status ("completed",
+// "cancelled", "expired"); empty means all of them.
+func (db *DB) GetPlayerOrderHistory(ctx context.Context, userID string, statuses []string, limit int) ([]PlayerOrder, error) {
+	query := `
+		SELECT * FROM (
+			SELECT po.id, po.user_id, po.item_id, po.order_type, po.price, po.quantity,
+			       po.port_id, po.notes, po.ingame_name, ` + historyStatus + ` AS final_status,
+			       po.created_at, po.expires_at, po.completed_at, po.cancelled_at,
+			       i.name, i.display_name,
+			       p.name, p.display_name, p.region
+			FROM player_orders po
+			JOIN items i ON po.item_id = i.id
+			LEFT JOIN ports p ON po.port_id = p.id
+			WHERE po.user_id = ?
+		)
+	`
+	args := []interface{}{userID}
+	if len(statuses) > 0 {
+		query += ` WHERE final_status IN (?` + repeatPlaceholders(len(statuses)-1) + `)`
+		for _, status := range statuses {
+			args = append(args, status)
+		}
+	} else {
+		query += ` WHERE final_status NOT IN ('active', 'pending_start')`
+	}
+	query += ` ORDER BY COALESCE(completed_at, cancelled_at, expires_at) DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order history: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []PlayerOrder
+	for rows.Next() {
+		var po PlayerOrder
+		var portID sql.NullInt64
+		var notes sql.NullString
+		var completedAt, cancelledAt sql.NullTime
+		var itemName, itemDisplay string
+		var portName, portDisplay, portRegion sql.NullString
+
+		err := rows.Scan(
+			&po.ID, &po.UserID, &po.ItemID, &po.OrderType, &po.Price, &po.Quantity,
+			&portID, &notes, &po.IngameName, &po.Status,
+			&po.CreatedAt, &po.ExpiresAt, &completedAt, &cancelledAt,
+			&itemName, &itemDisplay,
+			&portName, &portDisplay, &portRegion,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan player order: %w", err)
+		}
+
+		po.Item = &Item{ID: po.ItemID, Name: itemName, DisplayName: itemDisplay}
+		if portID.Valid {
+			id := int(portID.Int64)
+			po.PortID = &id
+			po.Port = &Port{ID: id, Name: portName.String, DisplayName: portDisplay.String, Region: portRegion.String}
+		}
+		if notes.Valid {
+			po.Notes = notes.String
+		}
+		if completedAt.Valid {
+			po.CompletedAt = &completedAt.Time
+		}
+		if cancelledAt.Valid {
+			po.CancelledAt = &cancelledAt.Time
+		}
+		orders = append(orders, po)
+	}
+	return orders, rows.Err()
+}
+
+// containsPattern is a LIKE pattern matching text anywhere, with LIKE's
+// wildcards in text escaped. Use it with ESCAPE '\'.
+func containsPattern(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(text) + "%"
+}
+
+// PlayerOrderSorts maps each SearchPlayerOrders sort to its ORDER BY. Ties
+// fall back to newest first.
+var PlayerOrderSorts = map[string]string{
+	"newest":     `po.created_at DESC, po.id DESC`,
+	"price-asc":  `po.price ASC, po.created_at DESC, po.id DESC`,
+	"price-desc": `po.price DESC, po.created_at DESC, po.id DESC`,
+	"expiring":   `po.expires_at ASC, po.created_at DESC, po.id DESC`,
+}
+
+// ErrUnknownSort is returned for a sort not in PlayerOrderSorts
+var ErrUnknownSort = errors.New("unknown sort")
+
+// SearchPlayerOrders searches orders with optional filters. trader matches
+// any part of the in-game name on the order, ignoring case. sort is a key of
+// PlayerOrderSorts; empty means newest first.
+func (db *DB) SearchPlayerOrders(ctx context.Context, itemID int, orderType string, portID int, trader string, minPrice int, maxPrice int, sort string, limit int) ([]PlayerOrder, error) {
+	if sort == "" {
+		sort = "newest"
+	}
+	orderBy, ok := PlayerOrderSorts[sort]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownSort, sort)
+	}
+
 	query := `
 		SELECT po.id, po.user_id, po.item_id, po.order_type, po.price, po.quantity,
-		       po.port_id, po.notes, po.ingame_name, po.status, po.created_at, po.expires_at,
+		       po.port_id, po.notes, po.ingame_name, po.status, po.created_at, po.expires_at, po.activates_at,
 		       i.name, i.display_name,
 		       p.name, p.display_name, p.region
 		FROM player_orders po
 		JOIN items i ON po.item_id = i.id
 		LEFT JOIN ports p ON po.port_id = p.id
-		WHERE po.status = 'active' AND po.expires_at > datetime('now')
-	`
+		WHERE ` + livePlayerOrder
 	args := []interface{}{}
 
 	if itemID > 0 {
@@ -160,6 +385,10 @@ func (db *DB) SearchPlayerOrders(ctx context.Context, itemID int, orderType stri
 		query += ` AND po.port_id = ?`
 		args = append(args, portID)
 	}
+	if trader != "" {
+		query += ` AND po.ingame_name LIKE ? ESCAPE '\'`
+		args = append(args, containsPattern(trader))
+	}
 	if minPrice > 0 {
 		query += ` AND po.price >= ?`
 		args = append(args, minPrice)
@@ -169,13 +398,18 @@ func (db *DB) SearchPlayerOrders(ctx context.Context, itemID int, orderType stri
 		args = append(args, maxPrice)
 	}
 
-	query += ` ORDER BY po.created_at DESC`
+	query += ` ORDER BY ` + orderBy
 	if limit <= 0 {
 		limit = 25
 	}
-	query += fmt.Sprintf(` LIMIT %d`, limit)
+	query += ` LIMIT ?`
+	args = append(args, limit)
 
-	rows, err := db.conn.QueryContext(ctx, query, args...)
+	stmt, err := db.prepared(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search player orders: %w", err)
 	}
@@ -183,38 +417,238 @@ func (db *DB) SearchPlayerOrders(ctx context.Context, itemID int, orderType stri
 	return scanPlayerOrdersWithJoins(rows)
 }
 
-// CancelPlayerOrder sets an order's status to "cancelled" (only owner can cancel)
+// CountActivePlayerOrders returns how many open orders a user has, counting
+// scheduled ones that haven't started yet
+func (db *DB) CountActivePlayerOrders(ctx context.Context, userID string) (int, error) {
+	query := `SELECT COUNT(*) FROM player_orders po WHERE po.user_id = ? AND ` + openPlayerOrder
+	var count int
+	if err := db.conn.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count active orders: %w", err)
+	}
+	return count, nil
+}
+
+// CountCompletedPlayerOrders returns how many of a user's orders were marked completed
+func (db *DB) CountCompletedPlayerOrders(ctx context.Context, userID string) (int, error) {
+	query := `SELECT COUNT(*) FROM player_orders WHERE user_id = ? AND status = 'completed'`
+	var count int
+	if err := db.conn.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count completed orders: %w", err)
+	}
+	return count, nil
+}
+
+// CancelPlayerOrder sets an order's status to "cancelled" (only owner can cancel).
+// Scheduled orders can be cancelled before they start. If nothing was
+// cancelled the error says why: one of CheckPlayerOrder's errors, or
+// ErrNotOrderOwner.
 func (db *DB) CancelPlayerOrder(ctx context.Context, orderID int, userID string) error {
-	query := `UPDATE player_orders SET status = 'cancelled' WHERE id = ? AND user_id = ? AND status = 'active'`
+	query := `UPDATE player_orders AS po SET status = 'cancelled', cancelled_at = CURRENT_TIMESTAMP WHERE po.id = ? AND po.user_id = ? AND ` + openPlayerOrder
 	result, err := db.conn.ExecContext(ctx, query, orderID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("order not found or not owned by you")
+		ownerID, err := db.CheckPlayerOrder(ctx, orderID)
+		if err != nil && !errors.Is(err, ErrOrderNotStarted) {
+			return err
+		}
+		if ownerID != userID {
+			return ErrNotOrderOwner
+		}
+		return fmt.Errorf("order %d could not be cancelled", orderID)
+	}
+	return nil
+}
+
+// ExtendPlayerOrder moves a live order's expiry to expiresAt (only owner can
+// extend) and re-arms its expiry reminder. Errors are as for CancelPlayerOrder.
+func (db *DB) ExtendPlayerOrder(ctx context.Context, orderID int, userID string, expiresAt time.Time) error {
+	if err := checkExpiry(expiresAt); err != nil {
+		return err
+	}
+
+	query := `UPDATE player_orders AS po SET expires_at = ?, reminded_at = NULL WHERE po.id = ? AND po.user_id = ? AND ` + livePlayerOrder
+	result, err := db.conn.ExecContext(ctx, query, expiresAt.UTC(), orderID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to extend order: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		ownerID, err := db.CheckPlayerOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if ownerID != userID {
+			return ErrNotOrderOwner
+		}
+		return fmt.Errorf("order %d could not be extended", orderID)
 	}
 	return nil
 }
 
-// CompletePlayerOrder sets an order's status to "completed"
-func (db *DB) CompletePlayerOrder(ctx context.Context, orderID int, userID string) error {
-	query := `UPDATE player_orders SET status = 'completed' WHERE id = ? AND user_id = ? AND status = 'active'`
-	_, err := db.conn.ExecContext(ctx, query, orderID, userID)
+// CompletePlayerOrder sets an order's status to "completed" (only owner can complete)
+// and records who the trade was with, if known (counterpartyID may be empty).
+func (db *DB) CompletePlayerOrder(ctx context.Context, orderID int, userID, counterpartyID string) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE player_orders AS po
+		SET status = 'completed', completed_at = CURRENT_TIMESTAMP, counterparty_user_id = ?
+		WHERE po.id = ? AND po.user_id = ? AND ` + livePlayerOrder
+	counterparty := sql.NullString{String: counterpartyID, Valid: counterpartyID != ""}
+	result, err := tx.ExecContext(ctx, query, counterparty, orderID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to complete order: %w", err)
 	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("order not found or not owned by you")
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"order_id":     orderID,
+		"counterparty": counterpartyID,
+	})
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO audit_log (action, user_id, details) VALUES (?, ?, ?)`,
+		"trades_completed", userID, string(details),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to log action: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
-// DeleteExpiredPlayerOrders removes expired player orders
-func (db *DB) DeleteExpiredPlayerOrders(ctx context.Context) (int64, error) {
-	query := `UPDATE player_orders SET status = 'cancelled' WHERE status = 'active' AND expires_at <= datetime('now')`
-	result, err := db.conn.ExecContext(ctx, query)
+// DeleteExpiredPlayerOrders marks active orders whose expiry has passed as
+// cancelled and returns them (with item and port) so owners can be told
+func (db *DB) DeleteExpiredPlayerOrders(ctx context.Context) ([]PlayerOrder, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT po.id, po.user_id, po.item_id, po.order_type, po.price, po.quantity,
+		       po.port_id, po.notes, po.ingame_name, po.status, po.created_at, po.expires_at, po.activates_at,
+		       i.name, i.display_name,
+		       p.name, p.display_name, p.region
+		FROM player_orders po
+		JOIN items i ON po.item_id = i.id
+		LEFT JOIN ports p ON po.port_id = p.id
+		WHERE po.status = 'active' AND NOT (` + openPlayerOrder + `)
+		ORDER BY po.user_id, po.id
+	`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired player orders: %w", err)
+	}
+	orders, err := scanPlayerOrdersWithJoins(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+
+	// Update exactly the rows found above so the result matches what changed
+	args := make([]interface{}, len(orders))
+	for idx := range orders {
+		orders[idx].Status = "cancelled"
+		args[idx] = orders[idx].ID
+	}
+	update := `UPDATE player_orders SET status = 'cancelled' WHERE id IN (?` + repeatPlaceholders(len(args)-1) + `)`
+	if _, err := tx.ExecContext(ctx, update, args...); err != nil {
+		return nil, fmt.Errorf("failed to expire player orders: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return orders, nil
+}
+
+// CountOpenPlayerOrdersByPort returns how many open player orders are set
+// at a port, scheduled ones included
+func (db *DB) CountOpenPlayerOrdersByPort(ctx context.Context, portID int) (int, error) {
+	query := `SELECT COUNT(*) FROM player_orders po WHERE po.port_id = ? AND ` + openPlayerOrder
+	var count int
+	if err := db.conn.QueryRowContext(ctx, query, portID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count port player orders: %w", err)
+	}
+	return count, nil
+}
+
+// PurgePlayerOrders cancels every open player order at a port, for when the
+// port is being retired, and returns them (with item and port) so owners can
+// be told. Orders are cancelled rather than deleted so conversations and
+// reports about them keep their order.
+func (db *DB) PurgePlayerOrders(ctx context.Context, portID int, adminUserID string) ([]PlayerOrder, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT po.id, po.user_id, po.item_id, po.order_type, po.price, po.quantity,
+		       po.port_id, po.notes, po.ingame_name, po.status, po.created_at, po.expires_at, po.activates_at,
+		       i.name, i.display_name,
+		       p.name, p.display_name, p.region
+		FROM player_orders po
+		JOIN items i ON po.item_id = i.id
+		LEFT JOIN ports p ON po.port_id = p.id
+		WHERE po.port_id = ? AND ` + openPlayerOrder + `
+		ORDER BY po.user_id, po.id
+	`
+	rows, err := tx.QueryContext(ctx, query, portID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to expire player orders: %w", err)
+		return nil, fmt.Errorf("failed to find port player orders: %w", err)
 	}
-	return result.RowsAffected()
+	orders, err := scanPlayerOrdersWithJoins(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(orders) > 0 {
+		args := make([]interface{}, len(orders))
+		for idx := range orders {
+			orders[idx].Status = "cancelled"
+			args[idx] = orders[idx].ID
+		}
+		update := `UPDATE player_orders SET status = 'cancelled', cancelled_at = CURRENT_TIMESTAMP WHERE id IN (?` + repeatPlaceholders(len(args)-1) + `)`
+		if _, err := tx.ExecContext(ctx, update, args...); err != nil {
+			return nil, fmt.Errorf("failed to cancel port player orders: %w", err)
+		}
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"port_id":   portID,
+		"cancelled": len(orders),
+	})
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO audit_log (action, user_id, details) VALUES (?, ?, ?)`,
+		"purge_player_orders", adminUserID, string(details),
+	); err != nil {
+		return nil, fmt.Errorf("failed to log action: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return orders, nil
 }
 
 // --- Trade Conversation Operations ---
@@ -332,6 +766,108 @@ func (db *DB) GetAllActiveConversations(ctx context.Context) ([]TradeConversatio
 	return scanTradeConversations(rows)
 }
 
+// --- Trade Rating Operations ---
+
+// CreateTradeRating records the rater's rating of the other party in a
+// conversation. RatedUserID is resolved from the conversation; each party can
+// rate a conversation once.
+func (db *DB) CreateTradeRating(ctx context.Context, rating TradeRating) (*TradeRating, error) {
+	if rating.Stars < 1 || rating.Stars > 5 {
+		return nil, fmt.Errorf("stars must be between 1 and 5 (got %d)", rating.Stars)
+	}
+
+	var initiatorID, creatorID string
+	err := db.conn.QueryRowContext(ctx,
+		`SELECT initiator_user_id, creator_user_id FROM trade_conversations WHERE id = ?`,
+		rating.ConversationID,
+	).Scan(&initiatorID, &creatorID)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotConversationParty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	switch rating.RaterUserID {
+	case initiatorID:
+		rating.RatedUserID = creatorID
+	case creatorID:
+		rating.RatedUserID = initiatorID
+	default:
+		return nil, ErrNotConversationParty
+	}
+
+	query := `
+		INSERT OR IGNORE INTO trade_ratings (rater_user_id, rated_user_id, conversation_id, stars, comment)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	result, err := db.conn.ExecContext(ctx, query,
+		rating.RaterUserID, rating.RatedUserID, rating.ConversationID, rating.Stars, rating.Comment,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rating: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return nil, ErrAlreadyRated
+	}
+
+	id, _ := result.LastInsertId()
+	rating.ID = int(id)
+	rating.CreatedAt = time.Now()
+	return &rating, nil
+}
+
+// GetAverageRating returns the average stars a user has received
+func (db *DB) GetAverageRating(ctx context.Context, userID string) (RatingSummary, error) {
+	var summary RatingSummary
+	var average sql.NullFloat64
+	err := db.conn.QueryRowContext(ctx,
+		`SELECT AVG(stars), COUNT(*) FROM trade_ratings WHERE rated_user_id = ?`, userID,
+	).Scan(&average, &summary.Count)
+	if err != nil {
+		return summary, fmt.Errorf("failed to get average rating: %w", err)
+	}
+	summary.Average = average.Float64
+	return summary, nil
+}
+
+// GetAverageRatings returns rating summaries for several users at once.
+// Users with no ratings are omitted from the map.
+func (db *DB) GetAverageRatings(ctx context.Context, userIDs []string) (map[string]RatingSummary, error) {
+	summaries := make(map[string]RatingSummary)
+	if len(userIDs) == 0 {
+		return summaries, nil
+	}
+
+	args := make([]interface{}, len(userIDs))
+	for idx, id := range userIDs {
+		args[idx] = id
+	}
+
+	query := `
+		SELECT rated_user_id, AVG(stars), COUNT(*)
+		FROM trade_ratings
+		WHERE rated_user_id IN (?` + repeatPlaceholders(len(userIDs)-1) + `)
+		GROUP BY rated_user_id
+	`
+	rows, err := db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get average ratings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		var summary RatingSummary
+		if err := rows.Scan(&userID, &summary.Average, &summary.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan rating: %w", err)
+		}
+		summaries[userID] = summary
+	}
+	return summaries, rows.Err()
+}
+
 // --- Helpers ---
 
 func scanPlayerOrdersWithJoins(rows *sql.Rows) ([]PlayerOrder, error) {
@@ -340,12 +876,13 @@ func scanPlayerOrdersWithJoins(rows *sql.Rows) ([]PlayerOrder, error) {
 		var po PlayerOrder
 		var portID sql.NullInt64
 		var notes sql.NullString
+		var activatesAt sql.NullTime
 		var itemName, itemDisplay string
 		var portName, portDisplay, portRegion sql.NullString
 
 		err := rows.Scan(
 			&po.ID, &po.UserID, &po.ItemID, &po.OrderType, &po.Price, &po.Quantity,
-			&portID, &notes, &po.IngameName, &po.Status, &po.CreatedAt, &po.ExpiresAt,
+			&portID, &notes, &po.IngameName, &po.Status, &po.CreatedAt, &po.ExpiresAt, &activatesAt,
 			&itemName, &itemDisplay,
 			&portName, &portDisplay, &portRegion,
 		)
@@ -362,6 +899,9 @@ func scanPlayerOrdersWithJoins(rows *sql.Rows) ([]PlayerOrder, error) {
 		if notes.Valid {
 			po.Notes = notes.String
 		}
+		if activatesAt.Valid {
+			po.ActivatesAt = &activatesAt.Time
+		}
 		orders = append(orders, po)
 	}
 	return orders, rows.Err()
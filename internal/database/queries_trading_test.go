@@ -0,0 +1,852 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustCreatePlayerOrder(t *testing.T, db *DB, userID string, itemID int, expiresAt time.Time) *PlayerOrder {
+	t.Helper()
+	order, err := db.CreatePlayerOrder(context.Background(), PlayerOrder{
+		UserID:     userID,
+		ItemID:     itemID,
+		OrderType:  "sell",
+		Price:      100,
+		Quantity:   10,
+		IngameName: "Captain " + userID,
+		ExpiresAt:  expiresAt,
+	})
+	if err != nil {
+		t.Fatalf("failed to create player order: %v", err)
+	}
+	return order
+}
+
+// expirePlayerOrderNow moves an order's expiry into the past without running the expiry job
+func expirePlayerOrderNow(t *testing.T, db *DB, orderID int) {
+	t.Helper()
+	_, err := db.conn.ExecContext(context.Background(),
+		`UPDATE player_orders SET expires_at = datetime('now', '-1 second') WHERE id = ?`, orderID)
+	if err != nil {
+		t.Fatalf("failed to backdate order: %v", err)
+	}
+}
+
+func TestPlayerOrderExpiryBoundary(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+
+	live := mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().Add(time.Hour))
+	expired := mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().Add(time.Hour))
+	expirePlayerOrderNow(t, db, expired.ID)
+
+	// Before the job runs the expired order is still stored as 'active',
+	// but every read must already treat it as gone.
+	got, err := db.GetPlayerOrder(ctx, expired.ID)
+	if err != nil {
+		t.Fatalf("GetPlayerOrder failed: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected expired order to be hidden before the expiry job runs")
+	}
+
+	results, err := db.SearchPlayerOrders(ctx, item.ID, "", 0, "", 0, 0, "", 0)
+	if err != nil {
+		t.Fatalf("SearchPlayerOrders failed: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != live.ID {
+		t.Errorf("expected only the live order in search results, got %d results", len(results))
+	}
+
+	mine, err := db.GetPlayerOrdersByUser(ctx, "seller1")
+	if err != nil {
+		t.Fatalf("GetPlayerOrdersByUser failed: %v", err)
+	}
+	if len(mine) != 1 {
+		t.Errorf("expected 1 order for user, got %d", len(mine))
+	}
+
+	if err := db.CancelPlayerOrder(ctx, expired.ID, "seller1"); err == nil {
+		t.Errorf("expected cancelling an expired order to fail")
+	}
+
+	// The job flips exactly the orders the reads already hide.
+	expiredOrders, err := db.DeleteExpiredPlayerOrders(ctx)
+	if err != nil {
+		t.Fatalf("DeleteExpiredPlayerOrders failed: %v", err)
+	}
+	if len(expiredOrders) != 1 {
+		t.Fatalf("expected 1 expired order, got %d", len(expiredOrders))
+	}
+	if e := expiredOrders[0]; e.ID != expired.ID || e.UserID != "seller1" || e.Item == nil || e.Item.DisplayName == "" {
+		t.Errorf("expected expired order with its item, got %+v", e)
+	}
+
+	var status string
+	if err := db.conn.QueryRowContext(ctx, `SELECT status FROM player_orders WHERE id = ?`, expired.ID).Scan(&status); err != nil {
+		t.Fatalf("failed to read order status: %v", err)
+	}
+	if status != "cancelled" {
+		t.Errorf("expected expired order status 'cancelled', got %q", status)
+	}
+
+	got, err = db.GetPlayerOrder(ctx, live.ID)
+	if err != nil || got == nil {
+		t.Fatalf("expected live order to remain readable, err=%v", err)
+	}
+
+	// A second pass finds nothing new.
+	expiredOrders, err = db.DeleteExpiredPlayerOrders(ctx)
+	if err != nil {
+		t.Fatalf("DeleteExpiredPlayerOrders failed: %v", err)
+	}
+	if len(expiredOrders) != 0 {
+		t.Errorf("expected no further expired orders, got %d", len(expiredOrders))
+	}
+}
+
+func TestPlayerOrderExpiryIgnoresCallerTimezone(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+
+	// An expiry an hour ahead must be live regardless of the zone it was built in
+	west := time.FixedZone("UTC-5", -5*60*60)
+	order := mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().In(west).Add(time.Hour))
+
+	got, err := db.GetPlayerOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("GetPlayerOrder failed: %v", err)
+	}
+	if got == nil {
+		t.Fatalf("expected order with future expiry to be readable")
+	}
+
+	expiredOrders, err := db.DeleteExpiredPlayerOrders(ctx)
+	if err != nil {
+		t.Fatalf("DeleteExpiredPlayerOrders failed: %v", err)
+	}
+	if len(expiredOrders) != 0 {
+		t.Errorf("expected order with future expiry not to be expired, got %d", len(expiredOrders))
+	}
+}
+
+func TestCreatePlayerOrderRejectsNonFutureExpiry(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+	}{
+		{"zero", time.Time{}},
+		{"past", time.Now().Add(-time.Hour)},
+		{"now", time.Now()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.CreatePlayerOrder(ctx, PlayerOrder{
+				UserID:     "seller1",
+				ItemID:     item.ID,
+				OrderType:  "sell",
+				Price:      100,
+				Quantity:   10,
+				IngameName: "Captain",
+				ExpiresAt:  tt.expiresAt,
+			})
+			if !errors.Is(err, ErrExpiryNotInFuture) {
+				t.Errorf("expected ErrExpiryNotInFuture, got %v", err)
+			}
+		})
+	}
+
+	var count int
+	if err := db.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM player_orders`).Scan(&count); err != nil {
+		t.Fatalf("failed to count orders: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected no orders to be inserted, got %d", count)
+	}
+}
+
+func TestCountActivePlayerOrders(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+
+	mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().Add(time.Hour))
+	expired := mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().Add(time.Hour))
+	expirePlayerOrderNow(t, db, expired.ID)
+	completed := mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().Add(time.Hour))
+	if err := db.CompletePlayerOrder(ctx, completed.ID, "seller1", ""); err != nil {
+		t.Fatalf("CompletePlayerOrder failed: %v", err)
+	}
+	mustCreatePlayerOrder(t, db, "seller2", item.ID, time.Now().Add(time.Hour))
+
+	active, err := db.CountActivePlayerOrders(ctx, "seller1")
+	if err != nil {
+		t.Fatalf("CountActivePlayerOrders failed: %v", err)
+	}
+	if active != 1 {
+		t.Errorf("expected 1 active order, got %d", active)
+	}
+
+	done, err := db.CountCompletedPlayerOrders(ctx, "seller1")
+	if err != nil {
+		t.Fatalf("CountCompletedPlayerOrders failed: %v", err)
+	}
+	if done != 1 {
+		t.Errorf("expected 1 completed order, got %d", done)
+	}
+}
+
+func TestCompletePlayerOrder(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+	order := mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().Add(time.Hour))
+
+	if err := db.CompletePlayerOrder(ctx, order.ID, "someone-else", ""); err == nil {
+		t.Error("expected completing another user's order to fail")
+	}
+
+	if err := db.CompletePlayerOrder(ctx, order.ID, "seller1", "buyer1"); err != nil {
+		t.Fatalf("CompletePlayerOrder failed: %v", err)
+	}
+
+	var status, counterparty string
+	err := db.conn.QueryRowContext(ctx,
+		`SELECT status, counterparty_user_id FROM player_orders WHERE id = ?`, order.ID,
+	).Scan(&status, &counterparty)
+	if err != nil {
+		t.Fatalf("failed to read order: %v", err)
+	}
+	if status != "completed" || counterparty != "buyer1" {
+		t.Errorf("expected completed with buyer1, got %s with %s", status, counterparty)
+	}
+
+	var audits int
+	err = db.conn.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM audit_log WHERE action = 'trades_completed' AND user_id = 'seller1'`,
+	).Scan(&audits)
+	if err != nil {
+		t.Fatalf("failed to count audit entries: %v", err)
+	}
+	if audits != 1 {
+		t.Errorf("expected 1 trades_completed audit entry, got %d", audits)
+	}
+
+	// A completed order can't be completed again
+	if err := db.CompletePlayerOrder(ctx, order.ID, "seller1", ""); err == nil {
+		t.Error("expected completing an already completed order to fail")
+	}
+}
+
+func mustCreateConversation(t *testing.T, db *DB, initiatorID, creatorID string) *TradeConversation {
+	t.Helper()
+	item := mustCreateItem(t, db, "Rope "+initiatorID+creatorID)
+	order := mustCreatePlayerOrder(t, db, creatorID, item.ID, time.Now().Add(time.Hour))
+	conv, err := db.CreateTradeConversation(context.Background(), TradeConversation{
+		OrderID:             order.ID,
+		InitiatorUserID:     initiatorID,
+		InitiatorIngameName: "Initiator",
+		CreatorUserID:       creatorID,
+		CreatorIngameName:   "Creator",
+	})
+	if err != nil {
+		t.Fatalf("failed to create conversation: %v", err)
+	}
+	return conv
+}
+
+func TestCreateTradeRating(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	conv := mustCreateConversation(t, db, "buyer1", "seller1")
+
+	rating, err := db.CreateTradeRating(ctx, TradeRating{RaterUserID: "buyer1", ConversationID: conv.ID, Stars: 4})
+	if err != nil {
+		t.Fatalf("CreateTradeRating failed: %v", err)
+	}
+	if rating.RatedUserID != "seller1" {
+		t.Errorf("expected seller1 to be rated, got %q", rating.RatedUserID)
+	}
+
+	if _, err := db.CreateTradeRating(ctx, TradeRating{RaterUserID: "buyer1", ConversationID: conv.ID, Stars: 1}); !errors.Is(err, ErrAlreadyRated) {
+		t.Errorf("expected ErrAlreadyRated on second rating, got %v", err)
+	}
+	if _, err := db.CreateTradeRating(ctx, TradeRating{RaterUserID: "stranger", ConversationID: conv.ID, Stars: 5}); !errors.Is(err, ErrNotConversationParty) {
+		t.Errorf("expected ErrNotConversationParty for outsider, got %v", err)
+	}
+	if _, err := db.CreateTradeRating(ctx, TradeRating{RaterUserID: "seller1", ConversationID: conv.ID, Stars: 6}); err == nil {
+		t.Error("expected out of range stars to be rejected")
+	}
+
+	// The other party can still rate their side
+	if _, err := db.CreateTradeRating(ctx, TradeRating{RaterUserID: "seller1", ConversationID: conv.ID, Stars: 5}); err != nil {
+		t.Errorf("expected other party's rating to succeed, got %v", err)
+	}
+}
+
+func TestGetAverageRating(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for idx, stars := range []int{5, 4} {
+		conv := mustCreateConversation(t, db, fmt.Sprintf("buyer%d", idx), "seller1")
+		if _, err := db.CreateTradeRating(ctx, TradeRating{RaterUserID: conv.InitiatorUserID, ConversationID: conv.ID, Stars: stars}); err != nil {
+			t.Fatalf("CreateTradeRating failed: %v", err)
+		}
+	}
+
+	summary, err := db.GetAverageRating(ctx, "seller1")
+	if err != nil {
+		t.Fatalf("GetAverageRating failed: %v", err)
+	}
+	if summary.Count != 2 || summary.Average != 4.5 {
+		t.Errorf("expected 4.5 from 2 ratings, got %.2f from %d", summary.Average, summary.Count)
+	}
+
+	unrated, err := db.GetAverageRating(ctx, "nobody")
+	if err != nil {
+		t.Fatalf("GetAverageRating failed: %v", err)
+	}
+	if unrated.Count != 0 || unrated.Average != 0 {
+		t.Errorf("expected empty summary for unrated user, got %+v", unrated)
+	}
+
+	summaries, err := db.GetAverageRatings(ctx, []string{"seller1", "nobody"})
+	if err != nil {
+		t.Fatalf("GetAverageRatings failed: %v", err)
+	}
+	if len(summaries) != 1 || summaries["seller1"] != summary {
+		t.Errorf("expected only seller1's summary, got %+v", summaries)
+	}
+}
+
+func TestGetPlayerOrderHistory(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+	expiry := time.Now().Add(time.Hour)
+
+	active := mustCreatePlayerOrder(t, db, "seller1", item.ID, expiry)
+	completed := mustCreatePlayerOrder(t, db, "seller1", item.ID, expiry)
+	cancelled := mustCreatePlayerOrder(t, db, "seller1", item.ID, expiry)
+	expired := mustCreatePlayerOrder(t, db, "seller1", item.ID, expiry)
+	lapsed := mustCreatePlayerOrder(t, db, "seller1", item.ID, expiry)
+	mustCreatePlayerOrder(t, db, "seller2", item.ID, expiry)
+
+	if err := db.CompletePlayerOrder(ctx, completed.ID, "seller1", "buyer1"); err != nil {
+		t.Fatalf("CompletePlayerOrder failed: %v", err)
+	}
+	if err := db.CancelPlayerOrder(ctx, cancelled.ID, "seller1"); err != nil {
+		t.Fatalf("CancelPlayerOrder failed: %v", err)
+	}
+	expirePlayerOrderNow(t, db, expired.ID)
+	if _, err := db.DeleteExpiredPlayerOrders(ctx); err != nil {
+		t.Fatalf("DeleteExpiredPlayerOrders failed: %v", err)
+	}
+	// Past expiry but not yet swept by the expiry job
+	expirePlayerOrderNow(t, db, lapsed.ID)
+
+	history, err := db.GetPlayerOrderHistory(ctx, "seller1", nil, 10)
+	if err != nil {
+		t.Fatalf("GetPlayerOrderHistory failed: %v", err)
+	}
+
+	statuses := make(map[int]string)
+	for _, o := range history {
+		statuses[o.ID] = o.Status
+	}
+	want := map[int]string{
+		completed.ID: "completed",
+		cancelled.ID: "cancelled",
+		expired.ID:   "expired",
+		lapsed.ID:    "expired",
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("expected %d history entries, got %v", len(want), statuses)
+	}
+	for id, status := range want {
+		if statuses[id] != status {
+			t.Errorf("order %d: expected %s, got %q", id, status, statuses[id])
+		}
+	}
+	if _, ok := statuses[active.ID]; ok {
+		t.Error("expected active order to be excluded from history")
+	}
+
+	for _, o := range history {
+		if o.ID == completed.ID && o.CompletedAt == nil {
+			t.Error("expected completed order to carry its completion time")
+		}
+		if o.ID == cancelled.ID && o.CancelledAt == nil {
+			t.Error("expected cancelled order to carry its cancellation time")
+		}
+	}
+
+	onlyCompleted, err := db.GetPlayerOrderHistory(ctx, "seller1", []string{"completed"}, 10)
+	if err != nil {
+		t.Fatalf("GetPlayerOrderHistory failed: %v", err)
+	}
+	if len(onlyCompleted) != 1 || onlyCompleted[0].ID != completed.ID {
+		t.Errorf("expected only the completed order, got %d orders", len(onlyCompleted))
+	}
+
+	limited, err := db.GetPlayerOrderHistory(ctx, "seller1", nil, 2)
+	if err != nil {
+		t.Fatalf("GetPlayerOrderHistory failed: %v", err)
+	}
+	if len(limited) != 2 {
+		t.Errorf("expected limit to cap results at 2, got %d", len(limited))
+	}
+}
+
+func TestSearchPlayerOrdersByTrader(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cannon := mustCreateItem(t, db, "Cannon")
+	rope := mustCreateItem(t, db, "Rope")
+	expiry := time.Now().Add(time.Hour)
+
+	mustCreatePlayerOrder(t, db, "seller1", cannon.ID, expiry)
+	ropeOrder := mustCreatePlayerOrder(t, db, "seller1", rope.ID, expiry)
+	mustCreatePlayerOrder(t, db, "seller2", rope.ID, expiry)
+	db.conn.ExecContext(ctx, `UPDATE player_orders SET ingame_name = 'Jack_Sparrow' WHERE user_id = 'seller2'`)
+
+	search := func(itemID int, trader string) []PlayerOrder {
+		t.Helper()
+		orders, err := db.SearchPlayerOrders(ctx, itemID, "", 0, trader, 0, 0, "", 0)
+		if err != nil {
+			t.Fatalf("SearchPlayerOrders failed: %v", err)
+		}
+		return orders
+	}
+
+	if got := search(0, "captain SELLER1"); len(got) != 2 {
+		t.Errorf("expected a case-insensitive partial match on both orders, got %d", len(got))
+	}
+	if got := search(rope.ID, "seller1"); len(got) != 1 || got[0].ID != ropeOrder.ID {
+		t.Errorf("expected the trader filter to combine with the item, got %+v", got)
+	}
+	// Underscore is literal, not a LIKE wildcard
+	if got := search(0, "k_S"); len(got) != 1 || got[0].UserID != "seller2" {
+		t.Errorf("expected only Jack_Sparrow, got %+v", got)
+	}
+	if got := search(0, "Captain_"); len(got) != 0 {
+		t.Errorf("expected no match for a literal underscore, got %d", len(got))
+	}
+}
+
+func TestSearchPlayerOrdersSort(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+
+	// Created oldest to newest and priced cheapest to dearest, with expiry
+	// in a different order from both
+	a := mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().Add(time.Hour))
+	b := mustCreatePlayerOrder(t, db, "seller2", item.ID, time.Now().Add(72*time.Hour))
+	c := mustCreatePlayerOrder(t, db, "seller3", item.ID, time.Now().Add(24*time.Hour))
+	for _, o := range []struct{ id, price, age int }{{a.ID, 50, 3}, {b.ID, 100, 2}, {c.ID, 200, 1}} {
+		_, err := db.conn.ExecContext(ctx,
+			`UPDATE player_orders SET price = ?, created_at = datetime('now', ?) WHERE id = ?`,
+			o.price, fmt.Sprintf("-%d hours", o.age), o.id)
+		if err != nil {
+			t.Fatalf("failed to set up order: %v", err)
+		}
+	}
+
+	cases := map[string][]int{
+		"":           {c.ID, b.ID, a.ID},
+		"newest":     {c.ID, b.ID, a.ID},
+		"price-asc":  {a.ID, b.ID, c.ID},
+		"price-desc": {c.ID, b.ID, a.ID},
+		"expiring":   {a.ID, c.ID, b.ID},
+	}
+	for sort, want := range cases {
+		orders, err := db.SearchPlayerOrders(ctx, item.ID, "", 0, "", 0, 0, sort, 0)
+		if err != nil {
+			t.Fatalf("%q: SearchPlayerOrders failed: %v", sort, err)
+		}
+		var got []int
+		for _, o := range orders {
+			got = append(got, o.ID)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%q: expected %v, got %v", sort, want, got)
+		}
+	}
+
+	if _, err := db.SearchPlayerOrders(ctx, item.ID, "", 0, "", 0, 0, "cheapest", 0); !errors.Is(err, ErrUnknownSort) {
+		t.Errorf("expected ErrUnknownSort, got %v", err)
+	}
+}
+
+func TestCancelPlayerOrderExplainsFailure(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+	expiry := time.Now().Add(time.Hour)
+
+	live := mustCreatePlayerOrder(t, db, "seller1", item.ID, expiry)
+	completed := mustCreatePlayerOrder(t, db, "seller1", item.ID, expiry)
+	cancelled := mustCreatePlayerOrder(t, db, "seller1", item.ID, expiry)
+	swept := mustCreatePlayerOrder(t, db, "seller1", item.ID, expiry)
+	lapsed := mustCreatePlayerOrder(t, db, "seller1", item.ID, expiry)
+
+	if err := db.CompletePlayerOrder(ctx, completed.ID, "seller1", ""); err != nil {
+		t.Fatalf("CompletePlayerOrder failed: %v", err)
+	}
+	if err := db.CancelPlayerOrder(ctx, cancelled.ID, "seller1"); err != nil {
+		t.Fatalf("CancelPlayerOrder failed: %v", err)
+	}
+	expirePlayerOrderNow(t, db, swept.ID)
+	if _, err := db.DeleteExpiredPlayerOrders(ctx); err != nil {
+		t.Fatalf("DeleteExpiredPlayerOrders failed: %v", err)
+	}
+	expirePlayerOrderNow(t, db, lapsed.ID)
+
+	cases := []struct {
+		name    string
+		orderID int
+		userID  string
+		want    error
+	}{
+		{"missing", 9999, "seller1", ErrOrderNotFound},
+		{"someone else's", live.ID, "seller2", ErrNotOrderOwner},
+		{"completed", completed.ID, "seller1", ErrOrderCompleted},
+		{"cancelled", cancelled.ID, "seller1", ErrOrderCancelled},
+		{"expired by the job", swept.ID, "seller1", ErrOrderExpired},
+		{"expired before the job", lapsed.ID, "seller1", ErrOrderExpired},
+	}
+	for _, tc := range cases {
+		if err := db.CancelPlayerOrder(ctx, tc.orderID, tc.userID); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	if owner, err := db.CheckPlayerOrder(ctx, live.ID); err != nil || owner != "seller1" {
+		t.Errorf("expected a live order owned by seller1, got %q, %v", owner, err)
+	}
+}
+
+func TestIsIngameNameTaken(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := db.SetPlayerProfile(ctx, "user1", "Jack Sparrow"); err != nil {
+		t.Fatalf("SetPlayerProfile failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		userID    string
+		wantTaken bool
+	}{
+		{"Jack Sparrow", "user2", true},
+		{"jack sparrow", "user2", true},  // case-insensitive
+		{"Jack Sparrow", "user1", false}, // keeping your own name is fine
+		{"Will Turner", "user2", false},
+	}
+	for _, tt := range tests {
+		taken, err := db.IsIngameNameTaken(ctx, tt.name, tt.userID)
+		if err != nil {
+			t.Fatalf("IsIngameNameTaken failed: %v", err)
+		}
+		if taken != tt.wantTaken {
+			t.Errorf("IsIngameNameTaken(%q, %q) = %v, want %v", tt.name, tt.userID, taken, tt.wantTaken)
+		}
+	}
+}
+
+func TestAdminSetAndClearPlayerProfile(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+	if err := db.SetPlayerProfile(ctx, "user1", "Rude Name"); err != nil {
+		t.Fatalf("SetPlayerProfile failed: %v", err)
+	}
+	order := mustCreatePlayerOrder(t, db, "user1", item.ID, time.Now().Add(time.Hour))
+
+	if err := db.AdminSetPlayerProfile(ctx, "user1", "Polite Name", "admin1"); err != nil {
+		t.Fatalf("AdminSetPlayerProfile failed: %v", err)
+	}
+	profile, err := db.GetPlayerProfile(ctx, "user1")
+	if err != nil || profile == nil || profile.IngameName != "Polite Name" {
+		t.Fatalf("expected renamed profile, got %+v (err=%v)", profile, err)
+	}
+	got, err := db.GetPlayerOrder(ctx, order.ID)
+	if err != nil || got == nil || got.IngameName != "Polite Name" {
+		t.Errorf("expected active order to be renamed, got %+v (err=%v)", got, err)
+	}
+
+	if err := db.ClearPlayerProfile(ctx, "user1", "admin1"); err != nil {
+		t.Fatalf("ClearPlayerProfile failed: %v", err)
+	}
+	if profile, _ := db.GetPlayerProfile(ctx, "user1"); profile != nil {
+		t.Errorf("expected profile to be cleared, got %+v", profile)
+	}
+	if err := db.ClearPlayerProfile(ctx, "user1", "admin1"); err == nil {
+		t.Errorf("expected clearing a missing profile to fail")
+	}
+
+	for action, want := range map[string]int{"admin_set_name": 1, "admin_clear_name": 1} {
+		entries, err := db.GetAuditLog(ctx, action, 10)
+		if err != nil {
+			t.Fatalf("GetAuditLog failed: %v", err)
+		}
+		if len(entries) != want || entries[0].UserID != "admin1" {
+			t.Errorf("%s: expected %d entry by admin1, got %+v", action, want, entries)
+		}
+	}
+}
+
+func TestExpireBans(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	for _, ban := range []TradeBan{
+		{UserID: "lapsed", Reason: "spam", BannedBy: "admin1", ExpiresAt: &past},
+		{UserID: "current", Reason: "spam", BannedBy: "admin1", ExpiresAt: &future},
+		{UserID: "permanent", Reason: "scam", BannedBy: "admin1"},
+	} {
+		if _, err := db.CreateTradeBan(ctx, ban); err != nil {
+			t.Fatalf("CreateTradeBan failed: %v", err)
+		}
+	}
+
+	expired, err := db.ExpireBans(ctx)
+	if err != nil {
+		t.Fatalf("ExpireBans failed: %v", err)
+	}
+	if len(expired) != 1 || expired[0].UserID != "lapsed" || expired[0].Active {
+		t.Fatalf("expected only the lapsed ban to be deactivated, got %+v", expired)
+	}
+
+	var active bool
+	if err := db.conn.QueryRowContext(ctx, `SELECT active FROM trade_bans WHERE user_id = 'lapsed'`).Scan(&active); err != nil {
+		t.Fatalf("failed to read ban: %v", err)
+	}
+	if active {
+		t.Errorf("expected the stored ban to be inactive")
+	}
+
+	entries, err := db.GetAuditLog(ctx, "trade_ban_expired", 10)
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected 1 audit entry, got %d", len(entries))
+	}
+
+	if expired, err := db.ExpireBans(ctx); err != nil || len(expired) != 0 {
+		t.Errorf("expected a second pass to find nothing, got %d (err=%v)", len(expired), err)
+	}
+}
+
+func TestExpireBansKeepsNewerBan(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	// The lapsed ban hasn't been swept yet when the user is banned again
+	for _, ban := range []TradeBan{
+		{UserID: "rebanned", Reason: "spam", BannedBy: "admin1", ExpiresAt: &past},
+		{UserID: "rebanned", Reason: "scam", BannedBy: "admin1", ExpiresAt: &future},
+		{UserID: "twice", Reason: "spam", BannedBy: "admin1", ExpiresAt: &past},
+		{UserID: "twice", Reason: "spam again", BannedBy: "admin1", ExpiresAt: &past},
+	} {
+		if _, err := db.CreateTradeBan(ctx, ban); err != nil {
+			t.Fatalf("CreateTradeBan failed: %v", err)
+		}
+	}
+
+	expired, err := db.ExpireBans(ctx)
+	if err != nil {
+		t.Fatalf("ExpireBans failed: %v", err)
+	}
+	if len(expired) != 1 || expired[0].UserID != "twice" || expired[0].Reason != "spam again" {
+		t.Fatalf("expected only twice's latest ban reported, got %+v", expired)
+	}
+
+	ban, err := db.IsUserBanned(ctx, "rebanned")
+	if err != nil || ban == nil || ban.Reason != "scam" {
+		t.Errorf("expected the newer ban to stay live, got %+v (%v)", ban, err)
+	}
+	if entries, _ := db.GetAuditLog(ctx, "trade_ban_expired", 10); len(entries) != 3 {
+		t.Errorf("expected every lapsed ban audited, got %d entries", len(entries))
+	}
+}
+
+func TestCancelAllUserOrders(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+
+	mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().Add(time.Hour))
+	start := time.Now().Add(time.Hour)
+	if _, err := db.CreatePlayerOrder(ctx, PlayerOrder{
+		UserID: "seller1", ItemID: item.ID, OrderType: "buy", Price: 10, Quantity: 1,
+		IngameName: "Captain", ExpiresAt: start.Add(time.Hour), ActivatesAt: &start,
+	}); err != nil {
+		t.Fatalf("failed to create scheduled order: %v", err)
+	}
+	expired := mustCreatePlayerOrder(t, db, "seller1", item.ID, time.Now().Add(time.Hour))
+	expirePlayerOrderNow(t, db, expired.ID)
+	mustCreatePlayerOrder(t, db, "seller2", item.ID, time.Now().Add(time.Hour))
+
+	cancelled, err := db.CancelAllUserOrders(ctx, "seller1")
+	if err != nil {
+		t.Fatalf("CancelAllUserOrders failed: %v", err)
+	}
+	if cancelled != 2 {
+		t.Errorf("expected the live and scheduled orders cancelled, got %d", cancelled)
+	}
+
+	// The lapsed order still reads as expired rather than cancelled
+	history, err := db.GetPlayerOrderHistory(ctx, "seller1", []string{"expired"}, 10)
+	if err != nil {
+		t.Fatalf("GetPlayerOrderHistory failed: %v", err)
+	}
+	if len(history) != 1 || history[0].ID != expired.ID {
+		t.Errorf("expected order %d to stay expired, got %+v", expired.ID, history)
+	}
+
+	if active, err := db.CountActivePlayerOrders(ctx, "seller2"); err != nil || active != 1 {
+		t.Errorf("expected other traders' orders untouched, got %d (%v)", active, err)
+	}
+}
+
+func TestPurgePlayerOrders(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+	port := mustCreatePort(t, db, "Port Royal")
+	other := mustCreatePort(t, db, "Tortuga")
+
+	atPort := func(userID string, portID int) *PlayerOrder {
+		order, err := db.CreatePlayerOrder(ctx, PlayerOrder{
+			UserID: userID, ItemID: item.ID, OrderType: "sell", Price: 100, Quantity: 1,
+			PortID: &portID, IngameName: "Captain " + userID, ExpiresAt: time.Now().Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("failed to create order: %v", err)
+		}
+		return order
+	}
+	atPort("seller1", port.ID)
+	atPort("seller2", port.ID)
+	expired := atPort("seller1", port.ID)
+	expirePlayerOrderNow(t, db, expired.ID)
+	atPort("seller1", other.ID)
+
+	if count, err := db.CountOpenPlayerOrdersByPort(ctx, port.ID); err != nil || count != 2 {
+		t.Fatalf("expected 2 open orders at the port, got %d (%v)", count, err)
+	}
+
+	purged, err := db.PurgePlayerOrders(ctx, port.ID, "admin1")
+	if err != nil {
+		t.Fatalf("PurgePlayerOrders failed: %v", err)
+	}
+	if len(purged) != 2 || purged[0].Port == nil || purged[0].Port.DisplayName != "Port Royal" {
+		t.Fatalf("expected both open orders returned with their port, got %+v", purged)
+	}
+	if count, _ := db.CountOpenPlayerOrdersByPort(ctx, port.ID); count != 0 {
+		t.Errorf("expected no open orders left at the port, got %d", count)
+	}
+	if count, _ := db.CountOpenPlayerOrdersByPort(ctx, other.ID); count != 1 {
+		t.Errorf("expected the other port untouched, got %d", count)
+	}
+
+	entries, err := db.GetAuditLog(ctx, "purge_player_orders", 10)
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].UserID != "admin1" || !strings.Contains(entries[0].Details, `"cancelled":2`) {
+		t.Errorf("unexpected audit entries %+v", entries)
+	}
+}
+
+func TestAdminCancelPlayerOrder(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+	order, err := db.CreatePlayerOrder(ctx, PlayerOrder{
+		UserID: "seller1", ItemID: item.ID, OrderType: "sell", Price: 100, Quantity: 1,
+		IngameName: "Captain Seller", Notes: "rude notes", ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("failed to create order: %v", err)
+	}
+
+	removed, err := db.AdminCancelPlayerOrder(ctx, order.ID, "admin1", "abusive notes")
+	if err != nil {
+		t.Fatalf("AdminCancelPlayerOrder failed: %v", err)
+	}
+	if removed.UserID != "seller1" || removed.Item == nil || removed.Item.DisplayName != "Cannon" {
+		t.Errorf("expected the owner's order back with its item, got %+v", removed)
+	}
+	if _, err := db.CheckPlayerOrder(ctx, order.ID); !errors.Is(err, ErrOrderCancelled) {
+		t.Errorf("expected the order cancelled, got %v", err)
+	}
+
+	if _, err := db.AdminCancelPlayerOrder(ctx, order.ID, "admin1", ""); !errors.Is(err, ErrOrderCancelled) {
+		t.Errorf("expected ErrOrderCancelled removing it again, got %v", err)
+	}
+	if _, err := db.AdminCancelPlayerOrder(ctx, 9999, "admin1", ""); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("expected ErrOrderNotFound, got %v", err)
+	}
+
+	entries, err := db.GetAuditLog(ctx, "admin_remove_order", 10)
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].UserID != "admin1" ||
+		!strings.Contains(entries[0].Details, `"notes":"rude notes"`) || !strings.Contains(entries[0].Details, `"reason":"abusive notes"`) {
+		t.Errorf("unexpected audit entries %+v", entries)
+	}
+}
@@ -1,13 +1,17 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"sync"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// schema is the table layout applied by migration 1. Later changes belong in
+// new migrations (see migrations.go), not here.
 const schema = `
 -- Items master table
 CREATE TABLE IF NOT EXISTS items (
@@ -49,12 +53,21 @@ CREATE TABLE IF NOT EXISTS item_tags (
 	FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
 );
 
--- Ports master table
+-- Known regions ports are grouped by
+CREATE TABLE IF NOT EXISTS regions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+	added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	added_by TEXT
+);
+
+-- Ports master table (region holds the canonical name of region_id)
 CREATE TABLE IF NOT EXISTS ports (
 	id INTEGER PRIMARY KEY AUTOINCREMENT,
 	name TEXT NOT NULL UNIQUE,
 	display_name TEXT NOT NULL,
 	region TEXT,
+	region_id INTEGER REFERENCES regions(id) ON DELETE SET NULL,
 	added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 	added_by TEXT,
 	notes TEXT
@@ -81,6 +94,7 @@ CREATE TABLE IF NOT EXISTS markets (
 	submitted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 	expires_at TIMESTAMP NOT NULL,
 	screenshot_hash TEXT NOT NULL,
+	screenshot_phash TEXT,
 	FOREIGN KEY (port_id) REFERENCES ports(id) ON DELETE CASCADE,
 	FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE
 );
@@ -110,6 +124,8 @@ CREATE INDEX IF NOT EXISTS idx_audit_user ON audit_log(user_id);
 CREATE TABLE IF NOT EXISTS guild_settings (
 	guild_id TEXT PRIMARY KEY,
 	admin_role_id TEXT,
+	trade_preview INTEGER NOT NULL DEFAULT 1,
+	log_channel_id TEXT,
 	configured_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 	configured_by TEXT NOT NULL,
 	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
@@ -137,6 +153,9 @@ CREATE TABLE IF NOT EXISTS player_orders (
 	status TEXT NOT NULL DEFAULT 'active' CHECK(status IN ('active', 'completed', 'cancelled')),
 	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 	expires_at TIMESTAMP NOT NULL,
+	completed_at TIMESTAMP,
+	counterparty_user_id TEXT,
+	cancelled_at TIMESTAMP,
 	FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
 	FOREIGN KEY (port_id) REFERENCES ports(id) ON DELETE SET NULL
 );
@@ -198,10 +217,42 @@ CREATE TABLE IF NOT EXISTS trade_reports (
 
 CREATE INDEX IF NOT EXISTS idx_trade_reports_reported ON trade_reports(reported_user_id);
 CREATE INDEX IF NOT EXISTS idx_trade_reports_status ON trade_reports(status);
+
+-- Trade ratings (one per rater per conversation)
+CREATE TABLE IF NOT EXISTS trade_ratings (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	rater_user_id TEXT NOT NULL,
+	rated_user_id TEXT NOT NULL,
+	conversation_id INTEGER NOT NULL,
+	stars INTEGER NOT NULL CHECK(stars BETWEEN 1 AND 5),
+	comment TEXT,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (conversation_id) REFERENCES trade_conversations(id) ON DELETE CASCADE,
+	UNIQUE(rater_user_id, conversation_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trade_ratings_rated ON trade_ratings(rated_user_id);
 `
 
+// legacyColumns lists columns added to tables before schema versioning
+// existed. Migration 1 adds them to older databases when missing.
+var legacyColumns = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"markets", "screenshot_phash", "TEXT"},
+	{"guild_settings", "trade_preview", "INTEGER NOT NULL DEFAULT 1"},
+	{"guild_settings", "log_channel_id", "TEXT"},
+	{"player_orders", "completed_at", "TIMESTAMP"},
+	{"player_orders", "counterparty_user_id", "TEXT"},
+	{"player_orders", "cancelled_at", "TIMESTAMP"},
+	{"ports", "region_id", "INTEGER REFERENCES regions(id) ON DELETE SET NULL"},
+}
+
 type DB struct {
-	conn *sql.DB
+	conn  *sql.DB
+	stmts sync.Map // query -> *sql.Stmt, see prepared
 }
 
 // New creates a new database connection and initializes the schema
@@ -221,9 +272,9 @@ func New(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
 	}
 
-	// Initialize schema
-	if _, err := conn.Exec(schema); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	// Create or upgrade the schema
+	if err := migrate(context.Background(), conn); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
 	return &DB{conn: conn}, nil
@@ -231,7 +282,11 @@ func New(dbPath string) (*DB, error) {
 
 // Close closes the database connection
 func (db *DB) Close() error {
-	return db.conn.Close()
+	stmtErr := db.closeStatements()
+	if err := db.conn.Close(); err != nil {
+		return err
+	}
+	return stmtErr
 }
 
 // Item represents an item in the game
@@ -240,6 +295,7 @@ type Item struct {
 	Name        string
 	DisplayName string
 	IsTagged    bool
+	Archived    bool // retired: kept for history, hidden from matching and listings
 	AddedAt     time.Time
 	AddedBy     string
 	Notes       string
@@ -269,12 +325,21 @@ type Port struct {
 	ID          int
 	Name        string
 	DisplayName string
-	Region      string
+	Region      string // Canonical name of RegionID
+	RegionID    int    // 0 when the port has no region
 	AddedAt     time.Time
 	AddedBy     string
 	Notes       string
 }
 
+// Region is a known port region
+type Region struct {
+	ID      int
+	Name    string
+	AddedAt time.Time
+	AddedBy string
+}
+
 // PortAlias represents an alias for port matching
 type PortAlias struct {
 	ID      int
@@ -285,16 +350,17 @@ type PortAlias struct {
 
 // Market represents a market order entry
 type Market struct {
-	ID             int
-	PortID         int
-	ItemID         int
-	OrderType      string
-	Price          int
-	Quantity       int
-	SubmittedBy    string
-	SubmittedAt    time.Time
-	ExpiresAt      time.Time
-	ScreenshotHash string
+	ID              int
+	PortID          int
+	ItemID          int
+	OrderType       string
+	Price           int
+	Quantity        int
+	SubmittedBy     string
+	SubmittedAt     time.Time
+	ExpiresAt       time.Time
+	ScreenshotHash  string
+	ScreenshotPHash string
 	// Populated when joined
 	Port *Port
 	Item *Item
@@ -309,6 +375,19 @@ type AuditLog struct {
 	Details   string
 }
 
+// SubmissionRecord is the audit trail of one /submit, stored as a
+// "submission" audit log entry
+type SubmissionRecord struct {
+	UserID         string    `json:"-"`
+	PortID         int       `json:"port_id"`
+	PortName       string    `json:"port"`
+	OrderType      string    `json:"order_type"`
+	TotalItems     int       `json:"total_items"`
+	NewItems       int       `json:"new_items"`
+	ScreenshotHash string    `json:"screenshot_hash"`
+	SubmittedAt    time.Time `json:"-"`
+}
+
 // PlayerProfile represents a player's trading profile
 type PlayerProfile struct {
 	UserID     string
@@ -328,9 +407,15 @@ type PlayerOrder struct {
 	PortID    *int
 	Notes     string
 	IngameName string
-	Status    string // "active", "completed", "cancelled"
+	Status    string // "active", "completed", "cancelled" ("expired" and "pending_start" in order history)
 	CreatedAt time.Time
 	ExpiresAt time.Time
+	// ActivatesAt is when a scheduled order starts. It is cleared once the
+	// activation job has announced the order; nil for unscheduled orders.
+	ActivatesAt *time.Time
+	// Set once the order leaves the active state
+	CompletedAt *time.Time
+	CancelledAt *time.Time
 	// Populated via joins
 	Item *Item
 	Port *Port
@@ -373,3 +458,20 @@ type TradeReport struct {
 	ReviewedAt     *time.Time
 	CreatedAt      time.Time
 }
+
+// TradeRating is one trader's star rating of their partner after a conversation
+type TradeRating struct {
+	ID             int
+	RaterUserID    string
+	RatedUserID    string
+	ConversationID int
+	Stars          int // 1-5
+	Comment        string
+	CreatedAt      time.Time
+}
+
+// RatingSummary aggregates the ratings a trader has received
+type RatingSummary struct {
+	Average float64
+	Count   int
+}
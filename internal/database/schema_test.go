@@ -2,12 +2,16 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
 
-func setupTestDB(t *testing.T) (*DB, func()) {
+func setupTestDB(t testing.TB) (*DB, func()) {
 	// Create temporary database file
 	tmpfile, err := os.CreateTemp("", "test-*.db")
 	if err != nil {
@@ -29,6 +33,30 @@ func setupTestDB(t *testing.T) (*DB, func()) {
 	return db, cleanup
 }
 
+func mustCreatePort(t testing.TB, db *DB, name string) *Port {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := db.ResolveRegion(ctx, "Caribbean"); errors.Is(err, ErrUnknownRegion) {
+		if _, err := db.CreateRegion(ctx, "Caribbean", "test"); err != nil {
+			t.Fatalf("failed to create region: %v", err)
+		}
+	}
+	port, err := db.CreatePort(ctx, name, name, "Caribbean", "test")
+	if err != nil {
+		t.Fatalf("failed to create port %s: %v", name, err)
+	}
+	return port
+}
+
+func mustCreateItem(t testing.TB, db *DB, name string) *Item {
+	t.Helper()
+	item, err := db.CreateItem(context.Background(), name, name, "test")
+	if err != nil {
+		t.Fatalf("failed to create item %s: %v", name, err)
+	}
+	return item
+}
+
 func TestDatabaseInitialization(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -61,20 +89,25 @@ func TestReplacePortOrders(t *testing.T) {
 	defer cleanup()
 
 	ctx := context.Background()
+	port := mustCreatePort(t, db, "Port Royal")
+	cannon := mustCreateItem(t, db, "Cannon")
+	wood := mustCreateItem(t, db, "Wood")
+	iron := mustCreateItem(t, db, "Iron")
+	rope := mustCreateItem(t, db, "Rope")
 
 	// Create initial orders
 	orders1 := []Market{
-		{Item: "Cannon", Price: 100, Quantity: 10},
-		{Item: "Wood", Price: 50, Quantity: 100},
+		{ItemID: cannon.ID, Price: 100, Quantity: 10},
+		{ItemID: wood.ID, Price: 50, Quantity: 100},
 	}
 
-	err := db.ReplacePortOrders(ctx, "Port Royal", "buy", orders1, "user123", "hash1")
+	err := db.ReplacePortOrders(ctx, port.ID, "buy", orders1, "user123", "hash1", "")
 	if err != nil {
 		t.Fatalf("failed to insert initial orders: %v", err)
 	}
 
 	// Verify orders were inserted
-	markets, err := db.GetOrdersByPort(ctx, "Port Royal")
+	markets, err := db.GetOrdersByPort(ctx, port.ID)
 	if err != nil {
 		t.Fatalf("failed to query orders: %v", err)
 	}
@@ -84,18 +117,18 @@ func TestReplacePortOrders(t *testing.T) {
 
 	// Replace with new orders
 	orders2 := []Market{
-		{Item: "Cannon", Price: 110, Quantity: 5},
-		{Item: "Iron", Price: 75, Quantity: 50},
-		{Item: "Rope", Price: 25, Quantity: 200},
+		{ItemID: cannon.ID, Price: 110, Quantity: 5},
+		{ItemID: iron.ID, Price: 75, Quantity: 50},
+		{ItemID: rope.ID, Price: 25, Quantity: 200},
 	}
 
-	err = db.ReplacePortOrders(ctx, "Port Royal", "buy", orders2, "user456", "hash2")
+	err = db.ReplacePortOrders(ctx, port.ID, "buy", orders2, "user456", "hash2", "")
 	if err != nil {
 		t.Fatalf("failed to replace orders: %v", err)
 	}
 
 	// Verify old orders were replaced
-	markets, err = db.GetOrdersByPort(ctx, "Port Royal")
+	markets, err = db.GetOrdersByPort(ctx, port.ID)
 	if err != nil {
 		t.Fatalf("failed to query updated orders: %v", err)
 	}
@@ -106,7 +139,7 @@ func TestReplacePortOrders(t *testing.T) {
 	// Verify new data
 	found := false
 	for _, m := range markets {
-		if m.Item == "Iron" && m.Price == 75 {
+		if m.ItemID == iron.ID && m.Price == 75 {
 			found = true
 			break
 		}
@@ -116,26 +149,73 @@ func TestReplacePortOrders(t *testing.T) {
 	}
 }
 
+func TestReplacePortOrdersRejectsPastExpiry(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	port := mustCreatePort(t, db, "Port Royal")
+	cannon := mustCreateItem(t, db, "Cannon")
+
+	// Zero expiry falls back to the 7 day default
+	err := db.ReplacePortOrders(ctx, port.ID, "buy", []Market{
+		{ItemID: cannon.ID, Price: 100, Quantity: 10},
+	}, "user123", "hash1", "")
+	if err != nil {
+		t.Fatalf("failed to insert orders with default expiry: %v", err)
+	}
+
+	markets, err := db.GetOrdersByPort(ctx, port.ID)
+	if err != nil {
+		t.Fatalf("failed to query orders: %v", err)
+	}
+	if len(markets) != 1 {
+		t.Fatalf("expected 1 order, got %d", len(markets))
+	}
+	if time.Until(markets[0].ExpiresAt) < 6*24*time.Hour {
+		t.Errorf("expected default expiry about 7 days out, got %v", markets[0].ExpiresAt)
+	}
+
+	// A past expiry is rejected without wiping the existing board
+	err = db.ReplacePortOrders(ctx, port.ID, "buy", []Market{
+		{ItemID: cannon.ID, Price: 120, Quantity: 5, ExpiresAt: time.Now().Add(-time.Minute)},
+	}, "user456", "hash2", "")
+	if !errors.Is(err, ErrExpiryNotInFuture) {
+		t.Fatalf("expected ErrExpiryNotInFuture, got %v", err)
+	}
+
+	markets, err = db.GetOrdersByPort(ctx, port.ID)
+	if err != nil {
+		t.Fatalf("failed to query orders: %v", err)
+	}
+	if len(markets) != 1 || markets[0].Price != 100 {
+		t.Errorf("expected original order to survive, got %+v", markets)
+	}
+}
+
 func TestDeleteExpiredOrders(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	ctx := context.Background()
+	port := mustCreatePort(t, db, "Test Port")
+	testItem := mustCreateItem(t, db, "Test Item")
+	validItem := mustCreateItem(t, db, "Valid Item")
 
 	// Insert order that expires in the past
 	query := `
-		INSERT INTO markets (port, item, order_type, price, quantity, submitted_by, expires_at, screenshot_hash)
+		INSERT INTO markets (port_id, item_id, order_type, price, quantity, submitted_by, expires_at, screenshot_hash)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	expiredTime := time.Now().Add(-1 * time.Hour)
-	_, err := db.conn.ExecContext(ctx, query, "Test Port", "Test Item", "buy", 100, 10, "user123", expiredTime, "hash1")
+	_, err := db.conn.ExecContext(ctx, query, port.ID, testItem.ID, "buy", 100, 10, "user123", expiredTime, "hash1")
 	if err != nil {
 		t.Fatalf("failed to insert test order: %v", err)
 	}
 
 	// Insert order that hasn't expired
 	futureTime := time.Now().Add(24 * time.Hour)
-	_, err = db.conn.ExecContext(ctx, query, "Test Port", "Valid Item", "buy", 200, 20, "user456", futureTime, "hash2")
+	_, err = db.conn.ExecContext(ctx, query, port.ID, validItem.ID, "buy", 200, 20, "user456", futureTime, "hash2")
 	if err != nil {
 		t.Fatalf("failed to insert valid order: %v", err)
 	}
@@ -150,15 +230,15 @@ func TestDeleteExpiredOrders(t *testing.T) {
 	}
 
 	// Verify only valid order remains
-	markets, err := db.GetOrdersByPort(ctx, "Test Port")
+	markets, err := db.GetOrdersByPort(ctx, port.ID)
 	if err != nil {
 		t.Fatalf("failed to query remaining orders: %v", err)
 	}
 	if len(markets) != 1 {
 		t.Errorf("expected 1 remaining order, got %d", len(markets))
 	}
-	if markets[0].Item != "Valid Item" {
-		t.Errorf("expected 'Valid Item', got '%s'", markets[0].Item)
+	if markets[0].ItemID != validItem.ID {
+		t.Errorf("expected 'Valid Item', got '%s'", markets[0].Item.DisplayName)
 	}
 }
 
@@ -181,16 +261,36 @@ func TestGetPricesByItem(t *testing.T) {
 		{"Port Royal", "Wood", "buy", 50},
 	}
 
+	ports := make(map[string]*Port)
+	items := make(map[string]*Item)
+	for _, o := range orders {
+		if ports[o.port] == nil {
+			ports[o.port] = mustCreatePort(t, db, o.port)
+		}
+		if items[o.item] == nil {
+			items[o.item] = mustCreateItem(t, db, o.item)
+		}
+	}
+
+	// Group orders per port and type, since each submission replaces that board
+	type board struct {
+		port      string
+		orderType string
+	}
+	boards := make(map[board][]Market)
 	for _, o := range orders {
-		markets := []Market{{Item: o.item, Price: o.price, Quantity: 10}}
-		err := db.ReplacePortOrders(ctx, o.port, o.orderType, markets, "user123", "hash")
+		key := board{o.port, o.orderType}
+		boards[key] = append(boards[key], Market{ItemID: items[o.item].ID, Price: o.price, Quantity: 10})
+	}
+	for key, markets := range boards {
+		err := db.ReplacePortOrders(ctx, ports[key.port].ID, key.orderType, markets, "user123", "hash", "")
 		if err != nil {
 			t.Fatalf("failed to insert order: %v", err)
 		}
 	}
 
 	// Query for Cannon
-	results, err := db.GetPricesByItem(ctx, "Cannon")
+	results, err := db.GetPricesByItem(ctx, items["Cannon"].ID, nil, 0, 0, 0, 0)
 	if err != nil {
 		t.Fatalf("failed to query prices: %v", err)
 	}
@@ -199,9 +299,213 @@ func TestGetPricesByItem(t *testing.T) {
 		t.Errorf("expected 3 Cannon orders, got %d", len(results))
 	}
 
-	// Verify sorted by price (buy orders first, then sell)
-	if results[0].Price > results[1].Price {
-		t.Error("expected results sorted by price")
+	// Buy orders come first, highest price first, then sell orders
+	if results[0].Price != 100 || results[1].Price != 95 || results[2].OrderType != "sell" {
+		t.Errorf("expected buys best-first then sells, got %d, %d, %s", results[0].Price, results[1].Price, results[2].OrderType)
+	}
+	if results[0].SubmittedAt.IsZero() {
+		t.Error("expected submitted_at to be scanned")
+	}
+
+	// Age the Nassau board past the freshness window
+	_, err = db.conn.ExecContext(ctx,
+		`UPDATE markets SET submitted_at = datetime('now', '-72 hours') WHERE port_id = ?`, ports["Nassau"].ID)
+	if err != nil {
+		t.Fatalf("failed to age orders: %v", err)
+	}
+
+	results, err = db.GetPricesByItem(ctx, items["Cannon"].ID, nil, 0, 0, 0, 48)
+	if err != nil {
+		t.Fatalf("failed to query prices: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected the 72h old order to be filtered out, got %d orders", len(results))
+	}
+	for _, m := range results {
+		if m.Port.DisplayName == "Nassau" {
+			t.Error("expected Nassau's stale order to be excluded")
+		}
+	}
+
+	// No limit still returns everything
+	if results, _ = db.GetPricesByItem(ctx, items["Cannon"].ID, nil, 0, 0, 0, 0); len(results) != 3 {
+		t.Errorf("expected 3 orders without an age limit, got %d", len(results))
+	}
+}
+
+func TestGetPricesByItemKeepsBestOfEachSide(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cannon := mustCreateItem(t, db, "Cannon")
+
+	// More buy orders than the per-side cap, plus two sell orders
+	for n := 1; n <= pricesPerSide+2; n++ {
+		port := mustCreatePort(t, db, fmt.Sprintf("Port %d", n))
+		orders := []Market{{ItemID: cannon.ID, Price: 100 + n, Quantity: 1}}
+		if err := db.ReplacePortOrders(ctx, port.ID, "buy", orders, "user123", "hash", ""); err != nil {
+			t.Fatalf("failed to insert orders: %v", err)
+		}
+		if n <= 2 {
+			orders = []Market{{ItemID: cannon.ID, Price: 200 - n, Quantity: 1}}
+			if err := db.ReplacePortOrders(ctx, port.ID, "sell", orders, "user123", "hash", ""); err != nil {
+				t.Fatalf("failed to insert orders: %v", err)
+			}
+		}
+	}
+
+	results, err := db.GetPricesByItem(ctx, cannon.ID, nil, 0, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to query prices: %v", err)
+	}
+	if len(results) != pricesPerSide+2 {
+		t.Fatalf("expected %d buys and 2 sells, got %d orders", pricesPerSide, len(results))
+	}
+	if results[0].Price != 100+pricesPerSide+2 {
+		t.Errorf("expected the highest buy first, got %d", results[0].Price)
+	}
+	if sells := results[pricesPerSide:]; sells[0].OrderType != "sell" || sells[0].Price != 198 || sells[1].Price != 199 {
+		t.Errorf("expected sells cheapest first, got %+v", sells)
+	}
+}
+
+func TestBulkCreatePorts(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	mustCreatePort(t, db, "Port Royal")
+
+	created, skipped, err := db.BulkCreatePorts(ctx, []Port{
+		{Name: "port royal", Region: "Caribbean", AddedBy: "admin1"},
+		{Name: "Tortuga", Region: "carib", Notes: "Pirate haven", AddedBy: "admin1"},
+		{Name: "TORTUGA", Region: "Caribbean", AddedBy: "admin1"},
+	})
+	if err != nil {
+		t.Fatalf("BulkCreatePorts failed: %v", err)
+	}
+	if created != 1 || skipped != 2 {
+		t.Errorf("expected 1 created and 2 skipped, got %d and %d", created, skipped)
+	}
+
+	tortuga, err := db.GetPortByName(ctx, "Tortuga")
+	if err != nil {
+		t.Fatalf("expected Tortuga to exist: %v", err)
+	}
+	if tortuga.Region != "Caribbean" || tortuga.RegionID == 0 || tortuga.Notes != "Pirate haven" {
+		t.Errorf("expected resolved region and notes, got %+v", tortuga)
+	}
+
+	// An unknown region rolls back the whole import
+	_, _, err = db.BulkCreatePorts(ctx, []Port{
+		{Name: "Nassau", Region: "Caribbean", AddedBy: "admin1"},
+		{Name: "Malta", Region: "Atlantis", AddedBy: "admin1"},
+	})
+	if !errors.Is(err, ErrUnknownRegion) {
+		t.Fatalf("expected ErrUnknownRegion, got %v", err)
+	}
+	if _, err := db.GetPortByName(ctx, "Nassau"); err == nil {
+		t.Error("expected Nassau to be rolled back")
+	}
+}
+
+func TestExportMarkets(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	tortuga := mustCreatePort(t, db, "Tortuga")
+	royal := mustCreatePort(t, db, "Port Royal")
+	cannon := mustCreateItem(t, db, "Cannon")
+	wood := mustCreateItem(t, db, "Wood")
+
+	for _, port := range []*Port{tortuga, royal} {
+		markets := []Market{
+			{ItemID: wood.ID, Price: 50, Quantity: 10},
+			{ItemID: cannon.ID, Price: 100, Quantity: 2},
+		}
+		if err := db.ReplacePortOrders(ctx, port.ID, "sell", markets, "user123", "hash", ""); err != nil {
+			t.Fatalf("failed to insert orders: %v", err)
+		}
+	}
+	if _, err := db.conn.ExecContext(ctx,
+		`UPDATE markets SET expires_at = datetime('now', '-1 hour') WHERE port_id = ? AND item_id = ?`,
+		tortuga.ID, wood.ID); err != nil {
+		t.Fatalf("failed to expire order: %v", err)
+	}
+
+	markets, err := db.ExportMarkets(ctx)
+	if err != nil {
+		t.Fatalf("ExportMarkets failed: %v", err)
+	}
+	if len(markets) != 3 {
+		t.Fatalf("expected 3 active orders, got %d", len(markets))
+	}
+	// Ordered by port, then item
+	want := []string{"Port Royal/Cannon", "Port Royal/Wood", "Tortuga/Cannon"}
+	for idx, m := range markets {
+		if got := m.Port.DisplayName + "/" + m.Item.DisplayName; got != want[idx] {
+			t.Errorf("row %d: expected %s, got %s", idx, want[idx], got)
+		}
+	}
+	if markets[0].Port.Region != "Caribbean" {
+		t.Errorf("expected port region, got %q", markets[0].Port.Region)
+	}
+}
+
+func TestGetOrdersByTagsMatchAll(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	weapon, err := db.CreateTag(ctx, "weapon", "type", "", "")
+	if err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	heavy, err := db.CreateTag(ctx, "heavy", "weight", "", "")
+	if err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+
+	port := mustCreatePort(t, db, "Port Royal")
+	cannon := mustCreateItem(t, db, "Cannon")
+	pistol := mustCreateItem(t, db, "Pistol")
+	anchor := mustCreateItem(t, db, "Anchor")
+	for item, tags := range map[*Item][]int{
+		cannon: {weapon.ID, heavy.ID},
+		pistol: {weapon.ID},
+		anchor: {heavy.ID},
+	} {
+		if err := db.AddTagsToItem(ctx, item.ID, tags); err != nil {
+			t.Fatalf("AddTagsToItem failed: %v", err)
+		}
+	}
+
+	markets := []Market{
+		{ItemID: cannon.ID, Price: 100, Quantity: 1},
+		{ItemID: pistol.ID, Price: 40, Quantity: 1},
+		{ItemID: anchor.ID, Price: 60, Quantity: 1},
+	}
+	if err := db.ReplacePortOrders(ctx, port.ID, "sell", markets, "user123", "hash", ""); err != nil {
+		t.Fatalf("failed to insert orders: %v", err)
+	}
+
+	anyTag, err := db.GetOrdersByTags(ctx, []int{weapon.ID, heavy.ID}, 0, false)
+	if err != nil {
+		t.Fatalf("GetOrdersByTags failed: %v", err)
+	}
+	// Cannon carries both tags but must only be listed once
+	if len(anyTag) != 3 {
+		t.Errorf("expected 3 orders matching any tag, got %d", len(anyTag))
+	}
+
+	allTags, err := db.GetOrdersByTags(ctx, []int{weapon.ID, heavy.ID, weapon.ID}, 0, true)
+	if err != nil {
+		t.Fatalf("GetOrdersByTags failed: %v", err)
+	}
+	if len(allTags) != 1 || allTags[0].ItemID != cannon.ID {
+		t.Errorf("expected only Cannon matching all tags, got %+v", allTags)
 	}
 }
 
@@ -210,18 +514,22 @@ func TestGetStats(t *testing.T) {
 	defer cleanup()
 
 	ctx := context.Background()
+	portRoyal := mustCreatePort(t, db, "Port Royal")
+	tortuga := mustCreatePort(t, db, "Tortuga")
+	cannon := mustCreateItem(t, db, "Cannon")
+	wood := mustCreateItem(t, db, "Wood")
 
 	// Insert some test data
 	orders := []Market{
-		{Item: "Cannon", Price: 100, Quantity: 10},
-		{Item: "Wood", Price: 50, Quantity: 100},
+		{ItemID: cannon.ID, Price: 100, Quantity: 10},
+		{ItemID: wood.ID, Price: 50, Quantity: 100},
 	}
-	err := db.ReplacePortOrders(ctx, "Port Royal", "buy", orders, "user123", "hash1")
+	err := db.ReplacePortOrders(ctx, portRoyal.ID, "buy", orders, "user123", "hash1", "")
 	if err != nil {
 		t.Fatalf("failed to insert orders: %v", err)
 	}
 
-	err = db.ReplacePortOrders(ctx, "Tortuga", "sell", orders, "user456", "hash2")
+	err = db.ReplacePortOrders(ctx, tortuga.ID, "sell", orders, "user456", "hash2", "")
 	if err != nil {
 		t.Fatalf("failed to insert orders: %v", err)
 	}
@@ -245,3 +553,978 @@ func TestGetStats(t *testing.T) {
 		t.Errorf("expected 2 submissions today, got %v", stats["submissions_today"])
 	}
 }
+
+func TestGetRegionStats(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	portRoyal := mustCreatePort(t, db, "Port Royal")
+	mustCreatePort(t, db, "Tortuga")
+	north, err := db.CreateRegion(ctx, "North Sea", "test")
+	if err != nil {
+		t.Fatalf("failed to create region: %v", err)
+	}
+	bergen, err := db.CreatePort(ctx, "Bergen", "Bergen", "North Sea", "test")
+	if err != nil {
+		t.Fatalf("failed to create port: %v", err)
+	}
+	cannon := mustCreateItem(t, db, "Cannon")
+	wood := mustCreateItem(t, db, "Wood")
+
+	orders := []Market{
+		{ItemID: cannon.ID, Price: 100, Quantity: 10},
+		{ItemID: wood.ID, Price: 50, Quantity: 100},
+	}
+	if err := db.ReplacePortOrders(ctx, portRoyal.ID, "buy", orders, "user1", "hash1", ""); err != nil {
+		t.Fatalf("failed to insert orders: %v", err)
+	}
+	if err := db.ReplacePortOrders(ctx, bergen.ID, "sell", orders[:1], "user2", "hash2", ""); err != nil {
+		t.Fatalf("failed to insert orders: %v", err)
+	}
+
+	stats, err := db.GetRegionStats(ctx, north.ID)
+	if err != nil {
+		t.Fatalf("GetRegionStats failed: %v", err)
+	}
+	if stats["total_orders"] != 1 || stats["unique_ports"] != 1 || stats["total_ports"] != 1 {
+		t.Errorf("expected 1 order at 1 of 1 ports, got %v", stats)
+	}
+	if _, ok := stats["last_update"]; !ok {
+		t.Error("expected a last update for the region")
+	}
+	if _, ok := stats["total_items"]; ok {
+		t.Error("region stats shouldn't include global item counts")
+	}
+
+	all, err := db.GetRegionStats(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetRegionStats failed: %v", err)
+	}
+	if all["total_orders"] != 3 || all["total_ports"] != 3 {
+		t.Errorf("expected 3 orders across 3 ports, got %v", all)
+	}
+}
+
+func TestGetOrdersByPortFiltered(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	port := mustCreatePort(t, db, "Tortuga")
+	cannon := mustCreateItem(t, db, "Long Cannon")
+	mortar := mustCreateItem(t, db, "Mortar")
+	wood := mustCreateItem(t, db, "Wood")
+
+	buys := []Market{{ItemID: cannon.ID, Price: 100, Quantity: 1}, {ItemID: wood.ID, Price: 5, Quantity: 50}}
+	sells := []Market{{ItemID: cannon.ID, Price: 120, Quantity: 1}, {ItemID: mortar.ID, Price: 200, Quantity: 2}}
+	if err := db.ReplacePortOrders(ctx, port.ID, "buy", buys, "user1", "", ""); err != nil {
+		t.Fatalf("failed to insert orders: %v", err)
+	}
+	if err := db.ReplacePortOrders(ctx, port.ID, "sell", sells, "user1", "", ""); err != nil {
+		t.Fatalf("failed to insert orders: %v", err)
+	}
+
+	tests := []struct {
+		orderType, item string
+		want            int
+	}{
+		{"", "", 4},
+		{"sell", "", 2},
+		{"", "CANNON", 2},
+		{"buy", "cannon", 1},
+		{"sell", "wood", 0},
+	}
+	for _, tt := range tests {
+		orders, err := db.GetOrdersByPortFiltered(ctx, port.ID, tt.orderType, tt.item)
+		if err != nil {
+			t.Fatalf("GetOrdersByPortFiltered(%q, %q) failed: %v", tt.orderType, tt.item, err)
+		}
+		if len(orders) != tt.want {
+			t.Errorf("GetOrdersByPortFiltered(%q, %q): expected %d orders, got %d", tt.orderType, tt.item, tt.want, len(orders))
+		}
+	}
+}
+
+func TestGetStatsStalePorts(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	portRoyal := mustCreatePort(t, db, "Port Royal")
+	tortuga := mustCreatePort(t, db, "Tortuga")
+	mustCreatePort(t, db, "Nassau") // never submitted, so not stale
+	cannon := mustCreateItem(t, db, "Cannon")
+	orders := []Market{{ItemID: cannon.ID, Price: 100, Quantity: 10}}
+
+	for _, port := range []*Port{portRoyal, tortuga} {
+		if err := db.ReplacePortOrders(ctx, port.ID, "buy", orders, "user1", "", ""); err != nil {
+			t.Fatalf("failed to insert orders: %v", err)
+		}
+	}
+	if _, err := db.conn.ExecContext(ctx,
+		`UPDATE markets SET submitted_at = datetime('now', '-4 days') WHERE port_id = ?`, tortuga.ID); err != nil {
+		t.Fatalf("failed to age orders: %v", err)
+	}
+
+	stats, err := db.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	if stats["stale_ports"] != 1 {
+		t.Errorf("expected 1 stale port, got %v", stats["stale_ports"])
+	}
+}
+
+func TestGetMarketOverview(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	portRoyal := mustCreatePort(t, db, "Port Royal")
+	tortuga := mustCreatePort(t, db, "Tortuga")
+	cannon := mustCreateItem(t, db, "Cannon")
+	wood := mustCreateItem(t, db, "Wood")
+	rope := mustCreateItem(t, db, "Rope")
+
+	boards := []struct {
+		port      *Port
+		orderType string
+		orders    []Market
+	}{
+		{portRoyal, "sell", []Market{
+			{ItemID: cannon.ID, Price: 100, Quantity: 10},
+			{ItemID: wood.ID, Price: 50, Quantity: 100},
+			{ItemID: rope.ID, Price: 20, Quantity: 5},
+		}},
+		{tortuga, "sell", []Market{
+			{ItemID: cannon.ID, Price: 160, Quantity: 3},
+			{ItemID: wood.ID, Price: 55, Quantity: 40},
+		}},
+		{tortuga, "buy", []Market{
+			{ItemID: rope.ID, Price: 15, Quantity: 50},
+		}},
+	}
+	for _, board := range boards {
+		if err := db.ReplacePortOrders(ctx, board.port.ID, board.orderType, board.orders, "user123", "hash", ""); err != nil {
+			t.Fatalf("failed to insert orders: %v", err)
+		}
+	}
+
+	// Player buy orders count towards demand; an expired one must not
+	_, err := db.CreatePlayerOrder(ctx, PlayerOrder{
+		UserID: "buyer1", ItemID: rope.ID, OrderType: "buy", Price: 18, Quantity: 10,
+		IngameName: "Buyer", ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("failed to create player order: %v", err)
+	}
+	expired, err := db.CreatePlayerOrder(ctx, PlayerOrder{
+		UserID: "buyer2", ItemID: wood.ID, OrderType: "buy", Price: 40, Quantity: 10,
+		IngameName: "Buyer", ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("failed to create player order: %v", err)
+	}
+	expirePlayerOrderNow(t, db, expired.ID)
+
+	overview, err := db.GetMarketOverview(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetMarketOverview failed: %v", err)
+	}
+
+	if overview.ActiveMarketOrders != 6 {
+		t.Errorf("expected 6 market orders, got %d", overview.ActiveMarketOrders)
+	}
+	if overview.ActivePlayerOrders != 1 {
+		t.Errorf("expected 1 player order, got %d", overview.ActivePlayerOrders)
+	}
+
+	if len(overview.TopPorts) != 2 || overview.TopPorts[0].PortName != "Port Royal" || overview.TopPorts[0].Orders != 3 {
+		t.Errorf("expected Port Royal with 3 orders first, got %+v", overview.TopPorts)
+	}
+
+	// Rope is only sold at one port, so it has no spread
+	if len(overview.WidestSpreads) != 2 {
+		t.Fatalf("expected 2 spreads, got %+v", overview.WidestSpreads)
+	}
+	if s := overview.WidestSpreads[0]; s.ItemName != "Cannon" || s.MinPrice != 100 || s.MaxPrice != 160 {
+		t.Errorf("expected Cannon 100-160 as widest spread, got %+v", s)
+	}
+
+	if len(overview.MostRequested) != 1 || overview.MostRequested[0].ItemName != "Rope" || overview.MostRequested[0].BuyOrders != 2 {
+		t.Errorf("expected Rope with 2 buy orders, got %+v", overview.MostRequested)
+	}
+
+	// Cannon and Wood have two sell orders each
+	if len(overview.TopItems) != 3 || overview.TopItems[0].ItemName != "Cannon" || overview.TopItems[0].Orders != 2 {
+		t.Errorf("expected Cannon with 2 orders first, got %+v", overview.TopItems)
+	}
+
+	// Rope is the only item with both sides: sold for 20, bought for 15
+	if len(overview.LargestSpreads) != 1 {
+		t.Fatalf("expected 1 buy/sell spread, got %+v", overview.LargestSpreads)
+	}
+	if s := overview.LargestSpreads[0]; s.ItemName != "Rope" || s.MinSellPort != "Port Royal" || s.MaxBuyPort != "Tortuga" || s.Spread() != 5 {
+		t.Errorf("expected Rope 20 at Port Royal vs 15 at Tortuga, got %+v", s)
+	}
+
+	// Lists are bounded by the limit
+	overview, err = db.GetMarketOverview(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetMarketOverview failed: %v", err)
+	}
+	if len(overview.TopPorts) != 1 || len(overview.WidestSpreads) != 1 {
+		t.Errorf("expected lists capped at 1, got %d ports and %d spreads", len(overview.TopPorts), len(overview.WidestSpreads))
+	}
+}
+
+func TestGetLargestSpreadsOrdersArbitrageFirst(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	portRoyal := mustCreatePort(t, db, "Port Royal")
+	tortuga := mustCreatePort(t, db, "Tortuga")
+	cannon := mustCreateItem(t, db, "Cannon")
+	wood := mustCreateItem(t, db, "Wood")
+
+	boards := []struct {
+		port      *Port
+		orderType string
+		orders    []Market
+	}{
+		{portRoyal, "sell", []Market{
+			{ItemID: cannon.ID, Price: 100, Quantity: 10},
+			{ItemID: wood.ID, Price: 50, Quantity: 10},
+		}},
+		{tortuga, "sell", []Market{{ItemID: cannon.ID, Price: 120, Quantity: 10}}},
+		{tortuga, "buy", []Market{
+			{ItemID: cannon.ID, Price: 140, Quantity: 10},
+			{ItemID: wood.ID, Price: 45, Quantity: 10},
+		}},
+	}
+	for _, board := range boards {
+		if err := db.ReplacePortOrders(ctx, board.port.ID, board.orderType, board.orders, "user123", "hash", ""); err != nil {
+			t.Fatalf("failed to insert orders: %v", err)
+		}
+	}
+
+	spreads, err := db.GetLargestSpreads(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetLargestSpreads failed: %v", err)
+	}
+	if len(spreads) != 2 {
+		t.Fatalf("expected 2 spreads, got %+v", spreads)
+	}
+
+	// Buying cannons at Port Royal for 100 and selling at Tortuga for 140 beats wood
+	if s := spreads[0]; s.ItemName != "Cannon" || s.MinSell != 100 || s.MinSellPort != "Port Royal" || s.MaxBuy != 140 || s.Spread() != -40 {
+		t.Errorf("expected Cannon arbitrage first, got %+v", s)
+	}
+	if s := spreads[1]; s.ItemName != "Wood" || s.Spread() != 5 {
+		t.Errorf("expected Wood with a spread of 5 second, got %+v", s)
+	}
+}
+
+func TestFindItemMatchesWithoutNotes(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	item := mustCreateItem(t, db, "Cannon")
+
+	for _, name := range []string{"Cannon", "canon"} {
+		matches, err := db.FindItemMatches(ctx, name, 1)
+		if err != nil {
+			t.Fatalf("FindItemMatches(%q) failed: %v", name, err)
+		}
+		if len(matches) != 1 || matches[0].Item.ID != item.ID {
+			t.Errorf("FindItemMatches(%q): expected Cannon, got %d matches", name, len(matches))
+		}
+	}
+}
+
+func TestCreateTagValidatesColor(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, color := range []string{"FF5733", "#FF573", "#GG5733", "red", "#FF57330"} {
+		if _, err := db.CreateTag(ctx, "bad"+color, "test", color, ""); !errors.Is(err, ErrInvalidTagColor) {
+			t.Errorf("CreateTag with color %q: expected ErrInvalidTagColor, got %v", color, err)
+		}
+	}
+
+	for idx, color := range []string{"#FF5733", "#ff5733", ""} {
+		if _, err := db.CreateTag(ctx, fmt.Sprintf("good%d", idx), "test", color, ""); err != nil {
+			t.Errorf("CreateTag with color %q failed: %v", color, err)
+		}
+	}
+
+	value, err := ParseTagColor("#FF5733")
+	if err != nil || value != 0xFF5733 {
+		t.Errorf("ParseTagColor: expected 0xFF5733, got %#x (%v)", value, err)
+	}
+}
+
+func TestUpdateTag(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	tag, err := db.CreateTag(ctx, "weapon", "type", "#FF0000", "⚔️")
+	if err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	if _, err := db.CreateTag(ctx, "heavy", "size", "", ""); err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+
+	category, color := "arms", "#00FF00"
+	updated, err := db.UpdateTag(ctx, tag.ID, nil, &category, &color, nil)
+	if err != nil {
+		t.Fatalf("UpdateTag failed: %v", err)
+	}
+	if updated.Name != "weapon" || updated.Category != "arms" || updated.Color != "#00FF00" || updated.Icon != "⚔️" {
+		t.Errorf("expected only category and color to change, got %+v", updated)
+	}
+
+	bad := "green"
+	if _, err := db.UpdateTag(ctx, tag.ID, nil, nil, &bad, nil); !errors.Is(err, ErrInvalidTagColor) {
+		t.Errorf("expected ErrInvalidTagColor, got %v", err)
+	}
+	taken := "HEAVY"
+	if _, err := db.UpdateTag(ctx, tag.ID, &taken, nil, nil, nil); !errors.Is(err, ErrTagNameTaken) {
+		t.Errorf("expected ErrTagNameTaken, got %v", err)
+	}
+
+	stored, err := db.GetTagByName(ctx, "Weapon")
+	if err != nil {
+		t.Fatalf("GetTagByName failed: %v", err)
+	}
+	if stored.Category != "arms" || stored.Color != "#00FF00" {
+		t.Errorf("expected the failed updates to change nothing, got %+v", stored)
+	}
+}
+
+func TestBulkTagUntaggedByPattern(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	light := mustCreateItem(t, db, "Light Cannonball")
+	heavy := mustCreateItem(t, db, "Heavy Cannonball")
+	tagged := mustCreateItem(t, db, "Chain Cannonball")
+	mustCreateItem(t, db, "Rope")
+	ammo, err := db.CreateTag(ctx, "ammunition", "type", "", "")
+	if err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	if err := db.AddTagsToItem(ctx, tagged.ID, []int{ammo.ID}); err != nil {
+		t.Fatalf("AddTagsToItem failed: %v", err)
+	}
+
+	items, err := db.FindUntaggedItemsByPattern(ctx, "CANNONBALL")
+	if err != nil {
+		t.Fatalf("FindUntaggedItemsByPattern failed: %v", err)
+	}
+	if len(items) != 2 || items[0].ID != heavy.ID || items[1].ID != light.ID {
+		t.Fatalf("expected the two untagged cannonballs, got %+v", items)
+	}
+
+	if err := db.AddTagsToItems(ctx, []int{heavy.ID, light.ID}, []int{ammo.ID}); err != nil {
+		t.Fatalf("AddTagsToItems failed: %v", err)
+	}
+	if left, _ := db.FindUntaggedItemsByPattern(ctx, "cannonball"); len(left) != 0 {
+		t.Errorf("expected no untagged cannonballs left, got %d", len(left))
+	}
+	if tagged, _ := db.GetItemsByTag(ctx, ammo.ID); len(tagged) != 3 {
+		t.Errorf("expected 3 items tagged ammunition, got %d", len(tagged))
+	}
+}
+
+func TestGetItemsWithTags(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cannon := mustCreateItem(t, db, "Cannon")
+	mustCreateItem(t, db, "Rope")
+	mortar := mustCreateItem(t, db, "Mortar")
+	weapon, _ := db.CreateTag(ctx, "weapon", "type", "", "")
+	heavy, _ := db.CreateTag(ctx, "heavy", "size", "", "")
+	if err := db.AddTagsToItem(ctx, cannon.ID, []int{weapon.ID, heavy.ID}); err != nil {
+		t.Fatalf("AddTagsToItem failed: %v", err)
+	}
+	if err := db.AddTagsToItem(ctx, mortar.ID, []int{weapon.ID}); err != nil {
+		t.Fatalf("AddTagsToItem failed: %v", err)
+	}
+
+	all, err := db.GetItemsWithTags(ctx, 0, "")
+	if err != nil {
+		t.Fatalf("GetItemsWithTags failed: %v", err)
+	}
+	if len(all) != 3 || all[0].DisplayName != "Cannon" || len(all[0].Tags) != 2 || len(all[2].Tags) != 0 {
+		t.Fatalf("expected 3 items with Cannon's 2 tags grouped, got %+v", all)
+	}
+
+	bySize, err := db.GetItemsWithTags(ctx, 0, "SIZE")
+	if err != nil {
+		t.Fatalf("GetItemsWithTags failed: %v", err)
+	}
+	if len(bySize) != 1 || bySize[0].ID != cannon.ID || len(bySize[0].Tags) != 2 {
+		t.Errorf("expected only Cannon, still listing both tags, got %+v", bySize)
+	}
+
+	byTag, err := db.GetItemsWithTags(ctx, weapon.ID, "")
+	if err != nil {
+		t.Fatalf("GetItemsWithTags failed: %v", err)
+	}
+	if len(byTag) != 2 {
+		t.Errorf("expected 2 weapons, got %d", len(byTag))
+	}
+}
+
+func TestRenameTagKeepsItems(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cannon := mustCreateItem(t, db, "Cannon")
+	tag, err := db.CreateTag(ctx, "weapon", "type", "", "")
+	if err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	if err := db.AddTagsToItem(ctx, cannon.ID, []int{tag.ID}); err != nil {
+		t.Fatalf("AddTagsToItem failed: %v", err)
+	}
+
+	if _, err := db.RenameTag(ctx, tag.ID, "armament"); err != nil {
+		t.Fatalf("RenameTag failed: %v", err)
+	}
+
+	// Handlers resolve tags by name, so the new name must lead to the same links
+	renamed, err := db.GetTagByName(ctx, "Armament")
+	if err != nil || renamed.ID != tag.ID {
+		t.Fatalf("expected the renamed tag under its new name, got %v, %v", renamed, err)
+	}
+	if _, err := db.GetTagByName(ctx, "weapon"); err != sql.ErrNoRows {
+		t.Errorf("expected the old name to be gone, got %v", err)
+	}
+
+	items, err := db.GetItemsByTag(ctx, renamed.ID)
+	if err != nil || len(items) != 1 || items[0].ID != cannon.ID {
+		t.Errorf("expected Cannon still tagged, got %+v, %v", items, err)
+	}
+	tags, err := db.GetItemTags(ctx, cannon.ID)
+	if err != nil || len(tags) != 1 || tags[0].Name != "armament" {
+		t.Errorf("expected Cannon's tag to show the new name, got %+v, %v", tags, err)
+	}
+}
+
+func TestGetItemCountsByTag(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	weapon, err := db.CreateTag(ctx, "weapon", "type", "", "")
+	if err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	heavy, err := db.CreateTag(ctx, "heavy", "weight", "", "")
+	if err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	if _, err := db.CreateTag(ctx, "unused", "type", "", ""); err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+
+	cannon := mustCreateItem(t, db, "Cannon")
+	mortar := mustCreateItem(t, db, "Mortar")
+	mustCreateItem(t, db, "Rope")
+	if err := db.AddTagsToItem(ctx, cannon.ID, []int{weapon.ID, heavy.ID}); err != nil {
+		t.Fatalf("AddTagsToItem failed: %v", err)
+	}
+	if err := db.AddTagsToItem(ctx, mortar.ID, []int{weapon.ID}); err != nil {
+		t.Fatalf("AddTagsToItem failed: %v", err)
+	}
+
+	counts, err := db.GetItemCountsByTag(ctx)
+	if err != nil {
+		t.Fatalf("GetItemCountsByTag failed: %v", err)
+	}
+
+	got := make(map[string]int)
+	for _, c := range counts {
+		got[c.Tag.Name] = c.ItemCount
+	}
+	want := map[string]int{"weapon": 2, "heavy": 1, "unused": 0}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d tags, got %v", len(want), got)
+	}
+	for name, count := range want {
+		if got[name] != count {
+			t.Errorf("%s: expected %d items, got %d", name, count, got[name])
+		}
+	}
+
+	items, err := db.GetItemsByTag(ctx, weapon.ID)
+	if err != nil {
+		t.Fatalf("GetItemsByTag failed: %v", err)
+	}
+	if len(items) != 2 || items[0].DisplayName != "Cannon" || items[1].DisplayName != "Mortar" {
+		t.Errorf("expected Cannon and Mortar, got %+v", items)
+	}
+}
+
+func TestGetAuditLog(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	port := mustCreatePort(t, db, "Port Royal")
+	cannon := mustCreateItem(t, db, "Cannon")
+
+	orders := []Market{{ItemID: cannon.ID, Price: 100, Quantity: 1}}
+	if err := db.ReplacePortOrders(ctx, port.ID, "sell", orders, "user123", "hash", ""); err != nil {
+		t.Fatalf("failed to insert orders: %v", err)
+	}
+	if _, err := db.PurgePort(ctx, port.ID, "admin1"); err != nil {
+		t.Fatalf("failed to purge port: %v", err)
+	}
+
+	entries, err := db.GetAuditLog(ctx, "", 10)
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != "purge_port" || entries[1].Action != "replace_orders" {
+		t.Fatalf("expected purge then replace, newest first, got %+v", entries)
+	}
+	if entries[0].UserID != "admin1" || !strings.Contains(entries[0].Details, `"deleted":1`) {
+		t.Errorf("unexpected purge entry %+v", entries[0])
+	}
+
+	entries, err = db.GetAuditLog(ctx, "replace_orders", 10)
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].UserID != "user123" {
+		t.Errorf("expected only the replace entry, got %+v", entries)
+	}
+
+	if entries, _ = db.GetAuditLog(ctx, "", 1); len(entries) != 1 {
+		t.Errorf("expected the limit to apply, got %d entries", len(entries))
+	}
+}
+
+func TestGetModerationStats(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	stats, err := db.GetModerationStats(ctx)
+	if err != nil {
+		t.Fatalf("GetModerationStats failed: %v", err)
+	}
+	if *stats != (ModerationStats{}) {
+		t.Errorf("expected zero stats on an empty database, got %+v", stats)
+	}
+
+	for _, reporter := range []string{"user1", "user2", "user3"} {
+		if _, _, err := db.CreateTradeReport(ctx, TradeReport{ReporterUserID: reporter, ReportedUserID: "user9", Reason: "scam"}, ReportAutoban{}); err != nil {
+			t.Fatalf("failed to create report: %v", err)
+		}
+	}
+	// The first report waited two hours before it was dismissed; the last
+	// is old enough not to count this week
+	db.conn.ExecContext(ctx, `UPDATE trade_reports SET created_at = datetime('now', '-2 hours') WHERE id = 1`)
+	db.conn.ExecContext(ctx, `UPDATE trade_reports SET created_at = datetime('now', '-10 days') WHERE id = 3`)
+	if err := db.UpdateTradeReportStatus(ctx, 1, "dismissed", "admin1"); err != nil {
+		t.Fatalf("failed to dismiss report: %v", err)
+	}
+	if _, err := db.CreateTradeBan(ctx, TradeBan{UserID: "user9", Reason: "scam", BannedBy: "admin1"}); err != nil {
+		t.Fatalf("failed to ban: %v", err)
+	}
+
+	stats, err = db.GetModerationStats(ctx)
+	if err != nil {
+		t.Fatalf("GetModerationStats failed: %v", err)
+	}
+	if stats.PendingReports != 2 || stats.ReportsThisWeek != 2 || stats.ActionedReports != 1 {
+		t.Errorf("unexpected report counts %+v", stats)
+	}
+	if stats.AvgTimeToAction < 119*time.Minute || stats.AvgTimeToAction > 121*time.Minute {
+		t.Errorf("expected about 2h to action, got %v", stats.AvgTimeToAction)
+	}
+	if stats.OldestPendingAge < 10*24*time.Hour-time.Minute {
+		t.Errorf("expected the oldest pending report to be 10 days old, got %v", stats.OldestPendingAge)
+	}
+	// Reports themselves aren't moderator actions; the dismissal and ban are
+	if stats.ActiveBans != 1 || stats.BansThisWeek != 1 || stats.ActionsThisWeek != 2 {
+		t.Errorf("unexpected ban and action counts %+v", stats)
+	}
+}
+
+func TestHasExistingPendingReport(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cannon := mustCreateItem(t, db, "Cannon")
+	expires := time.Now().Add(time.Hour)
+	orderID := mustCreatePlayerOrder(t, db, "user9", cannon.ID, expires).ID
+	otherOrder := mustCreatePlayerOrder(t, db, "user9", cannon.ID, expires).ID
+	if _, _, err := db.CreateTradeReport(ctx, TradeReport{ReporterUserID: "user1", ReportedUserID: "user9", OrderID: &orderID, Reason: "scam"}, ReportAutoban{}); err != nil {
+		t.Fatalf("failed to create report: %v", err)
+	}
+
+	check := func(reporter, reported string, order *int) bool {
+		t.Helper()
+		found, err := db.HasExistingPendingReport(ctx, reporter, reported, order)
+		if err != nil {
+			t.Fatalf("HasExistingPendingReport failed: %v", err)
+		}
+		return found
+	}
+	if !check("user1", "user9", &orderID) || !check("user1", "user9", nil) {
+		t.Error("expected the pending report to be found")
+	}
+	if check("user1", "user9", &otherOrder) || check("user2", "user9", &orderID) {
+		t.Error("expected other orders and reporters not to match")
+	}
+
+	if err := db.UpdateTradeReportStatus(ctx, 1, "dismissed", "admin1"); err != nil {
+		t.Fatalf("failed to dismiss report: %v", err)
+	}
+	if check("user1", "user9", &orderID) {
+		t.Error("expected a reviewed report to allow a new one")
+	}
+}
+
+func TestCreateTradeReportAutoban(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	autoban := ReportAutoban{Threshold: 3, BanFor: 24 * time.Hour}
+	report := func(reporter string) *ReportEscalation {
+		t.Helper()
+		_, escalation, err := db.CreateTradeReport(ctx, TradeReport{ReporterUserID: reporter, ReportedUserID: "user9", Reason: "scam"}, autoban)
+		if err != nil {
+			t.Fatalf("failed to create report: %v", err)
+		}
+		return escalation
+	}
+
+	// Repeat reports from one user and dismissed reports don't add up
+	if report("user1") != nil || report("user1") != nil {
+		t.Fatal("expected no ban from a single reporter")
+	}
+	report("user2")
+	if err := db.UpdateTradeReportStatus(ctx, 3, "dismissed", "admin1"); err != nil {
+		t.Fatalf("failed to dismiss report: %v", err)
+	}
+	if report("user3") != nil {
+		t.Fatal("expected a dismissed report not to count")
+	}
+
+	escalation := report("user4")
+	if escalation == nil {
+		t.Fatal("expected the third reporter to trigger a ban")
+	}
+	if escalation.Reporters != 3 || len(escalation.ReportIDs) != 4 {
+		t.Errorf("expected 3 reporters and the 4 pending reports, got %+v", escalation)
+	}
+	if escalation.Ban.BannedBy != SystemUserID || escalation.Ban.ExpiresAt == nil {
+		t.Errorf("expected a temporary system ban, got %+v", escalation.Ban)
+	}
+	if ban, _ := db.IsUserBanned(ctx, "user9"); ban == nil || ban.ID != escalation.Ban.ID {
+		t.Errorf("expected user9 to be banned, got %+v", ban)
+	}
+	if pending, _ := db.CountPendingReportsAgainst(ctx, "user9"); pending != 0 {
+		t.Errorf("expected the triggering reports to be reviewed, %d still pending", pending)
+	}
+
+	// Already banned: no second ban
+	if report("user5") != nil {
+		t.Error("expected no escalation while the user is banned")
+	}
+
+	// After the ban, only reports since it count again. Backdate the ban so
+	// the new reports land after it.
+	db.conn.ExecContext(ctx, `UPDATE trade_bans SET banned_at = datetime('now', '-1 day'), active = FALSE`)
+	db.conn.ExecContext(ctx, `UPDATE trade_reports SET created_at = datetime('now', '-2 days')`)
+	if report("user6") != nil || report("user7") != nil {
+		t.Error("expected old reports not to count after a ban")
+	}
+	if report("user8") == nil {
+		t.Error("expected three new reporters to trigger another ban")
+	}
+
+	// A zero threshold never bans
+	autoban.Threshold = 0
+	db.conn.ExecContext(ctx, `UPDATE trade_bans SET active = FALSE`)
+	if report("user10") != nil {
+		t.Error("expected no escalation with autoban off")
+	}
+}
+
+func TestGetSubmissionsByUser(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	records := []SubmissionRecord{
+		{UserID: "user1", PortID: 1, PortName: "Port Royal", OrderType: "sell", TotalItems: 12, NewItems: 2, ScreenshotHash: "abc"},
+		{UserID: "user2", PortID: 1, PortName: "Port Royal", OrderType: "buy", TotalItems: 3},
+		{UserID: "user1", PortID: 2, PortName: "Tortuga", OrderType: "buy", TotalItems: 5},
+	}
+	for _, r := range records {
+		if err := db.LogSubmission(ctx, r); err != nil {
+			t.Fatalf("LogSubmission failed: %v", err)
+		}
+	}
+
+	history, err := db.GetSubmissionsByUser(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetSubmissionsByUser failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 submissions for user1, got %+v", history)
+	}
+	if history[0].PortName != "Tortuga" || history[1].PortName != "Port Royal" {
+		t.Errorf("expected newest first, got %+v", history)
+	}
+	if h := history[1]; h.UserID != "user1" || h.TotalItems != 12 || h.NewItems != 2 || h.ScreenshotHash != "abc" || h.SubmittedAt.IsZero() {
+		t.Errorf("unexpected submission record %+v", h)
+	}
+
+	// Submissions are regular audit entries too
+	if entries, _ := db.GetAuditLog(ctx, "submission", 10); len(entries) != 3 {
+		t.Errorf("expected 3 submission audit entries, got %d", len(entries))
+	}
+}
+
+func TestGetTopContributors(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	port := mustCreatePort(t, db, "Port Royal")
+	cannon := mustCreateItem(t, db, "Cannon")
+	orders := []Market{{ItemID: cannon.ID, Price: 100, Quantity: 1}}
+
+	for _, user := range []string{"alice", "bob", "alice", "carol"} {
+		if err := db.ReplacePortOrders(ctx, port.ID, "sell", orders, user, "hash", ""); err != nil {
+			t.Fatalf("failed to insert orders: %v", err)
+		}
+	}
+
+	// Carol's submission is older than a week
+	_, err := db.conn.ExecContext(ctx,
+		`UPDATE audit_log SET timestamp = datetime('now', '-10 days') WHERE user_id = 'carol'`)
+	if err != nil {
+		t.Fatalf("failed to age audit entry: %v", err)
+	}
+
+	week, err := db.GetTopContributors(ctx, 7)
+	if err != nil {
+		t.Fatalf("GetTopContributors failed: %v", err)
+	}
+	if len(week) != 2 || week[0].UserID != "alice" || week[0].Submissions != 2 || week[1].UserID != "bob" {
+		t.Errorf("expected alice (2) then bob this week, got %+v", week)
+	}
+
+	if month, _ := db.GetTopContributors(ctx, 30); len(month) != 3 {
+		t.Errorf("expected 3 contributors this month, got %+v", month)
+	}
+}
+
+func TestCountOrdersByPortAndGetPortByID(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	port := mustCreatePort(t, db, "Port Royal")
+	cannon := mustCreateItem(t, db, "Cannon")
+	mortar := mustCreateItem(t, db, "Mortar")
+
+	orders := []Market{{ItemID: cannon.ID, Price: 100, Quantity: 1}, {ItemID: mortar.ID, Price: 200, Quantity: 2}}
+	if err := db.ReplacePortOrders(ctx, port.ID, "sell", orders, "user123", "hash", ""); err != nil {
+		t.Fatalf("failed to insert orders: %v", err)
+	}
+
+	count, err := db.CountOrdersByPort(ctx, port.ID)
+	if err != nil {
+		t.Fatalf("CountOrdersByPort failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 orders, got %d", count)
+	}
+
+	got, err := db.GetPortByID(ctx, port.ID)
+	if err != nil {
+		t.Fatalf("GetPortByID failed: %v", err)
+	}
+	if got == nil || got.DisplayName != port.DisplayName {
+		t.Errorf("expected %s, got %+v", port.DisplayName, got)
+	}
+
+	missing, err := db.GetPortByID(ctx, port.ID+1000)
+	if err != nil || missing != nil {
+		t.Errorf("expected nil, nil for unknown port, got %+v, %v", missing, err)
+	}
+}
+
+func TestMergePortOrders(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	port := mustCreatePort(t, db, "Port Royal")
+	cannon := mustCreateItem(t, db, "Cannon")
+	mortar := mustCreateItem(t, db, "Mortar")
+	rope := mustCreateItem(t, db, "Rope")
+
+	first := []Market{{ItemID: cannon.ID, Price: 100, Quantity: 1}, {ItemID: mortar.ID, Price: 200, Quantity: 2}}
+	if err := db.ReplacePortOrders(ctx, port.ID, "sell", first, "user1", "hash1", ""); err != nil {
+		t.Fatalf("failed to insert orders: %v", err)
+	}
+	if err := db.ReplacePortOrders(ctx, port.ID, "buy", []Market{{ItemID: cannon.ID, Price: 50, Quantity: 1}}, "user1", "hash2", ""); err != nil {
+		t.Fatalf("failed to insert buy orders: %v", err)
+	}
+
+	// A second screenshot updates the cannon and adds rope without losing the mortar
+	second := []Market{{ItemID: cannon.ID, Price: 120, Quantity: 3}, {ItemID: rope.ID, Price: 10, Quantity: 5}}
+	if err := db.MergePortOrders(ctx, port.ID, "sell", second, "user2", "hash3", ""); err != nil {
+		t.Fatalf("MergePortOrders failed: %v", err)
+	}
+
+	orders, err := db.GetOrdersByPort(ctx, port.ID)
+	if err != nil {
+		t.Fatalf("GetOrdersByPort failed: %v", err)
+	}
+	prices := make(map[string]int)
+	for _, o := range orders {
+		prices[o.OrderType+":"+o.Item.DisplayName] = o.Price
+	}
+	want := map[string]int{"sell:Cannon": 120, "sell:Mortar": 200, "sell:Rope": 10, "buy:Cannon": 50}
+	if len(orders) != len(want) {
+		t.Fatalf("expected %d orders, got %d: %v", len(want), len(orders), prices)
+	}
+	for key, price := range want {
+		if prices[key] != price {
+			t.Errorf("%s: expected price %d, got %d", key, price, prices[key])
+		}
+	}
+
+	entries, err := db.GetAuditLog(ctx, "merge_orders", 10)
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+	if len(entries) != 1 || !strings.Contains(entries[0].Details, `"deleted":1,"inserted":2`) {
+		t.Errorf("expected one merge entry deleting 1 and inserting 2, got %+v", entries)
+	}
+}
+
+func TestGuildSubmissionTimeout(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := db.SetGuildTradePreview(ctx, "guild1", true, "admin"); err != nil {
+		t.Fatalf("SetGuildTradePreview failed: %v", err)
+	}
+	settings, err := db.GetGuildSettings(ctx, "guild1")
+	if err != nil {
+		t.Fatalf("GetGuildSettings failed: %v", err)
+	}
+	if settings.SubmissionTimeoutMinutes != 0 {
+		t.Errorf("expected no timeout set, got %d", settings.SubmissionTimeoutMinutes)
+	}
+
+	if err := db.SetGuildSubmissionTimeout(ctx, "guild1", 15, "admin"); err != nil {
+		t.Fatalf("SetGuildSubmissionTimeout failed: %v", err)
+	}
+	settings, err = db.GetGuildSettings(ctx, "guild1")
+	if err != nil {
+		t.Fatalf("GetGuildSettings failed: %v", err)
+	}
+	if settings.SubmissionTimeoutMinutes != 15 || !settings.TradePreview {
+		t.Errorf("expected 15 minutes with preview kept, got %+v", settings)
+	}
+
+	if err := db.SetGuildSubmissionTimeout(ctx, "guild1", 0, "admin"); err != nil {
+		t.Fatalf("SetGuildSubmissionTimeout reset failed: %v", err)
+	}
+	all, err := db.GetAllGuildSettings(ctx)
+	if err != nil {
+		t.Fatalf("GetAllGuildSettings failed: %v", err)
+	}
+	if len(all) != 1 || all[0].SubmissionTimeoutMinutes != 0 {
+		t.Errorf("expected the timeout reset, got %+v", all)
+	}
+}
+
+func TestGuildReportAutobanThreshold(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := db.SetGuildReportAutobanThreshold(ctx, "guild1", 4, "admin"); err != nil {
+		t.Fatalf("SetGuildReportAutobanThreshold failed: %v", err)
+	}
+	if err := db.SetGuildSubmissionTimeout(ctx, "guild1", 10, "admin"); err != nil {
+		t.Fatalf("SetGuildSubmissionTimeout failed: %v", err)
+	}
+	settings, err := db.GetGuildSettings(ctx, "guild1")
+	if err != nil {
+		t.Fatalf("GetGuildSettings failed: %v", err)
+	}
+	if settings.ReportAutobanThreshold != 4 || settings.SubmissionTimeoutMinutes != 10 {
+		t.Errorf("expected threshold 4 with the timeout kept, got %+v", settings)
+	}
+
+	if err := db.SetGuildReportAutobanThreshold(ctx, "guild1", 0, "admin"); err != nil {
+		t.Fatalf("SetGuildReportAutobanThreshold reset failed: %v", err)
+	}
+	all, err := db.GetAllGuildSettings(ctx)
+	if err != nil {
+		t.Fatalf("GetAllGuildSettings failed: %v", err)
+	}
+	if len(all) != 1 || all[0].ReportAutobanThreshold != 0 {
+		t.Errorf("expected autoban off, got %+v", all)
+	}
+}
+
+func TestGuildMaxActiveOrders(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := db.SetGuildMaxActiveOrders(ctx, "guild1", 10, "admin"); err != nil {
+		t.Fatalf("SetGuildMaxActiveOrders failed: %v", err)
+	}
+	if err := db.SetGuildReportAutobanThreshold(ctx, "guild1", 3, "admin"); err != nil {
+		t.Fatalf("SetGuildReportAutobanThreshold failed: %v", err)
+	}
+	settings, err := db.GetGuildSettings(ctx, "guild1")
+	if err != nil {
+		t.Fatalf("GetGuildSettings failed: %v", err)
+	}
+	if settings.MaxActiveOrders != 10 || settings.ReportAutobanThreshold != 3 {
+		t.Errorf("expected a cap of 10 with the threshold kept, got %+v", settings)
+	}
+
+	if err := db.SetGuildMaxActiveOrders(ctx, "guild1", 0, "admin"); err != nil {
+		t.Fatalf("SetGuildMaxActiveOrders reset failed: %v", err)
+	}
+	all, err := db.GetAllGuildSettings(ctx)
+	if err != nil {
+		t.Fatalf("GetAllGuildSettings failed: %v", err)
+	}
+	if len(all) != 1 || all[0].MaxActiveOrders != 0 {
+		t.Errorf("expected the cap reset, got %+v", all)
+	}
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// prepared returns a cached prepared statement for query, preparing it on
+// first use. Statements live until Close, so only pass queries built from a
+// bounded set of fragments - never interpolate user input or per-call values.
+func (db *DB) prepared(ctx context.Context, query string) (*sql.Stmt, error) {
+	if stmt, ok := db.stmts.Load(query); ok {
+		return stmt.(*sql.Stmt), nil
+	}
+
+	stmt, err := db.conn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+
+	// Another caller may have prepared the same query concurrently
+	if existing, loaded := db.stmts.LoadOrStore(query, stmt); loaded {
+		stmt.Close()
+		return existing.(*sql.Stmt), nil
+	}
+	return stmt, nil
+}
+
+// closeStatements closes and forgets every cached statement
+func (db *DB) closeStatements() error {
+	var firstErr error
+	db.stmts.Range(func(key, value interface{}) bool {
+		if err := value.(*sql.Stmt).Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to close statement: %w", err)
+		}
+		db.stmts.Delete(key)
+		return true
+	})
+	return firstErr
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPreparedReusesStatements(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	query := `SELECT COUNT(*) FROM items WHERE name = ?`
+
+	first, err := db.prepared(ctx, query)
+	if err != nil {
+		t.Fatalf("prepared failed: %v", err)
+	}
+	second, err := db.prepared(ctx, query)
+	if err != nil {
+		t.Fatalf("prepared failed: %v", err)
+	}
+	if first != second {
+		t.Error("expected the cached statement to be reused")
+	}
+
+	if _, err := db.prepared(ctx, `SELECT nonsense FROM`); err == nil {
+		t.Error("expected invalid SQL to fail to prepare")
+	}
+
+	if err := db.closeStatements(); err != nil {
+		t.Fatalf("closeStatements failed: %v", err)
+	}
+	var count int
+	if err := first.QueryRowContext(ctx, "x").Scan(&count); err == nil {
+		t.Error("expected closed statement to be unusable")
+	}
+
+	// A fresh statement is prepared after the cache is cleared
+	third, err := db.prepared(ctx, query)
+	if err != nil {
+		t.Fatalf("prepared failed: %v", err)
+	}
+	if third == first {
+		t.Error("expected a new statement after closing the cache")
+	}
+}
+
+func seedPriceBenchmark(b *testing.B) (*DB, *Item, func()) {
+	b.Helper()
+	db, cleanup := setupTestDB(b)
+
+	port := mustCreatePort(b, db, "Port Royal")
+	item := mustCreateItem(b, db, "Cannon")
+	markets := []Market{{ItemID: item.ID, Price: 100, Quantity: 5}}
+	if err := db.ReplacePortOrders(context.Background(), port.ID, "buy", markets, "user123", "hash", ""); err != nil {
+		cleanup()
+		b.Fatalf("failed to insert orders: %v", err)
+	}
+	return db, item, cleanup
+}
+
+// BenchmarkGetPricesByItem compares the cached statement path against
+// re-parsing the same SQL on every call; run with -benchmem.
+func BenchmarkGetPricesByItem(b *testing.B) {
+	b.Run("prepared", func(b *testing.B) {
+		db, item, cleanup := seedPriceBenchmark(b)
+		defer cleanup()
+		ctx := context.Background()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			if _, err := db.GetPricesByItem(ctx, item.ID, nil, 0, 0, 0, 0); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("unprepared", func(b *testing.B) {
+		db, item, cleanup := seedPriceBenchmark(b)
+		defer cleanup()
+		ctx := context.Background()
+		query, args := pricesByItemQuery(item.ID, 0, 0, 0, 0)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			rows, err := db.conn.QueryContext(ctx, query, args...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := scanMarketsWithJoins(rows); err != nil {
+				b.Fatal(err)
+			}
+			rows.Close()
+		}
+	})
+}
@@ -0,0 +1,153 @@
+// Package metrics holds the bot's Prometheus instrumentation and the
+// optional /metrics listener.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Outcome label values shared by the counters below
+const (
+	OutcomeOK      = "ok"
+	OutcomeError   = "error"
+	OutcomeUnknown = "unknown"
+)
+
+// Registry is a dedicated registry so only the bot's own metrics, plus the
+// standard Go and process collectors, are exposed
+var Registry = prometheus.NewRegistry()
+
+var (
+	// CommandsTotal counts slash command invocations by command and outcome
+	CommandsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wosb_commands_total",
+		Help: "Slash command invocations by command name and outcome.",
+	}, []string{"command", "outcome"})
+
+	// CommandDuration tracks how long each slash command handler ran
+	CommandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "wosb_command_duration_seconds",
+		Help:    "Slash command handler latency by command name.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"command"})
+
+	// OCRTotal counts screenshot analyses by outcome
+	OCRTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wosb_ocr_total",
+		Help: "Screenshot analyses by outcome.",
+	}, []string{"outcome"})
+
+	// OCRDuration tracks screenshot analysis time; the CLI can take a while
+	OCRDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "wosb_ocr_duration_seconds",
+		Help:    "Screenshot analysis latency.",
+		Buckets: []float64{1, 2.5, 5, 10, 20, 30, 60, 120},
+	})
+
+	// JobRunsTotal counts background job passes by job and outcome
+	JobRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wosb_job_runs_total",
+		Help: "Background job passes by job name and outcome.",
+	}, []string{"job", "outcome"})
+
+	// DBQueryDuration tracks database calls made by background jobs
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "wosb_db_query_duration_seconds",
+		Help:    "Database query latency by query name.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"query"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		CommandsTotal,
+		CommandDuration,
+		OCRTotal,
+		OCRDuration,
+		JobRunsTotal,
+		DBQueryDuration,
+	)
+}
+
+// RegisterGauge exposes a value computed at scrape time, such as the number
+// of active trade conversations. Registering a name again replaces the
+// previous callback.
+func RegisterGauge(name, help string, value func() float64) {
+	gauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, value)
+	if err := Registry.Register(gauge); err != nil {
+		var exists prometheus.AlreadyRegisteredError
+		if !errors.As(err, &exists) {
+			panic(err)
+		}
+		Registry.Unregister(exists.ExistingCollector)
+		Registry.MustRegister(gauge)
+	}
+}
+
+// Outcome maps an error to the outcome label
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeOK
+}
+
+// ObserveDBQuery records how long a named query took since start
+func ObserveDBQuery(query string, start time.Time) {
+	DBQueryDuration.WithLabelValues(query).Observe(time.Since(start).Seconds())
+}
+
+// shutdownTimeout bounds how long Close waits for an in-flight scrape
+const shutdownTimeout = 5 * time.Second
+
+// Server serves /metrics on its own listener
+type Server struct {
+	http *http.Server
+}
+
+// NewServer creates a metrics listener on addr (e.g. ":9090")
+func NewServer(addr string) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	return &Server{http: &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}}
+}
+
+// Start listens in the background. Listen errors are returned immediately;
+// errors after that are logged.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.http.Addr, err)
+	}
+
+	log.Printf("Metrics listening on %s/metrics", listener.Addr())
+	go func() {
+		if err := s.http.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Metrics listener stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Close stops the listener
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.http.Shutdown(ctx)
+}
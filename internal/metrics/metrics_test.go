@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestOutcome(t *testing.T) {
+	if got := Outcome(nil); got != OutcomeOK {
+		t.Errorf("Expected %q for nil error, got %q", OutcomeOK, got)
+	}
+	if got := Outcome(errors.New("boom")); got != OutcomeError {
+		t.Errorf("Expected %q for an error, got %q", OutcomeError, got)
+	}
+}
+
+func TestRegisterGaugeReplacesCallback(t *testing.T) {
+	RegisterGauge("wosb_test_gauge", "Test gauge.", func() float64 { return 1 })
+	RegisterGauge("wosb_test_gauge", "Test gauge.", func() float64 { return 2 })
+
+	expected := "# HELP wosb_test_gauge Test gauge.\n# TYPE wosb_test_gauge gauge\nwosb_test_gauge 2\n"
+	if err := testutil.GatherAndCompare(Registry, strings.NewReader(expected), "wosb_test_gauge"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMetricsEndpointExposesLabels(t *testing.T) {
+	CommandsTotal.WithLabelValues("price", OutcomeOK).Inc()
+
+	s := NewServer(":0")
+	rec := httptest.NewRecorder()
+	s.http.Handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, _ := io.ReadAll(rec.Body)
+	if !strings.Contains(string(body), `wosb_commands_total{command="price",outcome="ok"}`) {
+		t.Errorf("Expected labeled command counter in output, got:\n%s", body)
+	}
+}
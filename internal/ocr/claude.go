@@ -3,9 +3,22 @@ package ocr
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
+	"time"
+
+	"wosbTrade/internal/metrics"
+)
+
+// Validation errors for OCR output. For ErrNoPort and ErrNoOrderType the
+// partially parsed MarketData is returned alongside the error so callers can
+// ask the user to fill in the missing piece.
+var (
+	ErrNoPort      = errors.New("could not determine port from screenshot")
+	ErrNoOrderType = errors.New("could not determine order type (buy/sell) from screenshot")
+	ErrNoItems     = errors.New("no items found in screenshot")
 )
 
 type ClaudeClient struct {
@@ -25,6 +38,29 @@ func NewClaudeClient(claudeCodePath string) *ClaudeClient {
 
 // AnalyzeScreenshot uses Claude Code CLI to analyze a market screenshot
 func (c *ClaudeClient) AnalyzeScreenshot(ctx context.Context, imagePath string) (*MarketData, error) {
+	start := time.Now()
+	data, err := c.analyzeScreenshot(ctx, imagePath)
+	metrics.OCRDuration.Observe(time.Since(start).Seconds())
+	metrics.OCRTotal.WithLabelValues(ocrOutcome(err)).Inc()
+	return data, err
+}
+
+// ocrOutcome labels an analysis: screenshots that parsed but miss the port,
+// order type or items are counted apart from CLI or parse failures
+func ocrOutcome(err error) string {
+	switch {
+	case err == nil:
+		return metrics.OutcomeOK
+	case errors.Is(err, ErrNoPort), errors.Is(err, ErrNoOrderType):
+		return "incomplete"
+	case errors.Is(err, ErrNoItems):
+		return "no_items"
+	default:
+		return metrics.OutcomeError
+	}
+}
+
+func (c *ClaudeClient) analyzeScreenshot(ctx context.Context, imagePath string) (*MarketData, error) {
 	// Construct the prompt for Claude Code
 	prompt := fmt.Sprintf(`Please analyze the image at "%s" and extract market data.
 
@@ -66,15 +102,17 @@ Please respond with ONLY the JSON object, nothing else.`, imagePath)
 		return nil, fmt.Errorf("claude code execution failed: %w (output: %s)", err, string(output))
 	}
 
-	// Parse the output
-	outputStr := string(output)
+	return parseMarketData(string(output))
+}
 
+// parseMarketData extracts and validates the market JSON from Claude's output
+func parseMarketData(outputStr string) (*MarketData, error) {
 	// Claude Code may include additional text, so we need to extract the JSON
 	// Look for the JSON structure in the output
 	jsonStart := strings.Index(outputStr, "{")
 	jsonEnd := strings.LastIndex(outputStr, "}")
 
-	if jsonStart == -1 || jsonEnd == -1 {
+	if jsonStart == -1 || jsonEnd == -1 || jsonEnd < jsonStart {
 		return nil, fmt.Errorf("no JSON found in claude code output: %s", outputStr)
 	}
 
@@ -93,17 +131,19 @@ Please respond with ONLY the JSON object, nothing else.`, imagePath)
 		return nil, fmt.Errorf("failed to parse market data: %w (json: %s)", err, jsonStr)
 	}
 
-	// Validate
-	if marketData.Port == "" || marketData.Port == "unknown" {
-		return nil, fmt.Errorf("could not determine port from screenshot")
+	// Validate. Without items there is nothing to recover, so that is checked first.
+	if len(marketData.Items) == 0 {
+		return nil, ErrNoItems
 	}
 
-	if marketData.OrderType != "buy" && marketData.OrderType != "sell" {
-		return nil, fmt.Errorf("could not determine order type (buy/sell) from screenshot")
+	marketData.Port = strings.TrimSpace(marketData.Port)
+	if marketData.Port == "" || strings.EqualFold(marketData.Port, "unknown") {
+		return &marketData, ErrNoPort
 	}
 
-	if len(marketData.Items) == 0 {
-		return nil, fmt.Errorf("no items found in screenshot")
+	marketData.OrderType = strings.ToLower(strings.TrimSpace(marketData.OrderType))
+	if marketData.OrderType != "buy" && marketData.OrderType != "sell" {
+		return &marketData, ErrNoOrderType
 	}
 
 	return &marketData, nil
@@ -0,0 +1,117 @@
+package ocr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseMarketDataValidationErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		wantErr     error
+		wantPartial bool
+	}{
+		{
+			name:    "missing port",
+			output:  `{"port": "", "order_type": "buy", "items": [{"name": "Cannon", "price": 100, "quantity": 5}]}`,
+			wantErr: ErrNoPort, wantPartial: true,
+		},
+		{
+			name:    "unknown port",
+			output:  `{"port": "unknown", "order_type": "sell", "items": [{"name": "Cannon", "price": 100, "quantity": 5}]}`,
+			wantErr: ErrNoPort, wantPartial: true,
+		},
+		{
+			name:    "unknown order type",
+			output:  `{"port": "Tortuga", "order_type": "unknown", "items": [{"name": "Cannon", "price": 100, "quantity": 5}]}`,
+			wantErr: ErrNoOrderType, wantPartial: true,
+		},
+		{
+			name:    "missing order type",
+			output:  `{"port": "Tortuga", "items": [{"name": "Cannon", "price": 100, "quantity": 5}]}`,
+			wantErr: ErrNoOrderType, wantPartial: true,
+		},
+		{
+			name:    "no items",
+			output:  `{"port": "Tortuga", "order_type": "buy", "items": []}`,
+			wantErr: ErrNoItems,
+		},
+		{
+			name:    "no items takes precedence over missing port",
+			output:  `{"port": "unknown", "order_type": "unknown"}`,
+			wantErr: ErrNoItems,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := parseMarketData(tt.output)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantPartial && (data == nil || len(data.Items) == 0) {
+				t.Errorf("expected partial market data to be returned with %v", tt.wantErr)
+			}
+			if !tt.wantPartial && data != nil {
+				t.Errorf("expected no market data with %v", tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseMarketDataValid(t *testing.T) {
+	output := "Here is the data:\n```json\n" +
+		`{"port": " Tortuga ", "order_type": "SELL", "items": [{"name": "Cannon", "price": 100, "quantity": 5}]}` +
+		"\n```"
+
+	data, err := parseMarketData(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.Port != "Tortuga" {
+		t.Errorf("expected port 'Tortuga', got %q", data.Port)
+	}
+	if data.OrderType != "sell" {
+		t.Errorf("expected order type 'sell', got %q", data.OrderType)
+	}
+	if len(data.Items) != 1 || data.Items[0].Name != "Cannon" {
+		t.Errorf("unexpected items: %+v", data.Items)
+	}
+}
+
+func TestParseMarketDataMalformed(t *testing.T) {
+	for _, output := range []string{"no json here", `{"port": "Tortuga",`, "} backwards {"} {
+		data, err := parseMarketData(output)
+		if err == nil {
+			t.Errorf("expected error for %q", output)
+		}
+		for _, sentinel := range []error{ErrNoPort, ErrNoOrderType, ErrNoItems} {
+			if errors.Is(err, sentinel) {
+				t.Errorf("malformed output %q should not report %v", output, sentinel)
+			}
+		}
+		if data != nil {
+			t.Errorf("expected no data for %q", output)
+		}
+	}
+}
+
+func TestOCROutcome(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, "ok"},
+		{ErrNoPort, "incomplete"},
+		{fmt.Errorf("wrapped: %w", ErrNoOrderType), "incomplete"},
+		{ErrNoItems, "no_items"},
+		{errors.New("claude code execution failed"), "error"},
+	}
+	for _, tt := range tests {
+		if got := ocrOutcome(tt.err); got != tt.want {
+			t.Errorf("ocrOutcome(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package ocr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+)
+
+// Screenshot limits applied before OCR. Below the minimum the market text is
+// too small to read; above the maximum the model only spends more tokens, so
+// larger images are scaled down to fit.
+const (
+	MinImageWidth     = 320
+	MinImageHeight    = 240
+	MaxImageDimension = 2000     // longest side after downscaling
+	MaxImageBytes     = 20 << 20 // largest file accepted at all
+	// MaxImagePixels caps the dimensions an image may declare. Decoding
+	// allocates the full bitmap, so a small, highly compressed file claiming
+	// huge dimensions would otherwise exhaust memory.
+	MaxImagePixels = 40_000_000
+)
+
+// Errors returned by PrepareImage; their messages are fit to show users
+var (
+	ErrImageTooSmall    = errors.New("image is too small to read")
+	ErrImageTooLarge    = errors.New("image is too large")
+	ErrUnsupportedImage = errors.New("image is corrupt or in an unsupported format")
+)
+
+// PrepareImage checks a downloaded screenshot before OCR and downscales it in
+// place, keeping its aspect ratio, if its longest side is over
+// MaxImageDimension. PNGs stay PNG and JPEGs stay JPEG; anything else that
+// has to be scaled is rewritten as PNG.
+//
+// WebP can't be decoded with the standard library, so WebP files only get the
+// file size check and are passed through unchanged.
+func PrepareImage(imagePath string) error {
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxImageBytes {
+		return fmt.Errorf("%w (%d MB, max %d MB)", ErrImageTooLarge, len(data)>>20, MaxImageBytes>>20)
+	}
+	if isWebP(data) {
+		return nil
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if cfg.Width < MinImageWidth || cfg.Height < MinImageHeight {
+		return fmt.Errorf("%w (%dx%d, need at least %dx%d)",
+			ErrImageTooSmall, cfg.Width, cfg.Height, MinImageWidth, MinImageHeight)
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxImagePixels {
+		return fmt.Errorf("%w (%dx%d, max %d megapixels)",
+			ErrImageTooLarge, cfg.Width, cfg.Height, MaxImagePixels/1_000_000)
+	}
+
+	width, height := scaledSize(cfg.Width, cfg.Height, MaxImageDimension)
+	if width == cfg.Width && height == cfg.Height {
+		return nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	scaled := downscale(img, width, height)
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: 90})
+	} else {
+		err = png.Encode(&buf, scaled)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode scaled image: %w", err)
+	}
+
+	// Write beside the original and swap it in so a failure leaves it intact
+	tmpPath := imagePath + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write scaled image: %w", err)
+	}
+	if err := os.Rename(tmpPath, imagePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace image: %w", err)
+	}
+	return nil
+}
+
+// isWebP reports whether data starts with a WebP RIFF header
+func isWebP(data []byte) bool {
+	return len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
+
+// scaledSize fits width x height within maxSide on its longest side,
+// keeping the aspect ratio. Sizes that already fit are returned unchanged.
+func scaledSize(width, height, maxSide int) (int, int) {
+	if width <= maxSide && height <= maxSide {
+		return width, height
+	}
+	if width >= height {
+		return maxSide, max(1, height*maxSide/width)
+	}
+	return max(1, width*maxSide/height), maxSide
+}
+
+// downscale shrinks img to width x height by averaging the source pixels that
+// fall in each destination pixel, which keeps small text legible
+func downscale(img image.Image, width, height int) *image.RGBA {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	type sum struct{ r, g, b, a, n uint64 }
+	sums := make([]sum, width*height)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		dy := (y - bounds.Min.Y) * height / srcH
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			dx := (x - bounds.Min.X) * width / srcW
+			r, g, b, a := img.At(x, y).RGBA()
+			s := &sums[dy*width+dx]
+			s.r += uint64(r)
+			s.g += uint64(g)
+			s.b += uint64(b)
+			s.a += uint64(a)
+			s.n++
+		}
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for idx, s := range sums {
+		if s.n == 0 {
+			continue
+		}
+		// RGBA() values are 16-bit; the output is 8-bit
+		p := out.Pix[idx*4 : idx*4+4]
+		p[0] = uint8(s.r / s.n >> 8)
+		p[1] = uint8(s.g / s.n >> 8)
+		p[2] = uint8(s.b / s.n >> 8)
+		p[3] = uint8(s.a / s.n >> 8)
+	}
+	return out
+}
+
+// dHash grid: 9 columns give 8 horizontal gradients per row, 8 rows give 64 bits
+const (
+	dHashWidth  = 9
+	dHashHeight = 8
+)
+
+// PerceptualHash computes a difference hash (dHash) of an image file.
+// Unlike a SHA256 of the bytes, visually similar images (re-encoded, resized,
+// lightly cropped) produce hashes with a small Hamming distance.
+func PerceptualHash(imagePath string) (string, error) {
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < dHashWidth || height < dHashHeight {
+		return "", fmt.Errorf("image too small to hash (%dx%d)", width, height)
+	}
+
+	// Downscale to a grayscale grid by averaging the luminance of each cell
+	var sums [dHashHeight][dHashWidth]float64
+	var counts [dHashHeight][dHashWidth]int
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		cy := (y - bounds.Min.Y) * dHashHeight / height
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			cx := (x - bounds.Min.X) * dHashWidth / width
+			r, g, b, _ := img.At(x, y).RGBA()
+			sums[cy][cx] += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			counts[cy][cx]++
+		}
+	}
+
+	// Each bit records whether brightness increases left to right
+	var hash uint64
+	for y := 0; y < dHashHeight; y++ {
+		for x := 0; x < dHashWidth-1; x++ {
+			left := sums[y][x] / float64(counts[y][x])
+			right := sums[y][x+1] / float64(counts[y][x+1])
+			hash <<= 1
+			if left < right {
+				hash |= 1
+			}
+		}
+	}
+
+	return fmt.Sprintf("%016x", hash), nil
+}
@@ -0,0 +1,123 @@
+package ocr
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestPNG(t *testing.T, width, height int) string {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 0, 255})
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "shot.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create image: %v", err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("failed to encode image: %v", err)
+	}
+	return path
+}
+
+func imageSize(t *testing.T, path string) (int, int) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open image: %v", err)
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		t.Fatalf("failed to decode image: %v", err)
+	}
+	return cfg.Width, cfg.Height
+}
+
+func TestPrepareImageDownscalesKeepingAspect(t *testing.T) {
+	path := writeTestPNG(t, 4000, 1000)
+
+	if err := PrepareImage(path); err != nil {
+		t.Fatalf("PrepareImage failed: %v", err)
+	}
+	if w, h := imageSize(t, path); w != MaxImageDimension || h != MaxImageDimension/4 {
+		t.Errorf("expected %dx%d, got %dx%d", MaxImageDimension, MaxImageDimension/4, w, h)
+	}
+}
+
+func TestPrepareImageLeavesNormalImages(t *testing.T) {
+	path := writeTestPNG(t, 1280, 720)
+	before, _ := os.ReadFile(path)
+
+	if err := PrepareImage(path); err != nil {
+		t.Fatalf("PrepareImage failed: %v", err)
+	}
+	after, _ := os.ReadFile(path)
+	if string(before) != string(after) {
+		t.Error("expected an image within limits to be left untouched")
+	}
+}
+
+func TestPrepareImageRejects(t *testing.T) {
+	small := writeTestPNG(t, 200, 100)
+	if err := PrepareImage(small); !errors.Is(err, ErrImageTooSmall) {
+		t.Errorf("expected ErrImageTooSmall, got %v", err)
+	}
+
+	corrupt := filepath.Join(t.TempDir(), "corrupt.png")
+	if err := os.WriteFile(corrupt, []byte("not an image"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := PrepareImage(corrupt); !errors.Is(err, ErrUnsupportedImage) {
+		t.Errorf("expected ErrUnsupportedImage, got %v", err)
+	}
+}
+
+func TestPrepareImageRejectsHugeDimensions(t *testing.T) {
+	// A tiny PNG whose header claims 60000x60000; decoding it would try to
+	// allocate gigabytes
+	path := writeTestPNG(t, 1, 1)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read image: %v", err)
+	}
+	ihdr := data[12:29] // chunk type and data, covered by the CRC
+	binary.BigEndian.PutUint32(ihdr[4:8], 60000)
+	binary.BigEndian.PutUint32(ihdr[8:12], 60000)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(ihdr))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("failed to write image: %v", err)
+	}
+
+	if err := PrepareImage(path); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("expected ErrImageTooLarge, got %v", err)
+	}
+}
+
+func TestScaledSize(t *testing.T) {
+	tests := []struct {
+		w, h, wantW, wantH int
+	}{
+		{1920, 1080, 1920, 1080},
+		{3840, 2160, 2000, 1125},
+		{1000, 5000, 400, 2000},
+	}
+	for _, tt := range tests {
+		if w, h := scaledSize(tt.w, tt.h, 2000); w != tt.wantW || h != tt.wantH {
+			t.Errorf("scaledSize(%d, %d) = %dx%d, want %dx%d", tt.w, tt.h, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
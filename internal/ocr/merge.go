@@ -0,0 +1,63 @@
+package ocr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergeMarketData combines the results of several screenshots of the same
+// market board into one. Items are deduplicated by name with later screenshots
+// winning, while keeping the order in which items were first seen.
+//
+// Nil results (screenshots with no items) are skipped. Like AnalyzeScreenshot,
+// the merged data is returned alongside ErrNoPort or ErrNoOrderType when no
+// screenshot identified them.
+func MergeMarketData(results []*MarketData) (*MarketData, error) {
+	merged := &MarketData{}
+	index := make(map[string]int)
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		port := strings.TrimSpace(result.Port)
+		if port != "" && !strings.EqualFold(port, "unknown") {
+			if merged.Port == "" {
+				merged.Port = port
+			} else if !strings.EqualFold(merged.Port, port) {
+				return nil, fmt.Errorf("screenshots show different ports (%s, %s)", merged.Port, port)
+			}
+		}
+
+		orderType := strings.ToLower(strings.TrimSpace(result.OrderType))
+		if orderType == "buy" || orderType == "sell" {
+			if merged.OrderType == "" {
+				merged.OrderType = orderType
+			} else if merged.OrderType != orderType {
+				return nil, fmt.Errorf("screenshots show different order types (%s, %s)", merged.OrderType, orderType)
+			}
+		}
+
+		for _, item := range result.Items {
+			if idx, ok := index[item.Name]; ok {
+				merged.Items[idx] = item
+				continue
+			}
+			index[item.Name] = len(merged.Items)
+			merged.Items = append(merged.Items, item)
+		}
+	}
+
+	if len(merged.Items) == 0 {
+		return nil, ErrNoItems
+	}
+	if merged.Port == "" {
+		return merged, ErrNoPort
+	}
+	if merged.OrderType == "" {
+		return merged, ErrNoOrderType
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,83 @@
+package ocr
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMergeMarketDataDedupesLaterWins(t *testing.T) {
+	results := []*MarketData{
+		{
+			Port:      "Tortuga",
+			OrderType: "sell",
+			Items: []MarketItem{
+				{Name: "Cannon", Price: 100, Quantity: 5},
+				{Name: "Rope", Price: 10, Quantity: 50},
+			},
+		},
+		nil,
+		{
+			Port:      "unknown",
+			OrderType: "SELL",
+			Items: []MarketItem{
+				{Name: "Rope", Price: 12, Quantity: 40},
+				{Name: "Sail", Price: 30, Quantity: 8},
+			},
+		},
+	}
+
+	merged, err := MergeMarketData(results)
+	if err != nil {
+		t.Fatalf("MergeMarketData failed: %v", err)
+	}
+
+	if merged.Port != "Tortuga" || merged.OrderType != "sell" {
+		t.Errorf("Expected Tortuga/sell, got %s/%s", merged.Port, merged.OrderType)
+	}
+
+	want := []MarketItem{
+		{Name: "Cannon", Price: 100, Quantity: 5},
+		{Name: "Rope", Price: 12, Quantity: 40},
+		{Name: "Sail", Price: 30, Quantity: 8},
+	}
+	if !reflect.DeepEqual(merged.Items, want) {
+		t.Errorf("Expected items %+v, got %+v", want, merged.Items)
+	}
+}
+
+func TestMergeMarketDataConflicts(t *testing.T) {
+	items := []MarketItem{{Name: "Cannon", Price: 100, Quantity: 5}}
+
+	if _, err := MergeMarketData([]*MarketData{
+		{Port: "Tortuga", OrderType: "buy", Items: items},
+		{Port: "Port Royal", OrderType: "buy", Items: items},
+	}); err == nil {
+		t.Error("Expected error for different ports")
+	}
+
+	if _, err := MergeMarketData([]*MarketData{
+		{Port: "Tortuga", OrderType: "buy", Items: items},
+		{Port: "Tortuga", OrderType: "sell", Items: items},
+	}); err == nil {
+		t.Error("Expected error for different order types")
+	}
+}
+
+func TestMergeMarketDataMissingFields(t *testing.T) {
+	items := []MarketItem{{Name: "Cannon", Price: 100, Quantity: 5}}
+
+	if _, err := MergeMarketData([]*MarketData{nil, nil}); !errors.Is(err, ErrNoItems) {
+		t.Errorf("Expected ErrNoItems, got %v", err)
+	}
+
+	merged, err := MergeMarketData([]*MarketData{{Port: "", OrderType: "buy", Items: items}})
+	if !errors.Is(err, ErrNoPort) || merged == nil {
+		t.Errorf("Expected partial data with ErrNoPort, got %v, %v", merged, err)
+	}
+
+	merged, err = MergeMarketData([]*MarketData{{Port: "Tortuga", OrderType: "unknown", Items: items}})
+	if !errors.Is(err, ErrNoOrderType) || merged == nil {
+		t.Errorf("Expected partial data with ErrNoOrderType, got %v, %v", merged, err)
+	}
+}